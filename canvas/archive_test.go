@@ -0,0 +1,65 @@
+package canvas
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestZip 按顺序写入 zip 成员（名称与内容成对出现）
+func writeTestZip(t *testing.T, entries ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for i := 0; i+1 < len(entries); i += 2 {
+		w, err := zw.Create(entries[i])
+		if err != nil {
+			t.Fatalf("无法创建 zip 成员: %v", err)
+		}
+		w.Write([]byte(entries[i+1]))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("无法关闭 zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestAnalyzeArchiveMembers(t *testing.T) {
+	tmpDir := t.TempDir()
+	jar := writeTestZip(t,
+		"META-INF/MANIFEST.MF", "Manifest-Version: 1.0\n",
+		"META-INF/maven/com.alibaba/fastjson/pom.properties", "groupId=com.alibaba\nartifactId=fastjson\nversion=1.2.83\n",
+	)
+	war := writeTestZip(t, "WEB-INF/lib/json-lib.jar", string(jar))
+	if err := os.WriteFile(filepath.Join(tmpDir, "app.war"), war, 0644); err != nil {
+		t.Fatalf("无法写入 app.war: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "Main.java"), []byte("class Main {}\n"), 0644); err != nil {
+		t.Fatalf("无法写入 Main.java: %v", err)
+	}
+
+	fastjsonVersion := func(depth int) (string, bool) {
+		report, err := AnalyzeWithOptions(tmpDir, Options{ArchiveDepth: depth})
+		if err != nil {
+			t.Fatalf("AnalyzeWithOptions 失败: %v", err)
+		}
+		for _, item := range report.Detection.Components {
+			if item.Name == "fastjson" {
+				return item.Version, true
+			}
+		}
+		return "", false
+	}
+
+	if _, ok := fastjsonVersion(0); ok {
+		t.Errorf("未展开压缩包时不应识别 fastjson")
+	}
+	if _, ok := fastjsonVersion(1); ok {
+		t.Errorf("只展开一层时不应识别嵌套 jar 中的 fastjson")
+	}
+	if version, ok := fastjsonVersion(2); !ok || version != "1.2.83" {
+		t.Errorf("应从嵌套 jar 的 pom.properties 识别 fastjson 1.2.83，实际为 %q (%v)", version, ok)
+	}
+}
@@ -3,6 +3,7 @@ package canvas
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"sort"
 	"strings"
 	"time"
@@ -12,34 +13,87 @@ import (
 	"github.com/winezer0/codecanvas/internal/model"
 )
 
+// Options 分析选项
+// - RulesDir: 用户规则目录，为空时仅使用嵌入式规则
+// - ArchiveDepth: 展开 jar/war/zip/whl/tgz 等压缩包的嵌套层数，0 表示不展开
+type Options struct {
+	RulesDir     string
+	ArchiveDepth int
+}
+
 // Analyze performs a full analysis and returns a CanvasReport.
 func Analyze(path string, rulesDir string) (*model.CanvasReport, error) {
-	ctx := context.Background()
+	return AnalyzeWithOptions(path, Options{RulesDir: rulesDir})
+}
 
+// AnalyzeWithOptions 按给定选项分析代码库并返回分析报告
+func AnalyzeWithOptions(path string, opts Options) (*model.CanvasReport, error) {
 	// Analyze code profile
 	az := analyzer.NewCodeAnalyzer()
+	az.ArchiveDepth = opts.ArchiveDepth
 	profile, index, err := az.AnalyzeCodeProfile(path)
 	if err != nil {
 		return nil, fmt.Errorf("error analyzing code profile: %v", err)
 	}
-
 	// Create rule engine
-	detectEngine, err := frameengine.NewCanvasEngine(rulesDir)
+	detectEngine, err := frameengine.NewCanvasEngine(opts.RulesDir)
+	if err != nil {
+		return nil, fmt.Errorf("error loading rules: %v", err)
+	}
+	return analyzeIndex(detectEngine, profile, index)
+}
+
+// AnalyzeFS 分析文件系统 fsys（如 fstest.MapFS、embed.FS 或其他非磁盘的文件树）中的代码库并返回分析报告，
+// name 作为报告中代码画像的根路径
+func AnalyzeFS(fsys fs.FS, name string, opts Options) (*model.CanvasReport, error) {
+	az := analyzer.NewCodeAnalyzer()
+	az.ArchiveDepth = opts.ArchiveDepth
+	detectEngine, err := frameengine.NewCanvasEngine(opts.RulesDir)
 	if err != nil {
 		return nil, fmt.Errorf("error loading rules: %v", err)
 	}
+	return analyzeFS(az, detectEngine, fsys, name)
+}
+
+// analyzeFS 使用给定的分析器与规则引擎分析文件系统，便于多次分析之间共享规则与缓存
+func analyzeFS(az *analyzer.CodeAnalyzer, detectEngine *frameengine.CanvasEngine, fsys fs.FS, name string) (*model.CanvasReport, error) {
+	profile, index, err := az.AnalyzeFS(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("error analyzing code profile: %v", err)
+	}
+	return analyzeIndex(detectEngine, profile, index)
+}
+
+// analyzeIndex 根据代码画像与文件索引检测框架、组件与子项目，生成分析报告
+func analyzeIndex(detectEngine *frameengine.CanvasEngine, profile *model.CodeProfile, index *model.FileIndex) (*model.CanvasReport, error) {
+	ctx := context.Background()
+
+	// 解析依赖清单
+	inventory := detectEngine.CollectDependencies(index)
 	// 检测框架和组件
-	detect, err := detectEngine.DetectFrameworks(ctx, index, profile.Expands)
+	detect, err := detectEngine.DetectFrameworksWithInventory(ctx, index, profile.Expands, inventory)
 	if err != nil {
 		return nil, fmt.Errorf("error detecting frameworks and components: %v", err)
 	}
+	// 分别分析各个子项目，顶层结果为全部项目的汇总
+	projects, err := analyzeProjects(ctx, detectEngine, index)
+	if err != nil {
+		return nil, err
+	}
 
 	// 生成分析报告
 	report := &model.CanvasReport{
 		CodeProfile: *profile,
 		Detection:   *detect,
-		Timestamp:   time.Now(),
+		// 源码中导入但未声明的依赖同样记录在依赖列表中
+		Dependencies: append(inventory.Dependencies, inventory.Undeclared...),
+		// 统计未被规则识别的依赖，便于补充规则
+		UnclassifiedDependencies: detectEngine.UnclassifiedDependencies(inventory, detect),
+		Projects:                 projects,
+		Timestamp:                time.Now(),
 	}
+	// 在清单文件中定位依赖的声明行，供策略违规与 SARIF 等输出指向具体位置
+	locateEvidence(index.FileSystem(), report)
 	return report, nil
 }
 
@@ -51,18 +105,24 @@ func ToSimpleReport(report *model.CanvasReport) *model.AnalysisResult {
 		LanguageInfos:         report.CodeProfile.LanguageInfos,
 		Languages:             report.CodeProfile.Languages,
 		DesktopLanguages:      report.CodeProfile.DesktopLanguages,
+		MobileLanguages:       report.CodeProfile.MobileLanguages,
 		FrontendLanguages:     report.CodeProfile.FrontendLanguages,
 		BackendLanguages:      report.CodeProfile.BackendLanguages,
 		OtherLanguages:        report.CodeProfile.OtherLanguages,
 		Frameworks:            getItemsWithVersions(report.Detection.Frameworks),
 		Components:            getItemsWithVersions(report.Detection.Components),
+		FrameworkVersions:     getItemsAllVersions(report.Detection.Frameworks),
+		ComponentVersions:     getItemsAllVersions(report.Detection.Components),
+		VersionSkew:           append(getVersionSkews(report.Detection.Frameworks), getVersionSkews(report.Detection.Components)...),
+		BuildSystems:          report.Detection.BuildSystems,
 		MainFrontendLanguages: getTopLanguages(report.CodeProfile.FrontendLanguages, langStats, nil, 3),
 		MainBackendLanguages:  getTopLanguages(report.CodeProfile.BackendLanguages, langStats, nil, 3),
 	}
 	return result
 }
 
-// getItemsWithVersions 提取去重后的 items (组件名或者框架名)及其版本，返回名称到版本的映射
+// getItemsWithVersions 提取去重后的 items (组件名或者框架名)及其版本，返回名称到版本的映射；
+// 只声明了版本约束时使用约束原文（如 "^18.2.0"）
 func getItemsWithVersions(items []model.DetectedItem) map[string]string {
 	result := make(map[string]string)
 
@@ -71,8 +131,11 @@ func getItemsWithVersions(items []model.DetectedItem) map[string]string {
 		if name == "" {
 			continue // 跳过空名称
 		}
-		// 如果版本为空，使用空字符串
+		// 没有具体版本时使用声明的约束，两者都没有时使用空字符串
 		version := item.Version
+		if version == "" {
+			version = item.DeclaredConstraint
+		}
 		// 如果已经存在，不覆盖，保留第一个匹配的版本
 		if _, exists := result[name]; !exists {
 			result[name] = version
@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
 )
 
 func TestAnalyzeDirectory(t *testing.T) {
@@ -67,6 +69,10 @@ func main() {
 	for _, fw := range result.Detection.Frameworks {
 		if fw.Name == "Gin" {
 			foundGin = true
+			// main.go 导入了 gin，使用权重为导入文件数量
+			if fw.Usage != model.UsageDeclaredAndUsed || fw.Weight != 1 {
+				t.Errorf("Gin 使用情况错误: %s (%d)", fw.Usage, fw.Weight)
+			}
 			break
 		}
 	}
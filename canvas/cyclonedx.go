@@ -0,0 +1,291 @@
+package canvas
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// CycloneDX SBOM 的输出格式
+const (
+	FormatCycloneDXJSON = "cyclonedx-json"
+	FormatCycloneDXXML  = "cyclonedx-xml"
+)
+
+// cycloneDXSpecVersion 输出的 CycloneDX 规范版本
+const cycloneDXSpecVersion = "1.5"
+
+// cdxBOM CycloneDX 文档，JSON 与 XML 共用同一组结构，只在一种格式中出现的字段用 "-" 忽略
+type cdxBOM struct {
+	XMLName      xml.Name               `json:"-" xml:"bom"`
+	XMLNS        string                 `json:"-" xml:"xmlns,attr"`
+	BOMFormat    string                 `json:"bomFormat" xml:"-"`
+	SpecVersion  string                 `json:"specVersion" xml:"-"`
+	SerialNumber string                 `json:"serialNumber" xml:"serialNumber,attr"`
+	Version      int                    `json:"version" xml:"version,attr"`
+	Metadata     cdxMetadata            `json:"metadata" xml:"metadata"`
+	Components   cdxList[cdxComponent]  `json:"components" xml:"components,omitempty"`
+	Dependencies cdxList[cdxDependency] `json:"dependencies,omitempty" xml:"dependencies,omitempty"`
+}
+
+type cdxMetadata struct {
+	Timestamp  string               `json:"timestamp" xml:"timestamp"`
+	Tools      *cdxTools            `json:"tools,omitempty" xml:"tools,omitempty"`
+	Component  *cdxComponent        `json:"component,omitempty" xml:"component,omitempty"`
+	Properties cdxList[cdxProperty] `json:"properties,omitempty" xml:"properties,omitempty"`
+}
+
+type cdxTools struct {
+	Components cdxList[cdxComponent] `json:"components" xml:"components,omitempty"`
+}
+
+// cdxComponent CycloneDX 组件，XML 子元素的顺序与 1.5 的 XSD 一致
+type cdxComponent struct {
+	Type       string               `json:"type" xml:"type,attr"`
+	BOMRef     string               `json:"bom-ref,omitempty" xml:"bom-ref,attr,omitempty"`
+	Group      string               `json:"group,omitempty" xml:"group,omitempty"`
+	Name       string               `json:"name" xml:"name"`
+	Version    string               `json:"version,omitempty" xml:"version,omitempty"`
+	Scope      string               `json:"scope,omitempty" xml:"scope,omitempty"`
+	Purl       string               `json:"purl,omitempty" xml:"purl,omitempty"`
+	Properties cdxList[cdxProperty] `json:"properties,omitempty" xml:"properties,omitempty"`
+	Evidence   *cdxEvidence         `json:"evidence,omitempty" xml:"evidence,omitempty"`
+}
+
+type cdxProperty struct {
+	Name  string `json:"name" xml:"name,attr"`
+	Value string `json:"value" xml:",chardata"`
+}
+
+type cdxEvidence struct {
+	Identity    *cdxIdentity           `json:"identity,omitempty" xml:"identity,omitempty"`
+	Occurrences cdxList[cdxOccurrence] `json:"occurrences,omitempty" xml:"occurrences,omitempty"`
+}
+
+type cdxIdentity struct {
+	Field      string             `json:"field" xml:"field"`
+	Confidence float64            `json:"confidence" xml:"confidence"`
+	Methods    cdxList[cdxMethod] `json:"methods,omitempty" xml:"methods,omitempty"`
+}
+
+type cdxMethod struct {
+	Technique  string  `json:"technique" xml:"technique"`
+	Confidence float64 `json:"confidence" xml:"confidence"`
+	Value      string  `json:"value,omitempty" xml:"value,omitempty"`
+}
+
+type cdxOccurrence struct {
+	Location string `json:"location" xml:"location"`
+}
+
+// cdxDependency 依赖关系：JSON 中为 dependsOn 引用列表，XML 中为嵌套的 dependency 元素
+type cdxDependency struct {
+	Ref       string          `json:"ref" xml:"ref,attr"`
+	DependsOn []string        `json:"dependsOn,omitempty" xml:"-"`
+	Children  []cdxDependency `json:"-" xml:"dependency,omitempty"`
+}
+
+// cdxListItems XML 中列表元素与其子元素的名称
+var cdxListItems = map[string]string{
+	"components":   "component",
+	"properties":   "property",
+	"methods":      "method",
+	"occurrences":  "occurrence",
+	"dependencies": "dependency",
+}
+
+// cdxList XML 中包在复数元素内的列表，为空时不输出外层元素；JSON 中即为数组
+type cdxList[T any] []T
+
+// MarshalXML 输出外层元素与逐个子元素
+func (l cdxList[T]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if len(l) == 0 {
+		return nil
+	}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	item := xml.StartElement{Name: xml.Name{Local: cdxListItems[start.Name.Local]}}
+	for _, value := range l {
+		if err := e.EncodeElement(value, item); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// cycloneDXRootRef 被分析项目在文档中的引用
+const cycloneDXRootRef = "codecanvas:project"
+
+// WriteCycloneDX 将分析报告写为 CycloneDX 1.5 SBOM，format 为 FormatCycloneDXJSON 或 FormatCycloneDXXML。
+// 框架、组件与依赖记录合并为带 purl、版本、作用域与检测证据的组件，语言统计写入元数据的属性，
+// 锁文件中记录的依赖关系写入 dependencies
+func WriteCycloneDX(w io.Writer, report *model.CanvasReport, format string) error {
+	bom := buildCycloneDX(report)
+	switch format {
+	case FormatCycloneDXJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(bom)
+	case FormatCycloneDXXML:
+		bom.XMLNS = "http://cyclonedx.org/schema/bom/" + cycloneDXSpecVersion
+		for i := range bom.Dependencies {
+			for _, ref := range bom.Dependencies[i].DependsOn {
+				bom.Dependencies[i].Children = append(bom.Dependencies[i].Children, cdxDependency{Ref: ref})
+			}
+		}
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return err
+		}
+		encoder := xml.NewEncoder(w)
+		encoder.Indent("", "  ")
+		if err := encoder.Encode(bom); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, "\n")
+		return err
+	}
+	return fmt.Errorf("unsupported CycloneDX format: %s", format)
+}
+
+// buildCycloneDX 生成 CycloneDX 文档
+func buildCycloneDX(report *model.CanvasReport) *cdxBOM {
+	doc := buildSBOM(report)
+	timestamp := report.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	root := &cdxComponent{Type: "application", BOMRef: cycloneDXRootRef, Name: doc.Name}
+	if report.Git != nil {
+		root.Version = report.Git.Commit
+	}
+	bom := &cdxBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  cycloneDXSpecVersion,
+		SerialNumber: newSerialNumber(),
+		Version:      1,
+		Metadata: cdxMetadata{
+			Timestamp:  timestamp.UTC().Format(time.RFC3339),
+			Tools:      &cdxTools{Components: cdxList[cdxComponent]{{Type: "application", Name: "codecanvas", Version: report.Version}}},
+			Component:  root,
+			Properties: cycloneDXMetadataProperties(report),
+		},
+		Components: cdxList[cdxComponent]{},
+	}
+
+	for _, pkg := range doc.Packages {
+		bom.Components = append(bom.Components, cycloneDXComponent(pkg))
+		if len(pkg.Requires) > 0 {
+			bom.Dependencies = append(bom.Dependencies, cdxDependency{Ref: pkg.Ref, DependsOn: pkg.Requires})
+		}
+	}
+	if len(doc.Roots) > 0 {
+		bom.Dependencies = append(cdxList[cdxDependency]{{Ref: cycloneDXRootRef, DependsOn: doc.Roots}}, bom.Dependencies...)
+	}
+	return bom
+}
+
+// cycloneDXMetadataProperties 将语言统计与构建系统写为元数据属性，语言属性的值为代码行数
+func cycloneDXMetadataProperties(report *model.CanvasReport) cdxList[cdxProperty] {
+	properties := cdxList[cdxProperty]{
+		{Name: "codecanvas:total_files", Value: strconv.Itoa(report.CodeProfile.TotalFiles)},
+		{Name: "codecanvas:total_lines", Value: strconv.Itoa(report.CodeProfile.TotalLines)},
+	}
+	for _, lang := range report.CodeProfile.LanguageInfos {
+		properties = append(properties, cdxProperty{Name: "codecanvas:language:" + lang.Name, Value: strconv.Itoa(lang.CodeLines)})
+	}
+	for _, buildSystem := range report.Detection.BuildSystems {
+		properties = append(properties, cdxProperty{Name: "codecanvas:build_system", Value: buildSystem})
+	}
+	return properties
+}
+
+// cycloneDXComponent 将软件包转换为组件：框架为 framework 类型，其余为 library；
+// 开发依赖与构建依赖（如构建插件）不随制品发布，作用域为 excluded，其余为 required
+func cycloneDXComponent(pkg *sbomPackage) cdxComponent {
+	component := cdxComponent{
+		Type:    "library",
+		BOMRef:  pkg.Ref,
+		Group:   pkg.Group,
+		Name:    pkg.Name,
+		Version: pkg.Version,
+		Scope:   "required",
+		Purl:    pkg.Purl,
+	}
+	if pkg.Scope == model.ScopeDev || pkg.Scope == model.ScopeBuild {
+		component.Scope = "excluded"
+	}
+
+	addProperty := func(name, value string) {
+		if value != "" {
+			component.Properties = append(component.Properties, cdxProperty{Name: name, Value: value})
+		}
+	}
+	addProperty("codecanvas:ecosystem", pkg.Ecosystem)
+	addProperty("codecanvas:dependency_scope", pkg.Scope)
+	addProperty("codecanvas:declared_constraint", pkg.Constraint)
+	addProperty("codecanvas:usage", pkg.Usage)
+	if pkg.Direct {
+		addProperty("codecanvas:direct", "true")
+	}
+	for _, item := range pkg.Items {
+		if item.Type == model.RuleTypeFramework {
+			component.Type = "framework"
+		}
+		addProperty("codecanvas:"+item.Type, item.Name)
+		addProperty("codecanvas:category", item.Category)
+		addProperty("codecanvas:language", item.Language)
+	}
+
+	// 锁文件中的记录置信度最高，清单中的声明次之，只由规则检测到的检测项最低
+	identity := &cdxIdentity{Field: "name"}
+	if pkg.Purl != "" {
+		identity.Field = "purl"
+	}
+	manifestConfidence := 0.7
+	if pkg.Locked {
+		manifestConfidence = 1
+	}
+	// 有生态的软件包的位置均为声明或锁定该包的清单文件
+	for _, path := range pkg.Paths {
+		if pkg.Ecosystem != "" {
+			identity.Methods = append(identity.Methods, cdxMethod{Technique: "manifest-analysis", Confidence: manifestConfidence, Value: path})
+		}
+	}
+	for _, item := range pkg.Items {
+		if item.Evidence != "" {
+			identity.Methods = append(identity.Methods, cdxMethod{Technique: "other", Confidence: 0.5, Value: item.Evidence})
+		}
+	}
+	for _, method := range identity.Methods {
+		identity.Confidence = max(identity.Confidence, method.Confidence)
+	}
+	evidence := &cdxEvidence{}
+	if len(identity.Methods) > 0 {
+		evidence.Identity = identity
+	}
+	for _, path := range pkg.Paths {
+		evidence.Occurrences = append(evidence.Occurrences, cdxOccurrence{Location: path})
+	}
+	if evidence.Identity != nil || len(evidence.Occurrences) > 0 {
+		component.Evidence = evidence
+	}
+	return component
+}
+
+// newSerialNumber 生成随机的 urn:uuid 序列号（UUID 第 4 版）
+func newSerialNumber() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
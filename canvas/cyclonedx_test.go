@@ -0,0 +1,207 @@
+package canvas
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"regexp"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// sbomTestFS SBOM 测试用项目：npm 锁文件记录了依赖关系、许可证与摘要，go.mod 中的 gin 被识别为框架
+var sbomTestFS = fstest.MapFS{
+	"package.json": {Data: []byte(`{"dependencies": {"react": "^18.2.0", "@babel/runtime": "^7.22.0"}, "devDependencies": {"vite": "^5.0.0"}}`)},
+	"package-lock.json": {Data: []byte(`{"lockfileVersion": 3, "packages": {"": {},
+  "node_modules/react": {"version": "18.2.0", "license": "MIT", "dependencies": {"loose-envify": "^1.1.0"},
+    "integrity": "sha512-/3IjMdb2L9QbBdWiW5e3P2/npwMBaU9mHCSCUzNln0ZCYbcfTsGbTJrU/kGemdH2IWmB2ioZ+zkxtmq6g09fGQ=="},
+  "node_modules/loose-envify": {"version": "1.4.0"},
+  "node_modules/@babel/runtime": {"version": "7.23.2"},
+  "node_modules/vite": {"version": "5.0.10", "dev": true}}}`)},
+	"go.mod":  {Data: []byte("module example.com/app\n\ngo 1.22\n\nrequire github.com/gin-gonic/gin v1.9.0\n")},
+	"main.go": {Data: []byte("package main\n\nimport \"github.com/gin-gonic/gin\"\n\nfunc main() { gin.Default() }\n")},
+}
+
+func TestWriteCycloneDXJSON(t *testing.T) {
+	report, err := AnalyzeFS(sbomTestFS, "app", Options{})
+	if err != nil {
+		t.Fatalf("AnalyzeFS 失败: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := WriteCycloneDX(&buf, report, FormatCycloneDXJSON); err != nil {
+		t.Fatalf("WriteCycloneDX 失败: %v", err)
+	}
+	var bom cdxBOM
+	if err := json.Unmarshal(buf.Bytes(), &bom); err != nil {
+		t.Fatalf("输出不是有效的 JSON: %v", err)
+	}
+	if bom.BOMFormat != "CycloneDX" || bom.SpecVersion != "1.5" || bom.Version != 1 {
+		t.Errorf("文档头错误: %s %s %d", bom.BOMFormat, bom.SpecVersion, bom.Version)
+	}
+	if !regexp.MustCompile(`^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(bom.SerialNumber) {
+		t.Errorf("序列号格式错误: %s", bom.SerialNumber)
+	}
+	if !slices.ContainsFunc(bom.Metadata.Properties, func(p cdxProperty) bool { return p.Name == "codecanvas:language:Go" && p.Value == "3" }) {
+		t.Errorf("元数据中缺少语言属性: %+v", bom.Metadata.Properties)
+	}
+
+	components := make(map[string]cdxComponent)
+	for _, component := range bom.Components {
+		if _, ok := components[component.BOMRef]; ok {
+			t.Errorf("bom-ref 重复: %s", component.BOMRef)
+		}
+		components[component.BOMRef] = component
+	}
+	if len(components) != 5 {
+		t.Errorf("应有 5 个组件（清单中未锁定的声明被锁定版本取代），实际为 %d", len(components))
+	}
+	gin := components["pkg:golang/github.com/gin-gonic/gin@v1.9.0"]
+	if gin.Type != "framework" || gin.Name != "github.com/gin-gonic/gin" || gin.Scope != "required" ||
+		!slices.Contains(gin.Properties, cdxProperty{Name: "codecanvas:framework", Value: "Gin"}) {
+		t.Errorf("gin 组件错误: %+v", gin)
+	}
+	if gin.Evidence == nil || gin.Evidence.Identity.Field != "purl" || gin.Evidence.Occurrences[0].Location != "go.mod" {
+		t.Errorf("gin 的检测证据错误: %+v", gin.Evidence)
+	}
+	babel := components["pkg:npm/%40babel/runtime@7.23.2"]
+	if babel.Group != "@babel" || babel.Name != "runtime" || babel.Version != "7.23.2" || babel.Evidence.Identity.Confidence != 1 {
+		t.Errorf("带作用域的 npm 组件错误: %+v", babel)
+	}
+	if vite := components["pkg:npm/vite@5.0.10"]; vite.Scope != "excluded" {
+		t.Errorf("开发依赖的作用域应为 excluded: %+v", vite)
+	}
+
+	dependsOn := make(map[string][]string)
+	for _, dep := range bom.Dependencies {
+		dependsOn[dep.Ref] = dep.DependsOn
+	}
+	if refs := dependsOn["pkg:npm/react@18.2.0"]; len(refs) != 1 || refs[0] != "pkg:npm/loose-envify@1.4.0" {
+		t.Errorf("锁文件中的依赖关系错误: %v", refs)
+	}
+	roots := dependsOn[cycloneDXRootRef]
+	if !slices.Contains(roots, "pkg:npm/vite@5.0.10") || slices.Contains(roots, "pkg:npm/loose-envify@1.4.0") {
+		t.Errorf("项目的直接依赖错误: %v", roots)
+	}
+	for ref, refs := range dependsOn {
+		for _, target := range refs {
+			if _, ok := components[target]; !ok {
+				t.Errorf("%s 依赖了不存在的组件 %s", ref, target)
+			}
+		}
+	}
+}
+
+func TestCycloneDXMergesDetectedComponents(t *testing.T) {
+	fsys := fstest.MapFS{
+		"pom.xml": {Data: []byte(`<project>
+  <dependencies>
+    <dependency><groupId>com.mchange</groupId><artifactId>c3p0</artifactId><version>0.9.5.5</version></dependency>
+  </dependencies>
+  <build><plugins>
+    <plugin><groupId>org.apache.maven.plugins</groupId><artifactId>maven-compiler-plugin</artifactId><version>3.8.1</version></plugin>
+  </plugins></build>
+</project>`)},
+		"src/main/java/App.java": {Data: []byte("public class App {}\n")},
+	}
+	report, err := AnalyzeFS(fsys, "app", Options{})
+	if err != nil {
+		t.Fatalf("AnalyzeFS 失败: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := WriteCycloneDX(&buf, report, FormatCycloneDXJSON); err != nil {
+		t.Fatalf("WriteCycloneDX 失败: %v", err)
+	}
+	var bom cdxBOM
+	if err := json.Unmarshal(buf.Bytes(), &bom); err != nil {
+		t.Fatalf("输出不是有效的 JSON: %v", err)
+	}
+
+	components := make(map[string]cdxComponent)
+	for _, component := range bom.Components {
+		components[component.BOMRef] = component
+	}
+	if len(components) != 2 {
+		t.Errorf("检测到的组件应合并到对应的依赖包，实际组件: %+v", bom.Components)
+	}
+	c3p0 := components["pkg:maven/com.mchange/c3p0@0.9.5.5"]
+	if c3p0.Scope != "required" || !slices.Contains(c3p0.Properties, cdxProperty{Name: "codecanvas:component", Value: "c3p0"}) {
+		t.Errorf("c3p0 组件错误: %+v", c3p0)
+	}
+	if plugin := components["pkg:maven/org.apache.maven.plugins/maven-compiler-plugin@3.8.1"]; plugin.Scope != "excluded" {
+		t.Errorf("构建依赖的作用域应为 excluded: %+v", plugin)
+	}
+}
+
+func TestWriteCycloneDXXML(t *testing.T) {
+	report, err := AnalyzeFS(sbomTestFS, "app", Options{})
+	if err != nil {
+		t.Fatalf("AnalyzeFS 失败: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := WriteCycloneDX(&buf, report, FormatCycloneDXXML); err != nil {
+		t.Fatalf("WriteCycloneDX 失败: %v", err)
+	}
+	var bom struct {
+		XMLName    xml.Name
+		Components []struct {
+			Type   string `xml:"type,attr"`
+			BOMRef string `xml:"bom-ref,attr"`
+			Purl   string `xml:"purl"`
+		} `xml:"components>component"`
+		Dependencies []struct {
+			Ref       string `xml:"ref,attr"`
+			DependsOn []struct {
+				Ref string `xml:"ref,attr"`
+			} `xml:"dependency"`
+		} `xml:"dependencies>dependency"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &bom); err != nil {
+		t.Fatalf("输出不是有效的 XML: %v", err)
+	}
+	if bom.XMLName.Space != "http://cyclonedx.org/schema/bom/1.5" || bom.XMLName.Local != "bom" {
+		t.Errorf("根元素错误: %+v", bom.XMLName)
+	}
+	if len(bom.Components) != 5 || bom.Components[0].Type != "framework" || bom.Components[0].Purl != bom.Components[0].BOMRef {
+		t.Errorf("组件错误: %+v", bom.Components)
+	}
+	found := false
+	for _, dep := range bom.Dependencies {
+		if dep.Ref == "pkg:npm/react@18.2.0" {
+			found = len(dep.DependsOn) == 1 && dep.DependsOn[0].Ref == "pkg:npm/loose-envify@1.4.0"
+		}
+	}
+	if !found {
+		t.Errorf("XML 中的依赖关系错误: %+v", bom.Dependencies)
+	}
+	if bytes.Contains(buf.Bytes(), []byte("<properties></properties>")) {
+		t.Errorf("空列表不应输出外层元素")
+	}
+	if err := WriteCycloneDX(&buf, report, "spdx"); err == nil {
+		t.Errorf("不支持的格式应返回错误")
+	}
+}
+
+func TestPackageURL(t *testing.T) {
+	tests := []struct {
+		ecosystem, name, version string
+		want                     string
+	}{
+		{model.EcosystemMaven, "org.apache.logging.log4j:log4j-core", "2.17.1", "pkg:maven/org.apache.logging.log4j/log4j-core@2.17.1"},
+		{model.EcosystemGradle, "com.google.guava:guava", "32.1.3-jre", "pkg:maven/com.google.guava/guava@32.1.3-jre"},
+		{model.EcosystemNpm, "@angular/core", "17.0.0", "pkg:npm/%40angular/core@17.0.0"},
+		{model.EcosystemGo, "github.com/docker/docker", "24.0.7+incompatible", "pkg:golang/github.com/docker/docker@v24.0.7%2Bincompatible"},
+		{model.EcosystemPyPI, "Django_Rest", "3.14", "pkg:pypi/django-rest@3.14"},
+		{model.EcosystemComposer, "laravel/framework", "10.48.4", "pkg:composer/laravel/framework@10.48.4"},
+		{model.EcosystemRubyGems, "rails", "", "pkg:gem/rails"},
+		{model.EcosystemDeb, "libssl3", "3.0.11-1~deb12u2", "pkg:deb/debian/libssl3@3.0.11-1~deb12u2"},
+		{model.EcosystemVcpkg, "fmt", "10.1.1", "pkg:generic/fmt@10.1.1"},
+	}
+	for _, tt := range tests {
+		if got := packageURL(tt.ecosystem, tt.name, tt.version); got != tt.want {
+			t.Errorf("packageURL(%s, %s, %s) = %s，期望 %s", tt.ecosystem, tt.name, tt.version, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,329 @@
+package canvas
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/winezer0/codecanvas/internal/model"
+	"github.com/winezer0/codecanvas/internal/version"
+)
+
+// 差异报告的输出格式
+const (
+	DiffFormatText     = "text"
+	DiffFormatJSON     = "json"
+	DiffFormatMarkdown = "markdown"
+)
+
+// DiffReports 比较两份分析报告，返回语言的新增、移除与代码行数变化，以及框架与组件的新增、移除、
+// 版本升降级与分类变化。版本按版本语义比较（1.2 与 1.2.0 视为相同），无法解析的版本按原文比较
+func DiffReports(oldReport, newReport *model.CanvasReport) *model.ReportDiff {
+	diff := &model.ReportDiff{
+		Old:        diffSource(oldReport),
+		New:        diffSource(newReport),
+		TotalLines: lineDelta(codeLines(oldReport), codeLines(newReport)),
+		Languages:  []model.LanguageDiff{},
+	}
+
+	oldLangs := languageInfosToMap(oldReport.CodeProfile.LanguageInfos)
+	newLangs := languageInfosToMap(newReport.CodeProfile.LanguageInfos)
+	for name, lang := range newLangs {
+		before, ok := oldLangs[name]
+		switch {
+		case !ok:
+			diff.Languages = append(diff.Languages, model.LanguageDiff{Name: name, Change: model.DiffAdded, LineDelta: lineDelta(0, lang.CodeLines)})
+		case before.CodeLines != lang.CodeLines:
+			diff.Languages = append(diff.Languages, model.LanguageDiff{Name: name, Change: model.DiffChanged, LineDelta: lineDelta(before.CodeLines, lang.CodeLines)})
+		}
+	}
+	for name, lang := range oldLangs {
+		if _, ok := newLangs[name]; !ok {
+			diff.Languages = append(diff.Languages, model.LanguageDiff{Name: name, Change: model.DiffRemoved, LineDelta: lineDelta(lang.CodeLines, 0)})
+		}
+	}
+	sort.Slice(diff.Languages, func(i, j int) bool { return diff.Languages[i].Name < diff.Languages[j].Name })
+
+	diff.Frameworks = diffItems(oldReport.Detection.Frameworks, newReport.Detection.Frameworks)
+	diff.Components = diffItems(oldReport.Detection.Components, newReport.Detection.Components)
+	return diff
+}
+
+// diffSource 提取报告的来源信息
+func diffSource(report *model.CanvasReport) model.DiffSource {
+	source := model.DiffSource{Path: report.CodeProfile.Path, Timestamp: report.Timestamp}
+	if report.Git != nil {
+		source.Commit = report.Git.Commit
+	}
+	return source
+}
+
+// codeLines 返回报告中全部语言的代码行数之和
+func codeLines(report *model.CanvasReport) int {
+	var lines int
+	for _, lang := range report.CodeProfile.LanguageInfos {
+		lines += lang.CodeLines
+	}
+	return lines
+}
+
+// lineDelta 返回变化前后的代码行数及差值
+func lineDelta(before, after int) model.LineDelta {
+	return model.LineDelta{Old: before, New: after, Delta: after - before}
+}
+
+// diffItems 按名称比较两组检测项，同名检测项只取第一个（版本为空时取后续同名项的版本）
+func diffItems(oldItems, newItems []model.DetectedItem) []model.ItemDiff {
+	before := uniqueItems(oldItems)
+	after := uniqueItems(newItems)
+	diffs := []model.ItemDiff{}
+	for name, item := range after {
+		previous, ok := before[name]
+		if !ok {
+			diffs = append(diffs, model.ItemDiff{
+				Name: item.Name, Type: item.Type, Language: item.Language, Change: model.DiffAdded,
+				NewVersion: item.Version, NewCategory: item.Category,
+			})
+			continue
+		}
+		change := versionChange(previous.Version, item.Version)
+		if change == "" && previous.Category == item.Category {
+			continue
+		}
+		if change == "" {
+			change = model.DiffRecategorized
+		}
+		diffs = append(diffs, model.ItemDiff{
+			Name: item.Name, Type: item.Type, Language: item.Language, Change: change,
+			OldVersion: previous.Version, NewVersion: item.Version,
+			OldCategory: previous.Category, NewCategory: item.Category,
+		})
+	}
+	for name, item := range before {
+		if _, ok := after[name]; !ok {
+			diffs = append(diffs, model.ItemDiff{
+				Name: item.Name, Type: item.Type, Language: item.Language, Change: model.DiffRemoved,
+				OldVersion: item.Version, OldCategory: item.Category,
+			})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Name < diffs[j].Name })
+	return diffs
+}
+
+// uniqueItems 返回名称到检测项的映射
+func uniqueItems(items []model.DetectedItem) map[string]model.DetectedItem {
+	result := make(map[string]model.DetectedItem, len(items))
+	for _, item := range items {
+		existing, ok := result[item.Name]
+		if !ok {
+			result[item.Name] = item
+			continue
+		}
+		if existing.Version == "" && item.Version != "" {
+			existing.Version = item.Version
+			result[item.Name] = existing
+		}
+	}
+	return result
+}
+
+// versionChange 比较两个版本，相同时返回空字符串；任一版本为空或无法解析时返回 "changed"
+func versionChange(before, after string) string {
+	if before == after {
+		return ""
+	}
+	if before == "" || after == "" {
+		return model.DiffChanged
+	}
+	c, err := version.CompareStrings(before, after)
+	switch {
+	case err != nil:
+		return model.DiffChanged
+	case c < 0:
+		return model.DiffUpgraded
+	case c > 0:
+		return model.DiffDowngraded
+	}
+	return ""
+}
+
+// WriteDiff 按指定格式（text、json 或 markdown）写出差异报告
+func WriteDiff(w io.Writer, diff *model.ReportDiff, format string) error {
+	switch format {
+	case DiffFormatText, "":
+		return WriteDiffText(w, diff)
+	case DiffFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(diff)
+	case DiffFormatMarkdown:
+		return WriteDiffMarkdown(w, diff)
+	}
+	return fmt.Errorf("unsupported diff format %q", format)
+}
+
+// WriteDiffText 以纯文本格式写出差异报告：+ 新增，- 移除，^ 升级，v 降级，~ 其他变化
+func WriteDiffText(w io.Writer, diff *model.ReportDiff) error {
+	var b strings.Builder
+	b.WriteString("CodeCanvas Report Diff\n")
+	b.WriteString("=========================\n")
+	fmt.Fprintf(&b, "Old: %s\n", sourceLabel(diff.Old))
+	fmt.Fprintf(&b, "New: %s\n", sourceLabel(diff.New))
+	fmt.Fprintf(&b, "Total Lines: %d -> %d (%s)\n", diff.TotalLines.Old, diff.TotalLines.New, signed(diff.TotalLines.Delta))
+	if diff.Empty() {
+		b.WriteString("\nNo changes.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	if len(diff.Languages) > 0 {
+		b.WriteString("\nLanguages:\n")
+		for _, lang := range diff.Languages {
+			switch lang.Change {
+			case model.DiffAdded:
+				fmt.Fprintf(&b, "+ %s: %d lines\n", lang.Name, lang.New)
+			case model.DiffRemoved:
+				fmt.Fprintf(&b, "- %s: %d lines\n", lang.Name, lang.Old)
+			default:
+				fmt.Fprintf(&b, "~ %s: %d -> %d lines (%s)\n", lang.Name, lang.Old, lang.New, signed(lang.Delta))
+			}
+		}
+	}
+	for _, section := range []struct {
+		title string
+		items []model.ItemDiff
+	}{{"Frameworks", diff.Frameworks}, {"Components", diff.Components}} {
+		if len(section.items) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s:\n", section.title)
+		for _, item := range section.items {
+			fmt.Fprintf(&b, "%s %s (%s)", changeMarks[item.Change], item.Name, item.Language)
+			switch item.Change {
+			case model.DiffAdded:
+				writeOptional(&b, item.NewVersion, item.NewCategory)
+			case model.DiffRemoved:
+				writeOptional(&b, item.OldVersion, item.OldCategory)
+			default:
+				if item.Change != model.DiffRecategorized {
+					fmt.Fprintf(&b, " %s -> %s", versionLabel(item.OldVersion), versionLabel(item.NewVersion))
+				}
+				if item.OldCategory != item.NewCategory {
+					fmt.Fprintf(&b, " [%s -> %s]", item.OldCategory, item.NewCategory)
+				}
+			}
+			b.WriteString("\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// changeMarks 纯文本格式中各变化类型的标记
+var changeMarks = map[string]string{
+	model.DiffAdded:         "+",
+	model.DiffRemoved:       "-",
+	model.DiffUpgraded:      "^",
+	model.DiffDowngraded:    "v",
+	model.DiffChanged:       "~",
+	model.DiffRecategorized: "~",
+}
+
+// writeOptional 写出新增或移除的检测项的版本与分类
+func writeOptional(b *strings.Builder, itemVersion, category string) {
+	if itemVersion != "" {
+		b.WriteString(" " + itemVersion)
+	}
+	if category != "" {
+		b.WriteString(" [" + category + "]")
+	}
+}
+
+// WriteDiffMarkdown 以 Markdown 表格格式写出差异报告，便于贴到合并请求中
+func WriteDiffMarkdown(w io.Writer, diff *model.ReportDiff) error {
+	var b strings.Builder
+	b.WriteString("## CodeCanvas Report Diff\n\n")
+	fmt.Fprintf(&b, "- Old: `%s`\n", sourceLabel(diff.Old))
+	fmt.Fprintf(&b, "- New: `%s`\n", sourceLabel(diff.New))
+	fmt.Fprintf(&b, "- Total lines: %d → %d (%s)\n", diff.TotalLines.Old, diff.TotalLines.New, signed(diff.TotalLines.Delta))
+	if diff.Empty() {
+		b.WriteString("\nNo changes.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	if len(diff.Languages) > 0 {
+		b.WriteString("\n### Languages\n\n")
+		b.WriteString("| Language | Change | Old Lines | New Lines | Delta |\n")
+		b.WriteString("| --- | --- | ---: | ---: | ---: |\n")
+		for _, lang := range diff.Languages {
+			fmt.Fprintf(&b, "| %s | %s | %d | %d | %s |\n", markdownCell(lang.Name), lang.Change, lang.Old, lang.New, signed(lang.Delta))
+		}
+	}
+	for _, section := range []struct {
+		title string
+		items []model.ItemDiff
+	}{{"Frameworks", diff.Frameworks}, {"Components", diff.Components}} {
+		if len(section.items) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n", section.title)
+		b.WriteString("| Name | Language | Change | Old Version | New Version | Category |\n")
+		b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+		for _, item := range section.items {
+			category := item.NewCategory
+			switch item.Change {
+			case model.DiffAdded:
+			case model.DiffRemoved:
+				category = item.OldCategory
+			default:
+				if item.OldCategory != item.NewCategory {
+					category = item.OldCategory + " → " + item.NewCategory
+				}
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+				markdownCell(item.Name), markdownCell(item.Language), item.Change,
+				markdownCell(item.OldVersion), markdownCell(item.NewVersion), markdownCell(category))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// sourceLabel 返回报告来源的描述：路径、提交哈希与生成时间
+func sourceLabel(source model.DiffSource) string {
+	label := source.Path
+	if source.Commit != "" {
+		label += "@" + source.Commit
+	}
+	if !source.Timestamp.IsZero() {
+		label += " (" + source.Timestamp.Format(time.RFC3339) + ")"
+	}
+	return label
+}
+
+// versionLabel 返回版本描述，版本为空时返回 "-"
+func versionLabel(v string) string {
+	if v == "" {
+		return "-"
+	}
+	return v
+}
+
+// signed 返回带符号的整数
+func signed(n int) string {
+	if n > 0 {
+		return fmt.Sprintf("+%d", n)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+// markdownCell 转义表格单元格中的竖线与换行
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
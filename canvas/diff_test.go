@@ -0,0 +1,131 @@
+package canvas
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestDiffReports(t *testing.T) {
+	oldReport := &model.CanvasReport{
+		CodeProfile: model.CodeProfile{Path: "app", LanguageInfos: []model.LangInfo{
+			{Name: "Go", CodeLines: 100},
+			{Name: "Python", CodeLines: 30},
+			{Name: "YAML", CodeLines: 5},
+		}},
+		Detection: model.DetectionInfo{
+			Frameworks: []model.DetectedItem{
+				{Name: "Gin", Type: model.RuleTypeFramework, Language: "Go", Version: "1.9.0", Category: model.CategoryBackend},
+				{Name: "Flask", Type: model.RuleTypeFramework, Language: "Python", Version: "2.3.0", Category: model.CategoryBackend},
+				{Name: "Wails", Type: model.RuleTypeFramework, Language: "Go", Version: "2.8", Category: model.CategoryBackend},
+			},
+			Components: []model.DetectedItem{
+				{Name: "gorm", Type: model.RuleTypeComponent, Language: "Go", Version: "1.25.0", Category: model.CategoryBackend},
+				{Name: "viper", Type: model.RuleTypeComponent, Language: "Go", Version: "1.18.0-rc.1", Category: model.CategoryBackend},
+				{Name: "zap", Type: model.RuleTypeComponent, Language: "Go", Version: "${zap.version}", Category: model.CategoryBackend},
+			},
+		},
+	}
+	newReport := &model.CanvasReport{
+		CodeProfile: model.CodeProfile{Path: "app", LanguageInfos: []model.LangInfo{
+			{Name: "Go", CodeLines: 120},
+			{Name: "Rust", CodeLines: 40},
+			{Name: "YAML", CodeLines: 5},
+		}},
+		Git: &model.GitInfo{Commit: "abc123"},
+		Detection: model.DetectionInfo{
+			Frameworks: []model.DetectedItem{
+				{Name: "Gin", Type: model.RuleTypeFramework, Language: "Go", Version: "1.10.0", Category: model.CategoryBackend},
+				{Name: "Axum", Type: model.RuleTypeFramework, Language: "Rust", Version: "0.7.4", Category: model.CategoryBackend},
+				// 1.2 与 1.2.0 视为相同版本，只有分类变化
+				{Name: "Wails", Type: model.RuleTypeFramework, Language: "Go", Version: "2.8.0", Category: model.CategoryDesktop},
+			},
+			Components: []model.DetectedItem{
+				{Name: "gorm", Type: model.RuleTypeComponent, Language: "Go", Version: "1.24.6", Category: model.CategoryBackend},
+				{Name: "viper", Type: model.RuleTypeComponent, Language: "Go", Version: "1.18.0", Category: model.CategoryBackend},
+				{Name: "zap", Type: model.RuleTypeComponent, Language: "Go", Version: "1.27.0", Category: model.CategoryBackend},
+			},
+		},
+	}
+
+	diff := DiffReports(oldReport, newReport)
+	if diff.TotalLines != (model.LineDelta{Old: 135, New: 165, Delta: 30}) {
+		t.Errorf("总代码行数变化错误: %+v", diff.TotalLines)
+	}
+	if diff.New.Commit != "abc123" {
+		t.Errorf("新报告的提交哈希错误: %+v", diff.New)
+	}
+
+	wantLanguages := []model.LanguageDiff{
+		{Name: "Go", Change: model.DiffChanged, LineDelta: model.LineDelta{Old: 100, New: 120, Delta: 20}},
+		{Name: "Python", Change: model.DiffRemoved, LineDelta: model.LineDelta{Old: 30, New: 0, Delta: -30}},
+		{Name: "Rust", Change: model.DiffAdded, LineDelta: model.LineDelta{Old: 0, New: 40, Delta: 40}},
+	}
+	if len(diff.Languages) != len(wantLanguages) {
+		t.Fatalf("语言变化应为 %+v，实际为 %+v", wantLanguages, diff.Languages)
+	}
+	for i, want := range wantLanguages {
+		if diff.Languages[i] != want {
+			t.Errorf("语言变化 %d 应为 %+v，实际为 %+v", i, want, diff.Languages[i])
+		}
+	}
+
+	changes := make(map[string]model.ItemDiff)
+	for _, item := range append(diff.Frameworks, diff.Components...) {
+		changes[item.Name] = item
+	}
+	wantChanges := map[string]string{
+		"Axum":  model.DiffAdded,
+		"Flask": model.DiffRemoved,
+		"Gin":   model.DiffUpgraded,
+		"Wails": model.DiffRecategorized,
+		"gorm":  model.DiffDowngraded,
+		"viper": model.DiffUpgraded,
+		"zap":   model.DiffChanged,
+	}
+	if len(changes) != len(wantChanges) {
+		t.Errorf("检测项变化应为 %v，实际为 %+v", wantChanges, changes)
+	}
+	for name, want := range wantChanges {
+		if changes[name].Change != want {
+			t.Errorf("%s 的变化应为 %s，实际为 %+v", name, want, changes[name])
+		}
+	}
+	if wails := changes["Wails"]; wails.OldCategory != model.CategoryBackend || wails.NewCategory != model.CategoryDesktop {
+		t.Errorf("Wails 的分类变化错误: %+v", wails)
+	}
+
+	var text, markdown, encoded bytes.Buffer
+	for _, output := range []struct {
+		format string
+		buf    *bytes.Buffer
+	}{{DiffFormatText, &text}, {DiffFormatMarkdown, &markdown}, {DiffFormatJSON, &encoded}} {
+		if err := WriteDiff(output.buf, diff, output.format); err != nil {
+			t.Fatalf("WriteDiff(%s) 失败: %v", output.format, err)
+		}
+	}
+	for _, want := range []string{"~ Go: 100 -> 120 lines (+20)", "^ Gin (Go) 1.9.0 -> 1.10.0", "~ Wails (Go) [backend -> desktop]", "- Flask (Python) 2.3.0 [backend]"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("文本输出缺少 %q:\n%s", want, text.String())
+		}
+	}
+	for _, want := range []string{"| Rust | added | 0 | 40 | +40 |", "| gorm | Go | downgraded | 1.25.0 | 1.24.6 | backend |", "| Wails | Go | recategorized | 2.8 | 2.8.0 | backend → desktop |"} {
+		if !strings.Contains(markdown.String(), want) {
+			t.Errorf("Markdown 输出缺少 %q:\n%s", want, markdown.String())
+		}
+	}
+	var decoded model.ReportDiff
+	if err := json.Unmarshal(encoded.Bytes(), &decoded); err != nil || len(decoded.Components) != 3 {
+		t.Errorf("JSON 输出错误: %v\n%s", err, encoded.String())
+	}
+	if err := WriteDiff(&text, diff, "xml"); err == nil {
+		t.Errorf("不支持的格式应返回错误")
+	}
+
+	if empty := DiffReports(oldReport, oldReport); !empty.Empty() {
+		t.Errorf("相同报告不应有差异: %+v", empty)
+	}
+}
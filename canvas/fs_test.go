@@ -0,0 +1,62 @@
+package canvas
+
+import (
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAnalyzeFS(t *testing.T) {
+	jar := writeTestZip(t,
+		"META-INF/maven/com.alibaba/fastjson/pom.properties", "groupId=com.alibaba\nartifactId=fastjson\nversion=1.2.83\n",
+	)
+	fsys := fstest.MapFS{
+		"go.work":            {Data: []byte("go 1.22\n\nuse (\n\t./api\n\t./admin\n)\n")},
+		"api/go.mod":         {Data: []byte("module example.com/api\n\ngo 1.22\n\nrequire github.com/gin-gonic/gin v1.9.1\n")},
+		"api/main.go":        {Data: []byte("package main\n\nimport \"github.com/gin-gonic/gin\"\n\nfunc main() { gin.Default() }\n")},
+		"admin/go.mod":       {Data: []byte("module example.com/admin\n\ngo 1.22\n\nrequire github.com/labstack/echo/v4 v4.11.4\n")},
+		"admin/main.go":      {Data: []byte("package main\n\nimport \"github.com/labstack/echo/v4\"\n\nfunc main() { echo.New() }\n")},
+		"lib/json-lib.jar":   {Data: jar},
+		"lib/Main.java":      {Data: []byte("class Main {}\n")},
+		".git/hooks/hook.go": {Data: []byte("package hooks\n")},
+	}
+
+	report, err := AnalyzeFS(fsys, "memfs", Options{ArchiveDepth: 1})
+	if err != nil {
+		t.Fatalf("AnalyzeFS 失败: %v", err)
+	}
+	if report.CodeProfile.Path != "memfs" {
+		t.Errorf("代码画像的根路径应为 memfs，实际为 %s", report.CodeProfile.Path)
+	}
+	// 隐藏目录中的文件不参与统计
+	if report.CodeProfile.TotalFiles != 3 {
+		t.Errorf("源码文件数应为 3，实际为 %d", report.CodeProfile.TotalFiles)
+	}
+
+	versions := make(map[string]string)
+	for _, item := range append(report.Detection.Frameworks, report.Detection.Components...) {
+		versions[item.Name] = item.Version
+	}
+	if versions["Gin"] != "1.9.1" {
+		t.Errorf("Gin 版本应为 1.9.1，实际为 %q", versions["Gin"])
+	}
+	// 压缩包成员同样从文件系统中读取
+	if versions["fastjson"] != "1.2.83" {
+		t.Errorf("fastjson 版本应为 1.2.83，实际为 %q", versions["fastjson"])
+	}
+
+	// 子项目在文件系统的子树上分析
+	if len(report.Projects) != 1 || len(report.Projects[0].Children) != 2 {
+		t.Fatalf("项目树错误: %+v", report.Projects)
+	}
+	for _, child := range report.Projects[0].Children {
+		var names []string
+		for _, fw := range child.Detection.Frameworks {
+			names = append(names, fw.Name)
+		}
+		want := map[string]string{"admin": "Echo", "api": "Gin"}[child.Path]
+		if !slices.Contains(names, want) {
+			t.Errorf("%s 项目应识别 %s，实际为 %v", child.Path, want, names)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package canvas
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// LoadReports 读取多个 JSON 格式的分析报告
+func LoadReports(paths []string) ([]*model.CanvasReport, error) {
+	var reports []*model.CanvasReport
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading report %s: %v", path, err)
+		}
+		var report model.CanvasReport
+		if err := json.Unmarshal(content, &report); err != nil {
+			return nil, fmt.Errorf("error parsing report %s: %v", path, err)
+		}
+		reports = append(reports, &report)
+	}
+	return reports, nil
+}
+
+// RuleGaps 汇总多份报告中未被规则识别的依赖，按出现的报告数量降序排列，
+// 数量相同时按生态与名称排序。同一报告中的依赖只计数一次，名称不区分大小写
+func RuleGaps(reports []*model.CanvasReport) []model.DependencyGap {
+	gaps := make(map[string]*model.DependencyGap)
+	for _, report := range reports {
+		counted := make(map[string]bool)
+		for ecosystem, names := range report.UnclassifiedDependencies {
+			for _, name := range names {
+				key := ecosystem + ":" + strings.ToLower(name)
+				if counted[key] {
+					continue
+				}
+				counted[key] = true
+				gap, ok := gaps[key]
+				if !ok {
+					gap = &model.DependencyGap{Ecosystem: ecosystem, Name: name}
+					gaps[key] = gap
+				}
+				gap.Count++
+				gap.Projects = append(gap.Projects, report.CodeProfile.Path)
+			}
+		}
+	}
+
+	result := make([]model.DependencyGap, 0, len(gaps))
+	for _, gap := range gaps {
+		result = append(result, *gap)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		if result[i].Ecosystem != result[j].Ecosystem {
+			return result[i].Ecosystem < result[j].Ecosystem
+		}
+		return strings.ToLower(result[i].Name) < strings.ToLower(result[j].Name)
+	})
+	return result
+}
+
+// PrintRuleGaps 输出未被规则识别的依赖排名，top 大于 0 时只输出前 top 个
+func PrintRuleGaps(gaps []model.DependencyGap, reports int, top int) {
+	fmt.Printf("Unclassified Dependencies (%d reports):\n", reports)
+	if top > 0 && len(gaps) > top {
+		gaps = gaps[:top]
+	}
+	for i, gap := range gaps {
+		fmt.Printf("%3d. [%s] %s: %d\n", i+1, gap.Ecosystem, gap.Name, gap.Count)
+	}
+	fmt.Println()
+}
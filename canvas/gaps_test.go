@@ -0,0 +1,58 @@
+package canvas
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
+	"github.com/winezer0/codecanvas/internal/utils"
+)
+
+func TestRuleGaps(t *testing.T) {
+	tmpDir := t.TempDir()
+	reports := []*model.CanvasReport{
+		{
+			CodeProfile: model.CodeProfile{Path: "a"},
+			UnclassifiedDependencies: map[string][]string{
+				"npm": {"left-pad", "Left-Pad"},
+				"go":  {"github.com/foo/bar"},
+			},
+		},
+		{
+			CodeProfile:              model.CodeProfile{Path: "b"},
+			UnclassifiedDependencies: map[string][]string{"npm": {"left-pad", "is-odd"}},
+		},
+		{CodeProfile: model.CodeProfile{Path: "c"}},
+	}
+	var paths []string
+	for i, report := range reports {
+		path := filepath.Join(tmpDir, string(rune('a'+i))+".json")
+		if err := utils.WriteJSON(path, report); err != nil {
+			t.Fatalf("无法写入报告: %v", err)
+		}
+		paths = append(paths, path)
+	}
+
+	loaded, err := LoadReports(paths)
+	if err != nil {
+		t.Fatalf("LoadReports 失败: %v", err)
+	}
+	gaps := RuleGaps(loaded)
+	if len(gaps) != 3 {
+		t.Fatalf("期望 3 个未识别依赖, 实际 %d: %+v", len(gaps), gaps)
+	}
+	if gaps[0].Name != "left-pad" || gaps[0].Count != 2 || len(gaps[0].Projects) != 2 {
+		t.Errorf("出现次数最多的依赖应为 left-pad: %+v", gaps[0])
+	}
+	if gaps[1].Ecosystem != "go" || gaps[2].Name != "is-odd" {
+		t.Errorf("相同次数时应按生态与名称排序: %+v", gaps[1:])
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "bad.json"), []byte("{"), 0644); err != nil {
+		t.Fatalf("无法写入文件: %v", err)
+	}
+	if _, err := LoadReports([]string{filepath.Join(tmpDir, "bad.json")}); err == nil {
+		t.Errorf("无效的报告应返回错误")
+	}
+}
@@ -0,0 +1,42 @@
+package canvas
+
+import (
+	"fmt"
+
+	"github.com/winezer0/codecanvas/internal/gitfs"
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// AnalyzeGit 直接从本地 git 仓库的对象库（松散对象与 packfile）读取修订对应的文件树并分析，不需要检出工作区。
+// rev 可以是分支、标签、完整引用名称或（缩写的）提交哈希，为空时使用 HEAD；报告中记录提交哈希、提交时间与引用
+func AnalyzeGit(repoPath, rev string, opts Options) (*model.CanvasReport, error) {
+	repo, err := gitfs.Open(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	defer repo.Close()
+
+	hash, ref, err := repo.ResolveRevision(rev)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := repo.Commit(hash)
+	if err != nil {
+		return nil, fmt.Errorf("error reading commit %s: %v", hash, err)
+	}
+	report, err := AnalyzeFS(repo.TreeFS(commit), repo.Path, opts)
+	if err != nil {
+		return nil, err
+	}
+	if rev == "" {
+		rev = "HEAD"
+	}
+	report.Git = &model.GitInfo{
+		Repository: repo.Path,
+		Revision:   rev,
+		Ref:        ref,
+		Commit:     commit.Hash,
+		Date:       commit.Date,
+	}
+	return report, nil
+}
@@ -0,0 +1,88 @@
+package canvas
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// newTestGitRepo 创建空的 git 仓库，返回仓库目录、执行 git 命令与写入工作区文件的函数；git 不可用时跳过测试
+func newTestGitRepo(t *testing.T) (string, func(args ...string) string, func(name, content string)) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git 不可用")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=tester", "GIT_AUTHOR_EMAIL=tester@example.com",
+			"GIT_COMMITTER_NAME=tester", "GIT_COMMITTER_EMAIL=tester@example.com",
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1",
+		)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s 失败: %v\n%s", strings.Join(args, " "), err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	write := func(name, content string) {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("无法创建目录: %v", err)
+		}
+		if content == "" {
+			os.Remove(path)
+			return
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("无法写入 %s: %v", name, err)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	return dir, git, write
+}
+
+func TestAnalyzeGit(t *testing.T) {
+	dir, git, write := newTestGitRepo(t)
+	write("go.mod", "module example.com/app\n\ngo 1.22\n\nrequire github.com/gin-gonic/gin v1.9.0\n")
+	write("main.go", "package main\n\nimport \"github.com/gin-gonic/gin\"\n\nfunc main() { gin.Default() }\n")
+	git("add", "-A")
+	git("commit", "-q", "-m", "release")
+	git("tag", "v1.0.0")
+	release := git("rev-parse", "HEAD")
+	write("go.mod", "module example.com/app\n\ngo 1.22\n\nrequire github.com/gin-gonic/gin v1.9.1\n")
+	git("commit", "-q", "-am", "upgrade gin")
+	// 工作区中未提交的修改不影响分析结果
+	write("go.mod", "module example.com/app\n")
+
+	ginVersion := func(rev string) (string, *model.GitInfo) {
+		report, err := AnalyzeGit(dir, rev, Options{})
+		if err != nil {
+			t.Fatalf("AnalyzeGit(%q) 失败: %v", rev, err)
+		}
+		for _, fw := range report.Detection.Frameworks {
+			if fw.Name == "Gin" {
+				return fw.Version, report.Git
+			}
+		}
+		return "", report.Git
+	}
+
+	version, info := ginVersion("v1.0.0")
+	if version != "1.9.0" {
+		t.Errorf("v1.0.0 中的 Gin 版本应为 1.9.0，实际为 %q", version)
+	}
+	if info == nil || info.Commit != release || info.Ref != "refs/tags/v1.0.0" || info.Revision != "v1.0.0" || info.Date.IsZero() {
+		t.Errorf("提交信息错误: %+v", info)
+	}
+	version, info = ginVersion("")
+	if version != "1.9.1" || info.Ref != "refs/heads/main" || info.Revision != "HEAD" {
+		t.Errorf("HEAD 的分析结果错误: %s %+v", version, info)
+	}
+}
@@ -0,0 +1,314 @@
+package canvas
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/winezer0/codecanvas/internal/analyzer"
+	"github.com/winezer0/codecanvas/internal/frameengine"
+	"github.com/winezer0/codecanvas/internal/gitfs"
+	"github.com/winezer0/codecanvas/internal/logging"
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// 历史采样方式
+const (
+	HistoryModeEvery = "every"
+	HistoryModeTags  = "tags"
+)
+
+// HistoryOptions 历史分析选项
+// - Rev: 按间隔采样时的起点修订，为空时使用 HEAD
+// - Every: 沿首个父提交每隔 N 个提交采样一次，起点提交总是被采样，小于 1 时按 1 处理
+// - Tags: 采样全部带标签的提交，此时忽略 Rev 与 Every
+// - MaxSamples: 最多采样的提交数，超出时保留最新的提交，0 表示不限制
+type HistoryOptions struct {
+	Options
+	Rev        string
+	Every      int
+	Tags       bool
+	MaxSamples int
+}
+
+// historyCommit 待分析的采样提交及指向它的标签
+type historyCommit struct {
+	commit *gitfs.Commit
+	refs   []string
+}
+
+// AnalyzeHistory 沿 git 历史采样多个提交并逐个分析，生成框架与组件出现、版本变化与消失的时间线以及各语言行数的变化。
+// 各提交直接从对象库读取，内容未变化的文件按 blob 哈希复用行数统计结果
+func AnalyzeHistory(repoPath string, opts HistoryOptions) (*model.HistoryReport, error) {
+	repo, err := gitfs.Open(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	defer repo.Close()
+
+	tags, err := repo.Tags()
+	if err != nil {
+		return nil, err
+	}
+	mode := HistoryModeEvery
+	var commits []historyCommit
+	if opts.Tags {
+		mode = HistoryModeTags
+		commits, err = taggedCommits(repo, tags)
+	} else {
+		commits, err = sampledCommits(repo, opts.Rev, opts.Every, tags)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if opts.MaxSamples > 0 && len(commits) > opts.MaxSamples {
+		commits = commits[len(commits)-opts.MaxSamples:]
+	}
+	if len(commits) == 0 {
+		return nil, errors.New("no commits to analyze")
+	}
+
+	detectEngine, err := frameengine.NewCanvasEngine(opts.RulesDir)
+	if err != nil {
+		return nil, fmt.Errorf("error loading rules: %v", err)
+	}
+	az := analyzer.NewCodeAnalyzer()
+	az.ArchiveDepth = opts.ArchiveDepth
+	az.Cache = analyzer.NewFileCache()
+
+	report := &model.HistoryReport{Repository: repo.Path, Mode: mode, Timestamp: time.Now()}
+	for i, sample := range commits {
+		logging.Infof("analyzing commit %s (%d/%d)", sample.commit.Hash, i+1, len(commits))
+		result, err := analyzeFS(az, detectEngine, repo.TreeFS(sample.commit), repo.Path)
+		if err != nil {
+			return nil, fmt.Errorf("error analyzing commit %s: %v", sample.commit.Hash, err)
+		}
+		report.Samples = append(report.Samples, historySample(sample, result))
+	}
+	report.Events = buildTimeline(report.Samples)
+	report.ReusedFiles, report.AnalyzedFiles = az.Cache.Counts()
+	return report, nil
+}
+
+// taggedCommits 返回全部带标签的提交，按提交时间从早到晚排列，指向同一提交的多个标签合并
+func taggedCommits(repo *gitfs.Repository, tags map[string]string) ([]historyCommit, error) {
+	byCommit := make(map[string][]string)
+	for name, hash := range tags {
+		byCommit[hash] = append(byCommit[hash], name)
+	}
+	var commits []historyCommit
+	for hash, refs := range byCommit {
+		commit, err := repo.Commit(hash)
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(refs)
+		commits = append(commits, historyCommit{commit: commit, refs: refs})
+	}
+	sort.Slice(commits, func(i, j int) bool {
+		if !commits[i].commit.Date.Equal(commits[j].commit.Date) {
+			return commits[i].commit.Date.Before(commits[j].commit.Date)
+		}
+		return commits[i].commit.Hash < commits[j].commit.Hash
+	})
+	return commits, nil
+}
+
+// sampledCommits 从 rev 开始沿首个父提交每隔 every 个提交采样一次，按提交时间从早到晚排列
+func sampledCommits(repo *gitfs.Repository, rev string, every int, tags map[string]string) ([]historyCommit, error) {
+	hash, _, err := repo.ResolveRevision(rev)
+	if err != nil {
+		return nil, err
+	}
+	chain, err := repo.FirstParents(hash, 0)
+	if err != nil {
+		return nil, err
+	}
+	every = max(every, 1)
+	refsByCommit := make(map[string][]string)
+	for name, target := range tags {
+		refsByCommit[target] = append(refsByCommit[target], name)
+	}
+	var commits []historyCommit
+	for i := 0; i < len(chain); i += every {
+		refs := refsByCommit[chain[i].Hash]
+		sort.Strings(refs)
+		commits = append(commits, historyCommit{commit: chain[i], refs: refs})
+	}
+	slices.Reverse(commits)
+	return commits, nil
+}
+
+// historySample 提取单个提交分析结果中的语言统计与框架、组件，同类型同名称的条目只保留一个
+func historySample(sample historyCommit, report *model.CanvasReport) model.HistorySample {
+	languages := slices.Clone(report.CodeProfile.LanguageInfos)
+	sort.Slice(languages, func(i, j int) bool { return languages[i].Name < languages[j].Name })
+
+	seen := make(map[string]int)
+	var items []model.HistoryItem
+	for _, detected := range append(slices.Clone(report.Detection.Frameworks), report.Detection.Components...) {
+		key := detected.Type + "\x00" + detected.Name
+		if i, ok := seen[key]; ok {
+			if items[i].Version == "" {
+				items[i].Version = detected.Version
+			}
+			continue
+		}
+		seen[key] = len(items)
+		items = append(items, model.HistoryItem{Type: detected.Type, Name: detected.Name, Version: detected.Version})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Type != items[j].Type {
+			return items[i].Type < items[j].Type
+		}
+		return items[i].Name < items[j].Name
+	})
+
+	return model.HistorySample{
+		Commit:    sample.commit.Hash,
+		Date:      sample.commit.Date,
+		Refs:      sample.refs,
+		Subject:   sample.commit.Subject(),
+		Languages: languages,
+		Items:     items,
+	}
+}
+
+// buildTimeline 比较相邻的采样提交，生成框架与组件出现、版本变化与消失的事件；第一个采样中的条目均视为出现
+func buildTimeline(samples []model.HistorySample) []model.TimelineEvent {
+	var events []model.TimelineEvent
+	previous := make(map[string]model.HistoryItem)
+	for _, sample := range samples {
+		newEvent := func(kind string, item model.HistoryItem) model.TimelineEvent {
+			return model.TimelineEvent{Commit: sample.Commit, Date: sample.Date, Refs: sample.Refs, Kind: kind, Type: item.Type, Name: item.Name}
+		}
+		current := make(map[string]model.HistoryItem, len(sample.Items))
+		for _, item := range sample.Items {
+			key := item.Type + "\x00" + item.Name
+			current[key] = item
+			before, ok := previous[key]
+			switch {
+			case !ok:
+				event := newEvent(model.TimelineAdded, item)
+				event.To = item.Version
+				events = append(events, event)
+			case before.Version != item.Version:
+				event := newEvent(model.TimelineChanged, item)
+				event.From, event.To = before.Version, item.Version
+				events = append(events, event)
+			}
+		}
+		var removed []model.HistoryItem
+		for key, item := range previous {
+			if _, ok := current[key]; !ok {
+				removed = append(removed, item)
+			}
+		}
+		sort.Slice(removed, func(i, j int) bool {
+			if removed[i].Type != removed[j].Type {
+				return removed[i].Type < removed[j].Type
+			}
+			return removed[i].Name < removed[j].Name
+		})
+		for _, item := range removed {
+			event := newEvent(model.TimelineRemoved, item)
+			event.From = item.Version
+			events = append(events, event)
+		}
+		previous = current
+	}
+	return events
+}
+
+// WriteHistoryCSV 以 CSV 格式写出时间线：每个采样提交先输出各语言的代码行数（record 为 lines，
+// from/to 为上一个采样与当前采样的代码行数），再输出框架与组件的 added/changed/removed 事件
+func WriteHistoryCSV(w io.Writer, report *model.HistoryReport) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"commit", "date", "refs", "record", "type", "name", "from", "to"}); err != nil {
+		return err
+	}
+	eventsByCommit := make(map[string][]model.TimelineEvent)
+	for _, event := range report.Events {
+		eventsByCommit[event.Commit] = append(eventsByCommit[event.Commit], event)
+	}
+
+	previous := make(map[string]int)
+	for _, sample := range report.Samples {
+		prefix := []string{sample.Commit, sample.Date.Format(time.RFC3339), strings.Join(sample.Refs, " ")}
+		current := make(map[string]int, len(sample.Languages))
+		names := make([]string, 0, len(sample.Languages))
+		for _, lang := range sample.Languages {
+			current[lang.Name] = lang.CodeLines
+			names = append(names, lang.Name)
+		}
+		// 上一个采样中存在而当前消失的语言以 0 行输出
+		for name := range previous {
+			if _, ok := current[name]; !ok {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			from := ""
+			if lines, ok := previous[name]; ok {
+				from = strconv.Itoa(lines)
+			}
+			row := append(slices.Clone(prefix), "lines", "language", name, from, strconv.Itoa(current[name]))
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+		for _, event := range eventsByCommit[sample.Commit] {
+			row := append(slices.Clone(prefix), event.Kind, event.Type, event.Name, event.From, event.To)
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+		previous = current
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// PrintHistory 在命令行输出时间线摘要
+func PrintHistory(report *model.HistoryReport) {
+	fmt.Println("\nCodeCanvas History")
+	fmt.Println("=========================")
+	fmt.Printf("Repository: %s\n", report.Repository)
+	fmt.Printf("Samples: %d (%s), reused files: %d, analyzed files: %d\n", len(report.Samples), report.Mode, report.ReusedFiles, report.AnalyzedFiles)
+
+	eventsByCommit := make(map[string][]model.TimelineEvent)
+	for _, event := range report.Events {
+		eventsByCommit[event.Commit] = append(eventsByCommit[event.Commit], event)
+	}
+	for _, sample := range report.Samples {
+		var lines int
+		for _, lang := range sample.Languages {
+			lines += lang.CodeLines
+		}
+		label := sample.Commit
+		if len(label) > 12 {
+			label = label[:12]
+		}
+		if len(sample.Refs) > 0 {
+			label += " (" + strings.Join(sample.Refs, ", ") + ")"
+		}
+		fmt.Printf("\n%s %s - %d code lines\n", sample.Date.Format("2006-01-02"), label, lines)
+		for _, event := range eventsByCommit[sample.Commit] {
+			switch event.Kind {
+			case model.TimelineAdded:
+				fmt.Printf("  + %s %s\n", event.Name, event.To)
+			case model.TimelineRemoved:
+				fmt.Printf("  - %s %s\n", event.Name, event.From)
+			case model.TimelineChanged:
+				fmt.Printf("  ~ %s %s -> %s\n", event.Name, event.From, event.To)
+			}
+		}
+	}
+}
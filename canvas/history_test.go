@@ -0,0 +1,96 @@
+package canvas
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestAnalyzeHistory(t *testing.T) {
+	dir, git, write := newTestGitRepo(t)
+	commit := func(date, message string) string {
+		t.Setenv("GIT_COMMITTER_DATE", date)
+		t.Setenv("GIT_AUTHOR_DATE", date)
+		git("add", "-A")
+		git("commit", "-q", "-m", message)
+		return git("rev-parse", "HEAD")
+	}
+
+	write("go.mod", "module example.com/app\n\ngo 1.22\n\nrequire github.com/gin-gonic/gin v1.9.0\n")
+	write("main.go", "package main\n\nimport \"github.com/gin-gonic/gin\"\n\nfunc main() { gin.Default() }\n")
+	first := commit("2024-01-01T10:00:00Z", "init")
+	git("tag", "v1.0.0")
+
+	write("go.mod", "module example.com/app\n\ngo 1.22\n\nrequire (\n\tgithub.com/gin-gonic/gin v1.9.1\n\tgithub.com/labstack/echo/v4 v4.11.0\n)\n")
+	write("admin.go", "package main\n\nimport \"github.com/labstack/echo/v4\"\n\nfunc admin() { echo.New() }\n")
+	commit("2024-02-01T10:00:00Z", "add admin")
+	git("tag", "v1.1.0")
+
+	write("go.mod", "module example.com/app\n\ngo 1.22\n\nrequire github.com/gin-gonic/gin v1.9.1\n")
+	write("admin.go", "")
+	commit("2024-03-01T10:00:00Z", "drop admin")
+	git("tag", "v1.2.0")
+	write("README.md", "# app\n")
+	commit("2024-04-01T10:00:00Z", "docs")
+
+	report, err := AnalyzeHistory(dir, HistoryOptions{Tags: true})
+	if err != nil {
+		t.Fatalf("AnalyzeHistory 失败: %v", err)
+	}
+	if report.Mode != HistoryModeTags || len(report.Samples) != 3 {
+		t.Fatalf("应按标签采样 3 个提交，实际为 %s %d", report.Mode, len(report.Samples))
+	}
+	if report.Samples[0].Commit != first || strings.Join(report.Samples[0].Refs, ",") != "v1.0.0" {
+		t.Errorf("第一个采样应为 v1.0.0，实际为 %s %v", report.Samples[0].Commit, report.Samples[0].Refs)
+	}
+	// main.go 在三个提交中内容不变，应复用统计结果
+	if report.ReusedFiles < 2 {
+		t.Errorf("未变化的文件应被复用，实际复用 %d 个", report.ReusedFiles)
+	}
+
+	events := make(map[string]model.TimelineEvent)
+	for _, event := range report.Events {
+		events[event.Kind+" "+event.Name] = event
+	}
+	checks := []struct {
+		key, sample, from, to string
+	}{
+		{"added Gin", report.Samples[0].Commit, "", "1.9.0"},
+		{"changed Gin", report.Samples[1].Commit, "1.9.0", "1.9.1"},
+		{"added Echo", report.Samples[1].Commit, "", "4.11.0"},
+		{"removed Echo", report.Samples[2].Commit, "4.11.0", ""},
+	}
+	for _, tt := range checks {
+		event, ok := events[tt.key]
+		if !ok {
+			t.Errorf("缺少事件 %s，实际事件: %+v", tt.key, report.Events)
+			continue
+		}
+		if event.Commit != tt.sample || event.From != tt.from || event.To != tt.to {
+			t.Errorf("事件 %s 错误: %+v", tt.key, event)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteHistoryCSV(&buf, report); err != nil {
+		t.Fatalf("WriteHistoryCSV 失败: %v", err)
+	}
+	csvText := buf.String()
+	if !strings.HasPrefix(csvText, "commit,date,refs,record,type,name,from,to\n") {
+		t.Errorf("CSV 表头错误: %q", csvText)
+	}
+	if !strings.Contains(csvText, ",v1.0.0,lines,language,Go,,") || !strings.Contains(csvText, ",v1.2.0,removed,") {
+		t.Errorf("CSV 缺少语言行数或移除事件:\n%s", csvText)
+	}
+
+	// 按间隔采样：从 HEAD 起每隔 2 个提交采样，得到第二个与第四个提交
+	report, err = AnalyzeHistory(dir, HistoryOptions{Every: 2})
+	if err != nil {
+		t.Fatalf("AnalyzeHistory 失败: %v", err)
+	}
+	if report.Mode != HistoryModeEvery || len(report.Samples) != 2 || report.Samples[0].Subject != "add admin" || report.Samples[1].Subject != "docs" {
+		t.Errorf("按间隔采样结果错误: %+v", report.Samples)
+	}
+}
@@ -0,0 +1,350 @@
+package canvas
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// htmlChartColors 图表使用的颜色，超出数量时循环使用
+var htmlChartColors = []string{"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f", "#edc948", "#b07aa1", "#ff9da7", "#9c755f", "#bab0ac"}
+
+// htmlDonutLimit 环形图中单独显示的语言数量，其余语言合并为 Others
+const htmlDonutLimit = 8
+
+// htmlPage HTML 报告模板的数据
+type htmlPage struct {
+	Report       *model.CanvasReport
+	Title        string
+	Generated    string
+	Segments     []htmlSegment
+	Languages    []htmlLanguage
+	Categories   []htmlBar
+	Ecosystems   []htmlBar
+	Sections     []htmlSection
+	Dependencies int
+}
+
+// htmlSegment 环形图的一段，Dash 与 Offset 为周长 100 的圆上的 stroke-dasharray 与 stroke-dashoffset
+type htmlSegment struct {
+	Name    string
+	Lines   int
+	Percent string
+	Color   string
+	Dash    string
+	Offset  string
+}
+
+type htmlLanguage struct {
+	model.LangInfo
+	Category string
+}
+
+// htmlBar 条形图的一行，Width 为相对最大值的百分比
+type htmlBar struct {
+	Label string
+	Value int
+	Extra string
+	Width string
+	Color string
+}
+
+type htmlSection struct {
+	Title string
+	Items []htmlItem
+}
+
+type htmlItem struct {
+	model.DetectedItem
+	Locations []string
+	Vulns     []string
+}
+
+// WriteHTML 写出单个自包含的 HTML 页面：语言占比的环形图、框架与组件分类及依赖生态的条形图，
+// 以及语言、框架、组件、漏洞与策略违规的表格，样式与图表均内联在页面中，不引用外部资源
+func WriteHTML(w io.Writer, report *model.CanvasReport) error {
+	return htmlTemplate.Execute(w, buildHTMLPage(report))
+}
+
+// buildHTMLPage 计算图表数据
+func buildHTMLPage(report *model.CanvasReport) *htmlPage {
+	page := &htmlPage{
+		Report:       report,
+		Title:        sbomName(report),
+		Generated:    report.Timestamp.Format(time.RFC3339),
+		Dependencies: len(report.Dependencies),
+	}
+
+	// 按代码行数排序的语言，前几种语言单独成段，其余合并
+	categories := languageCategories(report.CodeProfile)
+	langs := append([]model.LangInfo(nil), report.CodeProfile.LanguageInfos...)
+	sort.SliceStable(langs, func(i, j int) bool { return langs[i].CodeLines > langs[j].CodeLines })
+	total := 0
+	for _, lang := range langs {
+		total += lang.CodeLines
+		page.Languages = append(page.Languages, htmlLanguage{LangInfo: lang, Category: strings.Join(categories[lang.Name], ", ")})
+	}
+	if total > 0 {
+		type slice struct {
+			name  string
+			lines int
+		}
+		var parts []slice
+		for i, lang := range langs {
+			if i < htmlDonutLimit {
+				parts = append(parts, slice{lang.Name, lang.CodeLines})
+			} else if i == htmlDonutLimit {
+				parts = append(parts, slice{"Others", lang.CodeLines})
+			} else {
+				parts[htmlDonutLimit].lines += lang.CodeLines
+			}
+		}
+		// 从 12 点钟方向顺时针排列
+		offset := 25.0
+		for i, s := range parts {
+			if s.lines == 0 {
+				continue
+			}
+			percent := float64(s.lines) * 100 / float64(total)
+			page.Segments = append(page.Segments, htmlSegment{
+				Name:    s.name,
+				Lines:   s.lines,
+				Percent: fmt.Sprintf("%.1f", percent),
+				Color:   htmlChartColors[i%len(htmlChartColors)],
+				Dash:    fmt.Sprintf("%.3f %.3f", percent, 100-percent),
+				Offset:  fmt.Sprintf("%.3f", offset),
+			})
+			offset -= percent
+		}
+	}
+
+	// 各应用类别中的框架与组件数量
+	counts := make(map[string][2]int)
+	var names []string
+	for i, items := range [][]model.DetectedItem{report.Detection.Frameworks, report.Detection.Components} {
+		for _, item := range items {
+			category := item.Category
+			if category == "" {
+				category = model.CategoryOther
+			}
+			count, ok := counts[category]
+			if !ok {
+				names = append(names, category)
+			}
+			count[i]++
+			counts[category] = count
+		}
+	}
+	sort.Strings(names)
+	var bars []htmlBar
+	for _, name := range names {
+		count := counts[name]
+		bars = append(bars, htmlBar{Label: name, Value: count[0] + count[1], Extra: fmt.Sprintf("%d frameworks, %d components", count[0], count[1])})
+	}
+	page.Categories = scaleBars(bars)
+
+	bars = nil
+	for _, count := range dependencyCounts(report.Dependencies) {
+		bars = append(bars, htmlBar{Label: count.ecosystem, Value: count.total, Extra: fmt.Sprintf("%d direct", count.direct)})
+	}
+	page.Ecosystems = scaleBars(bars)
+
+	for _, section := range []struct {
+		title string
+		items []model.DetectedItem
+	}{{"Frameworks", report.Detection.Frameworks}, {"Components", report.Detection.Components}} {
+		s := htmlSection{Title: section.title}
+		for _, item := range section.items {
+			entry := htmlItem{DetectedItem: item}
+			for _, occurrence := range item.Occurrences {
+				entry.Locations = append(entry.Locations, locationLabel(occurrence.Path, occurrence.Line))
+			}
+			for _, vuln := range item.Vulnerabilities {
+				entry.Vulns = append(entry.Vulns, vulnerabilityLabel(vuln))
+			}
+			s.Items = append(s.Items, entry)
+		}
+		page.Sections = append(page.Sections, s)
+	}
+	return page
+}
+
+// scaleBars 按最大值计算条形宽度并分配颜色
+func scaleBars(bars []htmlBar) []htmlBar {
+	maximum := 0
+	for _, bar := range bars {
+		maximum = max(maximum, bar.Value)
+	}
+	for i := range bars {
+		if maximum > 0 {
+			bars[i].Width = fmt.Sprintf("%.1f", float64(bars[i].Value)*100/float64(maximum))
+		}
+		bars[i].Color = htmlChartColors[i%len(htmlChartColors)]
+	}
+	return bars
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"location": locationLabel,
+	"join":     strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>CodeCanvas Report - {{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0; color: #24292f; background: #f6f8fa; }
+header { background: #24292f; color: #fff; padding: 20px 32px; }
+header h1 { margin: 0 0 6px; font-size: 22px; }
+header p { margin: 0; color: #d0d7de; font-size: 13px; }
+main { padding: 24px 32px; max-width: 1200px; }
+.cards { display: flex; flex-wrap: wrap; gap: 16px; margin-bottom: 24px; }
+.card { background: #fff; border: 1px solid #d0d7de; border-radius: 6px; padding: 12px 20px; min-width: 140px; }
+.card b { display: block; font-size: 24px; }
+.card span { color: #57606a; font-size: 12px; text-transform: uppercase; }
+.charts { display: grid; grid-template-columns: repeat(auto-fit, minmax(320px, 1fr)); gap: 16px; margin-bottom: 24px; }
+section, .chart { background: #fff; border: 1px solid #d0d7de; border-radius: 6px; padding: 16px 20px; margin-bottom: 16px; }
+h2 { font-size: 16px; margin: 0 0 12px; }
+.donut { display: flex; align-items: center; gap: 20px; }
+.legend { list-style: none; margin: 0; padding: 0; font-size: 13px; }
+.legend li { margin: 3px 0; }
+.swatch { display: inline-block; width: 10px; height: 10px; border-radius: 2px; margin-right: 6px; }
+.bar { display: grid; grid-template-columns: 110px 1fr 48px; align-items: center; gap: 8px; font-size: 13px; margin: 6px 0; }
+.bar .track { background: #eaeef2; border-radius: 3px; height: 14px; }
+.bar .fill { height: 14px; border-radius: 3px; }
+.bar .value { text-align: right; }
+table { border-collapse: collapse; width: 100%; font-size: 13px; }
+th, td { border-bottom: 1px solid #eaeef2; padding: 6px 8px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+td.num, th.num { text-align: right; }
+.muted { color: #57606a; }
+.sev { font-weight: 600; text-transform: uppercase; font-size: 11px; }
+.sev-critical, .sev-high { color: #cf222e; }
+.sev-medium { color: #bc4c00; }
+.sev-low, .sev-unknown { color: #57606a; }
+</style>
+</head>
+<body>
+<header>
+<h1>CodeCanvas Report - {{.Title}}</h1>
+<p>{{.Report.CodeProfile.Path}}{{with .Report.Git}} · {{.Commit}}{{end}}{{with .Report.Image}} · {{.Reference}}{{end}} · generated {{.Generated}}</p>
+</header>
+<main>
+<div class="cards">
+<div class="card"><b>{{.Report.CodeProfile.TotalFiles}}</b><span>Files</span></div>
+<div class="card"><b>{{.Report.CodeProfile.TotalLines}}</b><span>Lines</span></div>
+<div class="card"><b>{{len .Languages}}</b><span>Languages</span></div>
+<div class="card"><b>{{len .Report.Detection.Frameworks}}</b><span>Frameworks</span></div>
+<div class="card"><b>{{len .Report.Detection.Components}}</b><span>Components</span></div>
+<div class="card"><b>{{.Dependencies}}</b><span>Dependencies</span></div>
+{{- with .Report.Vulnerabilities}}
+<div class="card"><b>{{.Total}}</b><span>Advisories</span></div>
+{{- end}}
+{{- with .Report.PolicyViolations}}
+<div class="card"><b>{{len .}}</b><span>Policy violations</span></div>
+{{- end}}
+</div>
+
+<div class="charts">
+<div class="chart">
+<h2>Code lines by language</h2>
+{{- if .Segments}}
+<div class="donut">
+<svg width="180" height="180" viewBox="0 0 42 42" role="img" aria-label="Code lines by language">
+<circle cx="21" cy="21" r="15.915" fill="transparent" stroke="#eaeef2" stroke-width="6"></circle>
+{{- range .Segments}}
+<circle cx="21" cy="21" r="15.915" fill="transparent" stroke="{{.Color}}" stroke-width="6" stroke-dasharray="{{.Dash}}" stroke-dashoffset="{{.Offset}}"><title>{{.Name}}: {{.Lines}} lines ({{.Percent}}%)</title></circle>
+{{- end}}
+</svg>
+<ul class="legend">
+{{- range .Segments}}
+<li><span class="swatch" style="background: {{.Color}}"></span>{{.Name}} <span class="muted">{{.Percent}}%</span></li>
+{{- end}}
+</ul>
+</div>
+{{- else}}
+<p class="muted">No code lines.</p>
+{{- end}}
+</div>
+<div class="chart">
+<h2>Frameworks and components by category</h2>
+{{- range .Categories}}
+<div class="bar" title="{{.Extra}}"><span>{{.Label}}</span><div class="track"><div class="fill" style="width: {{.Width}}%; background: {{.Color}}"></div></div><span class="value">{{.Value}}</span></div>
+{{- else}}
+<p class="muted">Nothing detected.</p>
+{{- end}}
+</div>
+{{- if .Ecosystems}}
+<div class="chart">
+<h2>Dependencies by ecosystem</h2>
+{{- range .Ecosystems}}
+<div class="bar" title="{{.Extra}}"><span>{{.Label}}</span><div class="track"><div class="fill" style="width: {{.Width}}%; background: {{.Color}}"></div></div><span class="value">{{.Value}}</span></div>
+{{- end}}
+</div>
+{{- end}}
+</div>
+
+{{- if .Languages}}
+<section>
+<h2>Languages</h2>
+<table>
+<tr><th>Language</th><th>Category</th><th class="num">Files</th><th class="num">Code</th><th class="num">Comment</th><th class="num">Blank</th></tr>
+{{- range .Languages}}
+<tr><td>{{.Name}}</td><td>{{.Category}}</td><td class="num">{{.Files}}</td><td class="num">{{.CodeLines}}</td><td class="num">{{.CommentLines}}</td><td class="num">{{.BlankLines}}</td></tr>
+{{- end}}
+</table>
+</section>
+{{- end}}
+
+{{- range .Sections}}
+<section>
+<h2>{{.Title}}</h2>
+{{- if .Items}}
+<table>
+<tr><th>Name</th><th>Category</th><th>Language</th><th>Version</th><th>Location</th><th>Evidence</th></tr>
+{{- range .Items}}
+<tr><td>{{.Name}}{{range .Vulns}}<br><span class="sev sev-high">{{.}}</span>{{end}}</td><td>{{.Category}}</td><td>{{.Language}}</td><td>{{if .Version}}{{.Version}}{{else if .UnresolvedVersion}}<span class="muted">unresolved {{.UnresolvedVersion}}</span>{{end}}</td><td>{{join .Locations ", "}}</td><td class="muted">{{.Evidence}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p class="muted">None detected.</p>
+{{- end}}
+</section>
+{{- end}}
+
+{{- with .Report.Vulnerabilities}}
+<section>
+<h2>Vulnerabilities</h2>
+<p class="muted">{{.Total}} advisories matched ({{.Advisories}} loaded).</p>
+{{- if .Dependencies}}
+<table>
+<tr><th>Package</th><th>Version</th><th>Manifest</th><th>Advisory</th><th>Severity</th><th>Fixed</th></tr>
+{{- range $dep := .Dependencies}}{{range .Vulnerabilities}}
+<tr><td>{{$dep.Ecosystem}}/{{$dep.Name}}</td><td>{{$dep.Version}}</td><td>{{location $dep.Manifest $dep.Line}}</td><td>{{.ID}}{{with .Summary}}<br><span class="muted">{{.}}</span>{{end}}</td><td class="sev sev-{{.Severity}}">{{.Severity}}</td><td>{{join .Fixed ", "}}</td></tr>
+{{- end}}{{end}}
+</table>
+{{- end}}
+</section>
+{{- end}}
+
+{{- with .Report.PolicyViolations}}
+<section>
+<h2>Policy violations</h2>
+<table>
+<tr><th>Severity</th><th>Rule</th><th>Message</th><th>Location</th></tr>
+{{- range .}}
+<tr><td class="sev sev-{{.Severity}}">{{.Severity}}</td><td>{{.Rule}}</td><td>{{.Message}}</td><td>{{if .Path}}{{location .Path .Line}}{{end}}</td></tr>
+{{- end}}
+</table>
+</section>
+{{- end}}
+</main>
+</body>
+</html>
+`))
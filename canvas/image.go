@@ -0,0 +1,57 @@
+package canvas
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/imagefs"
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// AnalyzeImage 离线分析 docker save 或 OCI 格式的镜像压缩包：各层在内存中合并为只读的文件系统，
+// 再执行与源码目录相同的分析流程，不将镜像内容写到磁盘。报告中的路径以镜像压缩包路径表示
+func AnalyzeImage(imagePath string, rulesDir string) (*model.CanvasReport, error) {
+	return AnalyzeImageWithOptions(imagePath, Options{RulesDir: rulesDir})
+}
+
+// AnalyzeImageWithOptions 按给定选项离线分析镜像压缩包
+func AnalyzeImageWithOptions(imagePath string, opts Options) (*model.CanvasReport, error) {
+	absImage, err := filepath.Abs(imagePath)
+	if err != nil {
+		return nil, err
+	}
+	fsys, image, err := imagefs.Open(absImage)
+	if err != nil {
+		return nil, fmt.Errorf("error opening image: %v", err)
+	}
+	defer fsys.Close()
+
+	report, err := AnalyzeFS(fsys, absImage, opts)
+	if err != nil {
+		return nil, err
+	}
+	report.Image = image
+	// 子项目的路径以 "image.tar!/app" 的形式表示镜像内的目录
+	rebaseProjects(report.Projects, absImage, absImage)
+	return report, nil
+}
+
+// rebaseProjects 替换项目树中代码画像的根路径
+func rebaseProjects(projects []model.ProjectReport, from, to string) {
+	for i := range projects {
+		projects[i].CodeProfile.Path = rebasePath(projects[i].CodeProfile.Path, from, to)
+		rebaseProjects(projects[i].Children, from, to)
+	}
+}
+
+// rebasePath 将位于 from 目录下的路径替换为 to 下的路径，如 image.tar/app 替换为 image.tar!/app
+func rebasePath(p, from, to string) string {
+	if p == from {
+		return to
+	}
+	if rest, ok := strings.CutPrefix(p, from+string(filepath.Separator)); ok {
+		return to + "!/" + filepath.ToSlash(rest)
+	}
+	return p
+}
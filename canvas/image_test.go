@@ -0,0 +1,79 @@
+package canvas
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestTar 按顺序写入 tar 条目（名称与内容成对出现）
+func writeTestTar(t *testing.T, entries ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for i := 0; i+1 < len(entries); i += 2 {
+		header := &tar.Header{Name: entries[i], Mode: 0644, Typeflag: tar.TypeReg, Size: int64(len(entries[i+1]))}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatalf("无法写入 tar 头: %v", err)
+		}
+		if _, err := tw.Write([]byte(entries[i+1])); err != nil {
+			t.Fatalf("无法写入 tar 内容: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("无法关闭 tar: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestAnalyzeImage(t *testing.T) {
+	layer := writeTestTar(t,
+		"etc/os-release", "PRETTY_NAME=\"Debian GNU/Linux 12 (bookworm)\"\nNAME=\"Debian GNU/Linux\"\nVERSION_ID=\"12\"\n",
+		"var/lib/dpkg/status", "Package: libc6\nStatus: install ok installed\nVersion: 2.36-9+deb12u4\n",
+		"app/go.mod", "module example.com/app\n\ngo 1.22\n\nrequire github.com/gin-gonic/gin v1.9.1\n",
+		"app/main.go", "package main\n\nimport \"github.com/gin-gonic/gin\"\n\nfunc main() { gin.Default() }\n",
+	)
+	archive := writeTestTar(t,
+		"manifest.json", `[{"Config": "config.json", "RepoTags": ["acme/app:1.0"], "Layers": ["layer/layer.tar"]}]`,
+		"config.json", `{"os": "linux", "architecture": "amd64"}`,
+		"layer/layer.tar", string(layer),
+	)
+	imagePath := filepath.Join(t.TempDir(), "app.tar")
+	if err := os.WriteFile(imagePath, archive, 0644); err != nil {
+		t.Fatalf("无法写入镜像: %v", err)
+	}
+
+	report, err := AnalyzeImage(imagePath, "")
+	if err != nil {
+		t.Fatalf("AnalyzeImage 失败: %v", err)
+	}
+	if report.Image == nil || report.Image.Reference != "acme/app:1.0" || report.Image.Layers != 1 {
+		t.Errorf("镜像信息错误: %+v", report.Image)
+	}
+	if report.CodeProfile.Path != imagePath {
+		t.Errorf("代码画像路径应为镜像路径，实际为 %s", report.CodeProfile.Path)
+	}
+
+	components := make(map[string]string)
+	for _, item := range report.Detection.Components {
+		components[item.Name] = item.Version
+	}
+	if components["Debian GNU/Linux"] != "12" {
+		t.Errorf("基础操作系统识别错误: %v", components)
+	}
+	if components["libc6"] != "2.36-9+deb12u4" {
+		t.Errorf("系统软件包识别错误: %v", components)
+	}
+	var gin bool
+	for _, item := range report.Detection.Frameworks {
+		gin = gin || item.Name == "Gin"
+	}
+	if !gin {
+		t.Errorf("应识别镜像中应用的 Gin 框架")
+	}
+	if names := report.UnclassifiedDependencies["deb"]; len(names) > 0 {
+		t.Errorf("已作为组件输出的系统软件包不应列为未识别依赖: %v", names)
+	}
+}
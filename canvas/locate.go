@@ -0,0 +1,111 @@
+package canvas
+
+import (
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// sourceFile 用于定位声明行的文件内容：小写的全文与各行的起始偏移
+type sourceFile struct {
+	content string
+	starts  []int
+}
+
+// locateEvidence 在依赖记录的来源清单与检测项的出现位置中查找声明依赖包的行号，
+// 结果写入依赖记录与出现位置的 Line 字段。压缩包成员等无法读取的文件不定位
+func locateEvidence(fsys fs.FS, report *model.CanvasReport) {
+	files := make(map[string]*sourceFile)
+	lookup := func(path, ecosystem, name string) int {
+		if path == "" || name == "" {
+			return 0
+		}
+		file, ok := files[path]
+		if !ok {
+			if data, err := fs.ReadFile(fsys, path); err == nil {
+				file = newSourceFile(string(data))
+			}
+			files[path] = file
+		}
+		if file == nil {
+			return 0
+		}
+		return file.find(declarationPatterns(ecosystem, name))
+	}
+
+	for i := range report.Dependencies {
+		dep := &report.Dependencies[i]
+		dep.Line = lookup(dep.Manifest, dep.Ecosystem, dep.Name)
+	}
+	for _, items := range [][]model.DetectedItem{report.Detection.Frameworks, report.Detection.Components} {
+		for _, item := range items {
+			name := item.Package
+			if name == "" {
+				name = item.Name
+			}
+			for i := range item.Occurrences {
+				item.Occurrences[i].Line = lookup(item.Occurrences[i].Path, item.Ecosystem, name)
+			}
+		}
+	}
+}
+
+// newSourceFile 记录文件内容与各行的起始偏移
+func newSourceFile(content string) *sourceFile {
+	file := &sourceFile{content: strings.ToLower(content), starts: []int{0}}
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\n' {
+			file.starts = append(file.starts, i+1)
+		}
+	}
+	return file
+}
+
+// find 按顺序查找各个模式第一处完整出现的位置（前后不紧邻名称字符），返回所在行号，均未找到时返回 0
+func (f *sourceFile) find(patterns []string) int {
+	for _, pattern := range patterns {
+		for offset := 0; offset < len(f.content); {
+			i := strings.Index(f.content[offset:], pattern)
+			if i < 0 {
+				break
+			}
+			start, end := offset+i, offset+i+len(pattern)
+			if (start == 0 || !isNameByte(f.content[start-1])) && (end == len(f.content) || !isNameByte(f.content[end])) {
+				return sort.SearchInts(f.starts, start+1)
+			}
+			offset = start + 1
+		}
+	}
+	return 0
+}
+
+// declarationPatterns 返回依赖包在清单中可能的写法，按优先级排列：
+// Maven 坐标优先匹配 artifactId 元素，npm 锁文件优先匹配包自身的条目
+func declarationPatterns(ecosystem, name string) []string {
+	name = strings.ToLower(name)
+	switch ecosystem {
+	case model.EcosystemMaven, model.EcosystemGradle, model.EcosystemAndroid:
+		if _, artifact, ok := strings.Cut(name, ":"); ok {
+			return []string{"<artifactid>" + artifact + "</artifactid>", name, artifact}
+		}
+	case model.EcosystemNpm:
+		return []string{`"node_modules/` + name + `"`, `"` + name + `"`, name}
+	}
+	return []string{name}
+}
+
+// isNameByte 判断字符是否可能属于依赖包名称
+func isNameByte(c byte) bool {
+	return 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '_' || c == '-' || c == '.'
+}
+
+// locationLabel 返回 "路径:行号" 形式的位置，行号未知时只有路径
+func locationLabel(path string, line int) string {
+	if line > 0 {
+		return path + ":" + strconv.Itoa(line)
+	}
+	return path
+}
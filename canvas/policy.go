@@ -0,0 +1,258 @@
+package canvas
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+	"github.com/winezer0/codecanvas/internal/version"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadPolicy 读取 YAML 格式的策略文件，校验严重程度、版本约束与最低版本的写法
+func LoadPolicy(path string) (*model.Policy, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading policy %s: %v", path, err)
+	}
+	var policy model.Policy
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&policy); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("error parsing policy %s: %v", path, err)
+	}
+	if err := validatePolicy(&policy); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %v", path, err)
+	}
+	return &policy, nil
+}
+
+// validatePolicy 校验策略中的严重程度、版本约束与最低版本
+func validatePolicy(policy *model.Policy) error {
+	severities := []string{policy.Allow.Severity, policy.Languages.Severity}
+	for _, rule := range policy.Deny {
+		if rule.Name == "" {
+			return errors.New("deny rule without name")
+		}
+		if rule.Version != "" {
+			if _, err := version.ParseConstraint(rule.Version, ""); err != nil {
+				return fmt.Errorf("deny rule %s: %v", rule.Name, err)
+			}
+		}
+		severities = append(severities, rule.Severity)
+	}
+	for _, rule := range policy.Require {
+		if rule.Name == "" {
+			return errors.New("require rule without name")
+		}
+		if _, err := version.Parse(rule.MinVersion); err != nil {
+			return fmt.Errorf("require rule %s: %v", rule.Name, err)
+		}
+		severities = append(severities, rule.Severity)
+	}
+	for _, severity := range severities {
+		if severity != "" && !slices.Contains(model.AllSeverity, severity) {
+			return fmt.Errorf("unknown severity %q", severity)
+		}
+	}
+	return nil
+}
+
+// EvaluatePolicy 按策略检查分析报告，返回按严重程度从高到低排列的违规项
+func EvaluatePolicy(report *model.CanvasReport, policy *model.Policy) []model.PolicyViolation {
+	violations := []model.PolicyViolation{}
+	items := append(slices.Clone(report.Detection.Frameworks), report.Detection.Components...)
+
+	for _, rule := range policy.Deny {
+		severity := defaultSeverity(rule.Severity, model.SeverityHigh)
+		var constraint *version.Constraint
+		if rule.Version != "" {
+			constraint, _ = version.ParseConstraint(rule.Version, "")
+		}
+		for _, item := range items {
+			if !strings.EqualFold(item.Name, rule.Name) {
+				continue
+			}
+			for _, occurrence := range versionOccurrences(item) {
+				if constraint != nil {
+					v, err := version.Parse(occurrence.Version)
+					if err != nil || !constraint.Check(v) {
+						continue
+					}
+				}
+				message := rule.Message
+				if message == "" {
+					message = fmt.Sprintf("%s %s is denied", item.Name, versionLabel(occurrence.Version))
+					if rule.Version != "" {
+						message = fmt.Sprintf("%s %s matches denied versions %s", item.Name, occurrence.Version, rule.Version)
+					}
+				}
+				violations = append(violations, itemViolation(model.PolicyDeny, severity, item, occurrence, rule.Version, message))
+			}
+		}
+	}
+
+	for _, rule := range policy.Require {
+		severity := defaultSeverity(rule.Severity, model.SeverityMedium)
+		minimum, _ := version.Parse(rule.MinVersion)
+		for _, item := range items {
+			if !strings.EqualFold(item.Name, rule.Name) {
+				continue
+			}
+			for _, occurrence := range versionOccurrences(item) {
+				v, err := version.Parse(occurrence.Version)
+				if err != nil || version.Compare(v, minimum) >= 0 {
+					continue
+				}
+				message := rule.Message
+				if message == "" {
+					message = fmt.Sprintf("%s %s is older than the required minimum %s", item.Name, occurrence.Version, rule.MinVersion)
+				}
+				violations = append(violations, itemViolation(model.PolicyMinVersion, severity, item, occurrence, ">= "+rule.MinVersion, message))
+			}
+		}
+	}
+
+	if len(policy.Allow.Frameworks) > 0 {
+		severity := defaultSeverity(policy.Allow.Severity, model.SeverityMedium)
+		reported := make(map[string]bool)
+		for _, item := range report.Detection.Frameworks {
+			if containsFold(policy.Allow.Frameworks, item.Name) || reported[item.Name] {
+				continue
+			}
+			reported[item.Name] = true
+			occurrence := model.Occurrence{Version: item.Version}
+			if len(item.Occurrences) > 0 {
+				occurrence = item.Occurrences[0]
+			}
+			message := fmt.Sprintf("framework %s is not in the allowed list", item.Name)
+			violations = append(violations, itemViolation(model.PolicyAllowFramework, severity, item, occurrence, "", message))
+		}
+	}
+
+	severity := defaultSeverity(policy.Languages.Severity, model.SeverityLow)
+	langs := languageInfosToMap(report.CodeProfile.LanguageInfos)
+	for _, name := range report.CodeProfile.Languages {
+		rule, message := "", ""
+		switch {
+		case containsFold(policy.Languages.Deny, name):
+			rule, message = model.PolicyDenyLanguage, fmt.Sprintf("language %s is denied", name)
+		case len(policy.Languages.Allow) > 0 && !containsFold(policy.Languages.Allow, name) &&
+			!slices.Contains(report.CodeProfile.OtherLanguages, name):
+			rule, message = model.PolicyAllowLanguage, fmt.Sprintf("language %s is not in the allowed list", name)
+		default:
+			continue
+		}
+		lang := langs[name]
+		violations = append(violations, model.PolicyViolation{
+			Rule: rule, Severity: severity, Type: "language", Name: name, Message: message,
+			Evidence: fmt.Sprintf("%d files, %d code lines", lang.Files, lang.CodeLines),
+		})
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		a, b := violations[i], violations[j]
+		if a.Severity != b.Severity {
+			return SeverityRank(a.Severity) > SeverityRank(b.Severity)
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Version < b.Version
+	})
+	return violations
+}
+
+// versionOccurrences 返回检测项各个不同版本的第一处出现位置，没有出现位置时使用检测项的版本
+func versionOccurrences(item model.DetectedItem) []model.Occurrence {
+	var result []model.Occurrence
+	seen := make(map[string]bool)
+	for _, occurrence := range item.Occurrences {
+		if occurrence.Version == "" || seen[occurrence.Version] {
+			continue
+		}
+		seen[occurrence.Version] = true
+		result = append(result, occurrence)
+	}
+	if len(result) == 0 {
+		result = append(result, model.Occurrence{Version: item.Version})
+	}
+	return result
+}
+
+// itemViolation 生成框架或组件的违规项
+func itemViolation(rule, severity string, item model.DetectedItem, occurrence model.Occurrence, constraint, message string) model.PolicyViolation {
+	return model.PolicyViolation{
+		Rule:       rule,
+		Severity:   severity,
+		Type:       item.Type,
+		Name:       item.Name,
+		Version:    occurrence.Version,
+		Constraint: constraint,
+		Path:       occurrence.Path,
+		Line:       occurrence.Line,
+		Message:    message,
+		Evidence:   item.Evidence,
+	}
+}
+
+// defaultSeverity 返回规则的严重程度，未设置时使用默认值
+func defaultSeverity(severity, fallback string) string {
+	if severity == "" {
+		return fallback
+	}
+	return severity
+}
+
+// containsFold 判断列表中是否包含指定名称，不区分大小写
+func containsFold(names []string, name string) bool {
+	return slices.ContainsFunc(names, func(candidate string) bool { return strings.EqualFold(candidate, name) })
+}
+
+// SeverityRank 返回严重程度的等级，low 为 1，critical 为 4，未知的严重程度为 0
+func SeverityRank(severity string) int {
+	return slices.Index(model.AllSeverity, severity) + 1
+}
+
+// MaxSeverity 返回违规项中最高的严重程度，没有违规项时返回空字符串
+func MaxSeverity(violations []model.PolicyViolation) string {
+	highest := ""
+	for _, violation := range violations {
+		if SeverityRank(violation.Severity) > SeverityRank(highest) {
+			highest = violation.Severity
+		}
+	}
+	return highest
+}
+
+// PrintViolations 在命令行输出策略违规项及其依据
+func PrintViolations(violations []model.PolicyViolation) {
+	var b strings.Builder
+	b.WriteString("\n")
+	writeViolations(&b, violations)
+	fmt.Print(b.String())
+}
+
+// writeViolations 写出策略违规项及其位置与依据
+func writeViolations(b *strings.Builder, violations []model.PolicyViolation) {
+	b.WriteString("Policy Violations:\n")
+	if len(violations) == 0 {
+		b.WriteString("- none\n")
+		return
+	}
+	for _, violation := range violations {
+		fmt.Fprintf(b, "- [%s] %s: %s\n", strings.ToUpper(violation.Severity), violation.Rule, violation.Message)
+		if violation.Path != "" {
+			fmt.Fprintf(b, "    At: %s\n", locationLabel(violation.Path, violation.Line))
+		}
+		if violation.Evidence != "" {
+			fmt.Fprintf(b, "    Evidence: %s\n", violation.Evidence)
+		}
+	}
+}
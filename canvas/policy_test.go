@@ -0,0 +1,164 @@
+package canvas
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestEvaluatePolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yml")
+	content := `deny:
+  - name: fastjson
+    version: "< 1.2.83"
+    severity: critical
+  - name: log4j-core
+    version: ">= 2.0 <2.17.1"
+    severity: critical
+  - name: commons-collections
+    version: "[3.0,3.2.2)"
+  - name: struts2
+allow:
+  frameworks: [Spring Boot]
+require:
+  - name: Spring Boot
+    min_version: 2.7.18
+languages:
+  allow: [Java]
+  deny: [PHP]
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("无法写入策略文件: %v", err)
+	}
+	policy, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatalf("LoadPolicy 失败: %v", err)
+	}
+
+	report := &model.CanvasReport{
+		CodeProfile: model.CodeProfile{
+			Languages:      []string{"Java", "Kotlin", "PHP", "YAML"},
+			OtherLanguages: []string{"YAML"},
+			LanguageInfos:  []model.LangInfo{{Name: "Java", Files: 10, CodeLines: 500}, {Name: "Kotlin", Files: 2, CodeLines: 40}, {Name: "PHP", Files: 1, CodeLines: 3}},
+		},
+		Detection: model.DetectionInfo{
+			Frameworks: []model.DetectedItem{
+				{Name: "Spring Boot", Type: model.RuleTypeFramework, Version: "2.7.0", Evidence: "FrameRule matched for Spring Boot",
+					Occurrences: []model.Occurrence{{Path: "pom.xml", Module: ".", Version: "2.7.0"}, {Path: "api/pom.xml", Module: "api", Version: "3.1.0"}}},
+				{Name: "Struts2", Type: model.RuleTypeFramework, Evidence: "FrameRule matched for Struts2"},
+			},
+			Components: []model.DetectedItem{
+				{Name: "fastjson", Type: model.RuleTypeComponent, Version: "1.2.80", Evidence: "FrameRule matched for fastjson",
+					Occurrences: []model.Occurrence{{Path: "pom.xml", Module: ".", Version: "1.2.80"}, {Path: "api/pom.xml", Module: "api", Version: "1.2.83"}}},
+				{Name: "log4j-core", Type: model.RuleTypeComponent, Version: "2.17.1"},
+				{Name: "commons-collections", Type: model.RuleTypeComponent, Version: "3.2.1"},
+			},
+		},
+	}
+
+	violations := EvaluatePolicy(report, policy)
+	type key struct{ rule, name, version string }
+	got := make(map[key]model.PolicyViolation)
+	for _, violation := range violations {
+		got[key{violation.Rule, violation.Name, violation.Version}] = violation
+	}
+	want := map[key]string{
+		{model.PolicyDeny, "fastjson", "1.2.80"}:           model.SeverityCritical,
+		{model.PolicyDeny, "commons-collections", "3.2.1"}: model.SeverityHigh,
+		{model.PolicyDeny, "Struts2", ""}:                  model.SeverityHigh,
+		{model.PolicyMinVersion, "Spring Boot", "2.7.0"}:   model.SeverityMedium,
+		{model.PolicyAllowFramework, "Struts2", ""}:        model.SeverityMedium,
+		{model.PolicyDenyLanguage, "PHP", ""}:              model.SeverityLow,
+		{model.PolicyAllowLanguage, "Kotlin", ""}:          model.SeverityLow,
+	}
+	if len(violations) != len(want) {
+		t.Errorf("违规项应为 %d 条，实际为 %d 条: %+v", len(want), len(violations), violations)
+	}
+	for k, severity := range want {
+		violation, ok := got[k]
+		if !ok {
+			t.Errorf("缺少违规项 %+v", k)
+			continue
+		}
+		if violation.Severity != severity {
+			t.Errorf("%+v 的严重程度应为 %s，实际为 %s", k, severity, violation.Severity)
+		}
+	}
+	if fastjson := got[key{model.PolicyDeny, "fastjson", "1.2.80"}]; fastjson.Path != "pom.xml" || fastjson.Evidence == "" || fastjson.Constraint != "< 1.2.83" {
+		t.Errorf("fastjson 违规项的位置或依据错误: %+v", fastjson)
+	}
+	if violations[0].Severity != model.SeverityCritical || MaxSeverity(violations) != model.SeverityCritical {
+		t.Errorf("违规项应按严重程度从高到低排列: %+v", violations)
+	}
+	if MaxSeverity(nil) != "" {
+		t.Errorf("没有违规项时最高严重程度应为空")
+	}
+
+	for _, invalid := range []string{
+		"deny:\n  - name: fastjson\n    severity: severe\n",
+		"deny:\n  - name: fastjson\n    version: \"< abc\"\n",
+		"require:\n  - name: Spring Boot\n    min_version: latest\n",
+		"denied:\n  - name: fastjson\n",
+	} {
+		if err := os.WriteFile(path, []byte(invalid), 0644); err != nil {
+			t.Fatalf("无法写入策略文件: %v", err)
+		}
+		if _, err := LoadPolicy(path); err == nil {
+			t.Errorf("无效的策略应返回错误:\n%s", invalid)
+		}
+	}
+}
+
+func TestEvaluatePolicyIgnoresDeclaredConstraint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yml")
+	content := `deny:
+  - name: Express
+    version: "< 4.18.0"
+require:
+  - name: Express
+    min_version: 4.18.0
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("无法写入策略文件: %v", err)
+	}
+	policy, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatalf("LoadPolicy 失败: %v", err)
+	}
+
+	// 只声明了 ^4.17.0 时实际安装的版本未知，不按约束的下限判断版本条件
+	report, err := AnalyzeFS(fstest.MapFS{
+		"package.json": {Data: []byte(`{"dependencies": {"express": "^4.17.0"}}`)},
+		"index.js":     {Data: []byte("const express = require('express');\n")},
+	}, "app", Options{})
+	if err != nil {
+		t.Fatalf("AnalyzeFS 失败: %v", err)
+	}
+	for _, violation := range EvaluatePolicy(report, policy) {
+		if violation.Name == "Express" {
+			t.Errorf("未锁定的约束不应产生版本违规项: %+v", violation)
+		}
+	}
+
+	// 锁文件中的版本低于要求时仍然产生违规项
+	report, err = AnalyzeFS(fstest.MapFS{
+		"package.json":      {Data: []byte(`{"dependencies": {"express": "^4.17.0"}}`)},
+		"package-lock.json": {Data: []byte(`{"lockfileVersion": 3, "packages": {"node_modules/express": {"version": "4.17.1"}}}`)},
+		"index.js":          {Data: []byte("const express = require('express');\n")},
+	}, "app", Options{})
+	if err != nil {
+		t.Fatalf("AnalyzeFS 失败: %v", err)
+	}
+	rules := make(map[string]bool)
+	for _, violation := range EvaluatePolicy(report, policy) {
+		if violation.Name == "Express" && violation.Version == "4.17.1" {
+			rules[violation.Rule] = true
+		}
+	}
+	if !rules[model.PolicyDeny] || !rules[model.PolicyMinVersion] {
+		t.Errorf("锁定版本 4.17.1 应违反 deny 与 min_version 规则: %v", rules)
+	}
+}
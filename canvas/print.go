@@ -2,87 +2,133 @@ package canvas
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/winezer0/codecanvas/internal/model"
-	"github.com/winezer0/codecanvas/internal/utils"
 )
 
 // PrintReport outputs the analysis report in text format.
 func PrintReport(report *model.CanvasReport) {
-	fmt.Println("CodeCanvas Analysis Report")
-	fmt.Println("=========================")
-	fmt.Printf("Path: %s\n", report.CodeProfile.Path)
-	fmt.Printf("Total Files: %d\n", report.CodeProfile.TotalFiles)
-	fmt.Printf("Total Lines: %d\n", report.CodeProfile.TotalLines)
-	fmt.Println()
-
-	// Frontend languages
-	if len(report.CodeProfile.FrontendLanguages) > 0 {
-		fmt.Println("Frontend LanguageInfos:")
-		for _, lang := range report.CodeProfile.FrontendLanguages {
-			fmt.Printf("- %s\n", lang)
-		}
-		fmt.Println()
-	}
+	WriteText(os.Stdout, report)
+}
 
-	// Backend languages
-	if len(report.CodeProfile.BackendLanguages) > 0 {
-		fmt.Println("Backend LanguageInfos:")
-		for _, lang := range report.CodeProfile.BackendLanguages {
-			fmt.Printf("- %s\n", lang)
-		}
-		fmt.Println()
+// WriteText 以文本格式写出分析报告
+func WriteText(w io.Writer, report *model.CanvasReport) error {
+	var b strings.Builder
+	b.WriteString("CodeCanvas Analysis Report\n")
+	b.WriteString("=========================\n")
+	fmt.Fprintf(&b, "Path: %s\n", report.CodeProfile.Path)
+	if image := report.Image; image != nil {
+		fmt.Fprintf(&b, "Image: %s (%s, %d layers, %s/%s)\n", image.Reference, image.Format, image.Layers, image.OS, image.Architecture)
 	}
-
-	// Desktop languages
-	if len(report.CodeProfile.DesktopLanguages) > 0 {
-		fmt.Println("Desktop LanguageInfos:")
-		for _, lang := range report.CodeProfile.DesktopLanguages {
-			fmt.Printf("- %s\n", lang)
+	if git := report.Git; git != nil {
+		ref := git.Ref
+		if ref == "" {
+			ref = git.Revision
 		}
-		fmt.Println()
+		fmt.Fprintf(&b, "Git: %s %s (%s)\n", ref, git.Commit, git.Date.Format(time.RFC3339))
 	}
+	fmt.Fprintf(&b, "Total Files: %d\n", report.CodeProfile.TotalFiles)
+	fmt.Fprintf(&b, "Total Lines: %d\n", report.CodeProfile.TotalLines)
+	b.WriteString("\n")
 
-	// Other languages
-	if len(report.CodeProfile.OtherLanguages) > 0 {
-		fmt.Println("Other LanguageInfos:")
-		for _, lang := range report.CodeProfile.OtherLanguages {
-			fmt.Printf("- %s\n", lang)
+	// Languages by category
+	for _, section := range []struct {
+		title string
+		langs []string
+	}{
+		{"Frontend", report.CodeProfile.FrontendLanguages},
+		{"Backend", report.CodeProfile.BackendLanguages},
+		{"Desktop", report.CodeProfile.DesktopLanguages},
+		{"Mobile", report.CodeProfile.MobileLanguages},
+		{"Other", report.CodeProfile.OtherLanguages},
+	} {
+		if len(section.langs) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "%s LanguageInfos:\n", section.title)
+		for _, lang := range section.langs {
+			fmt.Fprintf(&b, "- %s\n", lang)
 		}
-		fmt.Println()
+		b.WriteString("\n")
 	}
 
-	// All languages (verbose only)
-	fmt.Println("All LanguageInfos:")
+	// All languages
+	b.WriteString("All LanguageInfos:\n")
 	for _, lang := range report.CodeProfile.LanguageInfos {
-		fmt.Printf("- %s: %d files, %d lines\n", lang.Name, lang.Files, lang.CodeLines)
+		fmt.Fprintf(&b, "- %s: %d files, %d lines\n", lang.Name, lang.Files, lang.CodeLines)
 	}
-	fmt.Println()
+	b.WriteString("\n")
 
 	// Frameworks
 	if len(report.Detection.Frameworks) > 0 {
-		PrintDetectedItems("Detected Frameworks", report.Detection.Frameworks)
+		writeDetectedItems(&b, "Detected Frameworks", report.Detection.Frameworks)
 	} else {
-		fmt.Printf("Detected Frameworks Is Empty !!!\n")
+		b.WriteString("Detected Frameworks Is Empty !!!\n")
 	}
 
 	// Components
 	if len(report.Detection.Components) > 0 {
-		PrintDetectedItems("Detected Components", report.Detection.Components)
+		writeDetectedItems(&b, "Detected Components", report.Detection.Components)
 	} else {
-		fmt.Printf("Detected Components Is Empty !!!\n")
+		b.WriteString("Detected Components Is Empty !!!\n")
 	}
 
-	fmt.Printf("Generated: %s\n", report.Timestamp.Format(time.RFC1123))
+	// Build systems
+	if len(report.Detection.BuildSystems) > 0 {
+		b.WriteString("Build Systems:\n")
+		for _, system := range report.Detection.BuildSystems {
+			fmt.Fprintf(&b, "- %s\n", system)
+		}
+		b.WriteString("\n")
+	}
 
-	simpleReport := ToSimpleReport(report)
+	// Dependencies
+	if len(report.Dependencies) > 0 {
+		writeDependencySummary(&b, report.Dependencies)
+	}
+
+	// Vulnerabilities
+	if report.Vulnerabilities != nil {
+		writeVulnerabilitySummary(&b, report.Vulnerabilities)
+	}
+
+	// Policy violations
+	if len(report.PolicyViolations) > 0 {
+		writeViolations(&b, report.PolicyViolations)
+		b.WriteString("\n")
+	}
+
+	// Unclassified dependencies
+	if len(report.UnclassifiedDependencies) > 0 {
+		writeUnclassifiedSummary(&b, report.UnclassifiedDependencies)
+	}
 
-	fmt.Printf("Simple Report:\n%s", utils.ToJson(simpleReport))
+	// Projects
+	if len(report.Projects) > 0 {
+		b.WriteString("Projects:\n")
+		writeProjectTree(&b, report.Projects, 0)
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "Generated: %s\n", report.Timestamp.Format(time.RFC1123))
+	_, err := io.WriteString(w, b.String())
+	return err
 }
 
 func PrintDetectedItems(title string, items []model.DetectedItem) {
-	fmt.Println(title + ":")
+	var b strings.Builder
+	writeDetectedItems(&b, title, items)
+	fmt.Print(b.String())
+}
+
+// writeDetectedItems 按分类写出检测到的框架或组件
+func writeDetectedItems(b *strings.Builder, title string, items []model.DetectedItem) {
+	b.WriteString(title + ":\n")
 
 	// Group by category
 	byCategory := make(map[string][]model.DetectedItem)
@@ -92,10 +138,11 @@ func PrintDetectedItems(title string, items []model.DetectedItem) {
 
 	categories := model.AllCategory
 	for _, cat := range categories {
-		PrintCategoryItems(cat, byCategory[cat])
+		writeCategoryItems(b, cat, byCategory[cat])
 	}
 
 	// Handle any other categories
+	var others []string
 	for cat, items := range byCategory {
 		isKnown := false
 		for _, known := range categories {
@@ -105,23 +152,166 @@ func PrintDetectedItems(title string, items []model.DetectedItem) {
 			}
 		}
 		if !isKnown && len(items) > 0 {
-			PrintCategoryItems(cat, items)
+			others = append(others, cat)
 		}
 	}
-	fmt.Println()
+	sort.Strings(others)
+	for _, cat := range others {
+		writeCategoryItems(b, cat, byCategory[cat])
+	}
+	b.WriteString("\n")
 }
 
 func PrintCategoryItems(category string, items []model.DetectedItem) {
+	var b strings.Builder
+	writeCategoryItems(&b, category, items)
+	fmt.Print(b.String())
+}
+
+// writeCategoryItems 写出同一分类下的检测项及其版本、位置、依据与漏洞
+func writeCategoryItems(b *strings.Builder, category string, items []model.DetectedItem) {
 	if len(items) > 0 {
-		fmt.Printf("  [%s]\n", category)
+		fmt.Fprintf(b, "  [%s]\n", category)
 		for _, item := range items {
-			fmt.Printf("  - %s (%s)\n", item.Name, item.Language)
-			if item.Version != "" {
-				fmt.Printf("    Version: %s\n", item.Version)
+			fmt.Fprintf(b, "  - %s (%s)\n", item.Name, item.Language)
+			switch {
+			case item.Version != "" && item.DeclaredConstraint != "":
+				fmt.Fprintf(b, "    Version: %s (declared %s)\n", item.Version, item.DeclaredConstraint)
+			case item.Version != "":
+				fmt.Fprintf(b, "    Version: %s\n", item.Version)
+			case item.DeclaredConstraint != "":
+				fmt.Fprintf(b, "    Declared: %s\n", item.DeclaredConstraint)
+			case item.UnresolvedVersion != "":
+				fmt.Fprintf(b, "    Version: unresolved %s\n", item.UnresolvedVersion)
+			}
+			// 多个模块中出现时逐一列出位置与版本
+			if len(item.Occurrences) > 1 {
+				for _, occurrence := range item.Occurrences {
+					fmt.Fprintf(b, "    At: %s %s\n", locationLabel(occurrence.Path, occurrence.Line), occurrenceVersion(occurrence))
+				}
 			}
 			if item.Evidence != "" {
-				fmt.Printf("    Evidence: %s\n", item.Evidence)
+				fmt.Fprintf(b, "    Evidence: %s\n", item.Evidence)
+			}
+			if item.Usage != "" {
+				fmt.Fprintf(b, "    Usage: %s (%d files)\n", item.Usage, item.Weight)
 			}
+			for _, vuln := range item.Vulnerabilities {
+				fmt.Fprintf(b, "    Vulnerability: %s\n", vulnerabilityLabel(vuln))
+			}
+		}
+	}
+}
+
+// occurrenceVersion 返回出现位置的版本描述，没有具体版本时使用声明的约束或占位符
+func occurrenceVersion(occurrence model.Occurrence) string {
+	switch {
+	case occurrence.Version != "":
+		return occurrence.Version
+	case occurrence.DeclaredConstraint != "":
+		return occurrence.DeclaredConstraint
+	case occurrence.UnresolvedVersion != "":
+		return "unresolved " + occurrence.UnresolvedVersion
+	}
+	return "-"
+}
+
+// PrintProjectTree 按目录层级输出子项目及其主要语言与框架
+func PrintProjectTree(projects []model.ProjectReport, depth int) {
+	var b strings.Builder
+	writeProjectTree(&b, projects, depth)
+	fmt.Print(b.String())
+}
+
+// writeProjectTree 按目录层级写出子项目及其主要语言与框架
+func writeProjectTree(b *strings.Builder, projects []model.ProjectReport, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, project := range projects {
+		fmt.Fprintf(b, "%s- %s (%s) [%s]\n", indent, project.Name, project.Path, strings.Join(project.Kinds, ", "))
+		if len(project.CodeProfile.Languages) > 0 {
+			fmt.Fprintf(b, "%s  Languages: %s\n", indent, strings.Join(project.CodeProfile.Languages, ", "))
+		}
+		if frameworks := itemNames(project.Detection.Frameworks); len(frameworks) > 0 {
+			fmt.Fprintf(b, "%s  Frameworks: %s\n", indent, strings.Join(frameworks, ", "))
 		}
+		writeProjectTree(b, project.Children, depth+1)
+	}
+}
+
+// itemNames 返回检测项的名称列表
+func itemNames(items []model.DetectedItem) []string {
+	var names []string
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	return names
+}
+
+// PrintDependencySummary 按生态输出依赖数量及其中的直接依赖数量
+func PrintDependencySummary(deps []model.Dependency) {
+	var b strings.Builder
+	writeDependencySummary(&b, deps)
+	fmt.Print(b.String())
+}
+
+// writeDependencySummary 按生态写出依赖数量及其中的直接依赖数量
+func writeDependencySummary(b *strings.Builder, deps []model.Dependency) {
+	fmt.Fprintf(b, "Dependencies: %d\n", len(deps))
+	for _, count := range dependencyCounts(deps) {
+		fmt.Fprintf(b, "- %s: %d (direct %d)\n", count.ecosystem, count.total, count.direct)
+	}
+	b.WriteString("\n")
+}
+
+// ecosystemCount 某一生态的依赖数量及其中的直接依赖数量
+type ecosystemCount struct {
+	ecosystem     string
+	total, direct int
+}
+
+// dependencyCounts 按生态统计依赖数量，按生态名称排序
+func dependencyCounts(deps []model.Dependency) []ecosystemCount {
+	byEcosystem := make(map[string]*ecosystemCount)
+	var counts []*ecosystemCount
+	for _, dep := range deps {
+		count, ok := byEcosystem[dep.Ecosystem]
+		if !ok {
+			count = &ecosystemCount{ecosystem: dep.Ecosystem}
+			byEcosystem[dep.Ecosystem] = count
+			counts = append(counts, count)
+		}
+		count.total++
+		if dep.Direct {
+			count.direct++
+		}
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		return counts[i].ecosystem < counts[j].ecosystem
+	})
+	result := make([]ecosystemCount, 0, len(counts))
+	for _, count := range counts {
+		result = append(result, *count)
+	}
+	return result
+}
+
+// PrintUnclassifiedSummary 按生态输出未被规则识别的依赖数量
+func PrintUnclassifiedSummary(unclassified map[string][]string) {
+	var b strings.Builder
+	writeUnclassifiedSummary(&b, unclassified)
+	fmt.Print(b.String())
+}
+
+// writeUnclassifiedSummary 按生态写出未被规则识别的依赖数量
+func writeUnclassifiedSummary(b *strings.Builder, unclassified map[string][]string) {
+	b.WriteString("Unclassified Dependencies:\n")
+	var ecosystems []string
+	for ecosystem := range unclassified {
+		ecosystems = append(ecosystems, ecosystem)
+	}
+	sort.Strings(ecosystems)
+	for _, ecosystem := range ecosystems {
+		fmt.Fprintf(b, "- %s: %d\n", ecosystem, len(unclassified[ecosystem]))
 	}
+	b.WriteString("\n")
 }
@@ -0,0 +1,66 @@
+package canvas
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/winezer0/codecanvas/internal/analyzer"
+	"github.com/winezer0/codecanvas/internal/frameengine"
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// analyzeProjects 发现代码库中的子项目并分别生成代码画像与检测结果，返回按目录层级组织的项目树；
+// 每个项目仅统计自身目录中不属于嵌套子项目的文件。只有一个位于根目录的项目时返回 nil
+func analyzeProjects(ctx context.Context, engine *frameengine.CanvasEngine, index *model.FileIndex) ([]model.ProjectReport, error) {
+	projects := engine.DiscoverProjects(index)
+	if len(projects) == 0 || (len(projects) == 1 && projects[0].Path == ".") {
+		return nil, nil
+	}
+
+	reports := make(map[string]*model.ProjectReport, len(projects))
+	children := make(map[string][]string)
+	var roots []string
+	for _, project := range projects {
+		var nested []string
+		for _, other := range projects {
+			if other.Path != project.Path && isSubPath(project.Path, other.Path) {
+				nested = append(nested, other.Path)
+			}
+		}
+		sub := index.Subset(project.Path, nested)
+		profile := analyzer.ProfileFromIndex(sub)
+		inventory := engine.CollectDependencies(sub)
+		detect, err := engine.DetectFrameworksWithInventory(ctx, sub, profile.Expands, inventory)
+		if err != nil {
+			return nil, fmt.Errorf("error detecting frameworks for project %s: %v", project.Path, err)
+		}
+		reports[project.Path] = &model.ProjectReport{Project: project, CodeProfile: *profile, Detection: *detect}
+		if project.Parent == "" {
+			roots = append(roots, project.Path)
+		} else {
+			children[project.Parent] = append(children[project.Parent], project.Path)
+		}
+	}
+
+	var build func(dir string) model.ProjectReport
+	build = func(dir string) model.ProjectReport {
+		report := *reports[dir]
+		for _, child := range children[dir] {
+			report.Children = append(report.Children, build(child))
+		}
+		return report
+	}
+	var tree []model.ProjectReport
+	for _, root := range roots {
+		tree = append(tree, build(root))
+	}
+	return tree, nil
+}
+
+// isSubPath 判断 child 是否位于 dir 目录之下，根目录 "." 包含所有路径
+func isSubPath(dir, child string) bool {
+	if dir == "." {
+		return child != "."
+	}
+	return len(child) > len(dir) && child[:len(dir)] == dir && child[len(dir)] == '/'
+}
@@ -0,0 +1,71 @@
+package canvas
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestAnalyzeProjectTree(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"go.work":          "go 1.22\n\nuse (\n\t./api\n\t./admin\n)\n",
+		"api/go.mod":       "module example.com/api\n\ngo 1.22\n\nrequire github.com/gin-gonic/gin v1.9.1\n",
+		"api/main.go":      "package main\n\nimport \"github.com/gin-gonic/gin\"\n\nfunc main() { gin.Default() }\n",
+		"admin/go.mod":     "module example.com/admin\n\ngo 1.22\n\nrequire github.com/labstack/echo/v4 v4.11.4\n",
+		"admin/main.go":    "package main\n\nimport \"github.com/labstack/echo/v4\"\n\nfunc main() { echo.New() }\n",
+		"admin/handler.go": "package main\n",
+		"web/package.json": `{"name": "web", "dependencies": {"react": "^18.2.0"}}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("无法创建目录: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("无法写入 %s: %v", name, err)
+		}
+	}
+
+	report, err := Analyze(tmpDir, "")
+	if err != nil {
+		t.Fatalf("Analyze 失败: %v", err)
+	}
+	if len(report.Projects) != 1 {
+		t.Fatalf("顶层项目数量应为 1，实际为 %d", len(report.Projects))
+	}
+
+	root := report.Projects[0]
+	if root.Path != "." || !root.Workspace {
+		t.Errorf("根项目错误: %+v", root.Project)
+	}
+	if len(root.Children) != 3 {
+		t.Fatalf("根项目应包含 3 个子项目，实际为 %d", len(root.Children))
+	}
+	// 子项目的检测结果仅包含自身目录中的框架
+	frameworks := make(map[string][]string)
+	for _, child := range root.Children {
+		for _, fw := range child.Detection.Frameworks {
+			frameworks[child.Path] = append(frameworks[child.Path], fw.Name)
+		}
+	}
+	if !slices.Contains(frameworks["api"], "Gin") || slices.Contains(frameworks["api"], "Echo") {
+		t.Errorf("api 项目的框架错误: %v", frameworks["api"])
+	}
+	if !slices.Contains(frameworks["admin"], "Echo") || slices.Contains(frameworks["admin"], "Gin") {
+		t.Errorf("admin 项目的框架错误: %v", frameworks["admin"])
+	}
+
+	admin := root.Children[0]
+	if admin.Path != "admin" || admin.Name != "example.com/admin" {
+		t.Errorf("admin 项目错误: %+v", admin.Project)
+	}
+	if admin.CodeProfile.TotalFiles != 2 {
+		t.Errorf("admin 项目的源码文件数应为 2，实际为 %d", admin.CodeProfile.TotalFiles)
+	}
+	// 顶层代码画像仍为整个代码库的汇总
+	if report.CodeProfile.TotalFiles <= admin.CodeProfile.TotalFiles {
+		t.Errorf("顶层代码画像应汇总全部文件，实际为 %d", report.CodeProfile.TotalFiles)
+	}
+}
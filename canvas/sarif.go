@@ -0,0 +1,377 @@
+package canvas
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// FormatSARIF SARIF 2.1.0 输出格式，供 GitHub、GitLab 等代码扫描界面展示
+const FormatSARIF = "sarif"
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	// sarifSrcRoot 结果中相对路径的基准目录
+	sarifSrcRoot = "%SRCROOT%"
+)
+
+// 风险组件的规则编号
+const (
+	sarifRuleVersionSkew       = "risk/version-skew"
+	sarifRuleUnresolvedVersion = "risk/unresolved-version"
+)
+
+// sarifLog SARIF 日志，只包含 CodeCanvas 用到的字段
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                        `json:"tool"`
+	OriginalURIBaseIDs map[string]sarifArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	Results            []sarifResult                    `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Version        string      `json:"version,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+// sarifRule 规则描述，security-severity 属性为 GitHub 代码扫描使用的 0-10 分数
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name,omitempty"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	FullDescription      *sarifMessage      `json:"fullDescription,omitempty"`
+	HelpURI              string             `json:"helpUri,omitempty"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+	Properties           map[string]any     `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations,omitempty"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+	Properties          map[string]any    `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// sarifPolicyRules 策略规则类型对应的 SARIF 规则名称与说明
+var sarifPolicyRules = map[string][2]string{
+	model.PolicyDeny:           {"DeniedComponent", "A framework or component denied by the policy is in use"},
+	model.PolicyAllowFramework: {"FrameworkNotAllowed", "A framework outside the policy's allowed list is in use"},
+	model.PolicyMinVersion:     {"VersionBelowMinimum", "A framework or component is older than the minimum version required by the policy"},
+	model.PolicyDenyLanguage:   {"DeniedLanguage", "A language denied by the policy is in use"},
+	model.PolicyAllowLanguage:  {"LanguageNotAllowed", "A language outside the policy's allowed list is in use"},
+}
+
+// sarifSecuritySeverity 没有 CVSS 分数时严重程度对应的 security-severity 分数
+var sarifSecuritySeverity = map[string]float64{
+	model.SeverityLow:      2.0,
+	model.SeverityMedium:   5.5,
+	model.SeverityHigh:     8.0,
+	model.SeverityCritical: 9.5,
+}
+
+// WriteSARIF 将分析报告写为 SARIF 2.1.0 日志。策略违规、存在已知漏洞的组件与依赖以及风险组件
+// （在不同模块中使用不同版本、版本无法解析）各自为一条带规则编号的结果，位置指向声明该依赖的清单文件与行号
+func WriteSARIF(w io.Writer, report *model.CanvasReport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(buildSARIF(report))
+}
+
+// sarifBuilder 收集规则与结果，规则按首次引用的顺序编号
+type sarifBuilder struct {
+	run   *sarifRun
+	rules map[string]int
+}
+
+// rule 返回规则在规则列表中的下标，规则不存在时先加入列表
+func (b *sarifBuilder) rule(rule sarifRule) int {
+	if index, ok := b.rules[rule.ID]; ok {
+		return index
+	}
+	b.rules[rule.ID] = len(b.run.Tool.Driver.Rules)
+	b.run.Tool.Driver.Rules = append(b.run.Tool.Driver.Rules, rule)
+	return b.rules[rule.ID]
+}
+
+// add 添加一条结果，指纹由规则编号、位置与检测对象生成，便于代码扫描界面跨次扫描跟踪同一问题
+func (b *sarifBuilder) add(ruleIndex int, level, message string, locations []sarifLocation, properties map[string]any) {
+	rule := b.run.Tool.Driver.Rules[ruleIndex]
+	result := sarifResult{
+		RuleID:     rule.ID,
+		RuleIndex:  ruleIndex,
+		Level:      level,
+		Message:    sarifMessage{Text: message},
+		Locations:  locations,
+		Properties: properties,
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%v\x00%v\x00%v", rule.ID, properties["name"], properties["version"], properties["package"])
+	for _, location := range locations {
+		fmt.Fprintf(h, "\x00%s", location.PhysicalLocation.ArtifactLocation.URI)
+	}
+	result.PartialFingerprints = map[string]string{"codecanvas/v1": hex.EncodeToString(h.Sum(nil))[:32]}
+	// 省略值为空的属性
+	for key, value := range properties {
+		if value == "" {
+			delete(properties, key)
+		}
+	}
+	b.run.Results = append(b.run.Results, result)
+}
+
+// buildSARIF 生成 SARIF 日志
+func buildSARIF(report *model.CanvasReport) *sarifLog {
+	run := &sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "CodeCanvas",
+			InformationURI: "https://github.com/winezer0/codecanvas",
+			Version:        report.Version,
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+	// 只有分析磁盘上的源码目录时才能给出相对路径的基准目录
+	if root := report.CodeProfile.Path; report.Image == nil && report.Git == nil && filepath.IsAbs(root) {
+		uri := url.URL{Scheme: "file", Path: strings.TrimSuffix(filepath.ToSlash(root), "/") + "/"}
+		run.OriginalURIBaseIDs = map[string]sarifArtifactLocation{sarifSrcRoot: {URI: uri.String()}}
+	}
+	b := &sarifBuilder{run: run, rules: make(map[string]int)}
+
+	sarifPolicyResults(b, report.PolicyViolations)
+	sarifVulnerabilityResults(b, report)
+	for _, items := range [][]model.DetectedItem{report.Detection.Frameworks, report.Detection.Components} {
+		sarifRiskResults(b, items)
+	}
+	return &sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{*run}}
+}
+
+// sarifPolicyResults 每条策略违规为一条结果，规则编号为 "policy/<规则类型>"
+func sarifPolicyResults(b *sarifBuilder, violations []model.PolicyViolation) {
+	for _, violation := range violations {
+		info, ok := sarifPolicyRules[violation.Rule]
+		if !ok {
+			info = [2]string{"", "Policy violation"}
+		}
+		index := b.rule(sarifRule{
+			ID:                   "policy/" + violation.Rule,
+			Name:                 info[0],
+			ShortDescription:     sarifMessage{Text: info[1]},
+			DefaultConfiguration: sarifConfiguration{Level: "warning"},
+			Properties:           map[string]any{"tags": []string{"policy"}},
+		})
+		message := violation.Message
+		if violation.Evidence != "" {
+			message += ". Evidence: " + violation.Evidence
+		}
+		b.add(index, sarifLevel(violation.Severity), message, sarifLocations(violation.Path, violation.Line), map[string]any{
+			"name":       violation.Name,
+			"version":    violation.Version,
+			"type":       violation.Type,
+			"severity":   violation.Severity,
+			"constraint": violation.Constraint,
+		})
+	}
+}
+
+// sarifVulnerabilityResults 每个存在漏洞的依赖记录与公告的组合为一条结果，规则编号为公告编号；
+// 未对应到依赖记录的检测项漏洞位于检测项第一处出现的位置
+func sarifVulnerabilityResults(b *sarifBuilder, report *model.CanvasReport) {
+	items := make(map[string]string)
+	for _, list := range [][]model.DetectedItem{report.Detection.Frameworks, report.Detection.Components} {
+		for _, item := range list {
+			if item.Ecosystem != "" && item.Package != "" {
+				items[item.Ecosystem+"\x00"+strings.ToLower(item.Package)] = item.Name
+			}
+		}
+	}
+
+	reported := make(map[string]bool)
+	add := func(ecosystem, pkg, pkgVersion string, vuln model.Vulnerability, locations []sarifLocation) {
+		key := ecosystem + "\x00" + strings.ToLower(pkg)
+		reported[key+"\x00"+vuln.ID] = true
+		properties := map[string]any{"name": pkg, "version": pkgVersion, "ecosystem": ecosystem, "severity": vuln.Severity}
+		subject := pkg + " " + versionLabel(pkgVersion)
+		if name, ok := items[key]; ok {
+			properties["package"], properties["name"] = pkg, name
+			if !strings.EqualFold(name, pkg) {
+				subject = name + " (" + subject + ")"
+			}
+		}
+		message := subject + " is affected by " + vulnerabilityLabel(vuln)
+		if vuln.Summary != "" {
+			message += ": " + vuln.Summary
+		}
+		b.add(b.rule(sarifVulnerabilityRule(vuln)), sarifLevel(vuln.Severity), message, locations, properties)
+	}
+
+	if report.Vulnerabilities != nil {
+		for _, dep := range report.Vulnerabilities.Dependencies {
+			for _, vuln := range dep.Vulnerabilities {
+				add(dep.Ecosystem, dep.Name, dep.Version, vuln, sarifLocations(dep.Manifest, dep.Line))
+			}
+		}
+	}
+	for _, list := range [][]model.DetectedItem{report.Detection.Frameworks, report.Detection.Components} {
+		for _, item := range list {
+			for _, vuln := range item.Vulnerabilities {
+				if reported[item.Ecosystem+"\x00"+strings.ToLower(item.Package)+"\x00"+vuln.ID] {
+					continue
+				}
+				occurrence := versionOccurrences(item)[0]
+				add(item.Ecosystem, item.Package, occurrence.Version, vuln, sarifLocations(occurrence.Path, occurrence.Line))
+			}
+		}
+	}
+}
+
+// sarifVulnerabilityRule 由公告生成规则，帮助链接指向 OSV 的公告页面
+func sarifVulnerabilityRule(vuln model.Vulnerability) sarifRule {
+	rule := sarifRule{
+		ID:                   vuln.ID,
+		ShortDescription:     sarifMessage{Text: vuln.ID},
+		HelpURI:              "https://osv.dev/vulnerability/" + url.PathEscape(vuln.ID),
+		DefaultConfiguration: sarifConfiguration{Level: sarifLevel(vuln.Severity)},
+		Properties:           map[string]any{"tags": []string{"security", "vulnerability"}},
+	}
+	if vuln.Summary != "" {
+		rule.ShortDescription.Text = vuln.Summary
+	}
+	if len(vuln.Aliases) > 0 {
+		rule.FullDescription = &sarifMessage{Text: vuln.ID + " (" + strings.Join(vuln.Aliases, ", ") + ")"}
+	}
+	if vuln.Score > 0 {
+		rule.Properties["security-severity"] = fmt.Sprintf("%.1f", vuln.Score)
+	} else if score, ok := sarifSecuritySeverity[vuln.Severity]; ok {
+		rule.Properties["security-severity"] = fmt.Sprintf("%.1f", score)
+	}
+	return rule
+}
+
+// sarifRiskResults 风险组件：在不同模块中使用不同版本的检测项（位置为各版本第一处出现的位置），
+// 以及版本为占位符或无法解析的出现位置
+func sarifRiskResults(b *sarifBuilder, items []model.DetectedItem) {
+	for _, item := range items {
+		if versions := distinctVersions(item); len(versions) > 1 {
+			index := b.rule(sarifRule{
+				ID:                   sarifRuleVersionSkew,
+				Name:                 "VersionSkew",
+				ShortDescription:     sarifMessage{Text: "The same framework or component is used in different versions across modules"},
+				DefaultConfiguration: sarifConfiguration{Level: "warning"},
+				Properties:           map[string]any{"tags": []string{"maintainability"}},
+			})
+			var labels []string
+			for _, entry := range versions {
+				labels = append(labels, entry.version+" ("+strings.Join(entry.modules, ", ")+")")
+			}
+			var locations []sarifLocation
+			for _, occurrence := range versionOccurrences(item) {
+				locations = append(locations, sarifLocations(occurrence.Path, occurrence.Line)...)
+			}
+			message := fmt.Sprintf("%s %s is used in %d versions: %s", item.Type, item.Name, len(versions), strings.Join(labels, ", "))
+			b.add(index, "warning", message, locations, map[string]any{"name": item.Name, "type": item.Type})
+		}
+
+		var unresolved []model.Occurrence
+		for _, occurrence := range item.Occurrences {
+			if occurrence.UnresolvedVersion != "" {
+				unresolved = append(unresolved, occurrence)
+			}
+		}
+		if len(unresolved) == 0 && item.UnresolvedVersion != "" {
+			unresolved = append(unresolved, model.Occurrence{UnresolvedVersion: item.UnresolvedVersion})
+		}
+		for _, occurrence := range unresolved {
+			index := b.rule(sarifRule{
+				ID:                   sarifRuleUnresolvedVersion,
+				Name:                 "UnresolvedVersion",
+				ShortDescription:     sarifMessage{Text: "The version of a framework or component cannot be resolved from its declaration"},
+				FullDescription:      &sarifMessage{Text: "Placeholders such as latest, workspace:* or ${project.version} leave the version that is actually used unknown"},
+				DefaultConfiguration: sarifConfiguration{Level: "note"},
+				Properties:           map[string]any{"tags": []string{"maintainability"}},
+			})
+			message := fmt.Sprintf("%s %s is declared with the unresolved version %q", item.Type, item.Name, occurrence.UnresolvedVersion)
+			b.add(index, "note", message, sarifLocations(occurrence.Path, occurrence.Line), map[string]any{
+				"name":    item.Name,
+				"type":    item.Type,
+				"version": occurrence.UnresolvedVersion,
+			})
+		}
+	}
+}
+
+// sarifLocations 返回文件位置，路径为空时没有位置；压缩包成员（如 app.war!/WEB-INF/lib/x.jar）指向压缩包本身
+func sarifLocations(path string, line int) []sarifLocation {
+	if path == "" {
+		return nil
+	}
+	if archive, _, ok := strings.Cut(path, "!/"); ok {
+		path, line = archive, 0
+	}
+	location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{URI: (&url.URL{Path: path}).String(), URIBaseID: sarifSrcRoot},
+	}}
+	if line > 0 {
+		location.PhysicalLocation.Region = &sarifRegion{StartLine: line}
+	}
+	return []sarifLocation{location}
+}
+
+// sarifLevel 将严重程度转换为 SARIF 结果级别：critical 与 high 为 error，low 为 note，其余为 warning
+func sarifLevel(severity string) string {
+	switch severity {
+	case model.SeverityCritical, model.SeverityHigh:
+		return "error"
+	case model.SeverityLow:
+		return "note"
+	}
+	return "warning"
+}
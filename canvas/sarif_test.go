@@ -0,0 +1,129 @@
+package canvas
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	report, err := AnalyzeFS(fstest.MapFS{
+		"pom.xml": {Data: []byte(`<project>
+  <dependencies>
+    <dependency>
+      <groupId>com.alibaba</groupId>
+      <artifactId>fastjson</artifactId>
+      <version>1.2.80</version>
+    </dependency>
+  </dependencies>
+</project>`)},
+		"src/Main.java": {Data: []byte("import com.alibaba.fastjson.JSON;\nclass Main {}\n")},
+		"go.mod":        {Data: []byte("module example.com/app\n\ngo 1.22\n\nrequire github.com/gin-gonic/gin v1.9.0\n")},
+		"main.go":       {Data: []byte("package main\n\nimport \"github.com/gin-gonic/gin\"\n\nfunc main() { gin.Default() }\n")},
+		"api/go.mod":    {Data: []byte("module example.com/api\n\ngo 1.22\n\nrequire (\n\tgithub.com/gin-gonic/gin v1.8.0\n)\n")},
+		"api/main.go":   {Data: []byte("package main\n\nimport \"github.com/gin-gonic/gin\"\n\nfunc main() { gin.Default() }\n")},
+	}, "app", Options{})
+	if err != nil {
+		t.Fatalf("AnalyzeFS 失败: %v", err)
+	}
+	report.PolicyViolations = EvaluatePolicy(report, &model.Policy{Deny: []model.DenyRule{{Name: "fastjson", Version: "< 1.2.83", Severity: model.SeverityCritical}}})
+	if len(report.PolicyViolations) != 1 || report.PolicyViolations[0].Path != "pom.xml" || report.PolicyViolations[0].Line != 5 {
+		t.Fatalf("违规项应指向 pom.xml 第 5 行: %+v", report.PolicyViolations)
+	}
+	report.Vulnerabilities = &model.VulnerabilitySummary{}
+	for _, dep := range report.Dependencies {
+		if dep.Name == "github.com/gin-gonic/gin" && dep.Manifest == "api/go.mod" {
+			report.Vulnerabilities.Dependencies = append(report.Vulnerabilities.Dependencies, model.VulnerableDependency{
+				Ecosystem: dep.Ecosystem, Name: dep.Name, Version: dep.Version, Manifest: dep.Manifest, Line: dep.Line,
+				Vulnerabilities: []model.Vulnerability{{ID: "GO-2023-1737", Aliases: []string{"CVE-2023-29401"}, Severity: model.SeverityMedium, Score: 4.3}},
+			})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, report); err != nil {
+		t.Fatalf("WriteSARIF 失败: %v", err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("输出不是有效的 JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("文档头错误: %s %d", log.Version, len(log.Runs))
+	}
+	run := log.Runs[0]
+	results := make(map[string]sarifResult)
+	for _, result := range run.Results {
+		if run.Tool.Driver.Rules[result.RuleIndex].ID != result.RuleID {
+			t.Errorf("%s 的规则下标错误: %d", result.RuleID, result.RuleIndex)
+		}
+		if result.PartialFingerprints["codecanvas/v1"] == "" {
+			t.Errorf("%s 缺少指纹", result.RuleID)
+		}
+		results[result.RuleID] = result
+	}
+
+	deny := results["policy/deny"]
+	if deny.Level != "error" || len(deny.Locations) != 1 {
+		t.Fatalf("策略违规结果错误: %+v", deny)
+	}
+	if location := deny.Locations[0].PhysicalLocation; location.ArtifactLocation.URI != "pom.xml" ||
+		location.ArtifactLocation.URIBaseID != "%SRCROOT%" || location.Region == nil || location.Region.StartLine != 5 {
+		t.Errorf("策略违规的位置错误: %+v", location)
+	}
+
+	vuln := results["GO-2023-1737"]
+	if vuln.Level != "warning" || len(vuln.Locations) != 1 || vuln.Locations[0].PhysicalLocation.ArtifactLocation.URI != "api/go.mod" ||
+		vuln.Locations[0].PhysicalLocation.Region.StartLine != 6 {
+		t.Errorf("漏洞结果错误: %+v", vuln)
+	}
+	rule := run.Tool.Driver.Rules[vuln.RuleIndex]
+	if rule.Properties["security-severity"] != "4.3" || rule.HelpURI != "https://osv.dev/vulnerability/GO-2023-1737" {
+		t.Errorf("漏洞规则错误: %+v", rule)
+	}
+
+	skew := results["risk/version-skew"]
+	var uris []string
+	for _, location := range skew.Locations {
+		uris = append(uris, location.PhysicalLocation.ArtifactLocation.URI)
+	}
+	slices.Sort(uris)
+	if !slices.Equal(uris, []string{"api/go.mod", "go.mod"}) {
+		t.Errorf("版本不一致结果的位置错误: %v", uris)
+	}
+}
+
+func TestSARIFLocations(t *testing.T) {
+	if locations := sarifLocations("", 3); locations != nil {
+		t.Errorf("路径为空时不应有位置: %+v", locations)
+	}
+	locations := sarifLocations("app.war!/WEB-INF/lib/fastjson.jar!/META-INF/MANIFEST.MF", 4)
+	if len(locations) != 1 || locations[0].PhysicalLocation.ArtifactLocation.URI != "app.war" || locations[0].PhysicalLocation.Region != nil {
+		t.Errorf("压缩包成员应指向压缩包本身: %+v", locations)
+	}
+	if uri := sarifLocations("my docs/package.json", 0)[0].PhysicalLocation.ArtifactLocation.URI; uri != "my%20docs/package.json" {
+		t.Errorf("路径应按 URI 编码: %s", uri)
+	}
+}
+
+func TestLocateEvidence(t *testing.T) {
+	report, err := AnalyzeFS(sbomTestFS, "app", Options{})
+	if err != nil {
+		t.Fatalf("AnalyzeFS 失败: %v", err)
+	}
+	want := map[string]int{
+		"package.json:react":              1,
+		"package-lock.json:react":         2,
+		"package-lock.json:loose-envify":  4,
+		"go.mod:github.com/gin-gonic/gin": 5,
+	}
+	for _, dep := range report.Dependencies {
+		if line, ok := want[dep.Manifest+":"+dep.Name]; ok && dep.Line != line {
+			t.Errorf("%s 中 %s 的行号为 %d，期望 %d", dep.Manifest, dep.Name, dep.Line, line)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package canvas
+
+import (
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+	"github.com/winezer0/codecanvas/internal/version"
+)
+
+// sbomPackage SBOM 中的一个软件包，由依赖记录与检测到的框架、组件按 purl 合并而来
+// - Ref: 包在文档中的唯一引用，有 purl 时即为 purl
+// - Package: 依赖包的原始名称（如 "com.alibaba:fastjson"），没有依赖包的检测项为检测项名称
+// - Group/Name: purl 中的命名空间（如 Maven groupId、npm 作用域）与名称，Go 模块的名称为完整的模块路径
+// - Version: 具体版本；版本未锁定时只有约束固定了唯一版本（如 "=1.2.3"）才有值，否则为空
+// - Constraint: 版本未锁定时清单中声明的约束原文
+// - Scope: 依赖作用域，多处声明时取最强的作用域（runtime > build > dev）
+// - Items: 对应的框架与组件
+// - Paths: 声明该包的清单文件与检测项的出现位置
+// - Requires: 锁文件中记录的该包所依赖的包引用
+// - License/Checksum: 锁文件或已安装元数据中记录的许可证与包文件摘要
+type sbomPackage struct {
+	Ref        string
+	Purl       string
+	Ecosystem  string
+	Package    string
+	Group      string
+	Name       string
+	Version    string
+	Constraint string
+	Scope      string
+	Direct     bool
+	Locked     bool
+	Usage      string
+	Items      []model.DetectedItem
+	Paths      []string
+	Requires   []string
+	License    string
+	Checksum   string
+}
+
+// sbomDocument 从分析报告整理出的软件包与依赖关系，供 CycloneDX 与 SPDX 等格式共用
+// - Name: 被分析项目的名称
+// - Packages: 按引用排序的软件包
+// - Roots: 项目直接依赖的包引用（直接声明的依赖与检测到的框架、组件）
+type sbomDocument struct {
+	Name     string
+	Packages []*sbomPackage
+	Roots    []string
+}
+
+// scopeRanks 依赖作用域的强弱，合并同一软件包的多条记录时保留最强的作用域
+var scopeRanks = map[string]int{
+	model.ScopeDev:       1,
+	model.ScopeBuild:     2,
+	model.ScopeRuntime:   3,
+	model.ScopeFramework: 3,
+}
+
+// buildSBOM 将报告中的依赖记录与框架、组件整理为软件包列表。
+// 同一个包已有锁定版本时忽略清单中未锁定的声明；SDK 级别的记录（如 Go 版本、minSdk）不是软件包，被忽略
+func buildSBOM(report *model.CanvasReport) *sbomDocument {
+	doc := &sbomDocument{Name: sbomName(report)}
+	byKey := make(map[string]*sbomPackage)
+	add := func(pkg *sbomPackage) *sbomPackage {
+		key := strings.ToLower(pkg.Ref)
+		if existing, ok := byKey[key]; ok {
+			return existing
+		}
+		byKey[key] = pkg
+		doc.Packages = append(doc.Packages, pkg)
+		return pkg
+	}
+
+	locked := make(map[string]bool)
+	for _, dep := range report.Dependencies {
+		if dep.Locked {
+			locked[strings.ToLower(packageURL(dep.Ecosystem, dep.Name, ""))] = true
+		}
+	}
+
+	// 依赖关系按锁文件内的包名称解析为引用，同一锁文件中同名的包取第一条（npm 中为顶层的副本）
+	manifestRefs := make(map[string]map[string]string)
+	type pending struct {
+		pkg      *sbomPackage
+		manifest string
+		names    []string
+	}
+	var requires []pending
+	for _, dep := range report.Dependencies {
+		if dep.Scope == model.ScopeSDK {
+			continue
+		}
+		if !dep.Locked && locked[strings.ToLower(packageURL(dep.Ecosystem, dep.Name, ""))] {
+			continue
+		}
+		pkgVersion, constraint := dep.Version, ""
+		if !dep.Locked {
+			info := version.Resolve(dep.Version, version.SchemeForEcosystem(dep.Ecosystem))
+			pkgVersion, constraint = info.Version, info.Constraint
+		}
+		purl := packageURL(dep.Ecosystem, dep.Name, pkgVersion)
+		group, name := sbomNames(dep.Ecosystem, dep.Name)
+		pkg := add(&sbomPackage{
+			Ref:        purl,
+			Purl:       purl,
+			Ecosystem:  dep.Ecosystem,
+			Package:    dep.Name,
+			Group:      group,
+			Name:       name,
+			Version:    pkgVersion,
+			Constraint: constraint,
+			Scope:      dep.Scope,
+		})
+		if scopeRanks[dep.Scope] > scopeRanks[pkg.Scope] {
+			pkg.Scope = dep.Scope
+		}
+		pkg.Direct = pkg.Direct || dep.Direct
+		pkg.Locked = pkg.Locked || dep.Locked
+		if pkg.Usage == "" {
+			pkg.Usage = dep.Usage
+		}
+		if pkg.License == "" {
+			pkg.License = dep.License
+		}
+		if pkg.Checksum == "" {
+			pkg.Checksum = dep.Checksum
+		}
+		pkg.Paths = appendUnique(pkg.Paths, dep.Manifest)
+
+		if manifestRefs[dep.Manifest] == nil {
+			manifestRefs[dep.Manifest] = make(map[string]string)
+		}
+		if _, ok := manifestRefs[dep.Manifest][strings.ToLower(dep.Name)]; !ok {
+			manifestRefs[dep.Manifest][strings.ToLower(dep.Name)] = pkg.Ref
+		}
+		if len(dep.Requires) > 0 {
+			requires = append(requires, pending{pkg: pkg, manifest: dep.Manifest, names: dep.Requires})
+		}
+	}
+	for _, r := range requires {
+		for _, name := range r.names {
+			if ref, ok := manifestRefs[r.manifest][strings.ToLower(name)]; ok && ref != r.pkg.Ref {
+				r.pkg.Requires = appendUnique(r.pkg.Requires, ref)
+			}
+		}
+	}
+
+	// 框架与组件按提供版本的依赖包合并到对应的软件包，没有依赖包的检测项单独成为软件包
+	for _, items := range [][]model.DetectedItem{report.Detection.Frameworks, report.Detection.Components} {
+		for _, item := range items {
+			for _, occurrence := range versionOccurrences(item) {
+				var pkg *sbomPackage
+				if item.Ecosystem != "" && item.Package != "" {
+					purl := packageURL(item.Ecosystem, item.Package, occurrence.Version)
+					group, name := sbomNames(item.Ecosystem, item.Package)
+					pkg = add(&sbomPackage{Ref: purl, Purl: purl, Ecosystem: item.Ecosystem, Package: item.Package, Group: group, Name: name, Version: occurrence.Version, Scope: model.ScopeRuntime})
+				} else {
+					ref := "codecanvas:" + item.Type + ":" + item.Name
+					if occurrence.Version != "" {
+						ref += "@" + occurrence.Version
+					}
+					pkg = add(&sbomPackage{Ref: ref, Package: item.Name, Name: item.Name, Version: occurrence.Version, Scope: model.ScopeRuntime})
+				}
+				if !slices.ContainsFunc(pkg.Items, func(existing model.DetectedItem) bool { return existing.Name == item.Name }) {
+					pkg.Items = append(pkg.Items, item)
+				}
+				if occurrence.Path != "" {
+					pkg.Paths = appendUnique(pkg.Paths, occurrence.Path)
+				}
+			}
+		}
+	}
+
+	sort.Slice(doc.Packages, func(i, j int) bool {
+		return doc.Packages[i].Ref < doc.Packages[j].Ref
+	})
+	for _, pkg := range doc.Packages {
+		sort.Strings(pkg.Paths)
+		sort.Strings(pkg.Requires)
+		if pkg.Direct || len(pkg.Items) > 0 {
+			doc.Roots = append(doc.Roots, pkg.Ref)
+		}
+	}
+	return doc
+}
+
+// sbomName 返回被分析项目的名称：镜像引用、git 仓库目录名或源码目录名
+func sbomName(report *model.CanvasReport) string {
+	switch {
+	case report.Image != nil && report.Image.Reference != "":
+		return report.Image.Reference
+	case report.Git != nil && report.Git.Repository != "":
+		return filepath.Base(report.Git.Repository)
+	case report.CodeProfile.Path != "":
+		return filepath.Base(report.CodeProfile.Path)
+	}
+	return "project"
+}
+
+// sbomNames 返回软件包的分组与名称：与 purl 的命名空间与名称相同，Go 模块的名称为完整的模块路径
+func sbomNames(ecosystem, name string) (group, pkgName string) {
+	purlType, namespace, pkgName := purlParts(ecosystem, name)
+	if purlType == "golang" {
+		return "", name
+	}
+	return namespace, pkgName
+}
+
+// purlParts 返回依赖在 purl 中的类型、命名空间与名称，没有对应 purl 类型的生态使用 generic
+func purlParts(ecosystem, name string) (purlType, namespace, pkgName string) {
+	switch ecosystem {
+	case model.EcosystemMaven, model.EcosystemGradle, model.EcosystemAndroid:
+		if group, artifact, ok := strings.Cut(name, ":"); ok {
+			return "maven", group, artifact
+		}
+		if ecosystem != model.EcosystemAndroid {
+			return "maven", "", name
+		}
+	case model.EcosystemNpm:
+		if scope, rest, ok := strings.Cut(name, "/"); ok && strings.HasPrefix(scope, "@") {
+			return "npm", scope, rest
+		}
+		return "npm", "", name
+	case model.EcosystemGo:
+		if i := strings.LastIndex(name, "/"); i > 0 {
+			return "golang", name[:i], name[i+1:]
+		}
+		return "golang", "", name
+	case model.EcosystemPyPI:
+		return "pypi", "", strings.ReplaceAll(strings.ToLower(name), "_", "-")
+	case model.EcosystemComposer:
+		if vendor, rest, ok := strings.Cut(name, "/"); ok {
+			return "composer", vendor, rest
+		}
+		return "composer", "", name
+	case model.EcosystemCargo:
+		return "cargo", "", name
+	case model.EcosystemRubyGems:
+		return "gem", "", name
+	case model.EcosystemHex:
+		return "hex", "", name
+	case model.EcosystemNuGet:
+		return "nuget", "", name
+	case model.EcosystemPub:
+		return "pub", "", name
+	case model.EcosystemConan:
+		return "conan", "", name
+	case model.EcosystemCocoaPods:
+		return "cocoapods", "", name
+	case model.EcosystemDeb:
+		return "deb", "debian", name
+	case model.EcosystemApk:
+		return "apk", "alpine", name
+	}
+	return "generic", "", name
+}
+
+// packageURL 生成依赖的 purl（pkg:type/namespace/name@version），Go 模块的版本补回 v 前缀
+func packageURL(ecosystem, name, pkgVersion string) string {
+	purlType, namespace, pkgName := purlParts(ecosystem, name)
+	var b strings.Builder
+	b.WriteString("pkg:" + purlType + "/")
+	if namespace != "" {
+		for _, segment := range strings.Split(namespace, "/") {
+			b.WriteString(purlEscape(segment) + "/")
+		}
+	}
+	b.WriteString(purlEscape(pkgName))
+	if pkgVersion != "" {
+		if purlType == "golang" && pkgVersion[0] >= '0' && pkgVersion[0] <= '9' {
+			pkgVersion = "v" + pkgVersion
+		}
+		b.WriteString("@" + purlEscape(pkgVersion))
+	}
+	return b.String()
+}
+
+// purlEscape 按 purl 规范对命名空间、名称与版本进行百分号编码，只保留字母、数字与 ".-_~"
+func purlEscape(s string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte(".-_~", c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&15])
+	}
+	return b.String()
+}
+
+// appendUnique 追加列表中尚不存在的非空值
+func appendUnique(values []string, value string) []string {
+	if value == "" || slices.Contains(values, value) {
+		return values
+	}
+	return append(values, value)
+}
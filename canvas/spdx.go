@@ -0,0 +1,321 @@
+package canvas
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// SPDX 文档的输出格式
+const (
+	FormatSPDXJSON     = "spdx-json"
+	FormatSPDXTagValue = "spdx-tag-value"
+)
+
+// spdxNoAssertion 未知或无法断言的字段值
+const spdxNoAssertion = "NOASSERTION"
+
+var (
+	// spdxIDInvalidRe 匹配 SPDXID 中不允许的字符
+	spdxIDInvalidRe = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+	// spdxLicenseIDRe 匹配许可证表达式中的单个许可证标识符
+	spdxLicenseIDRe = regexp.MustCompile(`^(LicenseRef-)?[A-Za-z0-9][A-Za-z0-9.-]*\+?$`)
+)
+
+// spdxChecksumAlgorithms 依赖摘要的算法名称到 SPDX 算法名称的映射
+var spdxChecksumAlgorithms = map[string]string{
+	"md5":    "MD5",
+	"sha1":   "SHA1",
+	"sha256": "SHA256",
+	"sha384": "SHA384",
+	"sha512": "SHA512",
+}
+
+// spdxDocument SPDX 2.3 文档，JSON 字段名称与官方 JSON Schema 一致
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name                  string            `json:"name"`
+	SPDXID                string            `json:"SPDXID"`
+	VersionInfo           string            `json:"versionInfo,omitempty"`
+	DownloadLocation      string            `json:"downloadLocation"`
+	FilesAnalyzed         bool              `json:"filesAnalyzed"`
+	Checksums             []spdxChecksum    `json:"checksums,omitempty"`
+	LicenseConcluded      string            `json:"licenseConcluded"`
+	LicenseDeclared       string            `json:"licenseDeclared"`
+	CopyrightText         string            `json:"copyrightText"`
+	ExternalRefs          []spdxExternalRef `json:"externalRefs,omitempty"`
+	PrimaryPackagePurpose string            `json:"primaryPackagePurpose,omitempty"`
+	Comment               string            `json:"comment,omitempty"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// WriteSPDX 将分析报告写为 SPDX 2.3 文档，format 为 FormatSPDXJSON 或 FormatSPDXTagValue。
+// 文档描述被分析的项目，项目依赖直接声明的包与检测到的框架、组件，包之间的依赖关系来自锁文件。
+// SPDXID 与文档命名空间由包的 purl 与内容摘要生成，内容相同的扫描得到相同的标识
+func WriteSPDX(w io.Writer, report *model.CanvasReport, format string) error {
+	doc := buildSPDX(report)
+	switch format {
+	case FormatSPDXJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(doc)
+	case FormatSPDXTagValue:
+		return writeSPDXTagValue(w, doc)
+	}
+	return fmt.Errorf("unsupported SPDX format: %s", format)
+}
+
+// buildSPDX 生成 SPDX 文档
+func buildSPDX(report *model.CanvasReport) *spdxDocument {
+	sbom := buildSBOM(report)
+	timestamp := report.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	creator := "Tool: codecanvas"
+	if report.Version != "" {
+		creator += "-" + report.Version
+	}
+
+	root := spdxPackage{
+		Name:                  sbom.Name,
+		SPDXID:                "SPDXRef-Project",
+		DownloadLocation:      spdxNoAssertion,
+		LicenseConcluded:      spdxNoAssertion,
+		LicenseDeclared:       spdxNoAssertion,
+		CopyrightText:         spdxNoAssertion,
+		PrimaryPackagePurpose: "APPLICATION",
+	}
+	if report.Git != nil {
+		root.VersionInfo = report.Git.Commit
+	}
+	doc := &spdxDocument{
+		SPDXVersion:  "SPDX-2.3",
+		DataLicense:  "CC0-1.0",
+		SPDXID:       "SPDXRef-DOCUMENT",
+		Name:         sbom.Name,
+		CreationInfo: spdxCreationInfo{Created: timestamp.UTC().Format("2006-01-02T15:04:05Z"), Creators: []string{creator}},
+		Packages:     []spdxPackage{root},
+		Relationships: []spdxRelationship{
+			{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: root.SPDXID},
+		},
+	}
+
+	ids := make(map[string]string)
+	for _, pkg := range sbom.Packages {
+		ids[pkg.Ref] = spdxPackageID(pkg)
+	}
+	// 文档命名空间由项目名称与全部包的引用、许可证与摘要生成
+	content := sha256.New()
+	fmt.Fprintf(content, "%s\n", sbom.Name)
+	for _, pkg := range sbom.Packages {
+		spdxPkg := spdxPackageFor(pkg, ids[pkg.Ref])
+		doc.Packages = append(doc.Packages, spdxPkg)
+		fmt.Fprintf(content, "%s\x00%s\x00%s\x00%s\n", pkg.Ref, spdxPkg.LicenseDeclared, pkg.Checksum, strings.Join(pkg.Requires, ","))
+	}
+	for _, ref := range sbom.Roots {
+		doc.Relationships = append(doc.Relationships, spdxRelationship{SPDXElementID: root.SPDXID, RelationshipType: "DEPENDS_ON", RelatedSPDXElement: ids[ref]})
+	}
+	for _, pkg := range sbom.Packages {
+		for _, ref := range pkg.Requires {
+			doc.Relationships = append(doc.Relationships, spdxRelationship{SPDXElementID: ids[pkg.Ref], RelationshipType: "DEPENDS_ON", RelatedSPDXElement: ids[ref]})
+		}
+	}
+	doc.DocumentNamespace = "https://github.com/winezer0/codecanvas/spdxdocs/" + url.PathEscape(sbom.Name) + "-" + hex.EncodeToString(content.Sum(nil))[:16]
+	return doc
+}
+
+// spdxPackageFor 将软件包转换为 SPDX 包，带有 purl 与 CPE 外部引用、声明的许可证与摘要
+func spdxPackageFor(pkg *sbomPackage, id string) spdxPackage {
+	spdxPkg := spdxPackage{
+		Name:                  pkg.Package,
+		SPDXID:                id,
+		VersionInfo:           pkg.Version,
+		DownloadLocation:      spdxNoAssertion,
+		LicenseConcluded:      spdxNoAssertion,
+		LicenseDeclared:       spdxLicense(pkg.License),
+		CopyrightText:         spdxNoAssertion,
+		PrimaryPackagePurpose: "LIBRARY",
+	}
+	if algorithm, value, ok := strings.Cut(pkg.Checksum, ":"); ok && spdxChecksumAlgorithms[algorithm] != "" {
+		spdxPkg.Checksums = []spdxChecksum{{Algorithm: spdxChecksumAlgorithms[algorithm], ChecksumValue: value}}
+	}
+	if pkg.Purl != "" {
+		spdxPkg.ExternalRefs = append(spdxPkg.ExternalRefs, spdxExternalRef{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: pkg.Purl})
+	}
+	if cpe := packageCPE(pkg); cpe != "" {
+		spdxPkg.ExternalRefs = append(spdxPkg.ExternalRefs, spdxExternalRef{ReferenceCategory: "SECURITY", ReferenceType: "cpe23Type", ReferenceLocator: cpe})
+	}
+
+	var comments []string
+	for _, item := range pkg.Items {
+		if item.Type == model.RuleTypeFramework {
+			spdxPkg.PrimaryPackagePurpose = "FRAMEWORK"
+		}
+		comments = append(comments, fmt.Sprintf("Detected %s %s (%s, %s)", item.Type, item.Name, item.Category, item.Language))
+	}
+	if pkg.Scope != "" {
+		comments = append(comments, "Dependency scope: "+pkg.Scope)
+	}
+	if pkg.Constraint != "" {
+		comments = append(comments, "Declared constraint: "+pkg.Constraint)
+	}
+	if pkg.License != "" && spdxPkg.LicenseDeclared == spdxNoAssertion {
+		comments = append(comments, "Declared license: "+pkg.License)
+	}
+	spdxPkg.Comment = strings.Join(comments, "; ")
+	return spdxPkg
+}
+
+// spdxPackageID 生成稳定的 SPDXID：包名称中不允许的字符替换为 "-"，并附加引用摘要的前 8 位以保证唯一
+func spdxPackageID(pkg *sbomPackage) string {
+	sum := sha256.Sum256([]byte(pkg.Ref))
+	name := strings.Trim(spdxIDInvalidRe.ReplaceAllString(pkg.Package, "-"), "-")
+	return "SPDXRef-Package-" + name + "-" + hex.EncodeToString(sum[:4])
+}
+
+// spdxLicense 返回声明的许可证表达式，不是有效的 SPDX 表达式（如 "SEE LICENSE IN ..."、"UNLICENSED"）时返回 NOASSERTION
+func spdxLicense(license string) string {
+	license = strings.TrimSpace(license)
+	if license == "" {
+		return spdxNoAssertion
+	}
+	// 去掉括号后许可证标识符与 AND/OR/WITH 运算符应交替出现，且以标识符开头和结尾
+	fields := strings.Fields(strings.NewReplacer("(", " ", ")", " ").Replace(license))
+	if len(fields)%2 == 0 {
+		return spdxNoAssertion
+	}
+	for i, field := range fields {
+		operator := field == "AND" || field == "OR" || field == "WITH"
+		if operator != (i%2 == 1) || field == "UNLICENSED" || !operator && !spdxLicenseIDRe.MatchString(field) {
+			return spdxNoAssertion
+		}
+	}
+	return license
+}
+
+// packageCPE 按常见的命名习惯生成软件包的 CPE 2.3 名称，厂商取 Maven groupId 的第二段、npm 作用域、
+// Composer 厂商或 Go 模块路径中的所有者。厂商只从 purl 命名空间中确定，没有命名空间或版本未知时返回空字符串
+func packageCPE(pkg *sbomPackage) string {
+	if pkg.Version == "" || pkg.Ecosystem == "" {
+		return ""
+	}
+	purlType, namespace, product := purlParts(pkg.Ecosystem, pkg.Package)
+	var vendor string
+	switch purlType {
+	case "maven":
+		if segments := strings.Split(namespace, "."); len(segments) > 1 {
+			vendor = segments[1]
+		} else {
+			vendor = namespace
+		}
+	case "npm", "composer":
+		vendor = strings.TrimPrefix(namespace, "@")
+	case "golang":
+		if segments := strings.Split(namespace, "/"); len(segments) > 1 {
+			vendor = segments[1]
+		}
+	}
+	if vendor == "" {
+		return ""
+	}
+	return "cpe:2.3:a:" + cpeEscape(vendor) + ":" + cpeEscape(product) + ":" + cpeEscape(pkg.Version) + ":*:*:*:*:*:*:*"
+}
+
+// cpeEscape 按 CPE 2.3 格式化字符串的规则转为小写并用反斜杠转义字母、数字、"_"、"-" 与 "." 以外的字符
+func cpeEscape(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if !('a' <= r && r <= 'z' || '0' <= r && r <= '9' || r == '_' || r == '-' || r == '.') {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// writeSPDXTagValue 以 tag-value 格式写出 SPDX 文档
+func writeSPDXTagValue(w io.Writer, doc *spdxDocument) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "SPDXVersion: %s\n", doc.SPDXVersion)
+	fmt.Fprintf(bw, "DataLicense: %s\n", doc.DataLicense)
+	fmt.Fprintf(bw, "SPDXID: %s\n", doc.SPDXID)
+	fmt.Fprintf(bw, "DocumentName: %s\n", doc.Name)
+	fmt.Fprintf(bw, "DocumentNamespace: %s\n", doc.DocumentNamespace)
+	for _, creator := range doc.CreationInfo.Creators {
+		fmt.Fprintf(bw, "Creator: %s\n", creator)
+	}
+	fmt.Fprintf(bw, "Created: %s\n", doc.CreationInfo.Created)
+
+	for _, pkg := range doc.Packages {
+		fmt.Fprintf(bw, "\nPackageName: %s\n", pkg.Name)
+		fmt.Fprintf(bw, "SPDXID: %s\n", pkg.SPDXID)
+		if pkg.VersionInfo != "" {
+			fmt.Fprintf(bw, "PackageVersion: %s\n", pkg.VersionInfo)
+		}
+		fmt.Fprintf(bw, "PackageDownloadLocation: %s\n", pkg.DownloadLocation)
+		fmt.Fprintf(bw, "FilesAnalyzed: %t\n", pkg.FilesAnalyzed)
+		for _, checksum := range pkg.Checksums {
+			fmt.Fprintf(bw, "PackageChecksum: %s: %s\n", checksum.Algorithm, checksum.ChecksumValue)
+		}
+		fmt.Fprintf(bw, "PackageLicenseConcluded: %s\n", pkg.LicenseConcluded)
+		fmt.Fprintf(bw, "PackageLicenseDeclared: %s\n", pkg.LicenseDeclared)
+		fmt.Fprintf(bw, "PackageCopyrightText: %s\n", pkg.CopyrightText)
+		for _, ref := range pkg.ExternalRefs {
+			fmt.Fprintf(bw, "ExternalRef: %s %s %s\n", ref.ReferenceCategory, ref.ReferenceType, ref.ReferenceLocator)
+		}
+		if pkg.PrimaryPackagePurpose != "" {
+			fmt.Fprintf(bw, "PrimaryPackagePurpose: %s\n", pkg.PrimaryPackagePurpose)
+		}
+		if pkg.Comment != "" {
+			fmt.Fprintf(bw, "PackageComment: <text>%s</text>\n", pkg.Comment)
+		}
+	}
+
+	bw.WriteString("\n")
+	for _, rel := range doc.Relationships {
+		fmt.Fprintf(bw, "Relationship: %s %s %s\n", rel.SPDXElementID, rel.RelationshipType, rel.RelatedSPDXElement)
+	}
+	return bw.Flush()
+}
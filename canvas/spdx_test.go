@@ -0,0 +1,150 @@
+package canvas
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestWriteSPDXJSON(t *testing.T) {
+	var outputs [2][]byte
+	for i := range outputs {
+		report, err := AnalyzeFS(sbomTestFS, "app", Options{})
+		if err != nil {
+			t.Fatalf("AnalyzeFS 失败: %v", err)
+		}
+		report.Timestamp = time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+		var buf bytes.Buffer
+		if err := WriteSPDX(&buf, report, FormatSPDXJSON); err != nil {
+			t.Fatalf("WriteSPDX 失败: %v", err)
+		}
+		outputs[i] = buf.Bytes()
+	}
+	if !bytes.Equal(outputs[0], outputs[1]) {
+		t.Errorf("重复扫描应生成相同的文档")
+	}
+
+	var doc spdxDocument
+	if err := json.Unmarshal(outputs[0], &doc); err != nil {
+		t.Fatalf("输出不是有效的 JSON: %v", err)
+	}
+	if doc.SPDXVersion != "SPDX-2.3" || doc.DataLicense != "CC0-1.0" || doc.CreationInfo.Created != "2024-05-01T08:00:00Z" ||
+		!strings.HasPrefix(doc.DocumentNamespace, "https://") {
+		t.Errorf("文档信息错误: %+v", doc)
+	}
+
+	packages := make(map[string]spdxPackage)
+	ids := make(map[string]bool)
+	for _, pkg := range doc.Packages {
+		packages[pkg.Name] = pkg
+		if ids[pkg.SPDXID] || !regexp.MustCompile(`^SPDXRef-[A-Za-z0-9.-]+$`).MatchString(pkg.SPDXID) {
+			t.Errorf("SPDXID 重复或包含无效字符: %s", pkg.SPDXID)
+		}
+		ids[pkg.SPDXID] = true
+	}
+	if len(doc.Packages) != 6 {
+		t.Errorf("应有项目自身与 5 个依赖包，实际为 %d", len(doc.Packages))
+	}
+	react := packages["react"]
+	if react.VersionInfo != "18.2.0" || react.LicenseDeclared != "MIT" || len(react.Checksums) != 1 ||
+		react.Checksums[0].Algorithm != "SHA512" || !strings.HasPrefix(react.Checksums[0].ChecksumValue, "ff722331d6f62fd4") {
+		t.Errorf("react 包错误: %+v", react)
+	}
+	// 没有命名空间的包无法确定厂商，不生成 CPE
+	wantRefs := []spdxExternalRef{
+		{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:npm/react@18.2.0"},
+	}
+	if !slices.Equal(react.ExternalRefs, wantRefs) {
+		t.Errorf("react 的外部引用错误: %+v", react.ExternalRefs)
+	}
+	wantRefs = []spdxExternalRef{
+		{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:npm/%40babel/runtime@7.23.2"},
+		{ReferenceCategory: "SECURITY", ReferenceType: "cpe23Type", ReferenceLocator: "cpe:2.3:a:babel:runtime:7.23.2:*:*:*:*:*:*:*"},
+	}
+	if babel := packages["@babel/runtime"]; !slices.Equal(babel.ExternalRefs, wantRefs) {
+		t.Errorf("@babel/runtime 的外部引用错误: %+v", babel.ExternalRefs)
+	}
+	if gin := packages["github.com/gin-gonic/gin"]; gin.PrimaryPackagePurpose != "FRAMEWORK" || gin.LicenseDeclared != spdxNoAssertion {
+		t.Errorf("gin 包错误: %+v", gin)
+	}
+
+	relationships := make(map[spdxRelationship]bool)
+	for _, rel := range doc.Relationships {
+		relationships[rel] = true
+	}
+	for _, want := range []spdxRelationship{
+		{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: "SPDXRef-Project"},
+		{SPDXElementID: "SPDXRef-Project", RelationshipType: "DEPENDS_ON", RelatedSPDXElement: react.SPDXID},
+		{SPDXElementID: react.SPDXID, RelationshipType: "DEPENDS_ON", RelatedSPDXElement: packages["loose-envify"].SPDXID},
+	} {
+		if !relationships[want] {
+			t.Errorf("缺少关系 %+v", want)
+		}
+	}
+}
+
+func TestWriteSPDXTagValue(t *testing.T) {
+	report, err := AnalyzeFS(sbomTestFS, "app", Options{})
+	if err != nil {
+		t.Fatalf("AnalyzeFS 失败: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := WriteSPDX(&buf, report, FormatSPDXTagValue); err != nil {
+		t.Fatalf("WriteSPDX 失败: %v", err)
+	}
+	output := buf.String()
+	for _, want := range []string{
+		"SPDXVersion: SPDX-2.3\n",
+		"Relationship: SPDXRef-DOCUMENT DESCRIBES SPDXRef-Project\n",
+		"PackageName: @babel/runtime\nSPDXID: SPDXRef-Package-babel-runtime-",
+		"PackageLicenseDeclared: MIT\n",
+		"PackageChecksum: SHA512: ff722331d6f62fd4",
+		"ExternalRef: PACKAGE-MANAGER purl pkg:golang/github.com/gin-gonic/gin@v1.9.0\n",
+		"PackageComment: <text>Detected framework Gin (backend, Go); Dependency scope: runtime</text>\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("tag-value 输出缺少 %q", want)
+		}
+	}
+}
+
+func TestSPDXLicenseAndCPE(t *testing.T) {
+	for license, want := range map[string]string{
+		"MIT":                 "MIT",
+		"(MIT OR Apache-2.0)": "(MIT OR Apache-2.0)",
+		"GPL-2.0-or-later WITH Classpath-exception-2.0": "GPL-2.0-or-later WITH Classpath-exception-2.0",
+		"SEE LICENSE IN LICENSE.txt":                    spdxNoAssertion,
+		"UNLICENSED":                                    spdxNoAssertion,
+		"MIT OR":                                        spdxNoAssertion,
+		"":                                              spdxNoAssertion,
+	} {
+		if got := spdxLicense(license); got != want {
+			t.Errorf("spdxLicense(%q) = %q，期望 %q", license, got, want)
+		}
+	}
+
+	tests := []struct {
+		pkg  sbomPackage
+		want string
+	}{
+		{sbomPackage{Ecosystem: model.EcosystemMaven, Package: "org.apache.logging.log4j:log4j-core", Version: "2.14.1"}, "cpe:2.3:a:apache:log4j-core:2.14.1:*:*:*:*:*:*:*"},
+		{sbomPackage{Ecosystem: model.EcosystemComposer, Package: "laravel/framework", Version: "10.48.4"}, "cpe:2.3:a:laravel:framework:10.48.4:*:*:*:*:*:*:*"},
+		{sbomPackage{Ecosystem: model.EcosystemGo, Package: "github.com/docker/docker", Version: "24.0.7+incompatible"}, "cpe:2.3:a:docker:docker:24.0.7\\+incompatible:*:*:*:*:*:*:*"},
+		{sbomPackage{Ecosystem: model.EcosystemNpm, Package: "@angular/core", Version: "17.0.0"}, "cpe:2.3:a:angular:core:17.0.0:*:*:*:*:*:*:*"},
+		{sbomPackage{Ecosystem: model.EcosystemNpm, Package: "react", Version: "18.2.0"}, ""},
+		{sbomPackage{Ecosystem: model.EcosystemPyPI, Package: "requests", Version: "2.31.0"}, ""},
+		{sbomPackage{Package: "Spring Boot", Version: "3.2.0"}, ""},
+		{sbomPackage{Ecosystem: model.EcosystemNpm, Package: "react"}, ""},
+	}
+	for _, tt := range tests {
+		if got := packageCPE(&tt.pkg); got != tt.want {
+			t.Errorf("packageCPE(%s) = %s，期望 %s", tt.pkg.Package, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package canvas
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// languageCategories 返回语言名称到其所属应用类别的映射，同一语言可以属于多个类别
+func languageCategories(profile model.CodeProfile) map[string][]string {
+	categories := make(map[string][]string)
+	for _, section := range []struct {
+		category string
+		langs    []string
+	}{
+		{model.CategoryFrontend, profile.FrontendLanguages},
+		{model.CategoryBackend, profile.BackendLanguages},
+		{model.CategoryDesktop, profile.DesktopLanguages},
+		{model.CategoryMobile, profile.MobileLanguages},
+		{model.CategoryOther, profile.OtherLanguages},
+	} {
+		for _, lang := range section.langs {
+			categories[lang] = append(categories[lang], section.category)
+		}
+	}
+	return categories
+}
+
+// WriteCSV 以 CSV 格式写出语言、框架与组件三张表：table 列为 language、framework 或 component，
+// 语言行填写文件数与行数，框架与组件行填写版本、提供版本的依赖包与检测依据
+func WriteCSV(w io.Writer, report *model.CanvasReport) error {
+	writer := csv.NewWriter(w)
+	header := []string{"table", "name", "category", "language", "version", "ecosystem", "package",
+		"files", "code_lines", "comment_lines", "blank_lines", "evidence"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	categories := languageCategories(report.CodeProfile)
+	for _, lang := range report.CodeProfile.LanguageInfos {
+		row := []string{"language", lang.Name, strings.Join(categories[lang.Name], " "), "", "", "", "",
+			strconv.Itoa(lang.Files), strconv.Itoa(lang.CodeLines), strconv.Itoa(lang.CommentLines), strconv.Itoa(lang.BlankLines), ""}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	for _, items := range [][]model.DetectedItem{report.Detection.Frameworks, report.Detection.Components} {
+		for _, item := range items {
+			row := []string{item.Type, item.Name, item.Category, item.Language, item.Version, item.Ecosystem, item.Package,
+				"", "", "", "", item.Evidence}
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteMarkdown 以 Markdown 格式写出分析报告：概览、语言、框架、组件与依赖的表格，
+// 以及存在时的漏洞、策略违规与子项目
+func WriteMarkdown(w io.Writer, report *model.CanvasReport) error {
+	var b strings.Builder
+	b.WriteString("# CodeCanvas Analysis Report\n\n")
+	fmt.Fprintf(&b, "- Path: `%s`\n", report.CodeProfile.Path)
+	if image := report.Image; image != nil {
+		fmt.Fprintf(&b, "- Image: `%s` (%s, %d layers, %s/%s)\n", image.Reference, image.Format, image.Layers, image.OS, image.Architecture)
+	}
+	if git := report.Git; git != nil {
+		fmt.Fprintf(&b, "- Git: `%s` %s\n", git.Commit, git.Date.Format(time.RFC3339))
+	}
+	fmt.Fprintf(&b, "- Total files: %d\n", report.CodeProfile.TotalFiles)
+	fmt.Fprintf(&b, "- Total lines: %d\n", report.CodeProfile.TotalLines)
+	if len(report.Detection.BuildSystems) > 0 {
+		fmt.Fprintf(&b, "- Build systems: %s\n", strings.Join(report.Detection.BuildSystems, ", "))
+	}
+	fmt.Fprintf(&b, "- Generated: %s\n", report.Timestamp.Format(time.RFC3339))
+
+	if len(report.CodeProfile.LanguageInfos) > 0 {
+		categories := languageCategories(report.CodeProfile)
+		b.WriteString("\n## Languages\n\n")
+		b.WriteString("| Language | Category | Files | Code | Comment | Blank |\n")
+		b.WriteString("| --- | --- | ---: | ---: | ---: | ---: |\n")
+		for _, lang := range report.CodeProfile.LanguageInfos {
+			fmt.Fprintf(&b, "| %s | %s | %d | %d | %d | %d |\n", markdownCell(lang.Name), strings.Join(categories[lang.Name], ", "),
+				lang.Files, lang.CodeLines, lang.CommentLines, lang.BlankLines)
+		}
+	}
+
+	for _, section := range []struct {
+		title string
+		items []model.DetectedItem
+	}{{"Frameworks", report.Detection.Frameworks}, {"Components", report.Detection.Components}} {
+		fmt.Fprintf(&b, "\n## %s\n\n", section.title)
+		if len(section.items) == 0 {
+			b.WriteString("None detected.\n")
+			continue
+		}
+		b.WriteString("| Name | Category | Language | Version | Location | Evidence |\n")
+		b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+		for _, item := range section.items {
+			var locations []string
+			for _, occurrence := range item.Occurrences {
+				locations = append(locations, locationLabel(occurrence.Path, occurrence.Line))
+			}
+			itemVersion := item.Version
+			if itemVersion == "" && item.UnresolvedVersion != "" {
+				itemVersion = "unresolved " + item.UnresolvedVersion
+			}
+			name := markdownCell(item.Name)
+			if len(item.Vulnerabilities) > 0 {
+				name += fmt.Sprintf(" (%d vulnerabilities)", len(item.Vulnerabilities))
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n", name, markdownCell(item.Category), markdownCell(item.Language),
+				markdownCell(itemVersion), markdownCell(strings.Join(locations, ", ")), markdownCell(item.Evidence))
+		}
+	}
+
+	if len(report.Dependencies) > 0 {
+		fmt.Fprintf(&b, "\n## Dependencies\n\n%d dependencies.\n\n", len(report.Dependencies))
+		b.WriteString("| Ecosystem | Total | Direct |\n")
+		b.WriteString("| --- | ---: | ---: |\n")
+		for _, count := range dependencyCounts(report.Dependencies) {
+			fmt.Fprintf(&b, "| %s | %d | %d |\n", markdownCell(count.ecosystem), count.total, count.direct)
+		}
+	}
+
+	if summary := report.Vulnerabilities; summary != nil {
+		fmt.Fprintf(&b, "\n## Vulnerabilities\n\n%d advisories matched (%d loaded).\n", summary.Total, summary.Advisories)
+		if len(summary.Dependencies) > 0 {
+			b.WriteString("\n| Package | Version | Manifest | Advisory | Severity | Fixed |\n")
+			b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+			for _, dep := range summary.Dependencies {
+				for _, vuln := range dep.Vulnerabilities {
+					fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n", markdownCell(dep.Ecosystem+"/"+dep.Name), markdownCell(dep.Version),
+						markdownCell(locationLabel(dep.Manifest, dep.Line)), markdownCell(vuln.ID), vuln.Severity, markdownCell(strings.Join(vuln.Fixed, ", ")))
+				}
+			}
+		}
+	}
+
+	if len(report.PolicyViolations) > 0 {
+		b.WriteString("\n## Policy Violations\n\n")
+		b.WriteString("| Severity | Rule | Message | Location |\n")
+		b.WriteString("| --- | --- | --- | --- |\n")
+		for _, violation := range report.PolicyViolations {
+			location := ""
+			if violation.Path != "" {
+				location = locationLabel(violation.Path, violation.Line)
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", violation.Severity, violation.Rule, markdownCell(violation.Message), markdownCell(location))
+		}
+	}
+
+	if len(report.Projects) > 0 {
+		b.WriteString("\n## Projects\n\n")
+		writeMarkdownProjects(&b, report.Projects, 0)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeMarkdownProjects 以嵌套列表写出项目树
+func writeMarkdownProjects(b *strings.Builder, projects []model.ProjectReport, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, project := range projects {
+		fmt.Fprintf(b, "%s- **%s** `%s` (%s)", indent, markdownCell(project.Name), project.Path, strings.Join(project.Kinds, ", "))
+		if frameworks := itemNames(project.Detection.Frameworks); len(frameworks) > 0 {
+			fmt.Fprintf(b, ": %s", markdownCell(strings.Join(frameworks, ", ")))
+		}
+		b.WriteString("\n")
+		writeMarkdownProjects(b, project.Children, depth+1)
+	}
+}
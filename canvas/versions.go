@@ -0,0 +1,96 @@
+package canvas
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/winezer0/codecanvas/internal/model"
+	"github.com/winezer0/codecanvas/internal/version"
+)
+
+// itemVersion 某一版本号及使用该版本的模块
+type itemVersion struct {
+	version string
+	modules []string
+}
+
+// distinctVersions 返回检测项在各模块中出现的去重版本（按版本号升序），
+// 规范化后相同的版本（如 1.2 与 1.2.0）视为同一版本，没有出现位置时使用检测项的版本
+func distinctVersions(item model.DetectedItem) []itemVersion {
+	occurrences := item.Occurrences
+	if len(occurrences) == 0 && item.Version != "" {
+		occurrences = []model.Occurrence{{Module: ".", Version: item.Version}}
+	}
+
+	byKey := make(map[string]*itemVersion)
+	var keys []string
+	for _, occurrence := range occurrences {
+		if occurrence.Version == "" {
+			continue
+		}
+		key := occurrence.Version
+		if v, err := version.Parse(occurrence.Version); err == nil {
+			key = v.String()
+		}
+		entry, ok := byKey[key]
+		if !ok {
+			entry = &itemVersion{version: occurrence.Version}
+			byKey[key] = entry
+			keys = append(keys, key)
+		}
+		entry.modules = append(entry.modules, occurrence.Module)
+	}
+
+	result := make([]itemVersion, 0, len(keys))
+	for _, key := range keys {
+		entry := byKey[key]
+		sort.Strings(entry.modules)
+		result = append(result, *entry)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if c, err := version.CompareStrings(result[i].version, result[j].version); err == nil {
+			return c < 0
+		}
+		return result[i].version < result[j].version
+	})
+	return result
+}
+
+// getItemsAllVersions 返回名称到全部去重版本的映射，同名检测项的版本合并
+func getItemsAllVersions(items []model.DetectedItem) map[string][]string {
+	result := make(map[string][]string)
+	for _, item := range items {
+		if item.Name == "" {
+			continue
+		}
+		if _, exists := result[item.Name]; !exists {
+			result[item.Name] = []string{}
+		}
+		for _, entry := range distinctVersions(item) {
+			if !slices.Contains(result[item.Name], entry.version) {
+				result[item.Name] = append(result[item.Name], entry.version)
+			}
+		}
+	}
+	return result
+}
+
+// getVersionSkews 返回在不同模块中使用不同版本的检测项，按名称排序
+func getVersionSkews(items []model.DetectedItem) []model.VersionSkew {
+	skews := []model.VersionSkew{}
+	for _, item := range items {
+		versions := distinctVersions(item)
+		if len(versions) < 2 {
+			continue
+		}
+		skew := model.VersionSkew{Name: item.Name, Type: item.Type, Versions: make(map[string][]string)}
+		for _, entry := range versions {
+			skew.Versions[entry.version] = entry.modules
+		}
+		skews = append(skews, skew)
+	}
+	sort.Slice(skews, func(i, j int) bool {
+		return skews[i].Name < skews[j].Name
+	})
+	return skews
+}
@@ -0,0 +1,88 @@
+package canvas
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestVersionSkewAcrossModules(t *testing.T) {
+	tmpDir := t.TempDir()
+	pomTemplate := `<project>
+  <parent>
+    <groupId>org.springframework.boot</groupId>
+    <artifactId>spring-boot-starter-parent</artifactId>
+    <version>%s</version>
+  </parent>
+  <artifactId>%s</artifactId>
+  <dependencies>
+    <dependency>
+      <groupId>org.springframework.boot</groupId>
+      <artifactId>spring-boot-starter-web</artifactId>
+    </dependency>
+  </dependencies>
+</project>`
+	services := map[string]string{"order": "3.1.5", "payment": "2.7.18", "user": "3.1.5"}
+	for name, bootVersion := range services {
+		dir := filepath.Join(tmpDir, "services", name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("无法创建目录: %v", err)
+		}
+		content := fmt.Sprintf(pomTemplate, bootVersion, name)
+		if err := os.WriteFile(filepath.Join(dir, "pom.xml"), []byte(content), 0644); err != nil {
+			t.Fatalf("无法写入 pom.xml: %v", err)
+		}
+		javaFile := filepath.Join(dir, "App.java")
+		if err := os.WriteFile(javaFile, []byte("import org.springframework.boot.SpringApplication;\nclass App {}\n"), 0644); err != nil {
+			t.Fatalf("无法写入 App.java: %v", err)
+		}
+	}
+
+	report, err := Analyze(tmpDir, "")
+	if err != nil {
+		t.Fatalf("Analyze 失败: %v", err)
+	}
+
+	var occurrences int
+	for _, fw := range report.Detection.Frameworks {
+		if fw.Name == "Spring Boot" {
+			occurrences = len(fw.Occurrences)
+		}
+	}
+	if occurrences != 3 {
+		t.Errorf("Spring Boot 应在 3 个模块中出现，实际为 %d", occurrences)
+	}
+
+	simple := ToSimpleReport(report)
+	if got := simple.FrameworkVersions["Spring Boot"]; !reflect.DeepEqual(got, []string{"2.7.18", "3.1.5"}) {
+		t.Errorf("Spring Boot 版本列表错误: %v", got)
+	}
+	if len(simple.VersionSkew) != 1 || simple.VersionSkew[0].Name != "Spring Boot" {
+		t.Fatalf("应标记 Spring Boot 版本不一致: %+v", simple.VersionSkew)
+	}
+	expected := map[string][]string{
+		"2.7.18": {"services/payment"},
+		"3.1.5":  {"services/order", "services/user"},
+	}
+	if !reflect.DeepEqual(simple.VersionSkew[0].Versions, expected) {
+		t.Errorf("版本与模块映射错误: %v", simple.VersionSkew[0].Versions)
+	}
+}
+
+func TestSimpleReportFallsBackToDeclaredConstraint(t *testing.T) {
+	fsys := fstest.MapFS{
+		"package.json": {Data: []byte(`{"dependencies": {"express": "^4.18.2"}}`)},
+		"index.js":     {Data: []byte("const express = require('express');\n")},
+	}
+	report, err := AnalyzeFS(fsys, "app", Options{})
+	if err != nil {
+		t.Fatalf("AnalyzeFS 失败: %v", err)
+	}
+	simple := ToSimpleReport(report)
+	if got, ok := simple.Frameworks["Express"]; !ok || got != "^4.18.2" {
+		t.Errorf("只声明了版本约束时应使用约束原文，实际为 %q（存在: %v）", got, ok)
+	}
+}
@@ -0,0 +1,158 @@
+package canvas
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+	"github.com/winezer0/codecanvas/internal/version"
+	"github.com/winezer0/codecanvas/internal/vulnengine"
+)
+
+// VulnDatabase 离线的 OSV 公告数据
+type VulnDatabase = vulnengine.Database
+
+// LoadVulnDatabase 加载本地的 OSV 公告数据（目录、zip 压缩包或 JSON 文件），不访问网络。
+// cacheDir 非空时在其中读写索引缓存，数据未变化时直接从缓存加载
+func LoadVulnDatabase(path, cacheDir string) (*VulnDatabase, error) {
+	db, err := vulnengine.Open(path, cacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("error loading OSV database: %v", err)
+	}
+	return db, nil
+}
+
+// DefaultVulnCacheDir 返回默认的索引缓存目录（用户缓存目录下的 codecanvas/osv），无法确定时返回空字符串
+func DefaultVulnCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "codecanvas", "osv")
+}
+
+// MatchVulnerabilities 按生态、包名称与版本将报告中的框架、组件与依赖记录与公告数据匹配，
+// 匹配结果写入检测项的 Vulnerabilities 字段与报告的漏洞摘要。
+// 检测项在各模块中的每个版本分别匹配；依赖记录的版本为约束时只在约束固定了唯一版本时匹配
+func MatchVulnerabilities(report *model.CanvasReport, db *VulnDatabase) {
+	summary := &model.VulnerabilitySummary{
+		Database:     db.Path,
+		Advisories:   db.Advisories(),
+		BySeverity:   make(map[string]int),
+		Dependencies: []model.VulnerableDependency{},
+	}
+	severities := make(map[string]string)
+	record := func(vulns []model.Vulnerability) {
+		for _, vuln := range vulns {
+			severities[vuln.ID] = vuln.Severity
+		}
+	}
+
+	for _, items := range [][]model.DetectedItem{report.Detection.Frameworks, report.Detection.Components} {
+		for i := range items {
+			item := &items[i]
+			item.Vulnerabilities = nil
+			if item.Ecosystem == "" || item.Package == "" {
+				continue
+			}
+			seen := make(map[string]bool)
+			for _, occurrence := range versionOccurrences(*item) {
+				for _, vuln := range db.Query(item.Ecosystem, item.Package, occurrence.Version) {
+					if !seen[vuln.ID] {
+						seen[vuln.ID] = true
+						item.Vulnerabilities = append(item.Vulnerabilities, vuln)
+					}
+				}
+			}
+			if len(item.Vulnerabilities) > 0 {
+				vulnengine.SortVulnerabilities(item.Vulnerabilities)
+				record(item.Vulnerabilities)
+				summary.Components++
+			}
+		}
+	}
+
+	for _, dep := range report.Dependencies {
+		resolved := version.Resolve(dep.Version, version.SchemeForEcosystem(dep.Ecosystem)).Version
+		vulns := db.Query(dep.Ecosystem, dep.Name, resolved)
+		if len(vulns) == 0 {
+			continue
+		}
+		record(vulns)
+		summary.Dependencies = append(summary.Dependencies, model.VulnerableDependency{
+			Ecosystem:       dep.Ecosystem,
+			Name:            dep.Name,
+			Version:         dep.Version,
+			Manifest:        dep.Manifest,
+			Line:            dep.Line,
+			Vulnerabilities: vulns,
+		})
+	}
+	sort.SliceStable(summary.Dependencies, func(i, j int) bool {
+		a, b := summary.Dependencies[i], summary.Dependencies[j]
+		if a.Ecosystem != b.Ecosystem {
+			return a.Ecosystem < b.Ecosystem
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		return a.Manifest < b.Manifest
+	})
+
+	summary.Total = len(severities)
+	for _, severity := range severities {
+		summary.BySeverity[severity]++
+	}
+	report.Vulnerabilities = summary
+}
+
+// PrintVulnerabilitySummary 在命令行输出漏洞摘要与存在漏洞的依赖记录
+func PrintVulnerabilitySummary(summary *model.VulnerabilitySummary) {
+	var b strings.Builder
+	writeVulnerabilitySummary(&b, summary)
+	fmt.Print(b.String())
+}
+
+// writeVulnerabilitySummary 写出漏洞摘要与存在漏洞的依赖记录
+func writeVulnerabilitySummary(b *strings.Builder, summary *model.VulnerabilitySummary) {
+	fmt.Fprintf(b, "Vulnerabilities: %d advisories matched (%d loaded)\n", summary.Total, summary.Advisories)
+	for _, severity := range severitiesDescending() {
+		if count := summary.BySeverity[severity]; count > 0 {
+			fmt.Fprintf(b, "- %s: %d\n", severity, count)
+		}
+	}
+	for _, dep := range summary.Dependencies {
+		fmt.Fprintf(b, "  %s %s@%s (%s)\n", dep.Ecosystem, dep.Name, dep.Version, locationLabel(dep.Manifest, dep.Line))
+		for _, vuln := range dep.Vulnerabilities {
+			fmt.Fprintf(b, "    %s\n", vulnerabilityLabel(vuln))
+		}
+	}
+	b.WriteString("\n")
+}
+
+// severitiesDescending 返回由高到低排列的严重程度，最后为 unknown
+func severitiesDescending() []string {
+	severities := slices.Clone(model.AllSeverity)
+	slices.Reverse(severities)
+	return append(severities, model.SeverityUnknown)
+}
+
+// vulnerabilityLabel 返回公告的单行描述：编号、别名、严重程度与修复版本
+func vulnerabilityLabel(vuln model.Vulnerability) string {
+	label := vuln.ID
+	if len(vuln.Aliases) > 0 {
+		label += " (" + strings.Join(vuln.Aliases, ", ") + ")"
+	}
+	label += " [" + vuln.Severity + "]"
+	if len(vuln.Fixed) > 0 {
+		label += " fixed in " + strings.Join(vuln.Fixed, ", ")
+	}
+	return label
+}
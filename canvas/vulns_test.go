@@ -0,0 +1,123 @@
+package canvas
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestMatchVulnerabilities(t *testing.T) {
+	dump := t.TempDir()
+	advisories := map[string]string{
+		"Maven/GHSA-8r5m-5wc3-rvv5.json": `{"id": "GHSA-8r5m-5wc3-rvv5", "aliases": ["CVE-2022-25845"],
+  "severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}],
+  "affected": [{"package": {"ecosystem": "Maven", "name": "com.alibaba:fastjson"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "1.2.83"}]}]}]}`,
+		"Go/GO-2023-1737.json": `{"id": "GO-2023-1737", "aliases": ["CVE-2023-29401"], "database_specific": {"severity": "MODERATE"},
+  "affected": [{"package": {"ecosystem": "Go", "name": "github.com/gin-gonic/gin"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "1.3.1-0.20190301021747-ccb9e902956d"}, {"fixed": "1.9.1"}]}]}]}`,
+	}
+	for name, content := range advisories {
+		path := filepath.Join(dump, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("无法创建目录: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("无法写入 %s: %v", name, err)
+		}
+	}
+	db, err := LoadVulnDatabase(dump, t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadVulnDatabase 失败: %v", err)
+	}
+
+	report, err := AnalyzeFS(fstest.MapFS{
+		"pom.xml": {Data: []byte(`<project><dependencies><dependency>
+<groupId>com.alibaba</groupId><artifactId>fastjson</artifactId><version>1.2.80</version>
+</dependency></dependencies></project>`)},
+		"src/Main.java": {Data: []byte("import com.alibaba.fastjson.JSON;\nclass Main {}\n")},
+		"go.mod":        {Data: []byte("module example.com/app\n\ngo 1.22\n\nrequire github.com/gin-gonic/gin v1.9.0\n")},
+		"main.go":       {Data: []byte("package main\n\nimport \"github.com/gin-gonic/gin\"\n\nfunc main() { gin.Default() }\n")},
+	}, "app", Options{})
+	if err != nil {
+		t.Fatalf("AnalyzeFS 失败: %v", err)
+	}
+	MatchVulnerabilities(report, db)
+
+	items := make(map[string]model.DetectedItem)
+	for _, item := range append(report.Detection.Frameworks, report.Detection.Components...) {
+		items[item.Name] = item
+	}
+	fastjson := items["fastjson"]
+	if fastjson.Ecosystem != model.EcosystemMaven || fastjson.Package != "com.alibaba:fastjson" {
+		t.Errorf("fastjson 的依赖包错误: %s %s", fastjson.Ecosystem, fastjson.Package)
+	}
+	if len(fastjson.Vulnerabilities) != 1 || fastjson.Vulnerabilities[0].Severity != model.SeverityCritical || fastjson.Vulnerabilities[0].Fixed[0] != "1.2.83" {
+		t.Errorf("fastjson 的漏洞错误: %+v", fastjson.Vulnerabilities)
+	}
+	if gin := items["Gin"]; len(gin.Vulnerabilities) != 1 || gin.Vulnerabilities[0].Severity != model.SeverityMedium {
+		t.Errorf("Gin 的漏洞错误: %+v", gin.Vulnerabilities)
+	}
+
+	summary := report.Vulnerabilities
+	if summary == nil || summary.Advisories != 2 || summary.Total != 2 || summary.Components != 2 {
+		t.Fatalf("漏洞摘要错误: %+v", summary)
+	}
+	if summary.BySeverity[model.SeverityCritical] != 1 || summary.BySeverity[model.SeverityMedium] != 1 {
+		t.Errorf("按严重程度的统计错误: %v", summary.BySeverity)
+	}
+	if len(summary.Dependencies) != 2 || summary.Dependencies[0].Name != "github.com/gin-gonic/gin" || summary.Dependencies[1].Manifest != "pom.xml" {
+		t.Errorf("存在漏洞的依赖错误: %+v", summary.Dependencies)
+	}
+}
+
+func TestMatchVulnerabilitiesIgnoresDeclaredConstraint(t *testing.T) {
+	dump := t.TempDir()
+	path := filepath.Join(dump, "npm", "GHSA-rv95-896h-c2vc.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("无法创建目录: %v", err)
+	}
+	advisory := `{"id": "GHSA-rv95-896h-c2vc", "database_specific": {"severity": "MODERATE"},
+  "affected": [{"package": {"ecosystem": "npm", "name": "express"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "4.19.2"}]}]}]}`
+	if err := os.WriteFile(path, []byte(advisory), 0644); err != nil {
+		t.Fatalf("无法写入漏洞数据: %v", err)
+	}
+	db, err := LoadVulnDatabase(dump, t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadVulnDatabase 失败: %v", err)
+	}
+
+	// 未锁定的 ^4.17.0 允许安装已修复的版本，约束的下限不能作为实际版本匹配漏洞
+	report, err := AnalyzeFS(fstest.MapFS{
+		"package.json": {Data: []byte(`{"dependencies": {"express": "^4.17.0"}}`)},
+		"index.js":     {Data: []byte("const express = require('express');\n")},
+	}, "app", Options{})
+	if err != nil {
+		t.Fatalf("AnalyzeFS 失败: %v", err)
+	}
+	MatchVulnerabilities(report, db)
+
+	detected := false
+	for _, item := range append(report.Detection.Frameworks, report.Detection.Components...) {
+		if item.Name != "Express" {
+			continue
+		}
+		detected = true
+		if item.Version != "" || item.DeclaredConstraint != "^4.17.0" {
+			t.Errorf("Express 的版本字段错误: %+v", item)
+		}
+		if len(item.Vulnerabilities) != 0 {
+			t.Errorf("未锁定的约束不应匹配漏洞: %+v", item.Vulnerabilities)
+		}
+	}
+	if !detected {
+		t.Errorf("应检测到 Express")
+	}
+	if summary := report.Vulnerabilities; summary == nil || summary.Total != 0 || len(summary.Dependencies) != 0 {
+		t.Errorf("未锁定的约束不应计入漏洞摘要: %+v", summary)
+	}
+}
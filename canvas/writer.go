@@ -0,0 +1,143 @@
+package canvas
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/winezer0/codecanvas/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+// 内置的报告输出格式，SBOM 与 SARIF 格式见 FormatCycloneDXJSON、FormatSPDXJSON 与 FormatSARIF
+const (
+	FormatText       = "text"
+	FormatJSON       = "json"
+	FormatSimpleJSON = "simple-json"
+	FormatYAML       = "yaml"
+	FormatCSV        = "csv"
+	FormatMarkdown   = "markdown"
+	FormatHTML       = "html"
+)
+
+// ReportWriter 将分析报告写为一种输出格式，通过 RegisterReportWriter 注册后可按格式名称使用
+type ReportWriter interface {
+	WriteReport(w io.Writer, report *model.CanvasReport) error
+}
+
+// ReportWriterFunc 将普通函数适配为 ReportWriter
+type ReportWriterFunc func(w io.Writer, report *model.CanvasReport) error
+
+// WriteReport 调用 f(w, report)
+func (f ReportWriterFunc) WriteReport(w io.Writer, report *model.CanvasReport) error {
+	return f(w, report)
+}
+
+var (
+	reportWritersMu sync.RWMutex
+	reportWriters   = map[string]ReportWriter{
+		FormatText:          ReportWriterFunc(WriteText),
+		FormatJSON:          ReportWriterFunc(WriteJSON),
+		FormatSimpleJSON:    ReportWriterFunc(WriteSimpleJSON),
+		FormatYAML:          ReportWriterFunc(WriteYAML),
+		FormatCSV:           ReportWriterFunc(WriteCSV),
+		FormatMarkdown:      ReportWriterFunc(WriteMarkdown),
+		FormatHTML:          ReportWriterFunc(WriteHTML),
+		FormatSARIF:         ReportWriterFunc(WriteSARIF),
+		FormatCycloneDXJSON: formatWriter(WriteCycloneDX, FormatCycloneDXJSON),
+		FormatCycloneDXXML:  formatWriter(WriteCycloneDX, FormatCycloneDXXML),
+		FormatSPDXJSON:      formatWriter(WriteSPDX, FormatSPDXJSON),
+		FormatSPDXTagValue:  formatWriter(WriteSPDX, FormatSPDXTagValue),
+	}
+)
+
+// formatWriter 将同时支持多种格式的写入函数固定为其中一种格式
+func formatWriter(write func(io.Writer, *model.CanvasReport, string) error, format string) ReportWriter {
+	return ReportWriterFunc(func(w io.Writer, report *model.CanvasReport) error {
+		return write(w, report, format)
+	})
+}
+
+// RegisterReportWriter 注册格式名称对应的写入器，同名格式已存在时替换原有的写入器
+func RegisterReportWriter(format string, writer ReportWriter) {
+	reportWritersMu.Lock()
+	defer reportWritersMu.Unlock()
+	reportWriters[format] = writer
+}
+
+// LookupReportWriter 返回格式名称对应的写入器
+func LookupReportWriter(format string) (ReportWriter, bool) {
+	reportWritersMu.RLock()
+	defer reportWritersMu.RUnlock()
+	writer, ok := reportWriters[format]
+	return writer, ok
+}
+
+// ReportFormats 返回已注册的全部格式名称，按名称排序
+func ReportFormats() []string {
+	reportWritersMu.RLock()
+	defer reportWritersMu.RUnlock()
+	formats := make([]string, 0, len(reportWriters))
+	for format := range reportWriters {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// WriteReport 按格式名称写出分析报告，格式未注册时返回错误
+func WriteReport(w io.Writer, report *model.CanvasReport, format string) error {
+	writer, ok := LookupReportWriter(format)
+	if !ok {
+		return fmt.Errorf("unsupported report format %q", format)
+	}
+	return writer.WriteReport(w, report)
+}
+
+// WriteJSON 以 JSON 格式写出完整的分析报告
+func WriteJSON(w io.Writer, report *model.CanvasReport) error {
+	return writeIndentedJSON(w, report)
+}
+
+// WriteSimpleJSON 以 JSON 格式写出简单报告（见 ToSimpleReport）
+func WriteSimpleJSON(w io.Writer, report *model.CanvasReport) error {
+	return writeIndentedJSON(w, ToSimpleReport(report))
+}
+
+// writeIndentedJSON 写出缩进两个空格的 JSON，不转义 HTML 字符（如版本约束中的 "<"）
+func writeIndentedJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// WriteYAML 以 YAML 格式写出完整的分析报告，字段名称与顺序与 JSON 报告相同
+func WriteYAML(w io.Writer, report *model.CanvasReport) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	// JSON 即 YAML 的子集，解析为节点后保留字段顺序，再改用块样式输出
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	resetYAMLStyle(&node)
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// resetYAMLStyle 清除从 JSON 继承的流样式与引号，需要引号的字符串（如 "1.20"、"yes"）在输出时仍会加引号
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetYAMLStyle(child)
+	}
+}
@@ -0,0 +1,107 @@
+package canvas
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+func TestReportWriters(t *testing.T) {
+	report, err := AnalyzeFS(sbomTestFS, "app", Options{})
+	if err != nil {
+		t.Fatalf("AnalyzeFS 失败: %v", err)
+	}
+	report.PolicyViolations = []model.PolicyViolation{{Rule: model.PolicyDeny, Severity: model.SeverityHigh, Type: "framework",
+		Name: "Gin", Version: "1.9.0", Path: "go.mod", Line: 5, Message: "Gin 1.9.0 is <denied>"}}
+	write := func(format string) string {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := WriteReport(&buf, report, format); err != nil {
+			t.Fatalf("WriteReport(%s) 失败: %v", format, err)
+		}
+		return buf.String()
+	}
+
+	for _, format := range []string{FormatText, FormatJSON, FormatSimpleJSON, FormatYAML, FormatCSV, FormatMarkdown, FormatHTML,
+		FormatSARIF, FormatCycloneDXJSON, FormatCycloneDXXML, FormatSPDXJSON, FormatSPDXTagValue} {
+		if !slices.Contains(ReportFormats(), format) {
+			t.Errorf("内置格式 %s 未注册", format)
+		}
+	}
+
+	text := write(FormatText)
+	if !strings.Contains(text, "Detected Frameworks:") || !strings.Contains(text, "At: go.mod:5") || strings.Contains(text, "Simple Report") {
+		t.Errorf("文本报告错误:\n%s", text)
+	}
+
+	var full model.CanvasReport
+	if err := json.Unmarshal([]byte(write(FormatJSON)), &full); err != nil || len(full.Dependencies) != len(report.Dependencies) {
+		t.Errorf("JSON 报告错误: %v", err)
+	}
+	var simple model.AnalysisResult
+	if err := json.Unmarshal([]byte(write(FormatSimpleJSON)), &simple); err != nil || simple.Frameworks["Gin"] != "1.9.0" {
+		t.Errorf("简单报告错误: %v %+v", err, simple.Frameworks)
+	}
+
+	var doc map[string]any
+	if err := yaml.Unmarshal([]byte(write(FormatYAML)), &doc); err != nil {
+		t.Fatalf("YAML 报告无法解析: %v", err)
+	}
+	profile, _ := doc["code_profile"].(map[string]any)
+	if profile == nil || profile["path"] != "app" {
+		t.Errorf("YAML 报告的字段名称应与 JSON 相同: %v", doc["code_profile"])
+	}
+
+	records, err := csv.NewReader(strings.NewReader(write(FormatCSV))).ReadAll()
+	if err != nil {
+		t.Fatalf("CSV 报告无法解析: %v", err)
+	}
+	if records[0][0] != "table" || !slices.ContainsFunc(records, func(row []string) bool {
+		return row[0] == "framework" && row[1] == "Gin" && row[4] == "1.9.0" && row[6] == "github.com/gin-gonic/gin"
+	}) || !slices.ContainsFunc(records, func(row []string) bool {
+		return row[0] == "language" && row[1] == "Go" && row[2] == model.CategoryBackend && row[8] == "3"
+	}) {
+		t.Errorf("CSV 报告错误: %v", records)
+	}
+
+	markdown := write(FormatMarkdown)
+	if !strings.Contains(markdown, "| Gin | backend | Go | 1.9.0 | go.mod:5 |") || !strings.Contains(markdown, "## Policy Violations") {
+		t.Errorf("Markdown 报告错误:\n%s", markdown)
+	}
+
+	page := write(FormatHTML)
+	if !strings.Contains(page, "<svg") || !strings.Contains(page, "Gin 1.9.0 is &lt;denied&gt;") {
+		t.Errorf("HTML 报告缺少图表或未转义文本")
+	}
+	if strings.Contains(page, "<script") || strings.Contains(page, "<link") || strings.Contains(page, `src="http`) {
+		t.Errorf("HTML 报告不应引用外部资源")
+	}
+}
+
+func TestRegisterReportWriter(t *testing.T) {
+	RegisterReportWriter("names", ReportWriterFunc(func(w io.Writer, report *model.CanvasReport) error {
+		_, err := io.WriteString(w, strings.Join(itemNames(report.Detection.Frameworks), ","))
+		return err
+	}))
+	defer func() {
+		reportWritersMu.Lock()
+		delete(reportWriters, "names")
+		reportWritersMu.Unlock()
+	}()
+
+	report := &model.CanvasReport{Detection: model.DetectionInfo{Frameworks: []model.DetectedItem{{Name: "Gin"}, {Name: "Vue"}}}}
+	var buf bytes.Buffer
+	if err := WriteReport(&buf, report, "names"); err != nil || buf.String() != "Gin,Vue" {
+		t.Errorf("自定义格式输出错误: %v %q", err, buf.String())
+	}
+	if err := WriteReport(&buf, report, "docx"); err == nil {
+		t.Errorf("未注册的格式应返回错误")
+	}
+}
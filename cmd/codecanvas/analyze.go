@@ -0,0 +1,189 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/winezer0/codecanvas/canvas"
+	"github.com/winezer0/codecanvas/internal/model"
+	"github.com/winezer0/codecanvas/internal/utils"
+)
+
+// AnalyzeCommand 分析源码目录、容器镜像或 git 仓库中的修订
+type AnalyzeCommand struct {
+	Path         string   `short:"p" long:"path" description:"Path to the codebase to analyze"`
+	Image        string   `long:"image" description:"Analyze an OCI or docker-archive image tarball (docker save output) offline"`
+	GitRepo      string   `long:"git-repo" description:"Analyze a revision read directly from the object database of a local git repository"`
+	Rev          string   `long:"rev" description:"Revision to analyze with --git-repo: branch, tag, ref or commit hash" default:"HEAD"`
+	RulesDir     string   `short:"r" long:"rules" description:"Directory containing detection rules" default:"./rules"`
+	Output       []string `short:"o" long:"output" description:"Write the report to path; repeat to pair with each --format in order"`
+	Format       []string `short:"f" long:"format" description:"Report format (text, json, simple-json, yaml, csv, markdown, html, sarif, cyclonedx-json, cyclonedx-xml, spdx-json, spdx-tag-value); repeatable, a format without a matching --output goes to stdout"`
+	ArchiveDepth int      `long:"archive-depth" description:"Nesting depth to descend into jar/war/ear/zip/whl/tgz archives (0 disables)" default:"0"`
+	OSVDatabase  string   `long:"osv-db" description:"Match components and dependencies against a local OSV advisory dump (directory, zip or JSON file)"`
+	OSVCache     string   `long:"osv-cache" description:"Directory for the OSV index cache (defaults to the user cache directory)"`
+	NoOSVCache   bool     `long:"no-osv-cache" description:"Do not read or write the OSV index cache"`
+	Policy       string   `long:"policy" description:"Evaluate the report against a YAML policy file and exit non-zero on violations"`
+	FailOn       string   `long:"fail-on" description:"Lowest violation severity that makes the command exit non-zero" choice:"low" choice:"medium" choice:"high" choice:"critical" default:"low"`
+}
+
+// 存在策略违规时按最高严重程度返回的退出码
+var severityExitCodes = map[string]int{
+	model.SeverityLow:      10,
+	model.SeverityMedium:   11,
+	model.SeverityHigh:     12,
+	model.SeverityCritical: 13,
+}
+
+// exitCodeError 需要以指定退出码结束进程的错误
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string {
+	return e.err.Error()
+}
+
+// Execute 根据参数分析源码目录、镜像或 git 修订并输出报告
+func (c *AnalyzeCommand) Execute(args []string) error {
+	var (
+		report *model.CanvasReport
+		err    error
+	)
+	targets, err := reportTargets(c.Format, c.Output)
+	if err != nil {
+		return err
+	}
+	// 策略文件与公告数据在分析之前读取，出错时不必等待分析完成
+	var policy *model.Policy
+	if c.Policy != "" {
+		if policy, err = canvas.LoadPolicy(c.Policy); err != nil {
+			return err
+		}
+	}
+	var vulnDB *canvas.VulnDatabase
+	if c.OSVDatabase != "" {
+		cacheDir := c.OSVCache
+		switch {
+		case c.NoOSVCache:
+			cacheDir = ""
+		case cacheDir == "":
+			cacheDir = canvas.DefaultVulnCacheDir()
+		}
+		if vulnDB, err = canvas.LoadVulnDatabase(c.OSVDatabase, cacheDir); err != nil {
+			return err
+		}
+	}
+	opts := canvas.Options{RulesDir: c.RulesDir, ArchiveDepth: c.ArchiveDepth}
+	switch {
+	case c.Image != "":
+		report, err = canvas.AnalyzeImageWithOptions(c.Image, opts)
+	case c.GitRepo != "":
+		report, err = canvas.AnalyzeGit(c.GitRepo, c.Rev, opts)
+	case c.Path != "":
+		report, err = canvas.AnalyzeWithOptions(c.Path, opts)
+	default:
+		return errors.New("one of --path, --image or --git-repo is required")
+	}
+	if err != nil {
+		return err
+	}
+	if vulnDB != nil {
+		canvas.MatchVulnerabilities(report, vulnDB)
+	}
+	if policy != nil {
+		report.PolicyViolations = canvas.EvaluatePolicy(report, policy)
+	}
+	if err := writeReports(report, targets); err != nil {
+		return err
+	}
+	if policy == nil {
+		return nil
+	}
+	// 违规项已包含在各格式的报告中，退出码与错误信息反映检查结果
+	highest := canvas.MaxSeverity(report.PolicyViolations)
+	if highest == "" || canvas.SeverityRank(highest) < canvas.SeverityRank(c.FailOn) {
+		return nil
+	}
+	return &exitCodeError{
+		code: severityExitCodes[highest],
+		err:  fmt.Errorf("%d policy violations, highest severity %s", len(report.PolicyViolations), highest),
+	}
+}
+
+// writesStdout 判断是否有报告格式写到标准输出，即格式数量多于输出路径
+func (c *AnalyzeCommand) writesStdout() bool {
+	return len(c.Format) > len(c.Output)
+}
+
+// reportTarget 一种报告格式及其输出路径，路径为空时写到标准输出
+type reportTarget struct {
+	format string
+	output string
+}
+
+// reportTargets 将 --format 与 --output 按出现顺序配对：第 N 个格式写入第 N 个路径，没有对应路径的格式写到标准输出。
+// 未指定格式时，指定的路径写入 JSON 报告；最多一个格式可以写到标准输出
+func reportTargets(formats, outputs []string) ([]reportTarget, error) {
+	if len(formats) == 0 && len(outputs) > 0 {
+		formats = []string{canvas.FormatJSON}
+	}
+	if len(outputs) > len(formats) {
+		return nil, fmt.Errorf("%d --output paths given for %d formats", len(outputs), len(formats))
+	}
+	var targets []reportTarget
+	stdout := ""
+	for i, format := range formats {
+		if _, ok := canvas.LookupReportWriter(format); !ok {
+			return nil, fmt.Errorf("unsupported report format %q (available: %s)", format, strings.Join(canvas.ReportFormats(), ", "))
+		}
+		target := reportTarget{format: format}
+		if i < len(outputs) {
+			target.output = outputs[i]
+		} else if stdout != "" {
+			return nil, fmt.Errorf("formats %s and %s both write to stdout, give one of them an --output", stdout, format)
+		} else {
+			stdout = format
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// writeReports 按格式写出报告。没有格式写到标准输出时在命令行输出文本报告，
+// 否则标准输出只包含该格式的内容，便于通过管道交给其他工具
+func writeReports(report *model.CanvasReport, targets []reportTarget) error {
+	toStdout := false
+	for _, target := range targets {
+		if target.output == "" {
+			toStdout = true
+			if err := canvas.WriteReport(os.Stdout, report, target.format); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := writeReportFile(report, target); err != nil {
+			return err
+		}
+	}
+	if !toStdout {
+		canvas.PrintReport(report)
+	}
+	return nil
+}
+
+// writeReportFile 将报告按格式写入文件
+func writeReportFile(report *model.CanvasReport, target reportTarget) error {
+	utils.EnsureDir(target.output, true)
+	file, err := os.Create(target.output)
+	if err != nil {
+		return err
+	}
+	if err := canvas.WriteReport(file, report, target.format); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
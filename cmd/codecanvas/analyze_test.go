@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+)
+
+// TestAnalyzeStdoutIsMachineReadable 报告写到标准输出时日志不应混入其中，使用 debug 级别以确保分析过程中有日志输出
+func TestAnalyzeStdoutIsMachineReadable(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("无法创建管道: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+	output := make(chan []byte)
+	go func() {
+		var buf bytes.Buffer
+		io.Copy(&buf, reader)
+		output <- buf.Bytes()
+	}()
+
+	var opts Options
+	_, err = newParser(&opts).ParseArgs([]string{"--ll", "debug", "analyze", "-p", "../../testdata/mixed_project", "-f", "sarif"})
+	writer.Close()
+	data := <-output
+	if err != nil {
+		t.Fatalf("analyze 失败: %v", err)
+	}
+	var log map[string]any
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatalf("标准输出不是有效的 JSON: %v\n%s", err, data)
+	}
+	if log["version"] != "2.1.0" {
+		t.Errorf("SARIF 版本错误: %v", log["version"])
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+
+	"github.com/winezer0/codecanvas/canvas"
+	"github.com/winezer0/codecanvas/internal/utils"
+)
+
+// DiffCommand 比较两份 JSON 格式的分析报告
+type DiffCommand struct {
+	Format string `short:"f" long:"format" description:"Output format" choice:"text" choice:"json" choice:"markdown" default:"text"`
+	Output string `short:"o" long:"output" description:"Write the diff to path instead of stdout"`
+	Args   struct {
+		Old string `positional-arg-name:"OLD" required:"yes"`
+		New string `positional-arg-name:"NEW" required:"yes"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+// Execute 读取两份报告并输出差异
+func (c *DiffCommand) Execute(args []string) error {
+	reports, err := canvas.LoadReports([]string{c.Args.Old, c.Args.New})
+	if err != nil {
+		return err
+	}
+	diff := canvas.DiffReports(reports[0], reports[1])
+
+	if c.Output == "" {
+		return canvas.WriteDiff(os.Stdout, diff, c.Format)
+	}
+	utils.EnsureDir(c.Output, true)
+	file, err := os.Create(c.Output)
+	if err != nil {
+		return err
+	}
+	if err := canvas.WriteDiff(file, diff, c.Format); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"os"
+
+	"github.com/winezer0/codecanvas/canvas"
+	"github.com/winezer0/codecanvas/internal/utils"
+)
+
+// HistoryCommand 沿 git 历史采样提交并输出技术栈时间线
+type HistoryCommand struct {
+	GitRepo      string `long:"git-repo" description:"Path to the local git repository" required:"yes"`
+	Rev          string `long:"rev" description:"Revision to start sampling from with --every" default:"HEAD"`
+	Every        int    `long:"every" description:"Sample every N commits along the first-parent history" default:"1"`
+	Tags         bool   `long:"tags" description:"Sample tagged commits instead of every N commits"`
+	MaxSamples   int    `long:"max-samples" description:"Analyze at most N of the most recent samples (0 analyzes all)" default:"0"`
+	RulesDir     string `short:"r" long:"rules" description:"Directory containing detection rules" default:"./rules"`
+	ArchiveDepth int    `long:"archive-depth" description:"Nesting depth to descend into jar/war/ear/zip/whl/tgz archives (0 disables)" default:"0"`
+	Output       string `short:"o" long:"output" description:"Write the timeline as JSON to path"`
+	CSV          string `long:"csv" description:"Write the timeline as CSV to path"`
+}
+
+// Execute 分析采样的提交并输出时间线
+func (c *HistoryCommand) Execute(args []string) error {
+	if c.Every < 1 {
+		return errors.New("--every must be at least 1")
+	}
+	report, err := canvas.AnalyzeHistory(c.GitRepo, canvas.HistoryOptions{
+		Options:    canvas.Options{RulesDir: c.RulesDir, ArchiveDepth: c.ArchiveDepth},
+		Rev:        c.Rev,
+		Every:      c.Every,
+		Tags:       c.Tags,
+		MaxSamples: c.MaxSamples,
+	})
+	if err != nil {
+		return err
+	}
+
+	if c.Output != "" {
+		utils.EnsureDir(c.Output, true)
+		if err := utils.WriteJSON(c.Output, report); err != nil {
+			return err
+		}
+	}
+	if c.CSV != "" {
+		utils.EnsureDir(c.CSV, true)
+		file, err := os.Create(c.CSV)
+		if err != nil {
+			return err
+		}
+		if err := canvas.WriteHistoryCSV(file, report); err != nil {
+			file.Close()
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+	}
+	canvas.PrintHistory(report)
+	return nil
+}
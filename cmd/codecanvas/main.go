@@ -4,12 +4,12 @@ package main
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/jessevdk/go-flags"
 	"github.com/winezer0/codecanvas/canvas"
 	"github.com/winezer0/codecanvas/internal/logging"
-	"github.com/winezer0/codecanvas/internal/utils"
 )
 
 // Options defines the command-line parameters for CodeCanvas.
@@ -18,12 +18,20 @@ type Options struct {
 	Path     string `short:"p" long:"path" description:"Path to the codebase to analyze"`
 	RulesDir string `short:"r" long:"rules" description:"Directory containing detection RulesDirDir" default:"./rules"`
 	Output   string `short:"o" long:"output" description:"Write JSON to path or URL"`
+	// 压缩包展开层数
+	ArchiveDepth int `long:"archive-depth" description:"Nesting depth to descend into jar/war/ear/zip/whl/tgz archives (0 disables)" default:"0"`
 
 	// 日志参数（中文描述）
 	LogFile       string `long:"lf" description:"Log file path (if empty, no file will be written)"`
 	LogLevel      string `long:"ll" description:"log level (debug/info/warn/error)" default:"info"`
 	ConsoleFormat string `long:"cf" description:"console log format (TLCM OR off|null）" default:"CM"`
 	Version       bool   `short:"v" long:"version" description:"show version"`
+
+	// 子命令
+	AnalyzeCmd AnalyzeCommand `command:"analyze" description:"Analyze a codebase or a container image"`
+	DiffCmd    DiffCommand    `command:"diff" description:"Compare two JSON reports"`
+	HistoryCmd HistoryCommand `command:"history" description:"Build a tech stack timeline by sampling commits of a git repository"`
+	RulesCmd   RulesCommand   `command:"rules" description:"Rule maintenance commands"`
 }
 
 const (
@@ -37,24 +45,24 @@ const (
 func main() {
 	// 解析命令行参数
 	var opts Options
-	parser := flags.NewParser(&opts, flags.Default)
-	parser.Usage = "[OPTIONS]"
-	parser.ShortDescription = AppShortDesc
-	parser.LongDescription = AppLongDesc
+	parser := newParser(&opts)
 
 	// 命令行參數解析
 	if _, err := parser.Parse(); err != nil {
 		var flagsErr *flags.Error
 		if errors.As(err, &flagsErr) && errors.Is(flagsErr.Type, flags.ErrHelp) {
+			fmt.Println(err)
 			return
 		}
-		fmt.Printf("options parsed error: %v\n", err)
-		os.Exit(1)
+		os.Exit(reportError(os.Stderr, err))
+	}
+	// 子命令已在解析时执行
+	if parser.Active != nil {
+		return
 	}
 
 	// Initialize logger
-	logCfg := logging.NewLogConfig(opts.LogLevel, opts.LogFile, opts.ConsoleFormat)
-	if err := logging.InitLogger(logCfg); err != nil {
+	if err := initLogger(opts, false); err != nil {
 		fmt.Printf("Init logger failed: %v\n", err)
 		os.Exit(1)
 	}
@@ -70,22 +78,77 @@ func main() {
 	// 进行路径分析
 	if opts.Path != "" {
 		// Analyze operation
-		report, err := canvas.Analyze(opts.Path, opts.RulesDir)
+		report, err := canvas.AnalyzeWithOptions(opts.Path, canvas.Options{RulesDir: opts.RulesDir, ArchiveDepth: opts.ArchiveDepth})
 		if err != nil {
 			fmt.Printf("Error analyzing code profile: %v\n", err)
 			os.Exit(1)
 		}
 
-		// 输出Json结果
+		var targets []reportTarget
 		if opts.Output != "" {
-			utils.EnsureDir(opts.Output, true)
-			if err := utils.WriteJSON(opts.Output, report); err != nil {
-				fmt.Printf("Error writing output: %v\n", err)
-				os.Exit(1)
-			}
+			targets = append(targets, reportTarget{format: canvas.FormatJSON, output: opts.Output})
 		}
+		if err := writeReports(report, targets); err != nil {
+			fmt.Printf("Error writing output: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// commandError 子命令执行时返回的错误，与参数解析错误区分
+type commandError struct {
+	err error
+}
+
+func (e *commandError) Error() string {
+	return e.err.Error()
+}
 
-		// 输出命令行报告
-		canvas.PrintReport(report)
+// reportError 将解析或执行命令的错误写到 w 并返回进程退出码，策略检查未通过时按违规的最高严重程度退出
+func reportError(w io.Writer, err error) int {
+	var cmdErr *commandError
+	if !errors.As(err, &cmdErr) {
+		fmt.Fprintf(w, "options parsed error: %v\n", err)
+		return 1
 	}
+	fmt.Fprintf(w, "Error: %v\n", cmdErr.err)
+	var exitErr *exitCodeError
+	if errors.As(cmdErr.err, &exitErr) {
+		return exitErr.code
+	}
+	return 1
+}
+
+// newParser 创建命令行解析器，子命令在日志初始化之后执行；错误不由解析器输出，见 reportError
+func newParser(opts *Options) *flags.Parser {
+	parser := flags.NewParser(opts, flags.HelpFlag|flags.PassDoubleDash)
+	parser.Usage = "[OPTIONS]"
+	parser.ShortDescription = AppShortDesc
+	parser.LongDescription = AppLongDesc
+	// 未指定子命令时执行路径分析
+	parser.SubcommandsOptional = true
+
+	parser.CommandHandler = func(command flags.Commander, args []string) error {
+		if command == nil {
+			return nil
+		}
+		// 报告写到标准输出时日志改写到标准错误，保证标准输出可以直接交给其他工具解析
+		stderr := command == &opts.AnalyzeCmd && opts.AnalyzeCmd.writesStdout()
+		if err := initLogger(*opts, stderr); err != nil {
+			return &commandError{err: fmt.Errorf("init logger failed: %v", err)}
+		}
+		defer logging.Sync()
+		if err := command.Execute(args); err != nil {
+			return &commandError{err: err}
+		}
+		return nil
+	}
+	return parser
+}
+
+// initLogger 根据命令行参数初始化日志，stderr 为 true 时控制台日志写到标准错误
+func initLogger(opts Options, stderr bool) error {
+	logCfg := logging.NewLogConfig(opts.LogLevel, opts.LogFile, opts.ConsoleFormat)
+	logCfg.Stderr = stderr
+	return logging.InitLogger(logCfg)
 }
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/logging"
+)
+
+// TestReportError 参数解析错误与子命令执行错误分别说明，策略检查未通过时保留按严重程度确定的退出码
+func TestReportError(t *testing.T) {
+	var opts Options
+	_, parseErr := newParser(&opts).ParseArgs([]string{"--unknown-flag"})
+	// 子命令执行前会初始化日志，先关闭其他测试创建的日志器
+	logging.CloseAll()
+	opts = Options{}
+	_, runErr := newParser(&opts).ParseArgs([]string{"--ll", "error", "diff", "missing-old.json", "missing-new.json"})
+	policyErr := &commandError{err: &exitCodeError{code: 12, err: errors.New("1 policy violations, highest severity high")}}
+
+	tests := []struct {
+		name   string
+		err    error
+		prefix string
+		code   int
+	}{
+		{"parse", parseErr, "options parsed error: ", 1},
+		{"runtime", runErr, "Error: error reading report missing-old.json", 1},
+		{"policy", policyErr, "Error: 1 policy violations", 12},
+	}
+	for _, tt := range tests {
+		if tt.err == nil {
+			t.Fatalf("%s: 期望得到错误", tt.name)
+		}
+		var buf bytes.Buffer
+		if code := reportError(&buf, tt.err); code != tt.code {
+			t.Errorf("%s: 退出码为 %d，期望 %d", tt.name, code, tt.code)
+		}
+		if !strings.HasPrefix(buf.String(), tt.prefix) {
+			t.Errorf("%s: 错误输出为 %q，期望以 %q 开头", tt.name, buf.String(), tt.prefix)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"github.com/winezer0/codecanvas/canvas"
+	"github.com/winezer0/codecanvas/internal/utils"
+)
+
+// RulesCommand 规则维护相关的子命令
+type RulesCommand struct {
+	Gaps GapsCommand `command:"gaps" description:"Rank dependencies that no rule classifies across several JSON reports"`
+}
+
+// GapsCommand 汇总多份报告中未被规则识别的依赖，按出现次数排序输出
+type GapsCommand struct {
+	Top    int    `short:"n" long:"top" description:"Number of dependencies to show (0 shows all)" default:"50"`
+	Output string `short:"o" long:"output" description:"Write ranked dependencies as JSON to path"`
+	Args   struct {
+		Reports []string `positional-arg-name:"REPORT" required:"1"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+// Execute 读取报告并输出未识别依赖排名
+func (c *GapsCommand) Execute(args []string) error {
+	reports, err := canvas.LoadReports(c.Args.Reports)
+	if err != nil {
+		return err
+	}
+	gaps := canvas.RuleGaps(reports)
+
+	// 输出Json结果
+	if c.Output != "" {
+		utils.EnsureDir(c.Output, true)
+		if err := utils.WriteJSON(c.Output, gaps); err != nil {
+			return err
+		}
+	}
+
+	canvas.PrintRuleGaps(gaps, len(reports), c.Top)
+	return nil
+}
@@ -2,12 +2,16 @@
 package analyzer
 
 import (
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 
+	"github.com/winezer0/codecanvas/internal/archivefs"
 	"github.com/winezer0/codecanvas/internal/langengine"
 	"github.com/winezer0/codecanvas/internal/logging"
 	"github.com/winezer0/codecanvas/internal/model"
@@ -15,7 +19,13 @@ import (
 )
 
 // CodeAnalyzer 实现代码画像分析功能。
-type CodeAnalyzer struct{}
+// - ArchiveDepth: 展开 jar/war/zip/whl/tgz 等压缩包的嵌套层数，0 表示不展开；
+// 压缩包成员以 "app.war!/WEB-INF/lib/x.jar" 形式的虚拟路径加入文件索引，但不参与代码行数统计
+// - Cache: 按文件内容哈希复用单文件统计结果的缓存，为 nil 时不使用缓存
+type CodeAnalyzer struct {
+	ArchiveDepth int
+	Cache        *FileCache
+}
 
 // NewCodeAnalyzer 创建一个新的代码分析器实例。
 func NewCodeAnalyzer() *CodeAnalyzer {
@@ -24,14 +34,16 @@ func NewCodeAnalyzer() *CodeAnalyzer {
 
 // AnalysisTask 定义一个分析任务
 type AnalysisTask struct {
-	Path    string
+	RelPath string
 	LangDef *model.Language
 }
 
 // AnalysisResult 定义分析结果
 type AnalysisResult struct {
 	LangName string
+	RelPath  string
 	Stats    FileStats
+	Imports  []string
 	Err      error
 }
 
@@ -40,15 +52,25 @@ var (
 	fileToLanguage = make(map[string]*model.Language)
 )
 
-// init 初始化语言映射
+// init 初始化语言映射。内置规则中每个扩展名与文件名只归属一个语言；
+// 按语言名称的顺序建立映射并保留第一个声明，即使规则重复声明，同一文件每次也映射到同一语言
 func init() {
-	// 初始化语言映射，直接使用新的语言规则
-	for _, language := range langengine.LanguageRules {
+	names := make([]string, 0, len(langengine.LanguageRules))
+	for name := range langengine.LanguageRules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		language := langengine.LanguageRules[name]
 		for _, ext := range language.Extensions {
-			extToLanguage[strings.ToLower(ext)] = &language
+			if _, ok := extToLanguage[strings.ToLower(ext)]; !ok {
+				extToLanguage[strings.ToLower(ext)] = &language
+			}
 		}
-		for _, name := range language.Filenames {
-			fileToLanguage[name] = &language
+		for _, filename := range language.Filenames {
+			if _, ok := fileToLanguage[filename]; !ok {
+				fileToLanguage[filename] = &language
+			}
 		}
 	}
 }
@@ -60,8 +82,58 @@ func (a *CodeAnalyzer) AnalyzeCodeProfile(projectPath string) (*model.CodeProfil
 	if err != nil {
 		return nil, nil, err
 	}
-	// 初始化文件索引
-	fileIndex := model.NewFileIndex(absPath)
+	// 路径为单个文件（如 composer.json、app.war）时以其所在目录为根，只索引该文件
+	if info, err := os.Stat(absPath); err == nil && info.Mode().IsRegular() {
+		fileIndex := model.NewFileIndex(filepath.Dir(absPath))
+		fileIndex.FS = &singleFileFS{fsys: os.DirFS(fileIndex.RootDir), name: info.Name()}
+		profile, fileIndex, err := a.analyze(fileIndex)
+		if err != nil {
+			return nil, nil, err
+		}
+		profile.Path = absPath
+		return profile, fileIndex, nil
+	}
+	return a.analyze(model.NewFileIndex(absPath))
+}
+
+// singleFileFS 只包含 fsys 根目录下一个文件的文件系统，用于分析单个文件，
+// 避免同目录下的其他文件参与遍历或语言分类
+type singleFileFS struct {
+	fsys fs.FS
+	name string
+}
+
+// Open 只允许打开根目录与该文件
+func (s *singleFileFS) Open(name string) (fs.File, error) {
+	if name != "." && name != s.name {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return s.fsys.Open(name)
+}
+
+// ReadDir 根目录只列出该文件
+func (s *singleFileFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if name != "." {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	info, err := fs.Stat(s.fsys, s.name)
+	if err != nil {
+		return nil, err
+	}
+	return []fs.DirEntry{fs.FileInfoToDirEntry(info)}, nil
+}
+
+// AnalyzeFS 分析文件系统 fsys（如内存文件树、embed.FS）中的代码库并返回代码画像和文件索引，
+// rootDir 仅作为报告中展示的根路径，文件索引关联 fsys 以便后续检测从中读取文件
+func (a *CodeAnalyzer) AnalyzeFS(fsys fs.FS, rootDir string) (*model.CodeProfile, *model.FileIndex, error) {
+	fileIndex := model.NewFileIndex(rootDir)
+	fileIndex.FS = fsys
+	return a.analyze(fileIndex)
+}
+
+// analyze 遍历文件索引对应的文件系统，建立文件索引并统计各语言的代码行数
+func (a *CodeAnalyzer) analyze(fileIndex *model.FileIndex) (*model.CodeProfile, *model.FileIndex, error) {
+	fsys := fileIndex.FileSystem()
 	// 准备并发处理
 	workers := autoWorkers()
 
@@ -75,10 +147,12 @@ func (a *CodeAnalyzer) AnalyzeCodeProfile(projectPath string) (*model.CodeProfil
 		go func() {
 			defer wg.Done()
 			for task := range tasks {
-				stats, err := CountFileStats(task.Path)
+				stats, imports, err := a.countFile(fsys, task.RelPath, task.LangDef.Name)
 				results <- AnalysisResult{
 					LangName: task.LangDef.Name,
+					RelPath:  task.RelPath,
 					Stats:    stats,
+					Imports:  imports,
 					Err:      err,
 				}
 			}
@@ -87,14 +161,23 @@ func (a *CodeAnalyzer) AnalyzeCodeProfile(projectPath string) (*model.CodeProfil
 
 	// 启动结果收集协程
 	stats := make(map[string]*model.LangSummary)
+	imports := make(map[string]model.FileImports)
+	fileStats := make(map[string]model.FileStat)
 	var errorFiles int
 	done := make(chan struct{})
 	go func() {
 		for res := range results {
 			if res.Err != nil {
 				errorFiles++
+				fileStats[res.RelPath] = model.FileStat{Language: res.LangName, Error: true}
 				continue
 			}
+			fileStats[res.RelPath] = model.FileStat{
+				Language: res.LangName,
+				Code:     res.Stats.Code,
+				Comment:  res.Stats.Comment,
+				Blank:    res.Stats.Blank,
+			}
 			summary, ok := stats[res.LangName]
 			if !ok {
 				summary = &model.LangSummary{Name: res.LangName}
@@ -104,32 +187,36 @@ func (a *CodeAnalyzer) AnalyzeCodeProfile(projectPath string) (*model.CodeProfil
 			summary.Code += res.Stats.Code
 			summary.Comment += res.Stats.Comment
 			summary.Blank += res.Stats.Blank
+			// 记录支持导入提取的源码文件，没有导入语句的文件同样记录
+			if supportsImports(res.LangName) {
+				imports[res.RelPath] = model.FileImports{Language: res.LangName, Modules: res.Imports}
+			}
 		}
 		close(done)
 	}()
 
 	// 遍历目录并分发任务
-	err = filepath.WalkDir(absPath, func(path string, dirEntry os.DirEntry, err error) error {
+	err := fs.WalkDir(fsys, ".", func(relPath string, dirEntry fs.DirEntry, err error) error {
 		if err != nil {
 			// 如果无法访问文件/目录，跳过
 			return nil
 		}
 		if dirEntry.IsDir() {
 			// 跳过隐藏目录，如 .git
-			if strings.HasPrefix(dirEntry.Name(), ".") && dirEntry.Name() != "." {
-				return filepath.SkipDir
+			if strings.HasPrefix(dirEntry.Name(), ".") && relPath != "." {
+				return fs.SkipDir
 			}
 			return nil
 		}
 
-		// 计算相对路径并添加到索引 (保持在主协程，无需锁)
-		relPath, _ := filepath.Rel(absPath, path)
-		// 统一使用 "/" 作为路径分隔符
-		relPath = filepath.ToSlash(relPath)
-		fileIndex.AddFile(relPath, dirEntry.Name(), filepath.Ext(dirEntry.Name()))
+		// 添加到索引 (保持在主协程，无需锁)，fs.FS 中的路径统一使用 "/" 作为分隔符
+		fileIndex.AddFile(relPath, dirEntry.Name(), path.Ext(dirEntry.Name()))
+		if a.ArchiveDepth > 0 && archivefs.IsArchive(dirEntry.Name()) {
+			a.indexArchive(fileIndex, fsys, relPath)
+		}
 
 		// 识别语言
-		langDef := extToLanguage[strings.ToLower(filepath.Ext(path))]
+		langDef := extToLanguage[strings.ToLower(path.Ext(relPath))]
 		if langDef == nil {
 			langDef = fileToLanguage[dirEntry.Name()]
 		}
@@ -137,7 +224,7 @@ func (a *CodeAnalyzer) AnalyzeCodeProfile(projectPath string) (*model.CodeProfil
 		if langDef != nil {
 			// 分发任务
 			tasks <- AnalysisTask{
-				Path:    path,
+				RelPath: relPath,
 				LangDef: langDef,
 			}
 		}
@@ -152,11 +239,83 @@ func (a *CodeAnalyzer) AnalyzeCodeProfile(projectPath string) (*model.CodeProfil
 	if err != nil {
 		return nil, nil, err
 	}
+	fileIndex.Imports = imports
+	fileIndex.Stats = fileStats
 
-	codeProfile := convertToCodeProfile(absPath, stats, errorFiles)
+	codeProfile := convertToCodeProfile(fileIndex.RootDir, fsys, stats, errorFiles)
 	return codeProfile, fileIndex, nil
 }
 
+// countFile 统计单个文件，文件系统能够提供内容哈希时优先复用缓存的结果
+func (a *CodeAnalyzer) countFile(fsys fs.FS, relPath string, language string) (FileStats, []string, error) {
+	hasher, ok := fsys.(BlobHasher)
+	if a.Cache == nil || !ok {
+		return countFSFile(fsys, relPath, language)
+	}
+	hash, err := hasher.BlobHash(relPath)
+	if err != nil {
+		return countFSFile(fsys, relPath, language)
+	}
+	key := language + ":" + hash
+	if entry, ok := a.Cache.get(key); ok {
+		return entry.stats, entry.imports, nil
+	}
+	stats, imports, err := countFSFile(fsys, relPath, language)
+	if err == nil {
+		a.Cache.put(key, cachedFile{stats: stats, imports: imports})
+	}
+	return stats, imports, err
+}
+
+// countFSFile 统计文件系统中单个文件的行数并提取导入的模块
+func countFSFile(fsys fs.FS, relPath string, language string) (FileStats, []string, error) {
+	file, err := fsys.Open(relPath)
+	if err != nil {
+		return FileStats{}, nil, err
+	}
+	defer file.Close()
+	return CountReaderStatsWithImports(file, language)
+}
+
+// indexArchive 将压缩包成员以虚拟路径加入文件索引，无法读取的压缩包仅保留其自身路径
+func (a *CodeAnalyzer) indexArchive(fileIndex *model.FileIndex, fsys fs.FS, relPath string) {
+	members, err := archivefs.ListFS(fsys, relPath, a.ArchiveDepth)
+	if err != nil {
+		logging.Debugf("list archive %s failed: %v", relPath, err)
+	}
+	for _, member := range members {
+		name := path.Base(member)
+		fileIndex.AddFile(relPath+archivefs.Separator+member, name, path.Ext(name))
+	}
+}
+
+// ProfileFromIndex 根据文件索引中记录的单文件统计生成代码画像，用于子项目等已分析目录的子集，
+// 语言分类以索引的根目录为准
+func ProfileFromIndex(index *model.FileIndex) *model.CodeProfile {
+	stats := make(map[string]*model.LangSummary)
+	var errorFiles int
+	for _, relPath := range index.Files {
+		stat, ok := index.Stats[relPath]
+		if !ok {
+			continue
+		}
+		if stat.Error {
+			errorFiles++
+			continue
+		}
+		summary, ok := stats[stat.Language]
+		if !ok {
+			summary = &model.LangSummary{Name: stat.Language}
+			stats[stat.Language] = summary
+		}
+		summary.Count++
+		summary.Code += stat.Code
+		summary.Comment += stat.Comment
+		summary.Blank += stat.Blank
+	}
+	return convertToCodeProfile(index.RootDir, index.FileSystem(), stats, errorFiles)
+}
+
 func autoWorkers() int {
 	workers := runtime.NumCPU() / 4
 	if workers < 1 {
@@ -169,7 +328,7 @@ func autoWorkers() int {
 }
 
 // convertToCodeProfile converts statistics to CodeCanvas CodeProfile.
-func convertToCodeProfile(absPath string, stats map[string]*model.LangSummary, errorFiles int) *model.CodeProfile {
+func convertToCodeProfile(absPath string, fsys fs.FS, stats map[string]*model.LangSummary, errorFiles int) *model.CodeProfile {
 
 	profile := &model.CodeProfile{
 		Path:              absPath,
@@ -202,13 +361,14 @@ func convertToCodeProfile(absPath string, stats map[string]*model.LangSummary, e
 		profile.TotalLines += langInfo.CodeLines + langInfo.CommentLines + langInfo.BlankLines
 	}
 
-	logging.Infof("profile ToJson: %s", utils.ToJson(profile))
+	logging.Debugf("profile ToJson: %s", utils.ToJson(profile))
 
 	// 进行语言信息分析
-	frontend, backend, desktop, other, allLang, expand := langengine.NewLangClassifier().DetectCategories(absPath, profile.LanguageInfos)
+	frontend, backend, desktop, mobile, other, allLang, expand := langengine.NewLangClassifier().DetectCategoriesFS(fsys, profile.LanguageInfos)
 	profile.FrontendLanguages = frontend
 	profile.BackendLanguages = backend
 	profile.DesktopLanguages = desktop
+	profile.MobileLanguages = mobile
 	profile.OtherLanguages = other
 	profile.Languages = allLang
 	profile.Expands = expand
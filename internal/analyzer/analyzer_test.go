@@ -1,11 +1,16 @@
 package analyzer
 
 import (
+	"archive/zip"
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"testing/fstest"
 
+	"github.com/winezer0/codecanvas/internal/langengine"
 	"github.com/winezer0/codecanvas/internal/model"
 )
 
@@ -90,7 +95,123 @@ console.log("Hi"); // Line comment
 	}
 }
 
+func TestAnalyzeFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go":          {Data: []byte("package main\n\n// Comment\nfunc main() {}\n")},
+		"pkg/util.go":      {Data: []byte("package pkg\n\nimport \"fmt\"\n")},
+		"pubspec.yaml":     {Data: []byte("dependencies:\n  flutter:\n    sdk: flutter\n")},
+		".cache/skip.go":   {Data: []byte("package skip\n")},
+		"docs/readme.text": {Data: []byte("hello\n")},
+	}
+
+	profile, index, err := NewCodeAnalyzer().AnalyzeFS(fsys, "memfs")
+	if err != nil {
+		t.Fatalf("AnalyzeFS failed: %v", err)
+	}
+	if index.FS == nil || index.RootDir != "memfs" {
+		t.Errorf("Index should keep the file system and root name, got root %q", index.RootDir)
+	}
+	if len(index.Files) != 4 {
+		t.Errorf("Expected 4 indexed files, got %v", index.Files)
+	}
+	if profile.TotalFiles != 3 {
+		t.Errorf("Expected 3 source files, got %d", profile.TotalFiles)
+	}
+	if stat := index.Stats["main.go"]; stat.Language != "Go" || stat.Code != 2 || stat.Comment != 1 {
+		t.Errorf("Unexpected stats for main.go: %+v", stat)
+	}
+	if imports := index.Imports["pkg/util.go"]; len(imports.Modules) != 1 || imports.Modules[0] != "fmt" {
+		t.Errorf("Unexpected imports for pkg/util.go: %+v", imports)
+	}
+	content, err := index.FileSystem().Open("pubspec.yaml")
+	if err != nil {
+		t.Errorf("Index file system should open pubspec.yaml: %v", err)
+	} else {
+		content.Close()
+	}
+}
+
+func TestAnalyzeSingleFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "composer.json"), []byte("{\n  \"require\": {}\n}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write composer.json: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write main.go: %v", err)
+	}
+
+	file := filepath.Join(tmpDir, "composer.json")
+	profile, index, err := NewCodeAnalyzer().AnalyzeCodeProfile(file)
+	if err != nil {
+		t.Fatalf("AnalyzeCodeProfile failed: %v", err)
+	}
+	if profile.Path != file || index.RootDir != tmpDir {
+		t.Errorf("Unexpected paths: profile %q, index root %q", profile.Path, index.RootDir)
+	}
+	if len(index.Files) != 1 || index.Files[0] != "composer.json" {
+		t.Errorf("Only the given file should be indexed, got %v", index.Files)
+	}
+	if len(profile.LanguageInfos) != 1 || profile.LanguageInfos[0].Name != "JSON" || profile.LanguageInfos[0].Files != 1 || profile.LanguageInfos[0].CodeLines != 3 {
+		t.Errorf("Unexpected languages: %+v", profile.LanguageInfos)
+	}
+	if _, err := index.FileSystem().Open("main.go"); err == nil {
+		t.Errorf("Sibling files should not be visible in the index file system")
+	}
+}
+
+func TestAnalyzeSingleArchive(t *testing.T) {
+	tmpDir := t.TempDir()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("META-INF/maven/com.alibaba/fastjson/pom.properties")
+	if err != nil {
+		t.Fatalf("Failed to create zip entry: %v", err)
+	}
+	w.Write([]byte("version=1.2.83\n"))
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Failed to close zip: %v", err)
+	}
+	war := filepath.Join(tmpDir, "app.war")
+	if err := os.WriteFile(war, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write app.war: %v", err)
+	}
+
+	analyzer := NewCodeAnalyzer()
+	analyzer.ArchiveDepth = 1
+	_, index, err := analyzer.AnalyzeCodeProfile(war)
+	if err != nil {
+		t.Fatalf("AnalyzeCodeProfile failed: %v", err)
+	}
+	if !index.Contains("app.war") || !index.Contains("app.war!/META-INF/maven/com.alibaba/fastjson/pom.properties") {
+		t.Errorf("Archive members should be indexed, got %v", index.Files)
+	}
+}
+
 // TestAllLanguagesCoverage verifies that the analyzer can identify and count all supported languages.
+func TestLanguageMappingIsUnambiguous(t *testing.T) {
+	owners := make(map[string][]string)
+	for _, language := range langengine.LanguageRules {
+		for _, ext := range language.Extensions {
+			owners[strings.ToLower(ext)] = append(owners[strings.ToLower(ext)], language.Name)
+		}
+		for _, name := range language.Filenames {
+			owners[name] = append(owners[name], language.Name)
+		}
+	}
+	for key, names := range owners {
+		if len(names) > 1 {
+			t.Errorf("%s is claimed by multiple languages: %v", key, names)
+		}
+	}
+
+	expected := map[string]string{".js": "JavaScript", ".mjs": "JavaScript", ".cjs": "JavaScript", ".cs": "C#", ".scss": "SCSS", ".less": "Less", ".css": "CSS"}
+	for ext, name := range expected {
+		if language := extToLanguage[ext]; language == nil || language.Name != name {
+			t.Errorf("Expected %s to map to %s, got %+v", ext, name, language)
+		}
+	}
+}
+
 func TestAllLanguagesCoverage(t *testing.T) {
 	// Create a temporary directory for test data
 	tmpDir, err := os.MkdirTemp("", "codecanvas_coverage_test")
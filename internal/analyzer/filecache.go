@@ -0,0 +1,56 @@
+package analyzer
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// BlobHasher 能够提供文件内容哈希的文件系统（如 git 提交的文件树），内容哈希相同的文件内容相同
+type BlobHasher interface {
+	BlobHash(name string) (string, error)
+}
+
+// FileCache 以文件内容哈希为键缓存单文件的行数统计与导入的模块，在多次分析（如历史上的多个提交）之间共享，
+// 使内容未变化的文件不再重复统计。只有实现 BlobHasher 的文件系统使用缓存
+type FileCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedFile
+	hits    atomic.Int64
+	misses  atomic.Int64
+}
+
+// cachedFile 缓存的单文件统计结果
+type cachedFile struct {
+	stats   FileStats
+	imports []string
+}
+
+// NewFileCache 创建空的文件统计缓存
+func NewFileCache() *FileCache {
+	return &FileCache{entries: make(map[string]cachedFile)}
+}
+
+// Counts 返回命中缓存（复用）与未命中缓存（重新统计）的文件数
+func (c *FileCache) Counts() (hits, misses int64) {
+	return c.hits.Load(), c.misses.Load()
+}
+
+// get 读取缓存，键包含语言以区分导入提取规则
+func (c *FileCache) get(key string) (cachedFile, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if ok {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	return entry, ok
+}
+
+// put 写入缓存
+func (c *FileCache) put(key string, entry cachedFile) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry
+}
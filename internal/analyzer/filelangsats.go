@@ -2,6 +2,7 @@ package analyzer
 
 import (
 	"bufio"
+	"io"
 	"os"
 	"strings"
 )
@@ -16,14 +17,27 @@ type FileStats struct {
 
 // CountFileStats 分析文件并返回其统计信息
 func CountFileStats(path string) (FileStats, error) {
+	stats, _, err := CountFileStatsWithImports(path, "")
+	return stats, err
+}
+
+// CountFileStatsWithImports 分析文件并返回其统计信息，同时提取 Go、Java、Python、JS/TS 与 PHP
+// 源码中 import/require/use 语句导入的模块，其他语言返回的模块列表为空
+func CountFileStatsWithImports(path string, language string) (FileStats, []string, error) {
 	file, err := os.Open(path)
 	if err != nil {
-		return FileStats{}, err
+		return FileStats{}, nil, err
 	}
 	defer file.Close()
+	return CountReaderStatsWithImports(file, language)
+}
+
+// CountReaderStatsWithImports 与 CountFileStatsWithImports 相同，内容从 r 中读取
+func CountReaderStatsWithImports(r io.Reader, language string) (FileStats, []string, error) {
+	imports := newImportScanner(language)
 
 	stats := FileStats{}
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(r)
 	// 增加长行的缓冲区大小
 	const maxCapacity = 1024 * 1024
 	buf := make([]byte, maxCapacity)
@@ -72,6 +86,11 @@ func CountFileStats(path string) (FileStats, error) {
 			continue
 		}
 
+		// 提取导入语句
+		if imports != nil {
+			imports.scan(trimmedLine)
+		}
+
 		// 检查行内注释
 		if strings.Contains(line, "//") || strings.Contains(line, "#") {
 			stats.Code++
@@ -82,5 +101,8 @@ func CountFileStats(path string) (FileStats, error) {
 		stats.Code++
 	}
 
-	return stats, scanner.Err()
+	if imports == nil {
+		return stats, nil, scanner.Err()
+	}
+	return stats, imports.modules, scanner.Err()
 }
@@ -0,0 +1,155 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// goImportRe 匹配 Go 的 import "x"、import alias "x" 以及 import 块中的 alias "x"
+	goImportRe = regexp.MustCompile(`^(?:import\s+)?(?:[\w.]+\s+)?"([^"]+)"`)
+	// javaImportRe 匹配 Java 的 import a.b.C; 与 import static a.b.C.d;
+	javaImportRe = regexp.MustCompile(`^import\s+(?:static\s+)?([\w.]+(?:\.\*)?)\s*;`)
+	// pythonFromRe 匹配 Python 的 from a.b import c
+	pythonFromRe = regexp.MustCompile(`^from\s+([\w.]+)\s+import\b`)
+	// pythonImportRe 匹配 Python 的 import a.b, c as d
+	pythonImportRe = regexp.MustCompile(`^import\s+([\w.]+(?:\s+as\s+\w+)?(?:\s*,\s*[\w.]+(?:\s+as\s+\w+)?)*)`)
+	// jsFromRe 匹配 JS/TS 的 import x from 'm'、export { x } from 'm' 以及多行导入结尾的 } from 'm'
+	jsFromRe = regexp.MustCompile(`^(?:(?:import|export)\b|\}).*?\bfrom\s*['"]([^'"]+)['"]`)
+	// jsSideEffectRe 匹配 JS/TS 的 import 'm'
+	jsSideEffectRe = regexp.MustCompile(`^import\s*['"]([^'"]+)['"]`)
+	// jsRequireRe 匹配 require('m') 与动态 import('m')
+	jsRequireRe = regexp.MustCompile(`\b(?:require|import)\s*\(\s*['"]([^'"]+)['"]\s*\)`)
+	// phpUseRe 匹配 PHP 的 use A\B;、use A\B as C;、use function A\b; 与分组 use A\{B, C};
+	phpUseRe = regexp.MustCompile(`^use\s+(?:function\s+|const\s+)?\\?(\w+(?:\\\w+)*)(\\\{[^}]*\})?`)
+)
+
+// importScanner 在逐行统计时提取源码中的导入模块
+type importScanner struct {
+	language string
+	// inGoBlock 是否处于 Go 的 import ( ... ) 块中
+	inGoBlock bool
+	modules   []string
+	seen      map[string]bool
+}
+
+// supportsImports 判断是否支持提取该语言的导入语句
+func supportsImports(language string) bool {
+	switch language {
+	case "Go", "Java", "Python", "PHP", "JavaScript", "Node.js", "TypeScript", "JSX", "TSX", "Vue":
+		return true
+	}
+	return false
+}
+
+// newImportScanner 创建指定语言的导入提取器，不支持的语言返回 nil
+func newImportScanner(language string) *importScanner {
+	if !supportsImports(language) {
+		return nil
+	}
+	return &importScanner{language: language, seen: make(map[string]bool)}
+}
+
+// scan 处理一行去除首尾空白的代码
+func (s *importScanner) scan(line string) {
+	switch s.language {
+	case "Go":
+		s.scanGo(line)
+	case "Java":
+		if m := javaImportRe.FindStringSubmatch(line); m != nil {
+			s.add(strings.TrimSuffix(m[1], ".*"))
+		}
+	case "Python":
+		s.scanPython(line)
+	case "PHP":
+		s.scanPHP(line)
+	default:
+		s.scanJS(line)
+	}
+}
+
+// scanGo 提取单行 import 与 import 块中的包路径
+func (s *importScanner) scanGo(line string) {
+	if s.inGoBlock {
+		if strings.HasPrefix(line, ")") {
+			s.inGoBlock = false
+			return
+		}
+		if m := goImportRe.FindStringSubmatch(line); m != nil {
+			s.add(m[1])
+		}
+		return
+	}
+	if !strings.HasPrefix(line, "import") {
+		return
+	}
+	rest := strings.TrimSpace(strings.TrimPrefix(line, "import"))
+	if strings.HasPrefix(rest, "(") {
+		s.inGoBlock = true
+		s.scanGo(strings.TrimSpace(strings.TrimPrefix(rest, "(")))
+		return
+	}
+	if m := goImportRe.FindStringSubmatch(line); m != nil {
+		s.add(m[1])
+	}
+}
+
+// scanPython 提取 import 与 from ... import 中的模块，相对导入保留前导的 "."
+func (s *importScanner) scanPython(line string) {
+	if strings.HasPrefix(line, "from") {
+		if strings.HasPrefix(line, "from .") {
+			return
+		}
+		if m := pythonFromRe.FindStringSubmatch(line); m != nil {
+			s.add(m[1])
+		}
+		return
+	}
+	if m := pythonImportRe.FindStringSubmatch(line); m != nil {
+		for _, part := range strings.Split(m[1], ",") {
+			if fields := strings.Fields(part); len(fields) > 0 {
+				s.add(fields[0])
+			}
+		}
+	}
+}
+
+// scanPHP 提取 use 语句中的命名空间，分组导入展开为各个完整的命名空间
+func (s *importScanner) scanPHP(line string) {
+	m := phpUseRe.FindStringSubmatch(line)
+	if m == nil {
+		return
+	}
+	if m[2] == "" {
+		s.add(m[1])
+		return
+	}
+	for _, item := range strings.Split(strings.Trim(m[2], `\{}`), ",") {
+		if fields := strings.Fields(item); len(fields) > 0 {
+			s.add(m[1] + `\` + fields[0])
+		}
+	}
+}
+
+// scanJS 提取 import/export from、import 'm'、require('m') 与 import('m') 中的模块
+func (s *importScanner) scanJS(line string) {
+	if m := jsFromRe.FindStringSubmatch(line); m != nil {
+		s.add(m[1])
+	} else if m := jsSideEffectRe.FindStringSubmatch(line); m != nil {
+		s.add(m[1])
+	}
+	if strings.Contains(line, "(") {
+		for _, m := range jsRequireRe.FindAllStringSubmatch(line, -1) {
+			s.add(m[1])
+		}
+	}
+}
+
+// add 记录去重后的模块
+func (s *importScanner) add(module string) {
+	if module == "" || s.seen[module] {
+		return
+	}
+	s.seen[module] = true
+	s.modules = append(s.modules, module)
+}
@@ -0,0 +1,145 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCountFileStatsWithImports(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		content  string
+		expected []string
+	}{
+		{
+			name:     "main.go",
+			language: "Go",
+			content: `package main
+
+import "fmt"
+import log "github.com/sirupsen/logrus"
+
+import (
+	"os"
+	_ "github.com/lib/pq"
+	g "github.com/gin-gonic/gin"
+)
+`,
+			expected: []string{"fmt", "github.com/sirupsen/logrus", "os", "github.com/lib/pq", "github.com/gin-gonic/gin"},
+		},
+		{
+			name:     "Main.java",
+			language: "Java",
+			content: `package com.acme;
+import java.util.List;
+import static org.junit.Assert.assertTrue;
+import com.google.common.collect.*;
+`,
+			expected: []string{"java.util.List", "org.junit.Assert.assertTrue", "com.google.common.collect"},
+		},
+		{
+			name:     "app.py",
+			language: "Python",
+			content: `import os, yaml as y
+from django.conf import settings
+from . import sibling
+from .models import User
+# import commented
+`,
+			expected: []string{"os", "yaml", "django.conf"},
+		},
+		{
+			name:     "server.js",
+			language: "Node.js",
+			content: `const express = require('express');
+const { join } = require("node:path");
+`,
+			expected: []string{"express", "node:path"},
+		},
+		{
+			name:     "app.ts",
+			language: "TypeScript",
+			content: `import React, { useState } from 'react';
+import {
+  a,
+  b,
+} from "lodash";
+import './style.css';
+export * from '@scope/pkg/sub';
+const fs = require('fs');
+const lazy = () => import('dayjs');
+// import ignored from 'ignored';
+`,
+			expected: []string{"react", "lodash", "./style.css", "@scope/pkg/sub", "fs", "dayjs"},
+		},
+		{
+			name:     "index.php",
+			language: "PHP",
+			content: `<?php
+namespace App;
+use Illuminate\Support\Facades\Route;
+use \Monolog\Logger as Log;
+use function GuzzleHttp\json_encode;
+use Symfony\Component\{Console\Application, Process\Process};
+`,
+			expected: []string{`Illuminate\Support\Facades\Route`, `Monolog\Logger`, `GuzzleHttp\json_encode`,
+				`Symfony\Component\Console\Application`, `Symfony\Component\Process\Process`},
+		},
+	}
+
+	tmpDir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(tmpDir, tt.name)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", tt.name, err)
+			}
+			_, imports, err := CountFileStatsWithImports(path, tt.language)
+			if err != nil {
+				t.Fatalf("CountFileStatsWithImports failed: %v", err)
+			}
+			if !reflect.DeepEqual(imports, tt.expected) {
+				t.Errorf("Expected imports %v, got %v", tt.expected, imports)
+			}
+		})
+	}
+
+	path := filepath.Join(tmpDir, "style.css")
+	if err := os.WriteFile(path, []byte("@import 'x.css';\n"), 0644); err != nil {
+		t.Fatalf("Failed to write style.css: %v", err)
+	}
+	if _, imports, _ := CountFileStatsWithImports(path, "CSS"); imports != nil {
+		t.Errorf("Expected no imports for unsupported language, got %v", imports)
+	}
+}
+
+func TestAnalyzeCodeProfileImports(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"main.go":   "package main\n\nimport \"github.com/gin-gonic/gin\"\n",
+		"empty.go":  "package main\n",
+		"README.md": "import x from 'y'\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	_, index, err := NewCodeAnalyzer().AnalyzeCodeProfile(tmpDir)
+	if err != nil {
+		t.Fatalf("AnalyzeCodeProfile failed: %v", err)
+	}
+	if got := index.Imports["main.go"]; got.Language != "Go" || !reflect.DeepEqual(got.Modules, []string{"github.com/gin-gonic/gin"}) {
+		t.Errorf("Unexpected imports for main.go: %+v", got)
+	}
+	if _, ok := index.Imports["empty.go"]; !ok {
+		t.Errorf("Source files without imports should still be recorded")
+	}
+	if _, ok := index.Imports["README.md"]; ok {
+		t.Errorf("Unsupported languages should not be recorded")
+	}
+}
@@ -0,0 +1,373 @@
+// Package archivefs 读取 jar、war、ear、zip、whl、tgz 等压缩包的成员，
+// 以 "app.war!/WEB-INF/lib/x.jar!/META-INF/MANIFEST.MF" 形式的虚拟路径表示压缩包内（包括嵌套压缩包内）的文件。
+package archivefs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Separator 压缩包路径与成员路径之间的分隔符
+const Separator = "!/"
+
+// maxMemberSize 读取压缩包成员（包括嵌套压缩包）的最大字节数，超出时跳过
+const maxMemberSize = 64 * 1024 * 1024
+
+// 压缩包类型
+const (
+	kindZip = "zip"
+	kindTar = "tar"
+	kindTgz = "tgz"
+)
+
+// zipExtensions 使用 zip 格式的压缩包扩展名
+var zipExtensions = map[string]bool{
+	".jar": true, ".war": true, ".ear": true, ".zip": true, ".whl": true, ".aar": true, ".nupkg": true,
+}
+
+// archiveKind 根据文件名判断压缩包类型，非压缩包返回空字符串
+func archiveKind(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case zipExtensions[path.Ext(lower)]:
+		return kindZip
+	case strings.HasSuffix(lower, ".tgz"), strings.HasSuffix(lower, ".tar.gz"):
+		return kindTgz
+	case strings.HasSuffix(lower, ".tar"):
+		return kindTar
+	}
+	return ""
+}
+
+// IsArchive 判断文件名是否为支持展开的压缩包
+func IsArchive(name string) bool {
+	return archiveKind(name) != ""
+}
+
+// IsMember 判断路径是否为压缩包成员的虚拟路径
+func IsMember(p string) bool {
+	return strings.Contains(filepath.ToSlash(p), Separator)
+}
+
+// List 列出磁盘上压缩包中的成员文件，返回相对于压缩包的虚拟路径；
+// depth 为展开的嵌套层数，1 只列出压缩包自身的成员，2 同时展开成员中的压缩包，依此类推
+func List(archivePath string, depth int) ([]string, error) {
+	return ListFS(nil, archivePath, depth)
+}
+
+// ListFS 列出文件系统 fsys 中压缩包的成员文件，fsys 为 nil 时 name 为磁盘路径
+func ListFS(fsys fs.FS, name string, depth int) ([]string, error) {
+	if depth < 1 {
+		return nil, nil
+	}
+	reader, size, closer, err := openArchive(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	var members []string
+	err = listMembers(archiveKind(name), reader, size, "", depth, &members)
+	return members, err
+}
+
+// openArchive 打开压缩包用于随机读取；文件系统中的文件不支持随机读取时读入内存
+func openArchive(fsys fs.FS, name string) (io.ReaderAt, int64, io.Closer, error) {
+	var (
+		file fs.File
+		err  error
+	)
+	if fsys == nil {
+		file, err = os.Open(filepath.FromSlash(name))
+	} else {
+		file, err = fsys.Open(name)
+	}
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, nil, err
+	}
+	if reader, ok := file.(io.ReaderAt); ok {
+		return reader, stat.Size(), file, nil
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	return bytes.NewReader(content), int64(len(content)), io.NopCloser(nil), nil
+}
+
+// listMembers 遍历压缩包成员并记录虚拟路径，成员为压缩包且未达到嵌套层数时递归展开
+func listMembers(kind string, r io.ReaderAt, size int64, prefix string, depth int, members *[]string) error {
+	return eachMember(kind, r, size, func(name string, memberSize int64, open func() (io.Reader, error)) error {
+		*members = append(*members, prefix+name)
+		if depth <= 1 || !IsArchive(name) || memberSize > maxMemberSize {
+			return nil
+		}
+		content, err := readMember(open)
+		if err != nil {
+			return nil
+		}
+		// 无法解析的嵌套压缩包仅记录其自身路径
+		listMembers(archiveKind(name), bytes.NewReader(content), int64(len(content)), prefix+name+Separator, depth-1, members)
+		return nil
+	})
+}
+
+// ReadFile 读取虚拟路径对应的压缩包成员内容，如 /src/app.war!/WEB-INF/lib/x.jar!/META-INF/MANIFEST.MF
+func ReadFile(virtualPath string) ([]byte, error) {
+	return ReadFileFS(nil, filepath.ToSlash(virtualPath))
+}
+
+// ReadFileFS 读取文件系统 fsys 中虚拟路径对应的压缩包成员内容，fsys 为 nil 时压缩包为磁盘路径；
+// 读取同一压缩包的多个成员时应使用 Cache，避免每次重新打开并解压外层压缩包
+func ReadFileFS(fsys fs.FS, virtualPath string) ([]byte, error) {
+	cache := NewCache(fsys)
+	defer cache.Close()
+	return cache.ReadFile(virtualPath)
+}
+
+// Cache 缓存一次扫描中打开的压缩包：最外层压缩包保持打开，嵌套的压缩包解压一次后保留在内存中，
+// 每个压缩包只建立一次成员目录。Cache 不是并发安全的，使用完毕后调用 Close 关闭磁盘上的压缩包
+type Cache struct {
+	fsys     fs.FS
+	archives map[string]*openedArchive
+}
+
+// openedArchive 已打开的压缩包：zip 记录成员目录并按需解压成员，tar 与 tgz 只能顺序读取，打开时一次读出所有成员；
+// err 为打开失败的原因，失败的压缩包同样被缓存，不再重复尝试
+type openedArchive struct {
+	zipFiles   map[string]*zip.File
+	tarFiles   map[string][]byte
+	largeFiles map[string]bool
+	closer     io.Closer
+	err        error
+}
+
+// NewCache 创建读取文件系统 fsys 中压缩包成员的缓存，fsys 为 nil 时压缩包为磁盘路径
+func NewCache(fsys fs.FS) *Cache {
+	return &Cache{fsys: fsys, archives: make(map[string]*openedArchive)}
+}
+
+// ReadFile 读取虚拟路径对应的压缩包成员内容，路径中的各层压缩包在首次访问时打开并缓存
+func (c *Cache) ReadFile(virtualPath string) ([]byte, error) {
+	i := strings.LastIndex(virtualPath, Separator)
+	if i < 0 {
+		return nil, fmt.Errorf("%s is not an archive member path", virtualPath)
+	}
+	archivePath, member := virtualPath[:i], virtualPath[i+len(Separator):]
+	archive := c.open(archivePath)
+	if archive.err != nil {
+		return nil, archive.err
+	}
+	content, err := archive.read(member)
+	if err != nil {
+		return nil, fmt.Errorf("read %s from %s failed: %v", member, archivePath, err)
+	}
+	return content, nil
+}
+
+// Close 关闭缓存中打开的磁盘文件并清空缓存
+func (c *Cache) Close() error {
+	var firstErr error
+	for _, archive := range c.archives {
+		if archive.closer == nil {
+			continue
+		}
+		if err := archive.closer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	c.archives = make(map[string]*openedArchive)
+	return firstErr
+}
+
+// open 返回缓存中的压缩包，首次访问时打开：嵌套的压缩包从其所在的压缩包中读出
+func (c *Cache) open(archivePath string) *openedArchive {
+	if archive, ok := c.archives[archivePath]; ok {
+		return archive
+	}
+	archive := &openedArchive{}
+	c.archives[archivePath] = archive
+
+	var (
+		reader io.ReaderAt
+		size   int64
+	)
+	if IsMember(archivePath) {
+		content, err := c.ReadFile(archivePath)
+		if err != nil {
+			archive.err = err
+			return archive
+		}
+		reader, size = bytes.NewReader(content), int64(len(content))
+	} else {
+		var err error
+		reader, size, archive.closer, err = openArchive(c.fsys, archivePath)
+		if err != nil {
+			archive.err = err
+			return archive
+		}
+	}
+	if err := archive.index(archiveKind(archivePath), reader, size); err != nil {
+		archive.err = fmt.Errorf("open archive %s failed: %v", archivePath, err)
+	}
+	return archive
+}
+
+// index 建立压缩包的成员目录，同名成员保留第一个
+func (a *openedArchive) index(kind string, r io.ReaderAt, size int64) error {
+	if kind == kindZip {
+		reader, err := zip.NewReader(r, size)
+		if err != nil {
+			return err
+		}
+		a.zipFiles = make(map[string]*zip.File, len(reader.File))
+		for _, file := range reader.File {
+			name := cleanName(file.Name)
+			if name == "" || file.FileInfo().IsDir() {
+				continue
+			}
+			if _, ok := a.zipFiles[name]; !ok {
+				a.zipFiles[name] = file
+			}
+		}
+		return nil
+	}
+
+	a.tarFiles = make(map[string][]byte)
+	a.largeFiles = make(map[string]bool)
+	return eachMember(kind, r, size, func(name string, memberSize int64, open func() (io.Reader, error)) error {
+		if _, ok := a.tarFiles[name]; ok || a.largeFiles[name] {
+			return nil
+		}
+		if memberSize > maxMemberSize {
+			a.largeFiles[name] = true
+			return nil
+		}
+		content, err := readMember(open)
+		if err != nil {
+			return err
+		}
+		a.tarFiles[name] = content
+		return nil
+	})
+}
+
+// read 读取压缩包中的成员，zip 成员每次读取时解压
+func (a *openedArchive) read(member string) ([]byte, error) {
+	if a.zipFiles != nil {
+		file, ok := a.zipFiles[member]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		if file.UncompressedSize64 > maxMemberSize {
+			return nil, fmt.Errorf("member larger than %d bytes", maxMemberSize)
+		}
+		return readMember(func() (io.Reader, error) {
+			return file.Open()
+		})
+	}
+	if a.largeFiles[member] {
+		return nil, fmt.Errorf("member larger than %d bytes", maxMemberSize)
+	}
+	content, ok := a.tarFiles[member]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return content, nil
+}
+
+// eachMember 按压缩包类型遍历其中的普通文件，成员名称经过规范化，目录条目被跳过；
+// open 返回的读取器仅在回调期间有效
+func eachMember(kind string, r io.ReaderAt, size int64, fn func(name string, size int64, open func() (io.Reader, error)) error) error {
+	switch kind {
+	case kindZip:
+		reader, err := zip.NewReader(r, size)
+		if err != nil {
+			return err
+		}
+		for _, file := range reader.File {
+			name := cleanName(file.Name)
+			if name == "" || file.FileInfo().IsDir() {
+				continue
+			}
+			open := func() (io.Reader, error) {
+				return file.Open()
+			}
+			if err := fn(name, int64(file.UncompressedSize64), open); err != nil {
+				return err
+			}
+		}
+		return nil
+	case kindTar, kindTgz:
+		var stream io.Reader = io.NewSectionReader(r, 0, size)
+		if kind == kindTgz {
+			gz, err := gzip.NewReader(stream)
+			if err != nil {
+				return err
+			}
+			defer gz.Close()
+			stream = gz
+		}
+		tr := tar.NewReader(stream)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			name := cleanName(header.Name)
+			if name == "" || header.Typeflag != tar.TypeReg {
+				continue
+			}
+			if err := fn(name, header.Size, func() (io.Reader, error) { return tr, nil }); err != nil {
+				return err
+			}
+		}
+	}
+	return fmt.Errorf("unsupported archive type")
+}
+
+// readMember 读取成员内容，超过 maxMemberSize 时返回错误
+func readMember(open func() (io.Reader, error)) ([]byte, error) {
+	reader, err := open()
+	if err != nil {
+		return nil, err
+	}
+	if closer, ok := reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+	content, err := io.ReadAll(io.LimitReader(reader, maxMemberSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxMemberSize {
+		return nil, fmt.Errorf("member larger than %d bytes", maxMemberSize)
+	}
+	return content, nil
+}
+
+// cleanName 规范化成员路径并去除前导的 "/"，成员名称中的 "!/" 会与虚拟路径分隔符冲突，此类成员被忽略
+func cleanName(name string) string {
+	name = strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(name, "\\", "/")), "/")
+	if strings.Contains(name, Separator) {
+		return ""
+	}
+	return name
+}
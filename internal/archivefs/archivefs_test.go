@@ -0,0 +1,152 @@
+package archivefs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+// buildZip 生成 zip 内容，files 为成员名称与内容成对出现
+func buildZip(t *testing.T, files ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for i := 0; i+1 < len(files); i += 2 {
+		w, err := zw.Create(files[i])
+		if err != nil {
+			t.Fatalf("create zip member failed: %v", err)
+		}
+		w.Write([]byte(files[i+1]))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close zip failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestListAndReadNestedArchives(t *testing.T) {
+	jar := buildZip(t,
+		"META-INF/MANIFEST.MF", "Manifest-Version: 1.0\n",
+		"META-INF/maven/com.alibaba/fastjson/pom.properties", "version=1.2.83\n",
+	)
+	war := buildZip(t,
+		"WEB-INF/web.xml", "<web-app/>",
+		"WEB-INF/lib/fastjson-1.2.83.jar", string(jar),
+	)
+	dir := t.TempDir()
+	warPath := filepath.Join(dir, "app.war")
+	if err := os.WriteFile(warPath, war, 0644); err != nil {
+		t.Fatalf("write war failed: %v", err)
+	}
+
+	members, err := List(warPath, 1)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if want := []string{"WEB-INF/web.xml", "WEB-INF/lib/fastjson-1.2.83.jar"}; !reflect.DeepEqual(members, want) {
+		t.Errorf("List(depth 1) = %v, want %v", members, want)
+	}
+
+	members, err = List(warPath, 2)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	want := []string{
+		"WEB-INF/web.xml",
+		"WEB-INF/lib/fastjson-1.2.83.jar",
+		"WEB-INF/lib/fastjson-1.2.83.jar!/META-INF/MANIFEST.MF",
+		"WEB-INF/lib/fastjson-1.2.83.jar!/META-INF/maven/com.alibaba/fastjson/pom.properties",
+	}
+	if !reflect.DeepEqual(members, want) {
+		t.Errorf("List(depth 2) = %v, want %v", members, want)
+	}
+
+	content, err := ReadFile(warPath + "!/WEB-INF/lib/fastjson-1.2.83.jar!/META-INF/maven/com.alibaba/fastjson/pom.properties")
+	if err != nil || string(content) != "version=1.2.83\n" {
+		t.Errorf("ReadFile nested member = %q, %v", content, err)
+	}
+	if _, err := ReadFile(warPath + "!/WEB-INF/missing.xml"); err == nil {
+		t.Errorf("ReadFile should fail for missing members")
+	}
+}
+
+func TestListTarball(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	content := `{"name": "left-pad", "version": "1.3.0"}`
+	tw.WriteHeader(&tar.Header{Name: "package/", Typeflag: tar.TypeDir, Mode: 0755})
+	tw.WriteHeader(&tar.Header{Name: "package/package.json", Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(content))})
+	tw.Write([]byte(content))
+	tw.Close()
+	gz.Close()
+
+	tgzPath := filepath.Join(t.TempDir(), "left-pad-1.3.0.tgz")
+	if err := os.WriteFile(tgzPath, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("write tgz failed: %v", err)
+	}
+	members, err := List(tgzPath, 1)
+	if err != nil || !reflect.DeepEqual(members, []string{"package/package.json"}) {
+		t.Errorf("List tgz = %v, %v", members, err)
+	}
+	got, err := ReadFile(tgzPath + Separator + "package/package.json")
+	if err != nil || string(got) != content {
+		t.Errorf("ReadFile tgz member = %q, %v", got, err)
+	}
+}
+
+// countingFS 记录每个文件被打开的次数
+type countingFS struct {
+	fstest.MapFS
+	opens map[string]int
+}
+
+func (c *countingFS) Open(name string) (fs.File, error) {
+	c.opens[name]++
+	return c.MapFS.Open(name)
+}
+
+func TestCacheOpensArchivesOnce(t *testing.T) {
+	jar := buildZip(t,
+		"META-INF/MANIFEST.MF", "Manifest-Version: 1.0\n",
+		"META-INF/maven/com.alibaba/fastjson/pom.properties", "version=1.2.83\n",
+	)
+	war := buildZip(t,
+		"WEB-INF/web.xml", "<web-app/>",
+		"WEB-INF/lib/fastjson-1.2.83.jar", string(jar),
+	)
+	fsys := &countingFS{MapFS: fstest.MapFS{"app.war": {Data: war}}, opens: make(map[string]int)}
+
+	cache := NewCache(fsys)
+	defer cache.Close()
+	reads := map[string]string{
+		"app.war!/WEB-INF/web.xml":                                                                     "<web-app/>",
+		"app.war!/WEB-INF/lib/fastjson-1.2.83.jar!/META-INF/MANIFEST.MF":                               "Manifest-Version: 1.0\n",
+		"app.war!/WEB-INF/lib/fastjson-1.2.83.jar!/META-INF/maven/com.alibaba/fastjson/pom.properties": "version=1.2.83\n",
+	}
+	for virtualPath, want := range reads {
+		content, err := cache.ReadFile(virtualPath)
+		if err != nil || string(content) != want {
+			t.Errorf("ReadFile(%s) = %q, %v", virtualPath, content, err)
+		}
+	}
+	if _, err := cache.ReadFile("app.war!/WEB-INF/lib/fastjson-1.2.83.jar!/missing"); err == nil {
+		t.Errorf("ReadFile should fail for missing nested members")
+	}
+	if _, err := cache.ReadFile("missing.war!/WEB-INF/web.xml"); err == nil {
+		t.Errorf("ReadFile should fail for missing archives")
+	}
+	if got := fsys.opens["app.war"]; got != 1 {
+		t.Errorf("app.war opened %d times, expected 1", got)
+	}
+	if got := len(cache.archives); got != 3 {
+		t.Errorf("cache holds %d archives, expected app.war, the nested jar and the missing archive", got)
+	}
+}
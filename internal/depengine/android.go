@@ -0,0 +1,232 @@
+package depengine
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+	"github.com/winezer0/codecanvas/internal/utils"
+)
+
+// AndroidParser 解析 Android 项目的 Gradle 构建脚本与 AndroidManifest.xml，
+// 输出 Android Gradle 插件（build 作用域）与 minSdk/targetSdk/compileSdk（sdk 作用域）
+type AndroidParser struct{}
+
+func init() {
+	Register(&AndroidParser{})
+}
+
+// Android 插件与 SDK 级别名称
+const (
+	AndroidAppPlugin     = "com.android.application"
+	AndroidLibraryPlugin = "com.android.library"
+	AndroidGradlePlugin  = "com.android.tools.build:gradle"
+	AndroidMinSdk        = "Android minSdk"
+	AndroidTargetSdk     = "Android targetSdk"
+	AndroidCompileSdk    = "Android compileSdk"
+)
+
+var (
+	// gradlePluginIDRe 匹配 plugins 块中的 id 'com.android.application' version '8.2.0'
+	gradlePluginIDRe = regexp.MustCompile(`\bid\s*\(?\s*["'](com\.android\.(?:application|library))["']\s*\)?(?:\s*version\s*\(?\s*["']([^"']+)["'])?`)
+	// gradleApplyPluginRe 匹配 apply plugin: 'com.android.application'
+	gradleApplyPluginRe = regexp.MustCompile(`apply\s+plugin\s*:\s*["'](com\.android\.(?:application|library))["']`)
+	// gradleAliasRe 匹配 plugins 块中的 alias(libs.plugins.android.application)
+	gradleAliasRe = regexp.MustCompile(`\balias\s*\(\s*libs\.plugins\.([A-Za-z0-9_.]+)\s*\)`)
+	// gradleClasspathRe 匹配 buildscript 中的 Android Gradle 插件 classpath
+	gradleClasspathRe = regexp.MustCompile(`classpath\s*\(?\s*["']com\.android\.tools\.build:gradle:([^"']+)["']`)
+	// gradleSdkRe 匹配 minSdk/targetSdk/compileSdk 及其 *Version 写法
+	gradleSdkRe = regexp.MustCompile(`(?m)^\s*(minSdk|targetSdk|compileSdk)(?:Version)?\b\s*(?:=\s*)?\(?\s*([A-Za-z0-9_."']+)`)
+)
+
+// gradleSdkNames Gradle 中的 SDK 属性到 SDK 级别名称的映射
+var gradleSdkNames = map[string]string{
+	"minSdk":     AndroidMinSdk,
+	"targetSdk":  AndroidTargetSdk,
+	"compileSdk": AndroidCompileSdk,
+}
+
+// Ecosystem 返回 Android 生态名称
+func (p *AndroidParser) Ecosystem() string {
+	return model.EcosystemAndroid
+}
+
+// Match 匹配 Gradle 构建脚本与 src 目录下的 AndroidManifest.xml
+func (p *AndroidParser) Match(relPath string) bool {
+	if inVendorDir(relPath, "build", "node_modules", "intermediates") {
+		return false
+	}
+	switch strings.ToLower(path.Base(relPath)) {
+	case "build.gradle", "build.gradle.kts", "androidmanifest.xml":
+		return true
+	}
+	return false
+}
+
+// Parse 根据文件名分派到对应的解析逻辑
+func (p *AndroidParser) Parse(file ManifestFile) ([]model.Dependency, error) {
+	if strings.EqualFold(path.Base(file.Path), "AndroidManifest.xml") {
+		return p.parseManifest(file)
+	}
+	return p.parseGradle(file), nil
+}
+
+// parseGradle 解析 Gradle 脚本中的 Android 插件声明与 SDK 级别
+func (p *AndroidParser) parseGradle(file ManifestFile) []model.Dependency {
+	text := string(file.Content)
+	var deps []model.Dependency
+
+	plugins := make(map[string]string)
+	for _, m := range gradlePluginIDRe.FindAllStringSubmatch(text, -1) {
+		plugins[m[1]] = m[2]
+	}
+	for _, m := range gradleApplyPluginRe.FindAllStringSubmatch(text, -1) {
+		if _, ok := plugins[m[1]]; !ok {
+			plugins[m[1]] = ""
+		}
+	}
+	if aliases := gradleAliasRe.FindAllStringSubmatch(text, -1); len(aliases) > 0 {
+		catalog := p.pluginCatalog(file)
+		for _, m := range aliases {
+			if plugin, ok := catalog[gradleCatalogKey(m[1])]; ok && strings.HasPrefix(plugin[0], "com.android.") {
+				plugins[plugin[0]] = plugin[1]
+			}
+		}
+	}
+	for _, name := range []string{AndroidAppPlugin, AndroidLibraryPlugin} {
+		if version, ok := plugins[name]; ok {
+			deps = append(deps, p.newDependency(file.Path, name, version, model.ScopeBuild))
+		}
+	}
+	if m := gradleClasspathRe.FindStringSubmatch(text); m != nil {
+		deps = append(deps, p.newDependency(file.Path, AndroidGradlePlugin, m[1], model.ScopeBuild))
+	}
+
+	// SDK 级别只在应用或库模块中声明
+	seen := make(map[string]bool)
+	for _, m := range gradleSdkRe.FindAllStringSubmatch(text, -1) {
+		name := gradleSdkNames[m[1]]
+		if seen[name] {
+			continue
+		}
+		level := p.resolveSdkLevel(file, strings.Trim(m[2], `"'`))
+		if level == "" {
+			continue
+		}
+		seen[name] = true
+		deps = append(deps, p.newDependency(file.Path, name, level, model.ScopeSDK))
+	}
+	return deps
+}
+
+// parseManifest 解析 AndroidManifest.xml 中 uses-sdk 声明的 SDK 级别
+func (p *AndroidParser) parseManifest(file ManifestFile) ([]model.Dependency, error) {
+	root, err := utils.ParseXML(file.Content)
+	if err != nil {
+		return nil, err
+	}
+	var deps []model.Dependency
+	for _, usesSdk := range root.Children("uses-sdk") {
+		if level := usesSdk.Attr("minSdkVersion"); isDigits(level) {
+			deps = append(deps, p.newDependency(file.Path, AndroidMinSdk, level, model.ScopeSDK))
+		}
+		if level := usesSdk.Attr("targetSdkVersion"); isDigits(level) {
+			deps = append(deps, p.newDependency(file.Path, AndroidTargetSdk, level, model.ScopeSDK))
+		}
+	}
+	return deps, nil
+}
+
+// resolveSdkLevel 解析 SDK 级别：数字直接返回，rootProject.ext.minSdkVersion 等变量引用到根构建脚本中查找
+func (p *AndroidParser) resolveSdkLevel(file ManifestFile, value string) string {
+	if isDigits(value) {
+		return value
+	}
+	if file.Read == nil {
+		return ""
+	}
+	name := value[strings.LastIndex(value, ".")+1:]
+	if name == "" {
+		return ""
+	}
+	assignRe := regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\s*=\s*(\d+)`)
+	// 从模块目录向上查找定义了该变量的构建脚本
+	for dir := path.Dir(file.Path); ; dir = path.Dir(dir) {
+		for _, script := range []string{"build.gradle", "build.gradle.kts"} {
+			scriptPath := joinPath(dir, script)
+			if scriptPath == file.Path {
+				if m := assignRe.FindSubmatch(file.Content); m != nil {
+					return string(m[1])
+				}
+				continue
+			}
+			if content, err := file.Read(scriptPath); err == nil {
+				if m := assignRe.FindSubmatch(content); m != nil {
+					return string(m[1])
+				}
+			}
+		}
+		if dir == "." || dir == "/" || dir == "" {
+			return ""
+		}
+	}
+}
+
+// pluginCatalog 读取 gradle/libs.versions.toml 中的 [plugins]，返回别名（以 "-" 连接）到插件 ID 与版本的映射
+func (p *AndroidParser) pluginCatalog(file ManifestFile) map[string][2]string {
+	catalog := make(map[string][2]string)
+	doc := findVersionCatalog(file)
+	versions, _ := doc["versions"].(map[string]any)
+	plugins, _ := doc["plugins"].(map[string]any)
+	for alias, value := range plugins {
+		switch v := value.(type) {
+		case string:
+			id, version, _ := strings.Cut(v, ":")
+			catalog[gradleCatalogKey(alias)] = [2]string{id, version}
+		case map[string]any:
+			id, _ := v["id"].(string)
+			catalog[gradleCatalogKey(alias)] = [2]string{id, catalogVersion(v["version"], versions)}
+		}
+	}
+	return catalog
+}
+
+// catalogVersion 解析版本目录中的 version 字段，支持字符串与 { ref = "..." } 引用
+func catalogVersion(value any, versions map[string]any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]any:
+		if ref, ok := v["ref"].(string); ok {
+			version, _ := versions[ref].(string)
+			return version
+		}
+	}
+	return ""
+}
+
+// newDependency 生成 Android 生态的依赖记录
+func (p *AndroidParser) newDependency(manifest, name, version, scope string) model.Dependency {
+	return model.Dependency{
+		Ecosystem: model.EcosystemAndroid,
+		Name:      name,
+		Version:   version,
+		Scope:     scope,
+		Direct:    true,
+		Manifest:  manifest,
+	}
+}
+
+// isDigits 判断字符串是否为非空的纯数字
+func isDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
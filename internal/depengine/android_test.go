@@ -0,0 +1,77 @@
+package depengine
+
+import (
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestAndroidGradleAndManifest(t *testing.T) {
+	index, read := newTestIndex(map[string]string{
+		"build.gradle": `buildscript {
+    ext {
+        minSdkVersion = 21
+        targetSdkVersion = 34
+    }
+    dependencies {
+        classpath("com.android.tools.build:gradle:7.4.2")
+    }
+}`,
+		"app/build.gradle": `apply plugin: 'com.android.application'
+
+android {
+    compileSdk 34
+    defaultConfig {
+        minSdkVersion rootProject.ext.minSdkVersion
+        targetSdkVersion rootProject.ext.targetSdkVersion
+    }
+}`,
+		"lib/build.gradle.kts": `plugins {
+    alias(libs.plugins.android.library)
+}
+
+android {
+    compileSdk = 33
+    defaultConfig {
+        minSdk = 24
+    }
+}`,
+		"gradle/libs.versions.toml": `[versions]
+agp = "8.2.0"
+
+[plugins]
+android-library = { id = "com.android.library", version.ref = "agp" }
+`,
+		"legacy/AndroidManifest.xml": `<manifest xmlns:android="http://schemas.android.com/apk/res/android" package="com.example">
+  <uses-sdk android:minSdkVersion="16" android:targetSdkVersion="28" />
+</manifest>`,
+		"app/build/intermediates/AndroidManifest.xml": `<manifest><uses-sdk android:minSdkVersion="1" /></manifest>`,
+	})
+
+	inventory := Collect(index, read, []Parser{&AndroidParser{}})
+
+	if agp, ok := findDependency(inventory.Dependencies, AndroidGradlePlugin, "build.gradle"); !ok || agp.Version != "7.4.2" || agp.Scope != model.ScopeBuild {
+		t.Errorf("unexpected classpath plugin: %+v", agp)
+	}
+	if _, ok := findDependency(inventory.Dependencies, AndroidAppPlugin, "app/build.gradle"); !ok {
+		t.Errorf("applied application plugin should be collected")
+	}
+	if minSdk, _ := findDependency(inventory.Dependencies, AndroidMinSdk, "app/build.gradle"); minSdk.Version != "21" || minSdk.Scope != model.ScopeSDK {
+		t.Errorf("minSdk should resolve from the root build script: %+v", minSdk)
+	}
+	if compileSdk, _ := findDependency(inventory.Dependencies, AndroidCompileSdk, "app/build.gradle"); compileSdk.Version != "34" {
+		t.Errorf("unexpected compileSdk: %+v", compileSdk)
+	}
+	if lib, _ := findDependency(inventory.Dependencies, AndroidLibraryPlugin, "lib/build.gradle.kts"); lib.Version != "8.2.0" {
+		t.Errorf("plugin alias should resolve through the version catalog: %+v", lib)
+	}
+	if minSdk, _ := findDependency(inventory.Dependencies, AndroidMinSdk, "lib/build.gradle.kts"); minSdk.Version != "24" {
+		t.Errorf("unexpected kotlin DSL minSdk: %+v", minSdk)
+	}
+	if target, _ := findDependency(inventory.Dependencies, AndroidTargetSdk, "legacy/AndroidManifest.xml"); target.Version != "28" {
+		t.Errorf("uses-sdk should be parsed: %+v", target)
+	}
+	if _, ok := findDependency(inventory.Dependencies, AndroidMinSdk, "app/build/intermediates/AndroidManifest.xml"); ok {
+		t.Errorf("generated manifests should be skipped")
+	}
+}
@@ -0,0 +1,33 @@
+package depengine
+
+import (
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// ApkParser 解析 Alpine 根文件系统中 apk 的已安装软件包数据库
+type ApkParser struct{}
+
+func init() {
+	Register(&ApkParser{})
+}
+
+// Ecosystem 返回 apk 生态名称
+func (p *ApkParser) Ecosystem() string {
+	return model.EcosystemApk
+}
+
+// Match 匹配 lib/apk/db/installed
+func (p *ApkParser) Match(relPath string) bool {
+	return relPath == "lib/apk/db/installed"
+}
+
+// Parse 解析数据库中的软件包段落，P: 为包名，V: 为版本，L: 为许可证
+func (p *ApkParser) Parse(file ManifestFile) ([]model.Dependency, error) {
+	var deps []model.Dependency
+	for _, stanza := range parseStanzas(file.Content, ":") {
+		dep := osPackage(model.EcosystemApk, file.Path, stanza["P"], stanza["V"])
+		dep.License = stanza["L"]
+		deps = append(deps, dep)
+	}
+	return deps, nil
+}
@@ -0,0 +1,164 @@
+package depengine
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"path"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/archivefs"
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// ArchiveParser 解析压缩包成员中的包元数据：jar 的 META-INF/maven/**/pom.properties 与 META-INF/MANIFEST.MF、
+// wheel 的 *.dist-info/METADATA 以及 npm 包 tarball 的 package/package.json
+type ArchiveParser struct{}
+
+func init() {
+	Register(&ArchiveParser{})
+}
+
+// Ecosystem 返回解析器名称，解析出的依赖按元数据类型分别属于 maven、pypi 与 npm 生态
+func (p *ArchiveParser) Ecosystem() string {
+	return "archive"
+}
+
+// Match 磁盘上的文件不由该解析器处理
+func (p *ArchiveParser) Match(relPath string) bool {
+	return false
+}
+
+// MatchMember 匹配压缩包内的包元数据文件
+func (p *ArchiveParser) MatchMember(relPath string) bool {
+	_, inner := splitMember(relPath)
+	switch {
+	case path.Base(inner) == "pom.properties":
+		return strings.HasPrefix(inner, "META-INF/maven/")
+	case inner == "META-INF/MANIFEST.MF", inner == "package/package.json":
+		return true
+	case path.Base(inner) == "METADATA":
+		return strings.HasSuffix(path.Dir(inner), ".dist-info") && !strings.Contains(path.Dir(inner), "/")
+	}
+	return false
+}
+
+// Parse 根据元数据文件类型解析出压缩包对应的依赖包，版本为压缩包内记录的精确版本
+func (p *ArchiveParser) Parse(file ManifestFile) ([]model.Dependency, error) {
+	archive, inner := splitMember(file.Path)
+	var ecosystem, name, version string
+	switch {
+	case path.Base(inner) == "pom.properties":
+		props := parseProperties(file.Content)
+		ecosystem, version = model.EcosystemMaven, props["version"]
+		if props["artifactId"] != "" {
+			name = props["groupId"] + ":" + props["artifactId"]
+		}
+	case inner == "META-INF/MANIFEST.MF":
+		// 已包含 pom.properties 的 jar 以 Maven 坐标为准
+		if hasPomProperties(file.Index, archive) {
+			return nil, nil
+		}
+		attrs := parseManifestAttributes(file.Content)
+		ecosystem = model.EcosystemMaven
+		name, _, _ = strings.Cut(attrs["Bundle-SymbolicName"], ";")
+		version = attrs["Bundle-Version"]
+		if title := attrs["Implementation-Title"]; name == "" && title != "" {
+			name = title
+			if vendor := attrs["Implementation-Vendor-Id"]; vendor != "" {
+				name = vendor + ":" + title
+			}
+			version = attrs["Implementation-Version"]
+		}
+	case inner == "package/package.json":
+		var pkg struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		}
+		if err := json.Unmarshal(file.Content, &pkg); err != nil {
+			return nil, err
+		}
+		ecosystem, name, version = model.EcosystemNpm, pkg.Name, pkg.Version
+	default:
+		if stanzas := parseStanzas(file.Content, ": "); len(stanzas) > 0 {
+			ecosystem, name, version = model.EcosystemPyPI, stanzas[0]["Name"], stanzas[0]["Version"]
+		}
+	}
+	if name == "" {
+		return nil, nil
+	}
+	return []model.Dependency{{
+		Ecosystem: ecosystem,
+		Name:      strings.TrimSpace(name),
+		Version:   strings.TrimSpace(version),
+		Scope:     model.ScopeRuntime,
+		Direct:    true,
+		Locked:    true,
+		Manifest:  file.Path,
+	}}, nil
+}
+
+// splitMember 将虚拟路径拆分为最内层压缩包的虚拟路径与其中的成员路径
+func splitMember(relPath string) (archive, inner string) {
+	i := strings.LastIndex(relPath, archivefs.Separator)
+	if i < 0 {
+		return "", relPath
+	}
+	return relPath[:i], relPath[i+len(archivefs.Separator):]
+}
+
+// hasPomProperties 判断压缩包中是否包含 Maven 的 pom.properties
+func hasPomProperties(index *model.FileIndex, archive string) bool {
+	if index == nil {
+		return false
+	}
+	prefix := archive + archivefs.Separator + "META-INF/maven/"
+	for _, idx := range index.NameMap["pom.properties"] {
+		if strings.HasPrefix(index.Files[idx], prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseProperties 解析 Java properties 文件中的 key=value 行
+func parseProperties(content []byte) map[string]string {
+	props := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok {
+			props[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return props
+}
+
+// parseManifestAttributes 解析 MANIFEST.MF 主段的属性，以单个空格开头的续行拼接到上一行
+func parseManifestAttributes(content []byte) map[string]string {
+	attrs := make(map[string]string)
+	var lastKey string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			break
+		}
+		if strings.HasPrefix(line, " ") {
+			if lastKey != "" {
+				attrs[lastKey] += line[1:]
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		lastKey = strings.TrimSpace(key)
+		attrs[lastKey] = strings.TrimSpace(value)
+	}
+	return attrs
+}
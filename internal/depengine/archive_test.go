@@ -0,0 +1,47 @@
+package depengine
+
+import (
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestArchiveMemberMetadata(t *testing.T) {
+	index, read := newTestIndex(map[string]string{
+		"app.war!/WEB-INF/lib/fastjson-1.2.83.jar":                                                     "",
+		"app.war!/WEB-INF/lib/fastjson-1.2.83.jar!/META-INF/maven/com.alibaba/fastjson/pom.properties": "#Generated by Maven\ngroupId=com.alibaba\nartifactId=fastjson\nversion=1.2.83\n",
+		"app.war!/WEB-INF/lib/fastjson-1.2.83.jar!/META-INF/maven/com.alibaba/fastjson/pom.xml":        "<project><dependencies><dependency><groupId>junit</groupId><artifactId>junit</artifactId></dependency></dependencies></project>",
+		"app.war!/WEB-INF/lib/fastjson-1.2.83.jar!/META-INF/MANIFEST.MF":                               "Manifest-Version: 1.0\nBundle-SymbolicName: com.alibaba.fastjson\nBundle-Version: 1.2.83\n",
+		"app.war!/WEB-INF/lib/guava-32.1.3-jre.jar!/META-INF/MANIFEST.MF":                              "Manifest-Version: 1.0\r\nBundle-SymbolicName: com.google.guava.fail\r\n ureaccess;singleton:=true\r\nBundle-Version: 32.1.3.jre\r\n\r\nName: com/google/\r\nBundle-Version: 0\r\n",
+		"dist/requests-2.31.0-py3-none-any.whl!/requests-2.31.0.dist-info/METADATA":                    "Metadata-Version: 2.1\nName: requests\nVersion: 2.31.0\n\nRequests is an HTTP library.\nVersion: 9.9.9\n",
+		"vendor/left-pad-1.3.0.tgz!/package/package.json":                                              `{"name": "left-pad", "version": "1.3.0", "dependencies": {"nested": "1.0.0"}}`,
+		"package.json": `{"dependencies": {"express": "^4.18.2"}}`,
+	})
+
+	inventory := Collect(index, read, DefaultParsers())
+
+	tests := []struct {
+		ecosystem, name, manifest, version string
+	}{
+		{model.EcosystemMaven, "com.alibaba:fastjson", "app.war!/WEB-INF/lib/fastjson-1.2.83.jar!/META-INF/maven/com.alibaba/fastjson/pom.properties", "1.2.83"},
+		{model.EcosystemMaven, "com.google.guava.failureaccess", "app.war!/WEB-INF/lib/guava-32.1.3-jre.jar!/META-INF/MANIFEST.MF", "32.1.3.jre"},
+		{model.EcosystemPyPI, "requests", "dist/requests-2.31.0-py3-none-any.whl!/requests-2.31.0.dist-info/METADATA", "2.31.0"},
+		{model.EcosystemNpm, "left-pad", "vendor/left-pad-1.3.0.tgz!/package/package.json", "1.3.0"},
+	}
+	for _, tt := range tests {
+		dep, ok := findDependency(inventory.Dependencies, tt.name, tt.manifest)
+		if !ok || dep.Ecosystem != tt.ecosystem || dep.Version != tt.version || !dep.Locked {
+			t.Errorf("unexpected %s from archive: %+v", tt.name, dep)
+		}
+	}
+
+	// 包自带的清单（pom.xml、package.json 中的依赖）与已有 pom.properties 的 MANIFEST.MF 不应重复记录
+	for _, name := range []string{"junit:junit", "junit", "nested", "com.alibaba.fastjson"} {
+		if inventory.Has(name) {
+			t.Errorf("%s should not be recorded from archive members", name)
+		}
+	}
+	if !inventory.Has("express") {
+		t.Errorf("project manifests should still be parsed")
+	}
+}
@@ -0,0 +1,50 @@
+package depengine
+
+import (
+	"path"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// buildSystemMarkers 构建系统名称及其标志文件（文件名小写），按报告顺序排列
+var buildSystemMarkers = []struct {
+	Name  string
+	Files []string
+}{
+	{"CMake", []string{"cmakelists.txt"}},
+	{"Meson", []string{"meson.build"}},
+	{"Bazel", []string{"module.bazel", "workspace", "workspace.bazel", "build.bazel"}},
+	{"Autotools", []string{"configure.ac", "configure.in", "makefile.am"}},
+	{"Make", []string{"makefile", "gnumakefile"}},
+	{"SCons", []string{"sconstruct"}},
+	{"Premake", []string{"premake5.lua", "premake4.lua"}},
+	{"xmake", []string{"xmake.lua"}},
+}
+
+// DetectBuildSystems 根据标志文件识别项目使用的 C/C++ 构建系统，忽略第三方源码与构建输出目录
+func DetectBuildSystems(index *model.FileIndex) []string {
+	var systems []string
+	if index == nil {
+		return systems
+	}
+	for _, marker := range buildSystemMarkers {
+		if hasMarkerFile(index, marker.Files) {
+			systems = append(systems, marker.Name)
+		}
+	}
+	return systems
+}
+
+// hasMarkerFile 判断索引中是否存在位于项目自身目录中的任一标志文件
+func hasMarkerFile(index *model.FileIndex, names []string) bool {
+	for _, name := range names {
+		for _, idx := range index.NameMap[name] {
+			relPath := index.Files[idx]
+			if strings.EqualFold(path.Base(relPath), name) && !inVendorDir(relPath, cVendorDirs...) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package depengine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectBuildSystems(t *testing.T) {
+	index, _ := newTestIndex(map[string]string{
+		"CMakeLists.txt":                  "",
+		"tools/Makefile":                  "",
+		"configure.ac":                    "",
+		"third_party/abseil/BUILD.bazel":  "",
+		"build/CMakeFiles/Makefile.cmake": "",
+	})
+
+	got := DetectBuildSystems(index)
+	want := []string{"CMake", "Autotools", "Make"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DetectBuildSystems() = %v, want %v", got, want)
+	}
+}
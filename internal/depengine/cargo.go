@@ -0,0 +1,191 @@
+package depengine
+
+import (
+	"path"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+	"github.com/winezer0/codecanvas/internal/utils"
+)
+
+// CargoParser 解析 Rust Cargo 的 Cargo.toml 和 Cargo.lock
+type CargoParser struct{}
+
+func init() {
+	Register(&CargoParser{})
+}
+
+// Ecosystem 返回 Cargo 生态名称
+func (p *CargoParser) Ecosystem() string {
+	return model.EcosystemCargo
+}
+
+// Match 匹配项目中的 Cargo.toml 与 Cargo.lock
+func (p *CargoParser) Match(relPath string) bool {
+	name := strings.ToLower(path.Base(relPath))
+	if name != "cargo.toml" && name != "cargo.lock" {
+		return false
+	}
+	// target 与 vendor 目录下是构建产物或第三方 crate
+	return !inVendorDir(relPath, "target", "vendor")
+}
+
+// Parse 根据文件名分派到对应的解析逻辑
+func (p *CargoParser) Parse(file ManifestFile) ([]model.Dependency, error) {
+	if strings.EqualFold(path.Base(file.Path), "cargo.lock") {
+		return p.parseLock(file)
+	}
+	return p.parseManifest(file)
+}
+
+// cargoSections Cargo.toml 中的依赖表及其作用域
+var cargoSections = []struct {
+	key   string
+	scope string
+}{
+	{"dependencies", model.ScopeRuntime},
+	{"dev-dependencies", model.ScopeDev},
+	{"build-dependencies", model.ScopeBuild},
+}
+
+// parseManifest 解析 Cargo.toml 中的 [dependencies]、[dev-dependencies]、[build-dependencies]
+// 以及 [target.'cfg'.dependencies] 和 [workspace.dependencies]
+func (p *CargoParser) parseManifest(file ManifestFile) ([]model.Dependency, error) {
+	doc, err := utils.ParseTOML(file.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []map[string]any
+	tables = append(tables, doc)
+	if workspace, ok := doc["workspace"].(map[string]any); ok {
+		tables = append(tables, workspace)
+	}
+	if targets, ok := doc["target"].(map[string]any); ok {
+		for _, target := range targets {
+			if t, ok := target.(map[string]any); ok {
+				tables = append(tables, t)
+			}
+		}
+	}
+
+	var deps []model.Dependency
+	for _, table := range tables {
+		for _, section := range cargoSections {
+			entries, _ := table[section.key].(map[string]any)
+			for name, spec := range entries {
+				dep := model.Dependency{
+					Ecosystem: model.EcosystemCargo,
+					Name:      name,
+					Scope:     section.scope,
+					Direct:    true,
+					Manifest:  file.Path,
+				}
+				switch v := spec.(type) {
+				case string:
+					dep.Version = v
+				case map[string]any:
+					// 本地路径依赖属于项目自身的 crate
+					if _, isPath := v["path"]; isPath {
+						continue
+					}
+					if version, ok := v["version"].(string); ok {
+						dep.Version = version
+					}
+					if pkg, ok := v["package"].(string); ok {
+						dep.Name = pkg
+					}
+				}
+				deps = append(deps, dep)
+			}
+		}
+	}
+	return deps, nil
+}
+
+// parseLock 解析 Cargo.lock 中的 [[package]]，忽略没有 source 的本地 crate
+func (p *CargoParser) parseLock(file ManifestFile) ([]model.Dependency, error) {
+	doc, err := utils.ParseTOML(file.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	direct := p.directNames(file)
+	packages, _ := doc["package"].([]any)
+	var deps []model.Dependency
+	for _, item := range packages {
+		pkg, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		if _, hasSource := pkg["source"]; !hasSource {
+			continue
+		}
+		name, _ := pkg["name"].(string)
+		version, _ := pkg["version"].(string)
+		// dependencies 中的条目形如 "serde"、"serde 1.0.1" 或 "serde 1.0.1 (registry+...)"
+		required := make(map[string]string)
+		list, _ := pkg["dependencies"].([]any)
+		for _, entry := range list {
+			if spec, ok := entry.(string); ok && strings.TrimSpace(spec) != "" {
+				required[strings.Fields(spec)[0]] = spec
+			}
+		}
+		deps = append(deps, model.Dependency{
+			Ecosystem: model.EcosystemCargo,
+			Name:      name,
+			Version:   version,
+			Scope:     model.ScopeRuntime,
+			Direct:    direct[name],
+			Locked:    true,
+			Manifest:  file.Path,
+			Requires:  requiredNames(required),
+			Checksum:  cargoChecksum(pkg["checksum"]),
+		})
+	}
+	return deps, nil
+}
+
+// cargoChecksum 返回 Cargo.lock 中记录的 crate 压缩包 SHA-256 摘要
+func cargoChecksum(value any) string {
+	if checksum, _ := value.(string); checksum != "" {
+		return "sha256:" + strings.ToLower(checksum)
+	}
+	return ""
+}
+
+// directNames 读取锁文件所在目录及其子目录（workspace 成员）中 Cargo.toml 声明的直接依赖名称
+func (p *CargoParser) directNames(file ManifestFile) map[string]bool {
+	names := make(map[string]bool)
+	if file.Read == nil {
+		return names
+	}
+
+	manifests := []string{siblingPath(file.Path, "Cargo.toml")}
+	if file.Index != nil {
+		dir := path.Dir(file.Path)
+		for _, relPath := range file.Index.Files {
+			if relPath == manifests[0] || !p.Match(relPath) || !strings.EqualFold(path.Base(relPath), "cargo.toml") {
+				continue
+			}
+			if dir == "." || strings.HasPrefix(relPath, dir+"/") {
+				manifests = append(manifests, relPath)
+			}
+		}
+	}
+
+	for _, manifest := range manifests {
+		content, err := file.Read(manifest)
+		if err != nil {
+			continue
+		}
+		deps, err := p.parseManifest(ManifestFile{Path: manifest, Content: content})
+		if err != nil {
+			continue
+		}
+		for _, dep := range deps {
+			names[dep.Name] = true
+		}
+	}
+	return names
+}
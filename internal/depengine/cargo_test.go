@@ -0,0 +1,104 @@
+package depengine
+
+import (
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestCargoManifestAndLock(t *testing.T) {
+	index, read := newTestIndex(map[string]string{
+		"Cargo.toml": `[package]
+name = "demo"
+version = "0.1.0"
+
+[workspace]
+members = ["crates/api"]
+
+[dependencies]
+tokio = { version = "1.35", features = ["full"] } # 异步运行时
+serde = "1.0"
+local-util = { path = "crates/util" }
+json = { package = "serde_json", version = "1" }
+
+[dev-dependencies]
+criterion = "0.5"
+
+[target.'cfg(unix)'.dependencies]
+nix = "0.27"
+`,
+		"crates/api/Cargo.toml": `[dependencies]
+axum = "0.7"
+`,
+		"Cargo.lock": `# This file is automatically @generated by Cargo.
+version = 3
+
+[[package]]
+name = "axum"
+version = "0.7.4"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[[package]]
+name = "demo"
+version = "0.1.0"
+
+[[package]]
+name = "tokio"
+version = "1.35.1"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+checksum = "C89B4EFA943BE685F629B149F53829423F8F5531EA80249AC2DCE6BB6D4C7D5D"
+dependencies = [
+ "mio",
+ "pin-project-lite 0.2.13 (registry+https://github.com/rust-lang/crates.io-index)",
+]
+
+[[package]]
+name = "mio"
+version = "0.8.10"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+`,
+		"target/debug/build/Cargo.toml": `[dependencies]
+ignored = "1"`,
+	})
+
+	inventory := Collect(index, read, []Parser{&CargoParser{}})
+
+	if inventory.Has("local-util") || inventory.Has("ignored") || inventory.Has("demo") {
+		t.Errorf("local crates and build outputs should be skipped")
+	}
+
+	serdeJSON, ok := findDependency(inventory.Dependencies, "serde_json", "Cargo.toml")
+	if !ok || serdeJSON.Version != "1" {
+		t.Errorf("renamed dependency should use the package name: %+v", serdeJSON)
+	}
+
+	criterion, ok := findDependency(inventory.Dependencies, "criterion", "Cargo.toml")
+	if !ok || criterion.Scope != model.ScopeDev {
+		t.Errorf("criterion should be a dev dependency: %+v", criterion)
+	}
+
+	if _, ok := findDependency(inventory.Dependencies, "nix", "Cargo.toml"); !ok {
+		t.Errorf("target specific dependency not found")
+	}
+
+	tokio, ok := findDependency(inventory.Dependencies, "tokio", "Cargo.lock")
+	if !ok || tokio.Version != "1.35.1" || !tokio.Direct || !tokio.Locked || len(tokio.Requires) != 2 || tokio.Requires[1] != "pin-project-lite" ||
+		tokio.Checksum != "sha256:c89b4efa943be685f629b149f53829423f8f5531ea80249ac2dce6bb6d4c7d5d" {
+		t.Errorf("unexpected tokio dependency: %+v", tokio)
+	}
+
+	// workspace 成员中声明的依赖同样是直接依赖
+	axum, ok := findDependency(inventory.Dependencies, "axum", "Cargo.lock")
+	if !ok || !axum.Direct {
+		t.Errorf("axum should be a direct dependency of a workspace member: %+v", axum)
+	}
+
+	mio, ok := findDependency(inventory.Dependencies, "mio", "Cargo.lock")
+	if !ok || mio.Direct {
+		t.Errorf("mio should be a transitive dependency: %+v", mio)
+	}
+
+	if version := inventory.Version("tokio"); version != "1.35.1" {
+		t.Errorf("expected locked version 1.35.1, got %s", version)
+	}
+}
@@ -0,0 +1,309 @@
+package depengine
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// CMakeParser 解析 CMakeLists.txt 与 *.cmake 中的 find_package、FetchContent、CPM、
+// ExternalProject、pkg_check_modules 与 target_link_libraries 声明
+type CMakeParser struct{}
+
+func init() {
+	Register(&CMakeParser{})
+}
+
+// cVendorDirs C/C++ 项目中常见的第三方源码与构建输出目录
+var cVendorDirs = []string{"build", "_deps", "third_party", "thirdparty", "3rdparty", "external", "vendor", "subprojects", "vcpkg_installed"}
+
+var (
+	// cmakeVersionRe 匹配 find_package 中的版本参数，如 1.80、3.0...<4.0
+	cmakeVersionRe = regexp.MustCompile(`^\d+(\.\d+)*(\.\.\.<?\d+(\.\d+)*)?$`)
+	// cmakeTagVersionRe 从 GIT_TAG 或 URL 中提取版本号，如 v1.14.0、release-1.12.1
+	cmakeTagVersionRe = regexp.MustCompile(`(?:^|[^0-9A-Za-z])v?(\d+(?:\.\d+)+)`)
+	// pkgModuleRe 匹配 pkg-config 模块及可选的版本约束，如 libcurl>=7.0
+	pkgModuleRe = regexp.MustCompile(`^([A-Za-z0-9_.+-]+?)\s*((?:>=|<=|=|<|>).*)?$`)
+)
+
+// cmakeLinkKeywords target_link_libraries 中的关键字
+var cmakeLinkKeywords = map[string]bool{
+	"PRIVATE": true, "PUBLIC": true, "INTERFACE": true,
+	"LINK_PRIVATE": true, "LINK_PUBLIC": true, "LINK_INTERFACE_LIBRARIES": true,
+	"debug": true, "optimized": true, "general": true,
+}
+
+// cmakeCommand CMake 脚本中的一条命令调用
+type cmakeCommand struct {
+	Name string
+	Args []string
+}
+
+// Ecosystem 返回 CMake 生态名称
+func (p *CMakeParser) Ecosystem() string {
+	return model.EcosystemCMake
+}
+
+// Match 匹配项目中的 CMakeLists.txt 与 *.cmake 脚本
+func (p *CMakeParser) Match(relPath string) bool {
+	name := strings.ToLower(path.Base(relPath))
+	if name != "cmakelists.txt" && path.Ext(name) != ".cmake" {
+		return false
+	}
+	return !inVendorDir(relPath, cVendorDirs...)
+}
+
+// Parse 解析 CMake 脚本中声明的依赖
+func (p *CMakeParser) Parse(file ManifestFile) ([]model.Dependency, error) {
+	commands := parseCMakeCommands(string(file.Content))
+
+	// 项目自身定义的目标不是外部依赖
+	targets := make(map[string]bool)
+	for _, cmd := range commands {
+		switch cmd.Name {
+		case "add_library", "add_executable":
+			if len(cmd.Args) > 0 {
+				targets[cmd.Args[0]] = true
+			}
+		}
+	}
+
+	var deps []model.Dependency
+	newDep := func(name, version string) {
+		deps = append(deps, model.Dependency{
+			Ecosystem: model.EcosystemCMake,
+			Name:      name,
+			Version:   version,
+			Scope:     model.ScopeRuntime,
+			Direct:    true,
+			Manifest:  file.Path,
+		})
+	}
+
+	for _, cmd := range commands {
+		if len(cmd.Args) == 0 {
+			continue
+		}
+		switch cmd.Name {
+		case "find_package":
+			version := ""
+			if len(cmd.Args) > 1 && cmakeVersionRe.MatchString(cmd.Args[1]) {
+				version = cmd.Args[1]
+			}
+			newDep(cmd.Args[0], version)
+		case "fetchcontent_declare", "externalproject_add":
+			options := cmakeOptions(cmd.Args[1:])
+			version := cmakeTagVersion(options["GIT_TAG"])
+			if version == "" {
+				version = cmakeTagVersion(options["URL"])
+			}
+			newDep(cmd.Args[0], version)
+		case "cpmaddpackage":
+			if name, version := parseCPMPackage(cmd.Args); name != "" {
+				newDep(name, version)
+			}
+		case "pkg_check_modules", "pkg_search_module":
+			for _, arg := range cmd.Args[1:] {
+				switch arg {
+				case "REQUIRED", "QUIET", "NO_CMAKE_PATH", "NO_CMAKE_ENVIRONMENT_PATH", "IMPORTED_TARGET", "GLOBAL":
+					continue
+				}
+				if m := pkgModuleRe.FindStringSubmatch(arg); m != nil {
+					newDep(m[1], strings.TrimSpace(m[2]))
+				}
+			}
+		case "target_link_libraries":
+			for _, arg := range cmd.Args[1:] {
+				if name := cmakeLinkItem(arg); name != "" && !targets[name] {
+					newDep(name, "")
+				}
+			}
+		}
+	}
+	return deps, nil
+}
+
+// cmakeLinkItem 将 target_link_libraries 的参数转换为依赖名称，无法识别时返回空字符串
+func cmakeLinkItem(arg string) string {
+	switch {
+	case cmakeLinkKeywords[arg]:
+		return ""
+	case strings.Contains(arg, "${") || strings.Contains(arg, "$<"):
+		// 变量与生成器表达式无法静态求值
+		return ""
+	case strings.HasPrefix(arg, "-l"):
+		return arg[2:]
+	case strings.HasPrefix(arg, "-"), strings.ContainsAny(arg, "/\\"):
+		return ""
+	}
+	return arg
+}
+
+// cmakeOptions 将 "KEY value" 形式的参数列表转换为映射（仅取每个关键字后的第一个值）
+func cmakeOptions(args []string) map[string]string {
+	options := make(map[string]string)
+	for i := 0; i+1 < len(args); i++ {
+		if isCMakeKeyword(args[i]) {
+			if _, ok := options[args[i]]; !ok {
+				options[args[i]] = args[i+1]
+			}
+		}
+	}
+	return options
+}
+
+// isCMakeKeyword 判断参数是否为全大写的关键字
+func isCMakeKeyword(arg string) bool {
+	if arg == "" {
+		return false
+	}
+	for _, c := range arg {
+		if !(c >= 'A' && c <= 'Z') && c != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// cmakeTagVersion 从 Git 标签或下载地址中提取版本号
+func cmakeTagVersion(value string) string {
+	if m := cmakeTagVersionRe.FindStringSubmatch(value); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// parseCPMPackage 解析 CPMAddPackage 的简写（"gh:fmtlib/fmt#7.1.3"、"fmt@7.1.3"）与关键字参数形式
+func parseCPMPackage(args []string) (name, version string) {
+	if len(args) == 1 {
+		spec := args[0]
+		if i := strings.Index(spec, ":"); i >= 0 {
+			spec = spec[i+1:]
+		}
+		if i := strings.LastIndexAny(spec, "#@"); i >= 0 {
+			version = normalizeVersion(spec[i+1:])
+			spec = spec[:i]
+		}
+		return path.Base(spec), version
+	}
+	options := cmakeOptions(args)
+	name = options["NAME"]
+	version = options["VERSION"]
+	if version == "" {
+		version = cmakeTagVersion(options["GIT_TAG"])
+	}
+	if name == "" {
+		if repo := options["GITHUB_REPOSITORY"]; repo != "" {
+			name = path.Base(repo)
+		}
+	}
+	return name, version
+}
+
+// parseCMakeCommands 将 CMake 脚本拆分为命令调用列表，命令名统一为小写，并去除注释与引号
+func parseCMakeCommands(content string) []cmakeCommand {
+	var commands []cmakeCommand
+	n := len(content)
+	for i := 0; i < n; {
+		c := content[i]
+		switch {
+		case c == '#':
+			i = skipCMakeComment(content, i)
+		case isCMakeIdentStart(c):
+			start := i
+			for i < n && isCMakeIdentChar(content[i]) {
+				i++
+			}
+			name := content[start:i]
+			j := i
+			for j < n && (content[j] == ' ' || content[j] == '\t') {
+				j++
+			}
+			if j < n && content[j] == '(' {
+				args, end := parseCMakeArgs(content, j+1)
+				commands = append(commands, cmakeCommand{Name: strings.ToLower(name), Args: args})
+				i = end
+			}
+		default:
+			i++
+		}
+	}
+	return commands
+}
+
+// parseCMakeArgs 从左括号之后开始解析命令参数，返回参数列表与右括号之后的位置
+func parseCMakeArgs(content string, i int) ([]string, int) {
+	var args []string
+	var current strings.Builder
+	hasToken := false
+	flush := func() {
+		if hasToken {
+			args = append(args, current.String())
+		}
+		current.Reset()
+		hasToken = false
+	}
+
+	depth := 1
+	n := len(content)
+	for i < n {
+		c := content[i]
+		switch {
+		case c == '#':
+			flush()
+			i = skipCMakeComment(content, i)
+			continue
+		case c == '"':
+			i++
+			for i < n && content[i] != '"' {
+				if content[i] == '\\' && i+1 < n {
+					i++
+				}
+				current.WriteByte(content[i])
+				i++
+			}
+			hasToken = true
+		case c == '(':
+			flush()
+			depth++
+		case c == ')':
+			flush()
+			depth--
+			if depth == 0 {
+				return args, i + 1
+			}
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			flush()
+		default:
+			current.WriteByte(c)
+			hasToken = true
+		}
+		i++
+	}
+	flush()
+	return args, n
+}
+
+// skipCMakeComment 跳过行注释或 #[[ ]] 块注释，返回注释之后的位置
+func skipCMakeComment(content string, i int) int {
+	if strings.HasPrefix(content[i:], "#[[") {
+		if end := strings.Index(content[i:], "]]"); end >= 0 {
+			return i + end + 2
+		}
+		return len(content)
+	}
+	if end := strings.IndexByte(content[i:], '\n'); end >= 0 {
+		return i + end
+	}
+	return len(content)
+}
+
+func isCMakeIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isCMakeIdentChar(c byte) bool {
+	return isCMakeIdentStart(c) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,64 @@
+package depengine
+
+import (
+	"testing"
+)
+
+func TestCMakeDependencies(t *testing.T) {
+	index, read := newTestIndex(map[string]string{
+		"CMakeLists.txt": `cmake_minimum_required(VERSION 3.20)
+project(demo CXX)
+
+# find_package(Ignored 9.9)
+find_package(Qt6 6.5 REQUIRED COMPONENTS Core Widgets)
+find_package(Boost 1.80.0 REQUIRED COMPONENTS filesystem)
+find_package(OpenSSL REQUIRED)
+find_package(PkgConfig)
+pkg_check_modules(CURL REQUIRED IMPORTED_TARGET libcurl>=7.68)
+
+include(FetchContent)
+FetchContent_Declare(
+  googletest
+  GIT_REPOSITORY https://github.com/google/googletest.git
+  GIT_TAG        v1.14.0
+)
+FetchContent_Declare(json URL https://github.com/nlohmann/json/releases/download/v3.11.3/json.tar.xz)
+CPMAddPackage("gh:fmtlib/fmt#10.2.1")
+CPMAddPackage(NAME spdlog VERSION 1.13.0 GITHUB_REPOSITORY gabime/spdlog)
+
+add_library(core STATIC src/core.cpp)
+add_executable(demo src/main.cpp)
+target_link_libraries(demo PRIVATE core Qt6::Widgets OpenSSL::SSL ${EXTRA_LIBS} $<$<CONFIG:Debug>:dbg> -lpthread "gRPC::grpc++")
+`,
+		"third_party/zlib/CMakeLists.txt": `find_package(ZLIB)`,
+	})
+
+	inventory := Collect(index, read, []Parser{&CMakeParser{}})
+
+	expected := map[string]string{
+		"Qt6":          "6.5",
+		"Boost":        "1.80.0",
+		"OpenSSL":      "",
+		"libcurl":      ">=7.68",
+		"googletest":   "1.14.0",
+		"json":         "3.11.3",
+		"fmt":          "10.2.1",
+		"spdlog":       "1.13.0",
+		"Qt6::Widgets": "",
+		"OpenSSL::SSL": "",
+		"pthread":      "",
+		"gRPC::grpc++": "",
+	}
+	for name, version := range expected {
+		dep, ok := findDependency(inventory.Dependencies, name, "CMakeLists.txt")
+		if !ok || dep.Version != version || !dep.Direct {
+			t.Errorf("unexpected %s: %+v (found=%v, want version %q)", name, dep, ok, version)
+		}
+	}
+
+	for _, name := range []string{"Ignored", "core", "demo", "dbg", "ZLIB", "REQUIRED"} {
+		if inventory.Has(name) {
+			t.Errorf("%s should not be reported as a dependency", name)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package depengine
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// CocoaPodsParser 解析 iOS 项目的 Podfile 与 Podfile.lock
+type CocoaPodsParser struct{}
+
+func init() {
+	Register(&CocoaPodsParser{})
+}
+
+var (
+	// podLineRe 匹配 Podfile 中的 pod 声明，如 pod 'Alamofire', '~> 5.8'
+	podLineRe = regexp.MustCompile(`^\s*pod\s+["']([^"']+)["'](.*)$`)
+	// podTargetRe 匹配 Podfile 中的 target 'AppTests' do 块
+	podTargetRe = regexp.MustCompile(`^\s*target\s+["']([^"']+)["']`)
+	// podPlatformRe 匹配 Podfile 中的 platform :ios, '13.0'
+	podPlatformRe = regexp.MustCompile(`^\s*platform\s+:ios\s*,\s*["']([^"']+)["']`)
+	// podLockEntryRe 匹配 Podfile.lock 中的 "Name (version)" 条目
+	podLockEntryRe = regexp.MustCompile(`^"?([^"\s(]+)(?:\s+\(([^)]*)\))?"?:?$`)
+)
+
+// Ecosystem 返回 CocoaPods 生态名称
+func (p *CocoaPodsParser) Ecosystem() string {
+	return model.EcosystemCocoaPods
+}
+
+// Match 匹配项目中的 Podfile 与 Podfile.lock
+func (p *CocoaPodsParser) Match(relPath string) bool {
+	switch strings.ToLower(path.Base(relPath)) {
+	case "podfile", "podfile.lock":
+		return !inVendorDir(relPath, "Pods", "node_modules")
+	}
+	return false
+}
+
+// Parse 根据文件名分派到对应的解析逻辑
+func (p *CocoaPodsParser) Parse(file ManifestFile) ([]model.Dependency, error) {
+	if strings.EqualFold(path.Base(file.Path), "Podfile.lock") {
+		return p.parseLock(file), nil
+	}
+	return p.parsePodfile(file.Path, file.Content), nil
+}
+
+// parsePodfile 解析 Podfile 中的 pod 声明与 iOS 最低部署版本，测试 target 中的 pod 视为开发依赖
+func (p *CocoaPodsParser) parsePodfile(manifest string, content []byte) []model.Dependency {
+	var deps []model.Dependency
+	// 记录当前所在的 do...end 块是否为测试 target
+	var blocks []bool
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(stripRubyComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+		if line == "end" {
+			if len(blocks) > 0 {
+				blocks = blocks[:len(blocks)-1]
+			}
+			continue
+		}
+		if strings.HasSuffix(line, " do") || strings.Contains(line, " do |") {
+			isTest := len(blocks) > 0 && blocks[len(blocks)-1]
+			if m := podTargetRe.FindStringSubmatch(line); m != nil && strings.HasSuffix(m[1], "Tests") {
+				isTest = true
+			}
+			blocks = append(blocks, isTest)
+			continue
+		}
+		if m := podPlatformRe.FindStringSubmatch(line); m != nil {
+			deps = append(deps, model.Dependency{
+				Ecosystem: model.EcosystemCocoaPods,
+				Name:      IOSDeploymentTarget,
+				Version:   m[1],
+				Scope:     model.ScopeSDK,
+				Direct:    true,
+				Manifest:  manifest,
+			})
+			continue
+		}
+
+		m := podLineRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		// 紧跟名称之后的引号字符串为版本约束，:git/:path 等选项不含版本
+		rest := m[2]
+		var constraints []string
+		for {
+			q := gemQuotedRe.FindStringSubmatchIndex(rest)
+			if q == nil {
+				break
+			}
+			constraints = append(constraints, rest[q[2]:q[3]])
+			rest = rest[q[1]:]
+		}
+		scope := model.ScopeRuntime
+		if len(blocks) > 0 && blocks[len(blocks)-1] {
+			scope = model.ScopeDev
+		}
+		deps = append(deps, model.Dependency{
+			Ecosystem: model.EcosystemCocoaPods,
+			Name:      m[1],
+			Version:   strings.Join(constraints, ", "),
+			Scope:     scope,
+			Direct:    true,
+			Manifest:  manifest,
+		})
+	}
+	return deps
+}
+
+// parseLock 解析 Podfile.lock 中 PODS 的已安装版本，DEPENDENCIES 中列出的为直接依赖
+func (p *CocoaPodsParser) parseLock(file ManifestFile) []model.Dependency {
+	var (
+		section string
+		pods    []model.Dependency
+		direct  = make(map[string]bool)
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(file.Content))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if line[0] != ' ' {
+			section = strings.TrimSuffix(strings.TrimSpace(line), ":")
+			continue
+		}
+		// 2 个空格缩进为条目，4 个空格缩进为其依赖
+		if !strings.HasPrefix(line, "  - ") {
+			continue
+		}
+		m := podLockEntryRe.FindStringSubmatch(strings.TrimSpace(line[len("  - "):]))
+		if m == nil {
+			continue
+		}
+		switch section {
+		case "PODS":
+			pods = append(pods, model.Dependency{
+				Ecosystem: model.EcosystemCocoaPods,
+				Name:      m[1],
+				Version:   m[2],
+				Scope:     model.ScopeRuntime,
+				Locked:    true,
+				Manifest:  file.Path,
+			})
+		case "DEPENDENCIES":
+			direct[m[1]] = true
+		}
+	}
+
+	// 通过同目录的 Podfile 判断开发依赖
+	declared := make(map[string]model.Dependency)
+	if file.Read != nil {
+		podfilePath := siblingPath(file.Path, "Podfile")
+		if content, err := file.Read(podfilePath); err == nil {
+			for _, dep := range p.parsePodfile(podfilePath, content) {
+				declared[dep.Name] = dep
+			}
+		}
+	}
+	for i := range pods {
+		pods[i].Direct = direct[pods[i].Name]
+		if dep, ok := declared[pods[i].Name]; ok {
+			pods[i].Scope = dep.Scope
+		}
+	}
+	return pods
+}
@@ -0,0 +1,67 @@
+package depengine
+
+import (
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestCocoaPodsPodfileAndLock(t *testing.T) {
+	index, read := newTestIndex(map[string]string{
+		"ios/Podfile": `platform :ios, '13.0'
+
+target 'App' do
+  use_frameworks!
+  pod 'Alamofire', '~> 5.8'
+  pod 'SnapKit', :git => 'https://github.com/SnapKit/SnapKit.git'
+
+  target 'AppTests' do
+    inherit! :search_paths
+    pod 'Quick', '~> 7.0'
+  end
+end
+`,
+		"ios/Podfile.lock": `PODS:
+  - Alamofire (5.8.1)
+  - Quick (7.3.0)
+  - SnapKit (5.6.0)
+  - "React-Core (0.72.6)":
+    - glog
+
+DEPENDENCIES:
+  - Alamofire (~> 5.8)
+  - Quick (~> 7.0)
+  - SnapKit (from ` + "`https://github.com/SnapKit/SnapKit.git`" + `)
+
+COCOAPODS: 1.14.3
+`,
+		"ios/Pods/Local Podspecs/Podfile": `pod 'Bogus'`,
+	})
+
+	inventory := Collect(index, read, []Parser{&CocoaPodsParser{}})
+
+	if inventory.Has("Bogus") {
+		t.Errorf("Pods directory should be skipped")
+	}
+	if target, _ := findDependency(inventory.Dependencies, IOSDeploymentTarget, "ios/Podfile"); target.Version != "13.0" || target.Scope != model.ScopeSDK {
+		t.Errorf("unexpected deployment target: %+v", target)
+	}
+	if af, _ := findDependency(inventory.Dependencies, "Alamofire", "ios/Podfile"); af.Version != "~> 5.8" || !af.Direct {
+		t.Errorf("unexpected Podfile declaration: %+v", af)
+	}
+	if snap, _ := findDependency(inventory.Dependencies, "SnapKit", "ios/Podfile"); snap.Version != "" {
+		t.Errorf("git pods have no version constraint: %+v", snap)
+	}
+	if af, _ := findDependency(inventory.Dependencies, "Alamofire", "ios/Podfile.lock"); af.Version != "5.8.1" || !af.Locked || !af.Direct {
+		t.Errorf("unexpected locked pod: %+v", af)
+	}
+	if quick, _ := findDependency(inventory.Dependencies, "Quick", "ios/Podfile.lock"); quick.Scope != model.ScopeDev {
+		t.Errorf("test target pods should be dev dependencies: %+v", quick)
+	}
+	if core, ok := findDependency(inventory.Dependencies, "React-Core", "ios/Podfile.lock"); !ok || core.Version != "0.72.6" || core.Direct {
+		t.Errorf("quoted transitive pod should be collected: %+v", core)
+	}
+	if inventory.Has("glog") {
+		t.Errorf("nested pod dependencies should not be collected")
+	}
+}
@@ -0,0 +1,218 @@
+package depengine
+
+import (
+	"encoding/json"
+	"path"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// ComposerParser 解析 PHP Composer 的 composer.json、composer.lock 和 vendor/composer/installed.json
+type ComposerParser struct{}
+
+func init() {
+	Register(&ComposerParser{})
+}
+
+// composerManifest composer.json 中与依赖相关的字段
+type composerManifest struct {
+	Require    map[string]string `json:"require"`
+	RequireDev map[string]string `json:"require-dev"`
+}
+
+// composerPackage composer.lock / installed.json 中的单个包
+type composerPackage struct {
+	Name    string            `json:"name"`
+	Version string            `json:"version"`
+	Require map[string]string `json:"require"`
+	License []string          `json:"license"`
+	Dist    struct {
+		Shasum string `json:"shasum"`
+	} `json:"dist"`
+}
+
+// composerLock composer.lock 中与依赖相关的字段
+type composerLock struct {
+	Packages    []composerPackage `json:"packages"`
+	PackagesDev []composerPackage `json:"packages-dev"`
+}
+
+// composerInstalled Composer 2 格式的 installed.json
+type composerInstalled struct {
+	Packages        []composerPackage `json:"packages"`
+	DevPackageNames []string          `json:"dev-package-names"`
+}
+
+// Ecosystem 返回 Composer 生态名称
+func (p *ComposerParser) Ecosystem() string {
+	return model.EcosystemComposer
+}
+
+// Match 匹配项目中的 composer.json、composer.lock 以及 vendor/composer/installed.json
+func (p *ComposerParser) Match(relPath string) bool {
+	name := strings.ToLower(path.Base(relPath))
+	switch name {
+	case "composer.json", "composer.lock":
+		// vendor 目录下是第三方包自身的清单，不属于项目依赖
+		return !inVendorDir(relPath, "vendor")
+	case "installed.json":
+		// 只匹配完整的 vendor/composer 目录段，myvendor/composer 等同名后缀的目录不是 Composer 的安装目录
+		lower := strings.ToLower(relPath)
+		if lower != "vendor/composer/installed.json" && !strings.HasSuffix(lower, "/vendor/composer/installed.json") {
+			return false
+		}
+		return !inVendorDir(path.Dir(path.Dir(path.Dir(relPath))), "vendor")
+	}
+	return false
+}
+
+// Parse 根据文件名分派到对应的解析逻辑
+func (p *ComposerParser) Parse(file ManifestFile) ([]model.Dependency, error) {
+	switch strings.ToLower(path.Base(file.Path)) {
+	case "composer.json":
+		return p.parseManifest(file)
+	case "composer.lock":
+		return p.parseLock(file)
+	default:
+		return p.parseInstalled(file)
+	}
+}
+
+// parseManifest 解析 composer.json 中的 require 与 require-dev
+func (p *ComposerParser) parseManifest(file ManifestFile) ([]model.Dependency, error) {
+	var manifest composerManifest
+	if err := json.Unmarshal(file.Content, &manifest); err != nil {
+		return nil, err
+	}
+
+	var deps []model.Dependency
+	for _, group := range []struct {
+		require map[string]string
+		scope   string
+	}{{manifest.Require, model.ScopeRuntime}, {manifest.RequireDev, model.ScopeDev}} {
+		for name, constraint := range group.require {
+			if isComposerPlatformPackage(name) {
+				continue
+			}
+			deps = append(deps, model.Dependency{
+				Ecosystem: model.EcosystemComposer,
+				Name:      strings.ToLower(name),
+				Version:   strings.TrimSpace(constraint),
+				Scope:     group.scope,
+				Direct:    true,
+				Manifest:  file.Path,
+			})
+		}
+	}
+	return deps, nil
+}
+
+// parseLock 解析 composer.lock 中锁定的 packages 与 packages-dev
+func (p *ComposerParser) parseLock(file ManifestFile) ([]model.Dependency, error) {
+	var lock composerLock
+	if err := json.Unmarshal(file.Content, &lock); err != nil {
+		return nil, err
+	}
+
+	direct := p.directNames(file, siblingPath(file.Path, "composer.json"))
+	var deps []model.Dependency
+	deps = append(deps, p.lockedPackages(file.Path, lock.Packages, model.ScopeRuntime, direct)...)
+	deps = append(deps, p.lockedPackages(file.Path, lock.PackagesDev, model.ScopeDev, direct)...)
+	return deps, nil
+}
+
+// parseInstalled 解析 vendor/composer/installed.json，仅在项目没有 composer.lock 时使用
+func (p *ComposerParser) parseInstalled(file ManifestFile) ([]model.Dependency, error) {
+	projectRoot := path.Dir(path.Dir(path.Dir(file.Path)))
+	if file.Index != nil && file.Index.Contains(joinPath(projectRoot, "composer.lock")) {
+		return nil, nil
+	}
+
+	// Composer 2 使用对象格式，Composer 1 直接是包数组
+	var installed composerInstalled
+	if err := json.Unmarshal(file.Content, &installed); err != nil {
+		var packages []composerPackage
+		if err := json.Unmarshal(file.Content, &packages); err != nil {
+			return nil, err
+		}
+		installed.Packages = packages
+	}
+
+	devNames := make(map[string]bool)
+	for _, name := range installed.DevPackageNames {
+		devNames[strings.ToLower(name)] = true
+	}
+	direct := p.directNames(file, joinPath(projectRoot, "composer.json"))
+
+	var deps []model.Dependency
+	for _, pkg := range installed.Packages {
+		scope := model.ScopeRuntime
+		if devNames[strings.ToLower(pkg.Name)] {
+			scope = model.ScopeDev
+		}
+		deps = append(deps, p.lockedPackages(file.Path, []composerPackage{pkg}, scope, direct)...)
+	}
+	return deps, nil
+}
+
+// lockedPackages 将锁定的包转换为依赖记录
+func (p *ComposerParser) lockedPackages(manifest string, packages []composerPackage, scope string, direct map[string]bool) []model.Dependency {
+	var deps []model.Dependency
+	for _, pkg := range packages {
+		name := strings.ToLower(pkg.Name)
+		required := make(map[string]string)
+		for requiredName, constraint := range pkg.Require {
+			if requiredName = strings.ToLower(requiredName); !isComposerPlatformPackage(requiredName) {
+				required[requiredName] = constraint
+			}
+		}
+		deps = append(deps, model.Dependency{
+			Ecosystem: model.EcosystemComposer,
+			Name:      name,
+			Version:   normalizeVersion(pkg.Version),
+			Scope:     scope,
+			Direct:    direct[name],
+			Locked:    true,
+			Manifest:  manifest,
+			Requires:  requiredNames(required),
+			License:   disjunctiveLicense(pkg.License),
+		})
+		// dist.shasum 为压缩包的 SHA-1 摘要，从 GitHub 等来源下载时通常为空
+		if pkg.Dist.Shasum != "" {
+			deps[len(deps)-1].Checksum = "sha1:" + strings.ToLower(pkg.Dist.Shasum)
+		}
+	}
+	return deps
+}
+
+// directNames 读取 composer.json 中声明的直接依赖名称
+func (p *ComposerParser) directNames(file ManifestFile, manifestPath string) map[string]bool {
+	names := make(map[string]bool)
+	if file.Read == nil {
+		return names
+	}
+	content, err := file.Read(manifestPath)
+	if err != nil {
+		return names
+	}
+	var manifest composerManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return names
+	}
+	for name := range manifest.Require {
+		names[strings.ToLower(name)] = true
+	}
+	for name := range manifest.RequireDev {
+		names[strings.ToLower(name)] = true
+	}
+	return names
+}
+
+// isComposerPlatformPackage 判断是否为 php、ext-* 等平台依赖
+func isComposerPlatformPackage(name string) bool {
+	name = strings.ToLower(name)
+	return name == "php" || name == "php-64bit" || name == "hhvm" || name == "composer" ||
+		name == "composer-plugin-api" || name == "composer-runtime-api" ||
+		strings.HasPrefix(name, "ext-") || strings.HasPrefix(name, "lib-")
+}
@@ -0,0 +1,200 @@
+package depengine
+
+import (
+	"fmt"
+	"path"
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// newTestIndex 基于内存文件构建索引和读取方法
+func newTestIndex(files map[string]string) (*model.FileIndex, ContentReader) {
+	index := model.NewFileIndex("/virtual")
+	for relPath := range files {
+		index.AddFile(relPath, path.Base(relPath), path.Ext(relPath))
+	}
+	read := func(relPath string) ([]byte, error) {
+		content, ok := files[relPath]
+		if !ok {
+			return nil, fmt.Errorf("file not found: %s", relPath)
+		}
+		return []byte(content), nil
+	}
+	return index, read
+}
+
+// findDependency 在依赖列表中按名称和清单路径查找依赖
+func findDependency(deps []model.Dependency, name, manifest string) (model.Dependency, bool) {
+	for _, dep := range deps {
+		if dep.Name == name && dep.Manifest == manifest {
+			return dep, true
+		}
+	}
+	return model.Dependency{}, false
+}
+
+func TestComposerManifestAndLock(t *testing.T) {
+	index, read := newTestIndex(map[string]string{
+		"composer.json": `{
+  "require": {"php": "^8.1", "ext-json": "*", "laravel/framework": "^10.0", "guzzlehttp/guzzle": "^7.2"},
+  "require-dev": {"phpunit/phpunit": "^10.1"}
+}`,
+		"composer.lock": `{
+  "packages": [
+    {"name": "laravel/framework", "version": "v10.48.4", "require": {"php": "^8.1", "ext-mbstring": "*", "Monolog/Monolog": "^3.0"}},
+    {"name": "guzzlehttp/guzzle", "version": "7.8.1", "license": ["MIT"], "dist": {"shasum": ""}},
+    {"name": "monolog/monolog", "version": "3.5.0", "license": ["MIT", "Apache-2.0"], "dist": {"shasum": "0123456789ABCDEF0123456789abcdef01234567"}}
+  ],
+  "packages-dev": [
+    {"name": "phpunit/phpunit", "version": "10.5.10"}
+  ]
+}`,
+		"vendor/composer/installed.json":       `{"packages": [{"name": "laravel/framework", "version": "v10.0.0"}]}`,
+		"vendor/monolog/monolog/composer.json": `{"require": {"psr/log": "^2.0"}}`,
+	})
+
+	inventory := Collect(index, read, []Parser{&ComposerParser{}})
+
+	// composer.json 中的平台依赖不应进入清单
+	if inventory.Has("php") || inventory.Has("ext-json") {
+		t.Errorf("platform packages should be skipped")
+	}
+
+	// vendor 目录中第三方包自身的 composer.json 不应被解析
+	if inventory.Has("psr/log") {
+		t.Errorf("vendor manifests should be skipped")
+	}
+
+	// 存在 composer.lock 时忽略 installed.json
+	for _, dep := range inventory.Dependencies {
+		if dep.Manifest == "vendor/composer/installed.json" {
+			t.Errorf("installed.json should be ignored when composer.lock exists")
+		}
+	}
+
+	laravel, ok := findDependency(inventory.Dependencies, "laravel/framework", "composer.lock")
+	if !ok {
+		t.Fatalf("laravel/framework not found in composer.lock")
+	}
+	if laravel.Version != "10.48.4" || !laravel.Direct || !laravel.Locked || laravel.Scope != model.ScopeRuntime ||
+		len(laravel.Requires) != 1 || laravel.Requires[0] != "monolog/monolog" {
+		t.Errorf("unexpected laravel/framework dependency: %+v", laravel)
+	}
+
+	monolog, ok := findDependency(inventory.Dependencies, "monolog/monolog", "composer.lock")
+	if !ok || monolog.Direct || monolog.License != "(MIT OR Apache-2.0)" || monolog.Checksum != "sha1:0123456789abcdef0123456789abcdef01234567" {
+		t.Errorf("monolog/monolog should be a transitive dependency: %+v", monolog)
+	}
+
+	phpunit, ok := findDependency(inventory.Dependencies, "phpunit/phpunit", "composer.lock")
+	if !ok || phpunit.Scope != model.ScopeDev {
+		t.Errorf("phpunit/phpunit should be a dev dependency: %+v", phpunit)
+	}
+
+	// 锁文件中的精确版本优先于声明的约束
+	if version := inventory.Version("laravel/framework"); version != "10.48.4" {
+		t.Errorf("expected locked version 10.48.4, got %s", version)
+	}
+}
+
+func TestComposerInstalledWithoutLock(t *testing.T) {
+	testCases := []struct {
+		name      string
+		installed string
+	}{
+		{
+			name:      "Composer 2 format",
+			installed: `{"packages": [{"name": "topthink/framework", "version": "v6.1.4"}, {"name": "phpunit/phpunit", "version": "9.6.0"}], "dev-package-names": ["phpunit/phpunit"]}`,
+		},
+		{
+			name:      "Composer 1 format",
+			installed: `[{"name": "topthink/framework", "version": "v6.1.4"}, {"name": "phpunit/phpunit", "version": "9.6.0"}]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			index, read := newTestIndex(map[string]string{
+				"composer.json":                  `{"require": {"topthink/framework": "^6.0"}}`,
+				"vendor/composer/installed.json": tc.installed,
+			})
+
+			inventory := Collect(index, read, []Parser{&ComposerParser{}})
+
+			if version := inventory.Version("topthink/framework"); version != "6.1.4" {
+				t.Errorf("expected installed version 6.1.4, got %s", version)
+			}
+			dep, ok := findDependency(inventory.Dependencies, "topthink/framework", "vendor/composer/installed.json")
+			if !ok || !dep.Direct {
+				t.Errorf("topthink/framework should be a direct dependency: %+v", dep)
+			}
+		})
+	}
+}
+
+func TestInventoryFindWildcard(t *testing.T) {
+	inventory := NewInventory()
+	inventory.Add(
+		model.Dependency{Ecosystem: model.EcosystemComposer, Name: "symfony/console", Version: "6.4.1"},
+		model.Dependency{Ecosystem: model.EcosystemComposer, Name: "symfony/http-kernel", Version: "6.4.2"},
+		model.Dependency{Ecosystem: model.EcosystemComposer, Name: "twig/twig", Version: "3.8.0"},
+	)
+
+	if got := len(inventory.Find("symfony/*")); got != 2 {
+		t.Errorf("expected 2 symfony packages, got %d", got)
+	}
+	if !inventory.Has("Twig/Twig") {
+		t.Errorf("lookup should be case-insensitive")
+	}
+	if inventory.Has("laravel/*") {
+		t.Errorf("unexpected laravel package match")
+	}
+}
+
+func TestInventoryScoped(t *testing.T) {
+	inventory := NewInventory()
+	inventory.Add(
+		model.Dependency{Ecosystem: model.EcosystemRubyGems, Name: "rack", Version: "2.2.8"},
+		model.Dependency{Ecosystem: model.EcosystemNpm, Name: "rack", Version: "0.0.1"},
+	)
+
+	if got := len(inventory.Find("rack")); got != 2 {
+		t.Errorf("unscoped inventory should match both ecosystems, got %d", got)
+	}
+
+	scoped := inventory.Scoped(model.EcosystemRubyGems)
+	found := scoped.Find("rack")
+	if len(found) != 1 || found[0].Ecosystem != model.EcosystemRubyGems {
+		t.Errorf("expected only the rubygems package, got %+v", found)
+	}
+	if dep := scoped.Versioned("rack"); dep == nil || dep.Version != "2.2.8" {
+		t.Errorf("expected rubygems version 2.2.8, got %+v", dep)
+	}
+	if inventory.Scoped(model.EcosystemPyPI).Has("rack") {
+		t.Errorf("package should not match outside the scoped ecosystems")
+	}
+}
+
+func TestComposerMatch(t *testing.T) {
+	parser := &ComposerParser{}
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{"composer.json", true},
+		{"app/composer.lock", true},
+		{"vendor/laravel/framework/composer.json", false},
+		{"vendor/composer/installed.json", true},
+		{"app/vendor/composer/installed.json", true},
+		{"myvendor/composer/installed.json", false},
+		{"vendor/mycomposer/installed.json", false},
+		{"vendor/acme/lib/vendor/composer/installed.json", false},
+		{"installed.json", false},
+	}
+	for _, tt := range tests {
+		if got := parser.Match(tt.path); got != tt.expected {
+			t.Errorf("Match(%q) = %v, expected %v", tt.path, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package depengine
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// ConanParser 解析 Conan 的 conanfile.txt、conanfile.py 和 conan.lock
+type ConanParser struct{}
+
+func init() {
+	Register(&ConanParser{})
+}
+
+var (
+	// conanCallRe 匹配 conanfile.py 中的 self.requires("zlib/1.2.13") 调用
+	conanCallRe = regexp.MustCompile(`self\.(requires|tool_requires|build_requires|test_requires)\(\s*["']([^"']+)["']`)
+	// conanAttrRe 匹配 conanfile.py 中的 requires = ... 类属性
+	conanAttrRe = regexp.MustCompile(`(?m)^\s*(requires|tool_requires|build_requires|test_requires)\s*=\s*`)
+	// conanRefRe 匹配引号中的包引用，如 "openssl/3.1.0"
+	conanRefRe = regexp.MustCompile(`["']([^"'/\s]+/[^"'\s]+)["']`)
+)
+
+// Ecosystem 返回 Conan 生态名称
+func (p *ConanParser) Ecosystem() string {
+	return model.EcosystemConan
+}
+
+// Match 匹配项目中的 conanfile.txt、conanfile.py 与 conan.lock
+func (p *ConanParser) Match(relPath string) bool {
+	switch strings.ToLower(path.Base(relPath)) {
+	case "conanfile.txt", "conanfile.py", "conan.lock":
+		return !inVendorDir(relPath, cVendorDirs...)
+	}
+	return false
+}
+
+// Parse 根据文件名分派到对应的解析逻辑
+func (p *ConanParser) Parse(file ManifestFile) ([]model.Dependency, error) {
+	switch strings.ToLower(path.Base(file.Path)) {
+	case "conan.lock":
+		return p.parseLock(file)
+	case "conanfile.py":
+		return p.parsePython(file.Path, file.Content), nil
+	default:
+		return p.parseText(file.Path, file.Content), nil
+	}
+}
+
+// parseText 解析 conanfile.txt 的 [requires]、[tool_requires] 等分节
+func (p *ConanParser) parseText(manifest string, content []byte) []model.Dependency {
+	var deps []model.Dependency
+	section := ""
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.ToLower(strings.Trim(line, "[]"))
+			continue
+		}
+		if scope, ok := conanScope(section); ok {
+			if dep, ok := newConanDependency(manifest, line, scope); ok {
+				deps = append(deps, dep)
+			}
+		}
+	}
+	return deps
+}
+
+// parsePython 解析 conanfile.py 中的 requires 类属性与 self.requires() 调用
+func (p *ConanParser) parsePython(manifest string, content []byte) []model.Dependency {
+	text := string(content)
+	var deps []model.Dependency
+
+	for _, loc := range conanAttrRe.FindAllStringSubmatchIndex(text, -1) {
+		scope, _ := conanScope(text[loc[2]:loc[3]])
+		value := pythonValue(text[loc[1]:])
+		for _, m := range conanRefRe.FindAllStringSubmatch(value, -1) {
+			if dep, ok := newConanDependency(manifest, m[1], scope); ok {
+				deps = append(deps, dep)
+			}
+		}
+	}
+	for _, m := range conanCallRe.FindAllStringSubmatch(text, -1) {
+		scope, _ := conanScope(m[1])
+		if dep, ok := newConanDependency(manifest, m[2], scope); ok {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+// parseLock 解析 Conan 2 的 conan.lock（Conan 1 的图结构锁文件不解析）
+func (p *ConanParser) parseLock(file ManifestFile) ([]model.Dependency, error) {
+	var lock struct {
+		Requires      []string `json:"requires"`
+		BuildRequires []string `json:"build_requires"`
+	}
+	if err := json.Unmarshal(file.Content, &lock); err != nil {
+		return nil, err
+	}
+
+	// 通过同目录下的 conanfile 判断直接依赖
+	declared := make(map[string]bool)
+	if file.Read != nil {
+		for _, name := range []string{"conanfile.py", "conanfile.txt"} {
+			manifest := siblingPath(file.Path, name)
+			content, err := file.Read(manifest)
+			if err != nil {
+				continue
+			}
+			var direct []model.Dependency
+			if name == "conanfile.py" {
+				direct = p.parsePython(manifest, content)
+			} else {
+				direct = p.parseText(manifest, content)
+			}
+			for _, dep := range direct {
+				declared[strings.ToLower(dep.Name)] = true
+			}
+			break
+		}
+	}
+
+	var deps []model.Dependency
+	add := func(refs []string, scope string) {
+		for _, ref := range refs {
+			if dep, ok := newConanDependency(file.Path, ref, scope); ok {
+				dep.Locked = true
+				dep.Direct = declared[strings.ToLower(dep.Name)]
+				deps = append(deps, dep)
+			}
+		}
+	}
+	add(lock.Requires, model.ScopeRuntime)
+	add(lock.BuildRequires, model.ScopeBuild)
+	return deps, nil
+}
+
+// conanScope 将 Conan 的需求类型映射为依赖作用域
+func conanScope(kind string) (string, bool) {
+	switch kind {
+	case "requires":
+		return model.ScopeRuntime, true
+	case "tool_requires", "build_requires":
+		return model.ScopeBuild, true
+	case "test_requires":
+		return model.ScopeDev, true
+	}
+	return "", false
+}
+
+// newConanDependency 解析 Conan 包引用 name/version[@user/channel][#revision]
+func newConanDependency(manifest, ref, scope string) (model.Dependency, bool) {
+	ref = strings.TrimSpace(ref)
+	if i := strings.IndexByte(ref, '#'); i >= 0 {
+		ref = ref[:i]
+	}
+	if i := strings.IndexByte(ref, '@'); i >= 0 {
+		ref = ref[:i]
+	}
+	name, version, ok := strings.Cut(ref, "/")
+	if !ok || name == "" {
+		return model.Dependency{}, false
+	}
+	return model.Dependency{
+		Ecosystem: model.EcosystemConan,
+		Name:      name,
+		Version:   version,
+		Scope:     scope,
+		Direct:    true,
+		Manifest:  manifest,
+	}, true
+}
+
+// pythonValue 截取 Python 赋值语句右侧的值：括号包围时截取到匹配的右括号，否则截取到行尾
+func pythonValue(text string) string {
+	if text == "" {
+		return ""
+	}
+	var open, close byte
+	switch text[0] {
+	case '(':
+		open, close = '(', ')'
+	case '[':
+		open, close = '[', ']'
+	default:
+		if end := strings.IndexByte(text, '\n'); end >= 0 {
+			return text[:end]
+		}
+		return text
+	}
+	depth := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return text[:i+1]
+			}
+		}
+	}
+	return text
+}
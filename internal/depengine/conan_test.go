@@ -0,0 +1,89 @@
+package depengine
+
+import (
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestConanfileTextAndLock(t *testing.T) {
+	index, read := newTestIndex(map[string]string{
+		"conanfile.txt": `[requires]
+zlib/1.3.1
+openssl/3.2.1@demo/stable
+boost/[>=1.80 <2.0]
+
+[tool_requires]
+cmake/3.28.1
+
+[generators]
+CMakeDeps
+`,
+		"conan.lock": `{
+  "version": "0.5",
+  "requires": ["zlib/1.3.1#f52e03ae3d251dec704634230cd806a2%1708593606.497", "bzip2/1.0.8#d00dac990f08d991998d624be81a9526"],
+  "build_requires": ["cmake/3.28.1#45f0f4f4bf5a3f0cd8e6c5ad4f6b8f3b"]
+}`,
+	})
+
+	inventory := Collect(index, read, []Parser{&ConanParser{}})
+
+	openssl, ok := findDependency(inventory.Dependencies, "openssl", "conanfile.txt")
+	if !ok || openssl.Version != "3.2.1" || openssl.Scope != model.ScopeRuntime {
+		t.Errorf("unexpected openssl requirement: %+v", openssl)
+	}
+	if boost, _ := findDependency(inventory.Dependencies, "boost", "conanfile.txt"); boost.Version != "[>=1.80 <2.0]" {
+		t.Errorf("version range should be kept: %+v", boost)
+	}
+	if cmake, _ := findDependency(inventory.Dependencies, "cmake", "conanfile.txt"); cmake.Scope != model.ScopeBuild {
+		t.Errorf("tool_requires should be a build dependency: %+v", cmake)
+	}
+	if inventory.Has("CMakeDeps") {
+		t.Errorf("generators should not be reported as dependencies")
+	}
+
+	zlib, ok := findDependency(inventory.Dependencies, "zlib", "conan.lock")
+	if !ok || zlib.Version != "1.3.1" || !zlib.Locked || !zlib.Direct {
+		t.Errorf("unexpected locked zlib: %+v", zlib)
+	}
+	if bzip2, _ := findDependency(inventory.Dependencies, "bzip2", "conan.lock"); bzip2.Direct {
+		t.Errorf("transitive lock entry should not be direct: %+v", bzip2)
+	}
+}
+
+func TestConanfilePython(t *testing.T) {
+	index, read := newTestIndex(map[string]string{
+		"conanfile.py": `from conan import ConanFile
+
+class DemoConan(ConanFile):
+    settings = "os", "compiler", "build_type", "arch"
+    requires = (
+        "grpc/1.54.3",
+        "protobuf/3.21.12",
+    )
+    tool_requires = "ninja/1.11.1"
+
+    def requirements(self):
+        self.requires("libcurl/8.6.0")
+        self.test_requires("gtest/1.14.0")
+`,
+	})
+
+	inventory := Collect(index, read, []Parser{&ConanParser{}})
+
+	expected := map[string]string{
+		"grpc":     model.ScopeRuntime,
+		"protobuf": model.ScopeRuntime,
+		"ninja":    model.ScopeBuild,
+		"libcurl":  model.ScopeRuntime,
+		"gtest":    model.ScopeDev,
+	}
+	for name, scope := range expected {
+		if dep, ok := findDependency(inventory.Dependencies, name, "conanfile.py"); !ok || dep.Scope != scope {
+			t.Errorf("unexpected %s: %+v", name, dep)
+		}
+	}
+	if version := inventory.Version("grpc"); version != "1.54.3" {
+		t.Errorf("expected grpc 1.54.3, got %q", version)
+	}
+}
@@ -0,0 +1,92 @@
+package depengine
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// DpkgParser 解析 Debian/Ubuntu 根文件系统中 dpkg 的已安装软件包数据库
+type DpkgParser struct{}
+
+func init() {
+	Register(&DpkgParser{})
+}
+
+// Ecosystem 返回 deb 生态名称
+func (p *DpkgParser) Ecosystem() string {
+	return model.EcosystemDeb
+}
+
+// Match 匹配 var/lib/dpkg/status 以及 distroless 镜像使用的 var/lib/dpkg/status.d/ 目录中的文件
+func (p *DpkgParser) Match(relPath string) bool {
+	if relPath == "var/lib/dpkg/status" {
+		return true
+	}
+	return path.Dir(relPath) == "var/lib/dpkg/status.d" && !strings.HasSuffix(relPath, ".md5sums")
+}
+
+// Parse 解析状态文件中的软件包段落，仅记录状态为 installed 的软件包；
+// status.d 中的文件没有 Status 字段，均视为已安装
+func (p *DpkgParser) Parse(file ManifestFile) ([]model.Dependency, error) {
+	var deps []model.Dependency
+	for _, stanza := range parseStanzas(file.Content, ": ") {
+		name := stanza["Package"]
+		if status, ok := stanza["Status"]; ok && !strings.HasSuffix(status, " installed") {
+			continue
+		}
+		deps = append(deps, osPackage(model.EcosystemDeb, file.Path, name, stanza["Version"]))
+	}
+	return deps, nil
+}
+
+// osPackage 创建一条已安装系统软件包的依赖记录
+func osPackage(ecosystem, manifest, name, version string) model.Dependency {
+	return model.Dependency{
+		Ecosystem: ecosystem,
+		Name:      name,
+		Version:   version,
+		Scope:     model.ScopeRuntime,
+		Direct:    true,
+		Locked:    true,
+		Manifest:  manifest,
+	}
+}
+
+// parseStanzas 解析以空行分隔的 "键<分隔符>值" 段落，以空白开头的续行被忽略
+func parseStanzas(content []byte, separator string) []map[string]string {
+	var (
+		stanzas []map[string]string
+		current map[string]string
+	)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			if current != nil {
+				stanzas = append(stanzas, current)
+				current = nil
+			}
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+		key, value, ok := strings.Cut(line, separator)
+		if !ok {
+			continue
+		}
+		if current == nil {
+			current = make(map[string]string)
+		}
+		current[key] = strings.TrimSpace(value)
+	}
+	if current != nil {
+		stanzas = append(stanzas, current)
+	}
+	return stanzas
+}
@@ -0,0 +1,67 @@
+package depengine
+
+import (
+	"testing"
+)
+
+func TestOSPackageDatabases(t *testing.T) {
+	index, read := newTestIndex(map[string]string{
+		"var/lib/dpkg/status": `Package: libc6
+Status: install ok installed
+Priority: optional
+Version: 2.36-9+deb12u4
+Description: GNU C Library: Shared libraries
+ Contains the standard libraries that are used by nearly all programs.
+
+Package: openssl
+Status: deinstall ok config-files
+Version: 3.0.11-1~deb12u2
+
+Package: zlib1g
+Status: install ok installed
+Version: 1:1.2.13.dfsg-1
+`,
+		"var/lib/dpkg/status.d/tzdata":         "Package: tzdata\nVersion: 2024a-0+deb12u1\n",
+		"var/lib/dpkg/status.d/tzdata.md5sums": "d41d8cd98f00b204e9800998ecf8427e  usr/share/zoneinfo/UTC\n",
+		"lib/apk/db/installed": `C:Q1abc=
+P:musl
+V:1.2.4-r2
+A:x86_64
+L:MIT
+
+C:Q1def=
+P:busybox
+V:1.36.1-r5
+A:x86_64
+`,
+		"src/var/lib/dpkg/status": "Package: fixture\nVersion: 1.0\n",
+	})
+
+	inventory := Collect(index, read, []Parser{&DpkgParser{}, &ApkParser{}})
+
+	tests := []struct {
+		name, manifest, version string
+	}{
+		{"libc6", "var/lib/dpkg/status", "2.36-9+deb12u4"},
+		{"zlib1g", "var/lib/dpkg/status", "1:1.2.13.dfsg-1"},
+		{"tzdata", "var/lib/dpkg/status.d/tzdata", "2024a-0+deb12u1"},
+		{"musl", "lib/apk/db/installed", "1.2.4-r2"},
+		{"busybox", "lib/apk/db/installed", "1.36.1-r5"},
+	}
+	for _, tt := range tests {
+		dep, ok := findDependency(inventory.Dependencies, tt.name, tt.manifest)
+		if !ok || dep.Version != tt.version || !dep.Locked {
+			t.Errorf("unexpected %s package: %+v", tt.name, dep)
+		}
+	}
+	if musl, _ := findDependency(inventory.Dependencies, "musl", "lib/apk/db/installed"); musl.License != "MIT" {
+		t.Errorf("apk license not recorded: %+v", musl)
+	}
+	// 已卸载仅保留配置的软件包与非根目录下的数据库不应记录
+	if inventory.Has("openssl") || inventory.Has("fixture") {
+		t.Errorf("deinstalled packages and nested databases should be skipped")
+	}
+	if len(inventory.Dependencies) != len(tests) {
+		t.Errorf("expected %d packages, got %d", len(tests), len(inventory.Dependencies))
+	}
+}
@@ -0,0 +1,107 @@
+package depengine
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// GoModParser 解析 Go Modules 的 go.mod
+type GoModParser struct{}
+
+func init() {
+	Register(&GoModParser{})
+}
+
+// Ecosystem 返回 Go 生态名称
+func (p *GoModParser) Ecosystem() string {
+	return model.EcosystemGo
+}
+
+// Match 匹配项目中的 go.mod，vendor 与 testdata 目录除外
+func (p *GoModParser) Match(relPath string) bool {
+	return path.Base(relPath) == "go.mod" && !inVendorDir(relPath, "vendor", "testdata")
+}
+
+// Parse 解析 require 声明的模块，"// indirect" 标记的为间接依赖；
+// go 指令作为 sdk 作用域记录，replace 指向其他版本时使用替换后的版本
+func (p *GoModParser) Parse(file ManifestFile) ([]model.Dependency, error) {
+	var (
+		deps     []model.Dependency
+		block    string
+		replaced = make(map[string]string)
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(file.Content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		indirect := strings.HasSuffix(line, "// indirect")
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if line == "" {
+			continue
+		}
+		if line == ")" {
+			block = ""
+			continue
+		}
+
+		directive, args := block, line
+		if block == "" {
+			directive, args, _ = strings.Cut(line, " ")
+			args = strings.TrimSpace(args)
+			if args == "(" {
+				block = directive
+				continue
+			}
+		}
+
+		fields := strings.Fields(args)
+		switch directive {
+		case "go":
+			if len(fields) > 0 {
+				deps = append(deps, model.Dependency{
+					Ecosystem: model.EcosystemGo,
+					Name:      "go",
+					Version:   fields[0],
+					Scope:     model.ScopeSDK,
+					Direct:    true,
+					Manifest:  file.Path,
+				})
+			}
+		case "require":
+			if len(fields) < 2 {
+				continue
+			}
+			deps = append(deps, model.Dependency{
+				Ecosystem: model.EcosystemGo,
+				Name:      strings.Trim(fields[0], `"`),
+				Version:   normalizeVersion(fields[1]),
+				Scope:     model.ScopeRuntime,
+				Direct:    !indirect,
+				Manifest:  file.Path,
+			})
+		case "replace":
+			// 形如 old [v] => new v，本地路径替换没有版本
+			old, target, ok := strings.Cut(args, "=>")
+			if !ok {
+				continue
+			}
+			oldFields, newFields := strings.Fields(old), strings.Fields(target)
+			if len(oldFields) > 0 && len(newFields) == 2 {
+				replaced[oldFields[0]] = normalizeVersion(newFields[1])
+			}
+		}
+	}
+
+	for i := range deps {
+		if version, ok := replaced[deps[i].Name]; ok && deps[i].Scope == model.ScopeRuntime {
+			deps[i].Version = version
+		}
+	}
+	return deps, nil
+}
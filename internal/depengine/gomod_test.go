@@ -0,0 +1,50 @@
+package depengine
+
+import (
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestGoModManifest(t *testing.T) {
+	index, read := newTestIndex(map[string]string{
+		"go.mod": `module example.com/demo
+
+go 1.21
+
+require github.com/gin-gonic/gin v1.9.1
+
+require (
+	golang.org/x/net v0.17.0 // indirect
+	github.com/old/lib v1.0.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+replace github.com/old/lib => github.com/new/lib v1.2.0
+
+replace gopkg.in/yaml.v3 => ../yaml
+`,
+		"vendor/github.com/x/y/go.mod": "module github.com/x/y\n\nrequire github.com/bogus/bogus v1.0.0\n",
+	})
+
+	inventory := Collect(index, read, []Parser{&GoModParser{}})
+
+	if inventory.Has("github.com/bogus/bogus") {
+		t.Errorf("vendored modules should be skipped")
+	}
+	if sdk, _ := findDependency(inventory.Dependencies, "go", "go.mod"); sdk.Version != "1.21" || sdk.Scope != model.ScopeSDK {
+		t.Errorf("unexpected go directive: %+v", sdk)
+	}
+	if gin, _ := findDependency(inventory.Dependencies, "github.com/gin-gonic/gin", "go.mod"); gin.Version != "1.9.1" || !gin.Direct {
+		t.Errorf("unexpected single-line require: %+v", gin)
+	}
+	if net, _ := findDependency(inventory.Dependencies, "golang.org/x/net", "go.mod"); net.Direct {
+		t.Errorf("indirect requirement should not be direct: %+v", net)
+	}
+	if lib, _ := findDependency(inventory.Dependencies, "github.com/old/lib", "go.mod"); lib.Version != "1.2.0" {
+		t.Errorf("replace should override the version: %+v", lib)
+	}
+	if yaml, _ := findDependency(inventory.Dependencies, "gopkg.in/yaml.v3", "go.mod"); yaml.Version != "3.0.1" {
+		t.Errorf("local replacement should keep the required version: %+v", yaml)
+	}
+}
@@ -0,0 +1,287 @@
+package depengine
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+	"github.com/winezer0/codecanvas/internal/utils"
+)
+
+// GradleParser 解析 Gradle 构建脚本、版本目录引用与 gradle.lockfile。
+// 库依赖使用 Maven 坐标（groupId:artifactId）记录在 maven 生态，插件记录在 gradle 生态
+type GradleParser struct{}
+
+func init() {
+	Register(&GradleParser{})
+}
+
+var (
+	// gradleCoordinateRe 匹配字符串形式的依赖声明，如 implementation("g:a:1.0") 或 testImplementation 'g:a:1.0'
+	gradleCoordinateRe = regexp.MustCompile(`(?m)^\s*(\w+)\s*\(?\s*(?:(?:enforcedPlatform|platform)\s*\(\s*)?["']([^"':\s]+):([^"':\s]+)(?::([^"'@\s]+))?(?:@\w+)?["']`)
+	// gradleMapNotationRe 匹配 Map 形式的依赖声明，如 implementation group: 'g', name: 'a', version: '1.0'
+	gradleMapNotationRe = regexp.MustCompile(`(?m)^\s*(\w+)\s*\(?\s*group\s*[:=]\s*["']([^"']+)["']\s*,\s*name\s*[:=]\s*["']([^"']+)["'](?:\s*,\s*version\s*[:=]\s*["']([^"']+)["'])?`)
+	// gradleCatalogRefRe 匹配版本目录引用，如 implementation(libs.retrofit.core)
+	gradleCatalogRefRe = regexp.MustCompile(`(?m)^\s*(\w+)\s*\(?\s*(?:(?:enforcedPlatform|platform)\s*\(\s*)?libs\.([A-Za-z0-9_.]+)`)
+	// gradlePluginRe 匹配 plugins 块中的 id "x" version "y" 与 id("x") version("y")
+	gradlePluginRe = regexp.MustCompile(`(?m)^\s*id\s*\(?\s*["']([^"']+)["']\s*\)?(?:\s*version\s*\(?\s*["']([^"']+)["'])?`)
+	// gradleKotlinPluginRe 匹配 kotlin("jvm") version "1.9.0" 形式的 Kotlin 插件
+	gradleKotlinPluginRe = regexp.MustCompile(`(?m)^\s*kotlin\s*\(\s*["']([^"']+)["']\s*\)(?:\s*version\s*\(?\s*["']([^"']+)["'])?`)
+	// gradleVariableRe 匹配版本中的 $name、${name} 与 ${rootProject.ext.name} 变量引用
+	gradleVariableRe = regexp.MustCompile(`\$\{?([A-Za-z0-9_.]+)\}?`)
+)
+
+// Ecosystem 返回 Gradle 生态名称
+func (p *GradleParser) Ecosystem() string {
+	return model.EcosystemGradle
+}
+
+// Match 匹配构建脚本与依赖锁文件，构建输出目录除外
+func (p *GradleParser) Match(relPath string) bool {
+	if inVendorDir(relPath, "build", ".gradle", "node_modules") {
+		return false
+	}
+	switch path.Base(relPath) {
+	case "build.gradle", "build.gradle.kts", "gradle.lockfile", "buildscript-gradle.lockfile":
+		return true
+	}
+	return false
+}
+
+// Parse 根据文件名分派到对应的解析逻辑
+func (p *GradleParser) Parse(file ManifestFile) ([]model.Dependency, error) {
+	if strings.HasSuffix(file.Path, ".lockfile") {
+		return p.parseLockfile(file), nil
+	}
+	return p.parseScript(file), nil
+}
+
+// parseScript 解析构建脚本中的依赖声明与插件，Android 插件由 AndroidParser 记录
+func (p *GradleParser) parseScript(file ManifestFile) []model.Dependency {
+	text := string(file.Content)
+	var deps []model.Dependency
+
+	for _, m := range gradlePluginRe.FindAllStringSubmatch(text, -1) {
+		if !strings.HasPrefix(m[1], "com.android.") {
+			deps = append(deps, p.newPlugin(file.Path, m[1], m[2]))
+		}
+	}
+	for _, m := range gradleKotlinPluginRe.FindAllStringSubmatch(text, -1) {
+		deps = append(deps, p.newPlugin(file.Path, "org.jetbrains.kotlin."+m[1], m[2]))
+	}
+
+	for _, m := range gradleCoordinateRe.FindAllStringSubmatch(text, -1) {
+		if scope, ok := gradleScope(m[1]); ok {
+			deps = append(deps, p.newLibrary(file.Path, m[2]+":"+m[3], p.resolveVersion(file, m[4]), scope))
+		}
+	}
+	for _, m := range gradleMapNotationRe.FindAllStringSubmatch(text, -1) {
+		if scope, ok := gradleScope(m[1]); ok {
+			deps = append(deps, p.newLibrary(file.Path, m[2]+":"+m[3], p.resolveVersion(file, m[4]), scope))
+		}
+	}
+
+	if refs := gradleCatalogRefRe.FindAllStringSubmatch(text, -1); len(refs) > 0 {
+		libraries := gradleCatalogLibraries(findVersionCatalog(file))
+		for _, m := range refs {
+			scope, ok := gradleScope(m[1])
+			if !ok {
+				continue
+			}
+			// 访问器中的 "." 对应别名中的 "-"、"_" 或 "."
+			if lib, ok := libraries[gradleCatalogKey(m[2])]; ok {
+				deps = append(deps, p.newLibrary(file.Path, lib[0], lib[1], scope))
+			}
+		}
+	}
+	return deps
+}
+
+// parseLockfile 解析 gradle.lockfile 中锁定的依赖，格式为 group:artifact:version=configurations
+func (p *GradleParser) parseLockfile(file ManifestFile) []model.Dependency {
+	// 同目录构建脚本中声明的依赖为直接依赖
+	declared := make(map[string]bool)
+	if file.Read != nil {
+		for _, script := range []string{"build.gradle", "build.gradle.kts"} {
+			scriptPath := siblingPath(file.Path, script)
+			if content, err := file.Read(scriptPath); err == nil {
+				for _, dep := range p.parseScript(ManifestFile{Path: scriptPath, Content: content, Index: file.Index, Read: file.Read}) {
+					declared[dep.Name] = true
+				}
+			}
+		}
+	}
+
+	var deps []model.Dependency
+	scanner := bufio.NewScanner(bytes.NewReader(file.Content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "empty=") {
+			continue
+		}
+		coordinate, configurations, _ := strings.Cut(line, "=")
+		parts := strings.Split(coordinate, ":")
+		if len(parts) != 3 {
+			continue
+		}
+		scope := model.ScopeDev
+		// 只出现在测试配置中的依赖视为开发依赖
+		for _, configuration := range strings.Split(configurations, ",") {
+			if !strings.HasPrefix(strings.ToLower(configuration), "test") {
+				scope = model.ScopeRuntime
+				break
+			}
+		}
+		if strings.HasPrefix(path.Base(file.Path), "buildscript-") {
+			scope = model.ScopeBuild
+		}
+		name := parts[0] + ":" + parts[1]
+		dep := p.newLibrary(file.Path, name, parts[2], scope)
+		dep.Direct = declared[name]
+		dep.Locked = true
+		deps = append(deps, dep)
+	}
+	return deps
+}
+
+// resolveVersion 展开版本中的变量引用：依次在当前脚本、上级目录的构建脚本与 gradle.properties 中查找定义
+func (p *GradleParser) resolveVersion(file ManifestFile, version string) string {
+	if !strings.Contains(version, "$") {
+		return version
+	}
+	return gradleVariableRe.ReplaceAllStringFunc(version, func(ref string) string {
+		name := gradleVariableRe.FindStringSubmatch(ref)[1]
+		name = name[strings.LastIndex(name, ".")+1:]
+		if value := p.lookupVariable(file, name); value != "" {
+			return value
+		}
+		return ref
+	})
+}
+
+// lookupVariable 查找 Gradle 变量的字符串值
+func (p *GradleParser) lookupVariable(file ManifestFile, name string) string {
+	assignRe := regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\s*=\s*["']([^"'$]+)["']`)
+	propertyRe := regexp.MustCompile(`(?m)^\s*` + regexp.QuoteMeta(name) + `\s*[=:]\s*(\S+)`)
+	if m := assignRe.FindSubmatch(file.Content); m != nil {
+		return string(m[1])
+	}
+	if file.Read == nil {
+		return ""
+	}
+	for dir := path.Dir(file.Path); ; dir = path.Dir(dir) {
+		for _, script := range []string{"build.gradle", "build.gradle.kts"} {
+			if scriptPath := joinPath(dir, script); scriptPath != file.Path {
+				if content, err := file.Read(scriptPath); err == nil {
+					if m := assignRe.FindSubmatch(content); m != nil {
+						return string(m[1])
+					}
+				}
+			}
+		}
+		if content, err := file.Read(joinPath(dir, "gradle.properties")); err == nil {
+			if m := propertyRe.FindSubmatch(content); m != nil {
+				return string(m[1])
+			}
+		}
+		if dir == "." || dir == "/" || dir == "" {
+			return ""
+		}
+	}
+}
+
+// newLibrary 生成 Maven 坐标形式的库依赖记录
+func (p *GradleParser) newLibrary(manifest, name, version, scope string) model.Dependency {
+	return model.Dependency{
+		Ecosystem: model.EcosystemMaven,
+		Name:      name,
+		Version:   version,
+		Scope:     scope,
+		Direct:    true,
+		Manifest:  manifest,
+	}
+}
+
+// newPlugin 生成 Gradle 插件依赖记录
+func (p *GradleParser) newPlugin(manifest, id, version string) model.Dependency {
+	return model.Dependency{
+		Ecosystem: model.EcosystemGradle,
+		Name:      id,
+		Version:   version,
+		Scope:     model.ScopeBuild,
+		Direct:    true,
+		Manifest:  manifest,
+	}
+}
+
+// gradleScope 将依赖配置名映射为作用域，非依赖配置（如 id、version）返回 false
+func gradleScope(configuration string) (string, bool) {
+	lower := strings.ToLower(configuration)
+	switch {
+	case strings.HasPrefix(lower, "test"), strings.HasPrefix(lower, "androidtest"), strings.HasPrefix(lower, "integrationtest"):
+		return model.ScopeDev, true
+	case lower == "classpath", lower == "kapt", lower == "ksp", lower == "annotationprocessor":
+		return model.ScopeBuild, true
+	case lower == "implementation", lower == "api", lower == "compile", lower == "compileonly", lower == "runtimeonly",
+		lower == "runtime", lower == "debugimplementation", lower == "releaseimplementation", lower == "corelibrarydesugaring":
+		return model.ScopeRuntime, true
+	}
+	return "", false
+}
+
+// findVersionCatalog 从构建脚本所在目录向上查找并解析 gradle/libs.versions.toml
+func findVersionCatalog(file ManifestFile) map[string]any {
+	if file.Read == nil || file.Index == nil {
+		return nil
+	}
+	for dir := path.Dir(file.Path); ; dir = path.Dir(dir) {
+		catalogPath := joinPath(dir, "gradle/libs.versions.toml")
+		if file.Index.Contains(catalogPath) {
+			content, err := file.Read(catalogPath)
+			if err != nil {
+				return nil
+			}
+			doc, _ := utils.ParseTOML(content)
+			return doc
+		}
+		if dir == "." || dir == "/" || dir == "" {
+			return nil
+		}
+	}
+}
+
+// gradleCatalogLibraries 返回版本目录 [libraries] 中别名到 Maven 坐标与版本的映射
+func gradleCatalogLibraries(catalog map[string]any) map[string][2]string {
+	libraries := make(map[string][2]string)
+	versions, _ := catalog["versions"].(map[string]any)
+	entries, _ := catalog["libraries"].(map[string]any)
+	for alias, value := range entries {
+		key := gradleCatalogKey(alias)
+		switch v := value.(type) {
+		case string:
+			parts := strings.SplitN(v, ":", 3)
+			if len(parts) == 3 {
+				libraries[key] = [2]string{parts[0] + ":" + parts[1], parts[2]}
+			} else if len(parts) == 2 {
+				libraries[key] = [2]string{v, ""}
+			}
+		case map[string]any:
+			module, _ := v["module"].(string)
+			if module == "" {
+				group, _ := v["group"].(string)
+				name, _ := v["name"].(string)
+				module = group + ":" + name
+			}
+			libraries[key] = [2]string{module, catalogVersion(v["version"], versions)}
+		}
+	}
+	return libraries
+}
+
+// gradleCatalogKey 将版本目录别名或访问器统一为以 "-" 连接的形式
+func gradleCatalogKey(alias string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(alias, "_", "-"), ".", "-")
+}
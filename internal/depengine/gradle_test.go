@@ -0,0 +1,96 @@
+package depengine
+
+import (
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestGradleScriptsAndLockfile(t *testing.T) {
+	index, read := newTestIndex(map[string]string{
+		"gradle.properties": "jacksonVersion=2.15.3\n",
+		"build.gradle": `plugins {
+    id 'org.springframework.boot' version '3.1.5'
+    id 'com.android.application'
+}
+
+ext {
+    guavaVersion = '32.1.3-jre'
+}
+
+dependencies {
+    implementation 'org.springframework.boot:spring-boot-starter-web'
+    implementation "com.google.guava:guava:${guavaVersion}"
+    implementation "com.fasterxml.jackson.core:jackson-databind:$jacksonVersion"
+    implementation platform('org.springframework.cloud:spring-cloud-dependencies:2022.0.4')
+    testImplementation group: 'junit', name: 'junit', version: '4.13.2'
+    annotationProcessor 'org.projectlombok:lombok:1.18.30'
+}`,
+		"app/build.gradle.kts": `plugins {
+    kotlin("jvm") version "1.9.20"
+}
+
+dependencies {
+    implementation(libs.retrofit.core)
+    implementation(libs.okhttp)
+}`,
+		"gradle/libs.versions.toml": `[versions]
+retrofit = "2.9.0"
+
+[libraries]
+retrofit-core = { module = "com.squareup.retrofit2:retrofit", version.ref = "retrofit" }
+okhttp = "com.squareup.okhttp3:okhttp:4.12.0"
+`,
+		"gradle.lockfile": `# This is a Gradle generated file for dependency locking.
+com.google.guava:guava:32.1.3-jre=compileClasspath,runtimeClasspath
+com.google.guava:failureaccess:1.0.1=compileClasspath,runtimeClasspath
+junit:junit:4.13.2=testCompileClasspath,testRuntimeClasspath
+empty=annotationProcessor
+`,
+	})
+
+	inventory := Collect(index, read, []Parser{&GradleParser{}})
+
+	if boot, _ := findDependency(inventory.Dependencies, "org.springframework.boot", "build.gradle"); boot.Version != "3.1.5" || boot.Ecosystem != model.EcosystemGradle {
+		t.Errorf("unexpected plugin: %+v", boot)
+	}
+	if inventory.Has("com.android.application") {
+		t.Errorf("android plugins are reported by AndroidParser")
+	}
+	if web, ok := findDependency(inventory.Dependencies, "org.springframework.boot:spring-boot-starter-web", "build.gradle"); !ok || web.Version != "" {
+		t.Errorf("unexpected versionless dependency: %+v", web)
+	}
+	if guava, _ := findDependency(inventory.Dependencies, "com.google.guava:guava", "build.gradle"); guava.Version != "32.1.3-jre" {
+		t.Errorf("ext variable should be resolved: %+v", guava)
+	}
+	if jackson, _ := findDependency(inventory.Dependencies, "com.fasterxml.jackson.core:jackson-databind", "build.gradle"); jackson.Version != "2.15.3" {
+		t.Errorf("gradle.properties variable should be resolved: %+v", jackson)
+	}
+	if cloud, _ := findDependency(inventory.Dependencies, "org.springframework.cloud:spring-cloud-dependencies", "build.gradle"); cloud.Version != "2022.0.4" {
+		t.Errorf("platform dependency should be collected: %+v", cloud)
+	}
+	if junit, _ := findDependency(inventory.Dependencies, "junit:junit", "build.gradle"); junit.Scope != model.ScopeDev || junit.Version != "4.13.2" {
+		t.Errorf("unexpected map notation dependency: %+v", junit)
+	}
+	if lombok, _ := findDependency(inventory.Dependencies, "org.projectlombok:lombok", "build.gradle"); lombok.Scope != model.ScopeBuild {
+		t.Errorf("annotation processors should be build dependencies: %+v", lombok)
+	}
+	if kotlin, _ := findDependency(inventory.Dependencies, "org.jetbrains.kotlin.jvm", "app/build.gradle.kts"); kotlin.Version != "1.9.20" {
+		t.Errorf("unexpected kotlin plugin: %+v", kotlin)
+	}
+	if retrofit, _ := findDependency(inventory.Dependencies, "com.squareup.retrofit2:retrofit", "app/build.gradle.kts"); retrofit.Version != "2.9.0" {
+		t.Errorf("catalog reference should be resolved: %+v", retrofit)
+	}
+	if okhttp, _ := findDependency(inventory.Dependencies, "com.squareup.okhttp3:okhttp", "app/build.gradle.kts"); okhttp.Version != "4.12.0" {
+		t.Errorf("string catalog entry should be resolved: %+v", okhttp)
+	}
+	if guava, _ := findDependency(inventory.Dependencies, "com.google.guava:guava", "gradle.lockfile"); !guava.Locked || !guava.Direct || guava.Scope != model.ScopeRuntime {
+		t.Errorf("unexpected locked dependency: %+v", guava)
+	}
+	if access, _ := findDependency(inventory.Dependencies, "com.google.guava:failureaccess", "gradle.lockfile"); access.Direct {
+		t.Errorf("transitive locked dependency should not be direct: %+v", access)
+	}
+	if junit, _ := findDependency(inventory.Dependencies, "junit:junit", "gradle.lockfile"); junit.Scope != model.ScopeDev {
+		t.Errorf("test-only configuration should be dev scope: %+v", junit)
+	}
+}
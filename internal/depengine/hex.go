@@ -0,0 +1,133 @@
+package depengine
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// HexParser 解析 Elixir Mix 的 mix.exs 和 mix.lock
+type HexParser struct{}
+
+func init() {
+	Register(&HexParser{})
+}
+
+var (
+	// mixDepRe 匹配 mix.exs 中的依赖元组，如 {:phoenix, "~> 1.7.0", only: :dev}
+	mixDepRe = regexp.MustCompile(`\{\s*:([a-z0-9_]+)\s*,\s*(?:"([^"]*)")?([^{}]*)\}`)
+	// mixOnlyRe 匹配依赖选项中的 only: 环境限定
+	mixOnlyRe = regexp.MustCompile(`only:\s*(\[[^\]]*\]|:[a-z_]+)`)
+	// mixLockRe 匹配 mix.lock 中的 hex 包条目
+	mixLockRe = regexp.MustCompile(`"([^"]+)"\s*:\s*\{\s*:hex\s*,\s*:([a-z0-9_]+)\s*,\s*"([^"]+)"`)
+	// mixLockGitRe 匹配 mix.lock 中的 git 依赖条目
+	mixLockGitRe = regexp.MustCompile(`"([^"]+)"\s*:\s*\{\s*:git\s*,`)
+	// mixEnvRe 匹配 only: 选项中的环境名
+	mixEnvRe = regexp.MustCompile(`:([a-z_]+)`)
+)
+
+// Ecosystem 返回 Hex 生态名称
+func (p *HexParser) Ecosystem() string {
+	return model.EcosystemHex
+}
+
+// Match 匹配项目中的 mix.exs 与 mix.lock
+func (p *HexParser) Match(relPath string) bool {
+	switch strings.ToLower(path.Base(relPath)) {
+	case "mix.exs", "mix.lock":
+		// deps 与 _build 目录下是第三方依赖源码和构建产物
+		return !inVendorDir(relPath, "deps", "_build")
+	}
+	return false
+}
+
+// Parse 根据文件名分派到对应的解析逻辑
+func (p *HexParser) Parse(file ManifestFile) ([]model.Dependency, error) {
+	if strings.EqualFold(path.Base(file.Path), "mix.lock") {
+		return p.parseLock(file), nil
+	}
+	return p.parseMix(file.Path, file.Content), nil
+}
+
+// parseMix 解析 mix.exs 中 deps 函数声明的依赖，only: 仅限 dev/test 时视为开发依赖
+func (p *HexParser) parseMix(manifest string, content []byte) []model.Dependency {
+	text := string(content)
+	// 尽量只解析 deps 函数体，避免误匹配其他元组
+	if start := strings.Index(text, "defp deps"); start >= 0 {
+		text = text[start:]
+		if end := strings.Index(text[len("defp deps"):], "\n  defp "); end >= 0 {
+			text = text[:len("defp deps")+end]
+		}
+	}
+
+	var deps []model.Dependency
+	for _, m := range mixDepRe.FindAllStringSubmatch(text, -1) {
+		scope := model.ScopeRuntime
+		if only := mixOnlyRe.FindStringSubmatch(m[3]); only != nil && isMixDevEnvs(only[1]) {
+			scope = model.ScopeDev
+		}
+		deps = append(deps, model.Dependency{
+			Ecosystem: model.EcosystemHex,
+			Name:      m[1],
+			Version:   strings.TrimSpace(m[2]),
+			Scope:     scope,
+			Direct:    true,
+			Manifest:  manifest,
+		})
+	}
+	return deps
+}
+
+// parseLock 解析 mix.lock 中锁定的 hex 包与 git 依赖
+func (p *HexParser) parseLock(file ManifestFile) []model.Dependency {
+	declared := make(map[string]model.Dependency)
+	if file.Read != nil {
+		mixPath := siblingPath(file.Path, "mix.exs")
+		if content, err := file.Read(mixPath); err == nil {
+			for _, dep := range p.parseMix(mixPath, content) {
+				declared[dep.Name] = dep
+			}
+		}
+	}
+
+	newDep := func(name, version string) model.Dependency {
+		dep := model.Dependency{
+			Ecosystem: model.EcosystemHex,
+			Name:      name,
+			Version:   version,
+			Scope:     model.ScopeRuntime,
+			Locked:    true,
+			Manifest:  file.Path,
+		}
+		if d, ok := declared[name]; ok {
+			dep.Direct = true
+			dep.Scope = d.Scope
+		}
+		return dep
+	}
+
+	var deps []model.Dependency
+	for _, m := range mixLockRe.FindAllStringSubmatch(string(file.Content), -1) {
+		deps = append(deps, newDep(m[2], m[3]))
+	}
+	for _, m := range mixLockGitRe.FindAllStringSubmatch(string(file.Content), -1) {
+		deps = append(deps, newDep(m[1], ""))
+	}
+	return deps
+}
+
+// isMixDevEnvs 判断 only: 选项是否只包含 dev/test 环境
+func isMixDevEnvs(text string) bool {
+	envs := mixEnvRe.FindAllStringSubmatch(text, -1)
+	if len(envs) == 0 {
+		return false
+	}
+	for _, env := range envs {
+		if env[1] != "dev" && env[1] != "test" {
+			return false
+		}
+	}
+	return true
+}
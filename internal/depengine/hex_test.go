@@ -0,0 +1,72 @@
+package depengine
+
+import (
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestHexMixAndLock(t *testing.T) {
+	index, read := newTestIndex(map[string]string{
+		"mix.exs": `defmodule Demo.MixProject do
+  use Mix.Project
+
+  def project do
+    [app: :demo, version: "0.1.0", deps: deps()]
+  end
+
+  defp deps do
+    [
+      {:phoenix, "~> 1.7.10"},
+      {:ecto_sql, "~> 3.10"},
+      {:phoenix_live_reload, "~> 1.2", only: :dev},
+      {:credo, "~> 1.7", only: [:dev, :test], runtime: false},
+      {:heroicons, github: "tailwindlabs/heroicons", tag: "v2.1.1", sparse: "optimized", app: false}
+    ]
+  end
+end
+`,
+		"mix.lock": `%{
+  "ecto": {:hex, :ecto, "3.11.1", "4b4972b717e7ca83d30121b12998f5fcdc62ba0ed4f20fd390f16f3270d85c3e", [:mix], [], "hexpm", "ebd3d3772cd0dfcd8d772659e41ed527c28b2a8bde4b00fe03e0463da0f1983b"},
+  "ecto_sql": {:hex, :ecto_sql, "3.11.1", "e9abf28ae27ef3916b43545f9578b4750956ccea444853606472089e7d169470", [:mix], [], "hexpm", "ce14063ab3514424276e7e360108ad6c2308f6d88164a076aac8a387e1fea634"},
+  "heroicons": {:git, "https://github.com/tailwindlabs/heroicons.git", "88ab3a0d790e6a47404cba02800a6b25d2afae50", [tag: "v2.1.1", sparse: "optimized"]},
+  "phoenix": {:hex, :phoenix, "1.7.10", "02189140a61b2ce85bb633a9b6fd02dff705a5f1596869547aeb2b2b95edd729", [:mix], [], "hexpm", "cf784932e010fd736d656d7fead6a584a4498efefe5b8227e9f383bf15bb79d0"},
+}
+`,
+		"deps/phoenix/mix.exs": `defp deps do
+  [{:plug, "~> 1.14"}]
+end`,
+	})
+
+	inventory := Collect(index, read, []Parser{&HexParser{}})
+
+	if _, ok := findDependency(inventory.Dependencies, "plug", "deps/phoenix/mix.exs"); ok {
+		t.Errorf("dependency sources under deps/ should be skipped")
+	}
+
+	phoenix, ok := findDependency(inventory.Dependencies, "phoenix", "mix.exs")
+	if !ok || phoenix.Version != "~> 1.7.10" || phoenix.Scope != model.ScopeRuntime {
+		t.Errorf("unexpected phoenix declaration: %+v", phoenix)
+	}
+
+	for _, name := range []string{"phoenix_live_reload", "credo"} {
+		dep, ok := findDependency(inventory.Dependencies, name, "mix.exs")
+		if !ok || dep.Scope != model.ScopeDev {
+			t.Errorf("%s should be a dev dependency: %+v", name, dep)
+		}
+	}
+
+	lockedPhoenix, ok := findDependency(inventory.Dependencies, "phoenix", "mix.lock")
+	if !ok || lockedPhoenix.Version != "1.7.10" || !lockedPhoenix.Direct || !lockedPhoenix.Locked {
+		t.Errorf("unexpected locked phoenix: %+v", lockedPhoenix)
+	}
+
+	ecto, ok := findDependency(inventory.Dependencies, "ecto", "mix.lock")
+	if !ok || ecto.Direct {
+		t.Errorf("ecto should be a transitive dependency: %+v", ecto)
+	}
+
+	if _, ok := findDependency(inventory.Dependencies, "heroicons", "mix.lock"); !ok {
+		t.Errorf("git dependency not found in mix.lock")
+	}
+}
@@ -0,0 +1,84 @@
+package depengine
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// MakefileParser 解析 Makefile 与 Automake 中的链接参数（-lssl）和 pkg-config 模块
+type MakefileParser struct{}
+
+func init() {
+	Register(&MakefileParser{})
+}
+
+var (
+	// makeLinkVarRe 匹配链接相关变量的赋值，如 LDLIBS += -lz、app_LDADD = -lcurl
+	makeLinkVarRe = regexp.MustCompile(`^\s*(?:override\s+)?[A-Za-z0-9_]*(?:LIBS|LDLIBS|LDFLAGS|LDADD|LIBADD)\s*[:+?]?=`)
+	// makeCompilerRe 匹配编译器或链接器调用
+	makeCompilerRe = regexp.MustCompile(`\$[({](?:CC|CXX|LD)[)}]|(?:^|[\s/])(?:gcc|g\+\+|clang|clang\+\+|cc|c\+\+|ld)(?:\s|$)`)
+	// makeLinkFlagRe 匹配 -l 链接参数
+	makeLinkFlagRe = regexp.MustCompile(`(?:^|[\s'"=])-l([A-Za-z0-9_+.-]+)`)
+	// makePkgConfigRe 匹配 pkg-config 调用的参数部分
+	makePkgConfigRe = regexp.MustCompile("pkg-config\\s+([^)`;|&]*)")
+)
+
+// Ecosystem 返回系统库生态名称
+func (p *MakefileParser) Ecosystem() string {
+	return model.EcosystemSystem
+}
+
+// Match 匹配项目中的 Makefile、GNUmakefile、Makefile.am 与 *.mk
+func (p *MakefileParser) Match(relPath string) bool {
+	name := strings.ToLower(path.Base(relPath))
+	switch {
+	case name == "makefile", name == "gnumakefile", name == "makefile.am", path.Ext(name) == ".mk":
+		return !inVendorDir(relPath, cVendorDirs...)
+	}
+	return false
+}
+
+// Parse 提取链接变量与编译命令中的 -l 参数以及 pkg-config 模块名称
+func (p *MakefileParser) Parse(file ManifestFile) ([]model.Dependency, error) {
+	seen := make(map[string]bool)
+	var deps []model.Dependency
+	add := func(name string) {
+		if name == "" || seen[name] {
+			return
+		}
+		seen[name] = true
+		deps = append(deps, model.Dependency{
+			Ecosystem: model.EcosystemSystem,
+			Name:      name,
+			Scope:     model.ScopeRuntime,
+			Direct:    true,
+			Manifest:  file.Path,
+		})
+	}
+
+	// 合并以反斜杠结尾的续行
+	text := strings.ReplaceAll(string(file.Content), "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\\\n", " ")
+	for _, line := range strings.Split(text, "\n") {
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		for _, m := range makePkgConfigRe.FindAllStringSubmatch(line, -1) {
+			for _, field := range strings.Fields(m[1]) {
+				if !strings.HasPrefix(field, "-") && !strings.ContainsAny(field, "$=<>") {
+					add(field)
+				}
+			}
+		}
+		if !makeLinkVarRe.MatchString(line) && !makeCompilerRe.MatchString(line) {
+			continue
+		}
+		for _, m := range makeLinkFlagRe.FindAllStringSubmatch(line, -1) {
+			add(m[1])
+		}
+	}
+	return deps, nil
+}
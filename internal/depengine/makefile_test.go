@@ -0,0 +1,42 @@
+package depengine
+
+import (
+	"testing"
+)
+
+func TestMakefileLinkFlags(t *testing.T) {
+	index, read := newTestIndex(map[string]string{
+		"Makefile": `CC ?= gcc
+CFLAGS += -O2 $(shell pkg-config --cflags libcurl)
+LDLIBS += -lssl -lcrypto \
+	-lz
+LIBS = $(shell pkg-config --libs libcurl openssl)
+
+demo: main.o
+	$(CC) -o $@ $^ $(LDLIBS) -lpthread
+
+clean:
+	ls -la && rm -f *.o # -lignored
+`,
+		"src/Makefile.am": `bin_PROGRAMS = tool
+tool_LDADD = -lgrpc++ -lprotobuf
+`,
+		"third_party/lib/Makefile": `LDLIBS = -lbogus`,
+	})
+
+	inventory := Collect(index, read, []Parser{&MakefileParser{}})
+
+	for _, name := range []string{"ssl", "crypto", "z", "pthread", "libcurl", "openssl", "grpc++", "protobuf"} {
+		if !inventory.Has(name) {
+			t.Errorf("expected link dependency %s", name)
+		}
+	}
+	for _, name := range []string{"a", "ignored", "bogus"} {
+		if inventory.Has(name) {
+			t.Errorf("%s should not be reported as a dependency", name)
+		}
+	}
+	if matches := inventory.Find("libcurl"); len(matches) != 1 {
+		t.Errorf("duplicate pkg-config modules should be merged: %+v", matches)
+	}
+}
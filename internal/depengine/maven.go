@@ -0,0 +1,175 @@
+package depengine
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+	"github.com/winezer0/codecanvas/internal/utils"
+)
+
+// MavenParser 解析 Maven 项目的 pom.xml
+type MavenParser struct{}
+
+func init() {
+	Register(&MavenParser{})
+}
+
+// pomPropertyRe 匹配 pom.xml 中的 ${property} 引用
+var pomPropertyRe = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// pomParentDepth 向上解析父 POM 的最大层数
+const pomParentDepth = 5
+
+// pomProject 解析后的 POM 信息（已合并父 POM 的属性与依赖管理）
+// - Properties: 属性，包含 project.version 等内置属性
+// - Managed: dependencyManagement 中声明的 groupId:artifactId 到版本的映射
+type pomProject struct {
+	Root       *utils.XMLNode
+	Properties map[string]string
+	Managed    map[string]string
+}
+
+// Ecosystem 返回 Maven 生态名称
+func (p *MavenParser) Ecosystem() string {
+	return model.EcosystemMaven
+}
+
+// Match 匹配项目中的 pom.xml，target 构建输出目录除外
+func (p *MavenParser) Match(relPath string) bool {
+	return path.Base(relPath) == "pom.xml" && !inVendorDir(relPath, "target", "node_modules")
+}
+
+// Parse 解析 pom.xml 中的父 POM、依赖与构建插件：
+// test 作用域为开发依赖，父 POM 与插件为构建依赖，版本中的属性引用会被展开
+func (p *MavenParser) Parse(file ManifestFile) ([]model.Dependency, error) {
+	project, err := p.loadProject(file, file.Path, file.Content, 0)
+	if err != nil {
+		return nil, err
+	}
+	root := project.Root
+	var deps []model.Dependency
+
+	if parent := root.Child("parent"); parent != nil {
+		deps = append(deps, p.newDependency(file.Path, parent.ChildText("groupId"), parent.ChildText("artifactId"),
+			project.expand(parent.ChildText("version")), model.ScopeBuild))
+	}
+
+	if list := root.Child("dependencies"); list != nil {
+		for _, dep := range list.Children("dependency") {
+			groupID, artifactID := project.expand(dep.ChildText("groupId")), project.expand(dep.ChildText("artifactId"))
+			version := project.expand(dep.ChildText("version"))
+			if version == "" {
+				version = project.Managed[groupID+":"+artifactID]
+			}
+			scope := model.ScopeRuntime
+			if strings.EqualFold(dep.ChildText("scope"), "test") {
+				scope = model.ScopeDev
+			}
+			deps = append(deps, p.newDependency(file.Path, groupID, artifactID, version, scope))
+		}
+	}
+
+	if build := root.Child("build"); build != nil {
+		if plugins := build.Child("plugins"); plugins != nil {
+			for _, plugin := range plugins.Children("plugin") {
+				groupID := project.expand(plugin.ChildText("groupId"))
+				// 插件未声明 groupId 时默认为 org.apache.maven.plugins
+				if groupID == "" {
+					groupID = "org.apache.maven.plugins"
+				}
+				deps = append(deps, p.newDependency(file.Path, groupID, project.expand(plugin.ChildText("artifactId")),
+					project.expand(plugin.ChildText("version")), model.ScopeBuild))
+			}
+		}
+	}
+	return deps, nil
+}
+
+// loadProject 解析 POM 并沿 parent 的 relativePath（默认 ../pom.xml）合并父 POM 的属性与依赖管理
+func (p *MavenParser) loadProject(file ManifestFile, pomPath string, content []byte, depth int) (*pomProject, error) {
+	root, err := utils.ParseXML(content)
+	if err != nil {
+		return nil, err
+	}
+	project := &pomProject{Root: root, Properties: make(map[string]string), Managed: make(map[string]string)}
+
+	parent := root.Child("parent")
+	if parent != nil && file.Read != nil && depth < pomParentDepth {
+		relative := parent.ChildText("relativePath")
+		if relative == "" {
+			relative = "../pom.xml"
+		}
+		if !strings.HasSuffix(relative, ".xml") {
+			relative = strings.TrimSuffix(relative, "/") + "/pom.xml"
+		}
+		parentPath := path.Clean(path.Join(path.Dir(pomPath), relative))
+		if parentContent, err := file.Read(parentPath); err == nil {
+			if parentProject, err := p.loadProject(file, parentPath, parentContent, depth+1); err == nil {
+				project.Properties = parentProject.Properties
+				project.Managed = parentProject.Managed
+			}
+		}
+	}
+
+	if parent != nil {
+		project.Properties["project.parent.version"] = parent.ChildText("version")
+		project.Properties["project.parent.groupId"] = parent.ChildText("groupId")
+	}
+	for _, key := range []string{"version", "groupId", "artifactId"} {
+		value := root.ChildText(key)
+		// 子模块未声明 groupId/version 时继承父 POM
+		if value == "" && parent != nil && key != "artifactId" {
+			value = parent.ChildText(key)
+		}
+		project.Properties["project."+key] = value
+		project.Properties["pom."+key] = value
+	}
+	if props := root.Child("properties"); props != nil {
+		for _, prop := range props.Nodes {
+			project.Properties[prop.Name()] = prop.Text()
+		}
+	}
+	if management := root.Child("dependencyManagement"); management != nil {
+		if list := management.Child("dependencies"); list != nil {
+			for _, dep := range list.Children("dependency") {
+				key := project.expand(dep.ChildText("groupId")) + ":" + project.expand(dep.ChildText("artifactId"))
+				project.Managed[key] = project.expand(dep.ChildText("version"))
+			}
+		}
+	}
+	return project, nil
+}
+
+// expand 展开值中的 ${property} 引用，未定义的属性保持原样
+func (p *pomProject) expand(value string) string {
+	for i := 0; i < 5 && strings.Contains(value, "${"); i++ {
+		value = pomPropertyRe.ReplaceAllStringFunc(value, func(ref string) string {
+			if resolved, ok := p.Properties[ref[2:len(ref)-1]]; ok {
+				return resolved
+			}
+			return ref
+		})
+	}
+	return value
+}
+
+// newDependency 生成 Maven 生态的依赖记录，名称为 groupId:artifactId
+func (p *MavenParser) newDependency(manifest, groupID, artifactID, version, scope string) model.Dependency {
+	name := artifactID
+	if groupID != "" {
+		name = groupID + ":" + artifactID
+	}
+	if artifactID == "" {
+		name = ""
+	}
+	return model.Dependency{
+		Ecosystem: model.EcosystemMaven,
+		Name:      name,
+		Version:   version,
+		Scope:     scope,
+		Direct:    true,
+		Manifest:  manifest,
+	}
+}
@@ -0,0 +1,90 @@
+package depengine
+
+import (
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestMavenPom(t *testing.T) {
+	index, read := newTestIndex(map[string]string{
+		"pom.xml": `<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <groupId>com.example</groupId>
+  <artifactId>parent</artifactId>
+  <version>1.0.0</version>
+  <parent>
+    <groupId>org.springframework.boot</groupId>
+    <artifactId>spring-boot-starter-parent</artifactId>
+    <version>3.1.5</version>
+    <relativePath/>
+  </parent>
+  <properties>
+    <fastjson.version>1.2.83</fastjson.version>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.alibaba</groupId>
+        <artifactId>fastjson</artifactId>
+        <version>${fastjson.version}</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>`,
+		"service/pom.xml": `<project>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>parent</artifactId>
+    <version>1.0.0</version>
+  </parent>
+  <artifactId>service</artifactId>
+  <dependencies>
+    <dependency>
+      <groupId>com.alibaba</groupId>
+      <artifactId>fastjson</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>${project.groupId}</groupId>
+      <artifactId>common</artifactId>
+      <version>${project.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+      <version>4.13.2</version>
+      <scope>test</scope>
+    </dependency>
+  </dependencies>
+  <build>
+    <plugins>
+      <plugin>
+        <artifactId>maven-surefire-plugin</artifactId>
+        <version>3.2.2</version>
+      </plugin>
+    </plugins>
+  </build>
+</project>`,
+		"service/target/classes/META-INF/maven/pom.xml": `<project><dependencies><dependency><groupId>bogus</groupId><artifactId>bogus</artifactId></dependency></dependencies></project>`,
+	})
+
+	inventory := Collect(index, read, []Parser{&MavenParser{}})
+
+	if inventory.Has("bogus:bogus") {
+		t.Errorf("target directory should be skipped")
+	}
+	if boot, _ := findDependency(inventory.Dependencies, "org.springframework.boot:spring-boot-starter-parent", "pom.xml"); boot.Version != "3.1.5" || boot.Scope != model.ScopeBuild {
+		t.Errorf("unexpected parent POM: %+v", boot)
+	}
+	if fastjson, _ := findDependency(inventory.Dependencies, "com.alibaba:fastjson", "service/pom.xml"); fastjson.Version != "1.2.83" {
+		t.Errorf("version should come from the parent dependencyManagement: %+v", fastjson)
+	}
+	if common, _ := findDependency(inventory.Dependencies, "com.example:common", "service/pom.xml"); common.Version != "1.0.0" {
+		t.Errorf("project properties should be inherited from the parent: %+v", common)
+	}
+	if junit, _ := findDependency(inventory.Dependencies, "junit:junit", "service/pom.xml"); junit.Scope != model.ScopeDev {
+		t.Errorf("test scope should be a dev dependency: %+v", junit)
+	}
+	if surefire, _ := findDependency(inventory.Dependencies, "org.apache.maven.plugins:maven-surefire-plugin", "service/pom.xml"); surefire.Version != "3.2.2" || surefire.Scope != model.ScopeBuild {
+		t.Errorf("unexpected build plugin: %+v", surefire)
+	}
+}
@@ -0,0 +1,128 @@
+package depengine
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// MesonParser 解析 Meson 的 meson.build 与 subprojects/*.wrap
+type MesonParser struct{}
+
+func init() {
+	Register(&MesonParser{})
+}
+
+var (
+	// mesonDepRe 匹配 dependency('zlib' 与 find_library('m' 调用的起始部分
+	mesonDepRe = regexp.MustCompile(`\b(dependency|find_library)\(\s*'([^']+)'`)
+	// mesonVersionRe 匹配调用参数中的 version: '>=1.2' 或 version: ['>=1.0', '<2.0']
+	mesonVersionRe = regexp.MustCompile(`version\s*:\s*(\[[^\]]*\]|'[^']*')`)
+	// mesonNativeRe 匹配 native: true（构建机依赖）
+	mesonNativeRe = regexp.MustCompile(`native\s*:\s*true`)
+	// mesonQuotedRe 匹配单引号字符串
+	mesonQuotedRe = regexp.MustCompile(`'([^']*)'`)
+	// wrapVersionRe 从 wrap 文件的目录名或源码包名中提取版本号，如 zlib-1.3.1
+	wrapVersionRe = regexp.MustCompile(`[-_]v?(\d+(?:\.\d+)+)`)
+)
+
+// Ecosystem 返回 Meson 生态名称
+func (p *MesonParser) Ecosystem() string {
+	return model.EcosystemMeson
+}
+
+// Match 匹配项目中的 meson.build 与 subprojects 目录下的 .wrap 文件
+func (p *MesonParser) Match(relPath string) bool {
+	if strings.EqualFold(path.Ext(relPath), ".wrap") {
+		return strings.EqualFold(path.Base(path.Dir(relPath)), "subprojects")
+	}
+	return strings.EqualFold(path.Base(relPath), "meson.build") && !inVendorDir(relPath, cVendorDirs...)
+}
+
+// Parse 根据文件类型分派到对应的解析逻辑
+func (p *MesonParser) Parse(file ManifestFile) ([]model.Dependency, error) {
+	if strings.EqualFold(path.Ext(file.Path), ".wrap") {
+		return p.parseWrap(file), nil
+	}
+	return p.parseBuild(file), nil
+}
+
+// parseBuild 解析 meson.build 中的 dependency() 与 find_library() 调用
+func (p *MesonParser) parseBuild(file ManifestFile) []model.Dependency {
+	text := string(file.Content)
+	var deps []model.Dependency
+	for _, loc := range mesonDepRe.FindAllStringSubmatchIndex(text, -1) {
+		name := text[loc[4]:loc[5]]
+		// 截取到调用结束的右括号，用于读取关键字参数
+		args := text[loc[1]:]
+		if end := strings.IndexByte(args, ')'); end >= 0 {
+			args = args[:end]
+		}
+
+		version := ""
+		if m := mesonVersionRe.FindStringSubmatch(args); m != nil {
+			var constraints []string
+			for _, q := range mesonQuotedRe.FindAllStringSubmatch(m[1], -1) {
+				constraints = append(constraints, strings.TrimSpace(q[1]))
+			}
+			version = strings.Join(constraints, ", ")
+		}
+		scope := model.ScopeRuntime
+		if mesonNativeRe.MatchString(args) {
+			scope = model.ScopeBuild
+		}
+		deps = append(deps, model.Dependency{
+			Ecosystem: model.EcosystemMeson,
+			Name:      name,
+			Version:   version,
+			Scope:     scope,
+			Direct:    true,
+			Manifest:  file.Path,
+		})
+	}
+	return deps
+}
+
+// parseWrap 解析 wrap 文件，版本从 directory 或 source_filename 中提取，git 类型使用 revision
+func (p *MesonParser) parseWrap(file ManifestFile) []model.Dependency {
+	name := strings.TrimSuffix(path.Base(file.Path), path.Ext(file.Path))
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(file.Content))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		if _, exists := values[key]; !exists {
+			values[key] = strings.TrimSpace(value)
+		}
+	}
+
+	version := ""
+	for _, key := range []string{"directory", "source_filename"} {
+		if m := wrapVersionRe.FindStringSubmatch(values[key]); m != nil {
+			version = m[1]
+			break
+		}
+	}
+	if version == "" {
+		if revision := values["revision"]; revision != "" && !strings.EqualFold(revision, "head") {
+			version = normalizeVersion(revision)
+		}
+	}
+
+	return []model.Dependency{{
+		Ecosystem: model.EcosystemMeson,
+		Name:      name,
+		Version:   version,
+		Scope:     model.ScopeRuntime,
+		Direct:    true,
+		Locked:    version != "",
+		Manifest:  file.Path,
+	}}
+}
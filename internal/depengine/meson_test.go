@@ -0,0 +1,48 @@
+package depengine
+
+import (
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestMesonBuildAndWrap(t *testing.T) {
+	index, read := newTestIndex(map[string]string{
+		"meson.build": `project('demo', 'c', version: '1.0')
+cc = meson.get_compiler('c')
+zlib_dep = dependency('zlib', version: '>=1.2.8', fallback: ['zlib', 'zlib_dep'])
+ssl_dep = dependency('openssl', version: ['>=3.0', '<4.0'])
+glib_dep = dependency('glib-2.0', native: true)
+m_dep = cc.find_library('m', required: false)
+`,
+		"subprojects/zlib.wrap": `[wrap-file]
+directory = zlib-1.3.1
+source_url = https://zlib.net/fossils/zlib-1.3.1.tar.gz
+
+[provide]
+zlib = zlib_dep
+`,
+		"subprojects/zlib-1.3.1/meson.build": `dependency('bogus')`,
+	})
+
+	inventory := Collect(index, read, []Parser{&MesonParser{}})
+
+	if inventory.Has("bogus") {
+		t.Errorf("subproject sources should be skipped")
+	}
+	if zlib, _ := findDependency(inventory.Dependencies, "zlib", "meson.build"); zlib.Version != ">=1.2.8" {
+		t.Errorf("unexpected zlib declaration: %+v", zlib)
+	}
+	if ssl, _ := findDependency(inventory.Dependencies, "openssl", "meson.build"); ssl.Version != ">=3.0, <4.0" {
+		t.Errorf("version list should be joined: %+v", ssl)
+	}
+	if glib, _ := findDependency(inventory.Dependencies, "glib-2.0", "meson.build"); glib.Scope != model.ScopeBuild {
+		t.Errorf("native dependency should be a build dependency: %+v", glib)
+	}
+	if !inventory.Has("m") {
+		t.Errorf("find_library should be collected")
+	}
+	if version := inventory.Version("zlib"); version != "1.3.1" {
+		t.Errorf("wrap file version should be preferred, got %q", version)
+	}
+}
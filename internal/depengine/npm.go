@@ -0,0 +1,329 @@
+package depengine
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// NpmParser 解析 Node.js 项目的 package.json 以及 npm、Yarn、pnpm 锁文件
+type NpmParser struct{}
+
+func init() {
+	Register(&NpmParser{})
+}
+
+// npmPackageJSON package.json 中与依赖相关的字段
+type npmPackageJSON struct {
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+}
+
+// npmPackageLock package-lock.json 的结构，v2/v3 使用 packages，v1 使用嵌套的 dependencies
+type npmPackageLock struct {
+	Packages map[string]struct {
+		Version              string            `json:"version"`
+		Dev                  bool              `json:"dev"`
+		Link                 bool              `json:"link"`
+		License              any               `json:"license"`
+		Integrity            string            `json:"integrity"`
+		Dependencies         map[string]string `json:"dependencies"`
+		OptionalDependencies map[string]string `json:"optionalDependencies"`
+		PeerDependencies     map[string]string `json:"peerDependencies"`
+	} `json:"packages"`
+	Dependencies map[string]npmLockDependency `json:"dependencies"`
+}
+
+// npmLockDependency package-lock.json v1 中的依赖条目
+type npmLockDependency struct {
+	Version      string                       `json:"version"`
+	Dev          bool                         `json:"dev"`
+	Integrity    string                       `json:"integrity"`
+	Requires     map[string]string            `json:"requires"`
+	Dependencies map[string]npmLockDependency `json:"dependencies"`
+}
+
+// Ecosystem 返回 npm 生态名称
+func (p *NpmParser) Ecosystem() string {
+	return model.EcosystemNpm
+}
+
+// Match 匹配 package.json 与各类锁文件，node_modules 与前端构建产物目录除外
+func (p *NpmParser) Match(relPath string) bool {
+	switch path.Base(relPath) {
+	case "package.json", "package-lock.json", "npm-shrinkwrap.json", "yarn.lock", "pnpm-lock.yaml":
+		return !inVendorDir(relPath, "node_modules", "bower_components", ".next", ".nuxt", "dist")
+	}
+	return false
+}
+
+// Parse 根据文件名分派到对应的解析逻辑
+func (p *NpmParser) Parse(file ManifestFile) ([]model.Dependency, error) {
+	switch path.Base(file.Path) {
+	case "package-lock.json", "npm-shrinkwrap.json":
+		return p.parsePackageLock(file)
+	case "yarn.lock":
+		return p.parseYarnLock(file), nil
+	case "pnpm-lock.yaml":
+		return p.parsePnpmLock(file)
+	}
+	return p.parsePackageJSON(file.Path, file.Content)
+}
+
+// parsePackageJSON 解析 package.json，devDependencies 为开发依赖，其余为运行时依赖
+func (p *NpmParser) parsePackageJSON(manifest string, content []byte) ([]model.Dependency, error) {
+	var pkg npmPackageJSON
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return nil, err
+	}
+
+	var deps []model.Dependency
+	for _, group := range []struct {
+		deps  map[string]string
+		scope string
+	}{
+		{pkg.Dependencies, model.ScopeRuntime},
+		{pkg.DevDependencies, model.ScopeDev},
+		{pkg.PeerDependencies, model.ScopeRuntime},
+		{pkg.OptionalDependencies, model.ScopeRuntime},
+	} {
+		for _, name := range sortedKeys(group.deps) {
+			deps = append(deps, model.Dependency{
+				Ecosystem: model.EcosystemNpm,
+				Name:      name,
+				Version:   group.deps[name],
+				Scope:     group.scope,
+				Direct:    true,
+				Manifest:  manifest,
+			})
+		}
+	}
+	return deps, nil
+}
+
+// parsePackageLock 解析 package-lock.json 中安装的包版本
+func (p *NpmParser) parsePackageLock(file ManifestFile) ([]model.Dependency, error) {
+	var lock npmPackageLock
+	if err := json.Unmarshal(file.Content, &lock); err != nil {
+		return nil, err
+	}
+	declared := p.declared(file)
+
+	var deps []model.Dependency
+	if len(lock.Packages) > 0 {
+		for _, key := range sortedKeys(lock.Packages) {
+			pkg := lock.Packages[key]
+			idx := strings.LastIndex(key, "node_modules/")
+			// 空键为项目自身，link 为工作区内的本地包
+			if idx < 0 || pkg.Link {
+				continue
+			}
+			name := key[idx+len("node_modules/"):]
+			// 只有顶层 node_modules 中的包可能是直接依赖
+			topLevel := idx == 0
+			dep := p.lockDependency(file.Path, name, pkg.Version, pkg.Dev, topLevel, declared)
+			dep.Requires = requiredNames(pkg.Dependencies, pkg.OptionalDependencies, pkg.PeerDependencies)
+			dep.License = npmLicense(pkg.License)
+			dep.Checksum = integrityChecksum(pkg.Integrity)
+			deps = append(deps, dep)
+		}
+		return deps, nil
+	}
+
+	var walk func(entries map[string]npmLockDependency, topLevel bool)
+	walk = func(entries map[string]npmLockDependency, topLevel bool) {
+		for _, name := range sortedKeys(entries) {
+			entry := entries[name]
+			dep := p.lockDependency(file.Path, name, entry.Version, entry.Dev, topLevel, declared)
+			dep.Requires = requiredNames(entry.Requires)
+			dep.Checksum = integrityChecksum(entry.Integrity)
+			deps = append(deps, dep)
+			walk(entry.Dependencies, false)
+		}
+	}
+	walk(lock.Dependencies, true)
+	return deps, nil
+}
+
+// parseYarnLock 解析 yarn.lock（v1 与 Berry 格式），条目头形如 "name@^1.0.0", name@npm:^1.0.0:
+func (p *NpmParser) parseYarnLock(file ManifestFile) []model.Dependency {
+	declared := p.declared(file)
+	var (
+		deps []model.Dependency
+		name string
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(file.Content))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if line[0] != ' ' {
+			name = ""
+			if !strings.HasSuffix(line, ":") || strings.HasPrefix(line, "__metadata") {
+				continue
+			}
+			spec := strings.TrimSpace(strings.SplitN(strings.TrimSuffix(line, ":"), ",", 2)[0])
+			name = npmSpecName(strings.Trim(spec, `"`))
+			continue
+		}
+		if name == "" {
+			continue
+		}
+		field := strings.TrimSpace(line)
+		if !strings.HasPrefix(field, "version") {
+			continue
+		}
+		version := strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(field, "version"), ":"))
+		version = strings.Trim(version, `"`)
+		// 工作区本地包的版本形如 0.0.0-use.local
+		if !strings.HasSuffix(version, "-use.local") {
+			deps = append(deps, p.lockDependency(file.Path, name, version, false, true, declared))
+		}
+		name = ""
+	}
+	return deps
+}
+
+// parsePnpmLock 解析 pnpm-lock.yaml 中 packages 的键，兼容 /name/1.0.0、/name@1.0.0 与 name@1.0.0(peer) 格式
+func (p *NpmParser) parsePnpmLock(file ManifestFile) ([]model.Dependency, error) {
+	var lock struct {
+		Packages map[string]struct {
+			Dev        bool `yaml:"dev"`
+			Resolution struct {
+				Integrity string `yaml:"integrity"`
+			} `yaml:"resolution"`
+			Dependencies         map[string]string `yaml:"dependencies"`
+			OptionalDependencies map[string]string `yaml:"optionalDependencies"`
+		} `yaml:"packages"`
+	}
+	if err := yaml.Unmarshal(file.Content, &lock); err != nil {
+		return nil, err
+	}
+	declared := p.declared(file)
+
+	var deps []model.Dependency
+	for _, key := range sortedKeys(lock.Packages) {
+		spec := strings.TrimPrefix(key, "/")
+		// 去除 v6+ 的 (peer@1.0.0) 与 v5 的 _peer@1.0.0 后缀
+		if i := strings.Index(spec, "("); i > 0 {
+			spec = spec[:i]
+		}
+		var name, version string
+		if i := strings.LastIndex(spec, "@"); i > 0 {
+			name, version = spec[:i], spec[i+1:]
+		} else if i := strings.LastIndex(spec, "/"); i > 0 {
+			name, version = spec[:i], spec[i+1:]
+		} else {
+			continue
+		}
+		if i := strings.Index(version, "_"); i > 0 {
+			version = version[:i]
+		}
+		pkg := lock.Packages[key]
+		dep := p.lockDependency(file.Path, name, version, pkg.Dev, true, declared)
+		dep.Requires = requiredNames(pkg.Dependencies, pkg.OptionalDependencies)
+		dep.Checksum = integrityChecksum(pkg.Resolution.Integrity)
+		deps = append(deps, dep)
+	}
+	return deps, nil
+}
+
+// declared 读取锁文件同目录的 package.json，返回直接依赖名称到作用域的映射
+func (p *NpmParser) declared(file ManifestFile) map[string]string {
+	declared := make(map[string]string)
+	if file.Read == nil {
+		return declared
+	}
+	manifestPath := siblingPath(file.Path, "package.json")
+	content, err := file.Read(manifestPath)
+	if err != nil {
+		return declared
+	}
+	deps, _ := p.parsePackageJSON(manifestPath, content)
+	for _, dep := range deps {
+		if _, ok := declared[dep.Name]; !ok {
+			declared[dep.Name] = dep.Scope
+		}
+	}
+	return declared
+}
+
+// lockDependency 生成锁文件中的依赖记录，直接依赖的作用域以 package.json 中的声明为准
+func (p *NpmParser) lockDependency(manifest, name, version string, dev, topLevel bool, declared map[string]string) model.Dependency {
+	scope := model.ScopeRuntime
+	if dev {
+		scope = model.ScopeDev
+	}
+	declaredScope, direct := declared[name]
+	direct = direct && topLevel
+	if direct {
+		scope = declaredScope
+	}
+	return model.Dependency{
+		Ecosystem: model.EcosystemNpm,
+		Name:      name,
+		Version:   version,
+		Scope:     scope,
+		Direct:    direct,
+		Locked:    true,
+		Manifest:  manifest,
+	}
+}
+
+// npmSpecName 从 name@range 形式的依赖描述中取包名，兼容 @scope/name@range
+func npmSpecName(spec string) string {
+	if i := strings.LastIndex(spec, "@"); i > 0 {
+		return spec[:i]
+	}
+	return spec
+}
+
+// npmLicense 返回 package-lock.json 中的许可证：通常为 SPDX 表达式字符串，
+// 旧的包使用 {"type": "MIT"} 对象或对象数组（数组中的许可证为 OR 关系）
+func npmLicense(value any) string {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case map[string]any:
+		license, _ := v["type"].(string)
+		return strings.TrimSpace(license)
+	case []any:
+		var licenses []string
+		for _, entry := range v {
+			if license := npmLicense(entry); license != "" {
+				licenses = append(licenses, license)
+			}
+		}
+		return disjunctiveLicense(licenses)
+	}
+	return ""
+}
+
+// integrityChecksum 将 SRI 完整性摘要（如 "sha512-<base64>"）转换为 "sha512:<十六进制>"，有多个摘要时取第一个
+func integrityChecksum(integrity string) string {
+	fields := strings.Fields(integrity)
+	if len(fields) == 0 {
+		return ""
+	}
+	algorithm, value, ok := strings.Cut(fields[0], "-")
+	if !ok {
+		return ""
+	}
+	sum, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(algorithm) + ":" + hex.EncodeToString(sum)
+}
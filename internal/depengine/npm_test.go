@@ -0,0 +1,89 @@
+package depengine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestNpmPackageJSONAndLocks(t *testing.T) {
+	packageJSON := `{
+  "dependencies": {"react": "^18.2.0", "@babel/runtime": "^7.22.0"},
+  "devDependencies": {"vite": "^5.0.0"}
+}`
+	index, read := newTestIndex(map[string]string{
+		"package.json": packageJSON,
+		"package-lock.json": `{
+  "lockfileVersion": 3,
+  "packages": {
+    "": {"dependencies": {"react": "^18.2.0"}},
+    "node_modules/react": {"version": "18.2.0", "license": "MIT", "integrity": "sha512-/3IjMdb2L9QbBdWiW5e3P2/npwMBaU9mHCSCUzNln0ZCYbcfTsGbTJrU/kGemdH2IWmB2ioZ+zkxtmq6g09fGQ==", "dependencies": {"loose-envify": "^1.1.0"}},
+    "node_modules/vite": {"version": "5.0.10", "dev": true},
+    "node_modules/loose-envify": {"version": "1.4.0"},
+    "node_modules/vite/node_modules/react": {"version": "17.0.2", "dev": true},
+    "node_modules/local-pkg": {"link": true}
+  }
+}`,
+		"web/package.json": `{"dependencies": {"@babel/runtime": "^7.22.0", "lodash": "^4.17.0"}}`,
+		"web/yarn.lock": `# yarn lockfile v1
+
+"@babel/runtime@^7.22.0", "@babel/runtime@^7.8.4":
+  version "7.23.2"
+  resolved "https://registry.yarnpkg.com/@babel/runtime/-/runtime-7.23.2.tgz"
+
+lodash@^4.17.0:
+  version "4.17.21"
+`,
+		"app/package.json": `{"dependencies": {"vue": "^3.3.0"}}`,
+		"app/pnpm-lock.yaml": `lockfileVersion: '6.0'
+packages:
+  /vue@3.3.8(typescript@5.2.2):
+    resolution: {integrity: sha512-x}
+    dev: false
+  /@vue/shared@3.3.8:
+    resolution: {integrity: sha512-y}
+    dev: false
+`,
+		"node_modules/react/package.json": `{"dependencies": {"bogus": "1.0.0"}}`,
+	})
+
+	inventory := Collect(index, read, []Parser{&NpmParser{}})
+
+	if inventory.Has("bogus") {
+		t.Errorf("node_modules should be skipped")
+	}
+	if vite, _ := findDependency(inventory.Dependencies, "vite", "package.json"); vite.Scope != model.ScopeDev || vite.Version != "^5.0.0" {
+		t.Errorf("unexpected devDependency: %+v", vite)
+	}
+	if inventory.Version("react") != "18.2.0" {
+		t.Errorf("expected locked react version, got %q", inventory.Version("react"))
+	}
+	for _, dep := range inventory.Find("react") {
+		if dep.Version == "17.0.2" && dep.Direct {
+			t.Errorf("nested react copy should not be direct: %+v", dep)
+		}
+	}
+	react, _ := findDependency(inventory.Dependencies, "react", "package-lock.json")
+	if len(react.Requires) != 1 || react.Requires[0] != "loose-envify" {
+		t.Errorf("lockfile dependency relationships not recorded: %+v", react)
+	}
+	if react.License != "MIT" || !strings.HasPrefix(react.Checksum, "sha512:ff722331d6f62fd41b05d5a2") || len(react.Checksum) != len("sha512:")+128 {
+		t.Errorf("lockfile license or checksum not recorded: %+v", react)
+	}
+	if envify, _ := findDependency(inventory.Dependencies, "loose-envify", "package-lock.json"); envify.Direct || !envify.Locked {
+		t.Errorf("transitive package should be locked and indirect: %+v", envify)
+	}
+	if _, ok := findDependency(inventory.Dependencies, "local-pkg", "package-lock.json"); ok {
+		t.Errorf("linked workspace packages should be skipped")
+	}
+	if runtime, _ := findDependency(inventory.Dependencies, "@babel/runtime", "web/yarn.lock"); runtime.Version != "7.23.2" || !runtime.Direct {
+		t.Errorf("unexpected yarn entry: %+v", runtime)
+	}
+	if vue, _ := findDependency(inventory.Dependencies, "vue", "app/pnpm-lock.yaml"); vue.Version != "3.3.8" || !vue.Direct {
+		t.Errorf("unexpected pnpm entry: %+v", vue)
+	}
+	if shared, _ := findDependency(inventory.Dependencies, "@vue/shared", "app/pnpm-lock.yaml"); shared.Version != "3.3.8" || shared.Direct {
+		t.Errorf("unexpected scoped pnpm entry: %+v", shared)
+	}
+}
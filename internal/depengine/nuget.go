@@ -0,0 +1,522 @@
+package depengine
+
+import (
+	"encoding/json"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+	"github.com/winezer0/codecanvas/internal/utils"
+)
+
+// NuGetParser 解析 .NET 项目模型：.sln、SDK 风格与传统的 .csproj/.fsproj/.vbproj、
+// Directory.Packages.props（中央包版本管理）、packages.config 和 packages.lock.json。
+// 目标框架、MSBuild SDK 与框架引用以 framework 作用域的依赖记录输出。
+type NuGetParser struct{}
+
+func init() {
+	Register(&NuGetParser{})
+}
+
+// .NET 目标框架族名称
+const (
+	DotNetFramework = ".NETFramework"
+	DotNetCoreApp   = ".NETCoreApp"
+	DotNetStandard  = ".NETStandard"
+)
+
+// 隐式框架引用名称
+const (
+	FrameworkRefWPF      = "Microsoft.WindowsDesktop.App.WPF"
+	FrameworkRefWinForms = "Microsoft.WindowsDesktop.App.WindowsForms"
+	FrameworkRefMaui     = "Microsoft.Maui"
+	FrameworkRefAspNet   = "System.Web"
+)
+
+var (
+	// slnProjectRe 匹配 .sln 中的项目声明：Project("{类型GUID}") = "名称", "路径", "{项目GUID}"
+	slnProjectRe = regexp.MustCompile(`(?m)^Project\("\{([0-9A-Fa-f-]+)\}"\)\s*=\s*"([^"]*)"\s*,\s*"([^"]*)"`)
+	// msbuildPropertyRe 匹配 MSBuild 属性引用 $(Name)
+	msbuildPropertyRe = regexp.MustCompile(`\$\(([A-Za-z0-9_.-]+)\)`)
+	// hintPathPackageRe 匹配传统项目 HintPath 中的 packages\名称.版本\ 目录
+	hintPathPackageRe = regexp.MustCompile(`(?i)packages[\\/]([^\\/]+?)\.(\d+(?:\.\d+)+(?:-[^\\/]+)?)[\\/]`)
+	// tfmRe 匹配目标框架名称，如 net8.0-windows、netcoreapp3.1、netstandard2.0、net472
+	tfmRe = regexp.MustCompile(`^(netcoreapp|netstandard|net)(\d+(?:\.\d+)*)`)
+)
+
+// projectTypeFrameworks 项目类型 GUID 到框架引用的映射（用于 .sln 与传统项目的 ProjectTypeGuids）
+var projectTypeFrameworks = map[string]string{
+	"349C5851-65DF-11DA-9384-00065B846F21": FrameworkRefAspNet, // ASP.NET Web Application
+	"E24C65DC-7377-472B-9ABA-BC803B73C61A": FrameworkRefAspNet, // ASP.NET Web Site
+	"603C0E0B-DB56-11DC-BE95-000D561079B0": FrameworkRefAspNet, // ASP.NET MVC 1
+	"F85E285D-A4E0-4152-9332-AB1D724D3325": FrameworkRefAspNet, // ASP.NET MVC 2
+	"E53F8FEA-EAE0-44A6-8774-FFD645390401": FrameworkRefAspNet, // ASP.NET MVC 3
+	"E3E379DF-F4C6-4180-9B81-6769533ABE47": FrameworkRefAspNet, // ASP.NET MVC 4
+	"60DC8134-EBA5-43B8-BCC9-BB4BC16C2548": FrameworkRefWPF,    // WPF
+}
+
+// gacFrameworks 传统项目中无 HintPath 的程序集引用到框架引用的映射
+var gacFrameworks = map[string]string{
+	"system.web":            FrameworkRefAspNet,
+	"presentationframework": FrameworkRefWPF,
+	"system.windows.forms":  FrameworkRefWinForms,
+}
+
+// DotNetPackageReference 项目中的 PackageReference
+type DotNetPackageReference struct {
+	Name    string
+	Version string
+	Dev     bool
+}
+
+// DotNetProject 解析后的 .NET 项目信息
+// - Sdk: MSBuild SDK（传统项目为空）
+// - TargetFrameworks: 目标框架名称列表，如 ["net8.0", "net472"]
+// - Properties: PropertyGroup 中定义的属性
+// - PackageReferences: NuGet 包引用
+// - FrameworkReferences: 框架引用（含 UseWPF 等隐式引用）
+// - AssemblyReferences: 传统项目的程序集引用 Include 与 HintPath
+type DotNetProject struct {
+	Sdk                 string
+	TargetFrameworks    []string
+	Properties          map[string]string
+	PackageReferences   []DotNetPackageReference
+	FrameworkReferences []string
+	AssemblyReferences  [][2]string
+}
+
+// DotNetSolutionProject .sln 中声明的项目
+type DotNetSolutionProject struct {
+	TypeGUID string
+	Name     string
+	Path     string
+}
+
+// Ecosystem 返回 NuGet 生态名称
+func (p *NuGetParser) Ecosystem() string {
+	return model.EcosystemNuGet
+}
+
+// Match 匹配 .NET 项目文件、解决方案文件与 NuGet 清单
+func (p *NuGetParser) Match(relPath string) bool {
+	// bin/obj 为构建产物，packages 为传统 NuGet 包目录
+	if inVendorDir(relPath, "bin", "obj", "packages") {
+		return false
+	}
+	name := strings.ToLower(path.Base(relPath))
+	switch name {
+	case "packages.config", "packages.lock.json":
+		return true
+	}
+	switch path.Ext(name) {
+	case ".csproj", ".fsproj", ".vbproj", ".sln":
+		return true
+	}
+	return false
+}
+
+// Parse 根据文件类型分派到对应的解析逻辑
+func (p *NuGetParser) Parse(file ManifestFile) ([]model.Dependency, error) {
+	name := strings.ToLower(path.Base(file.Path))
+	switch {
+	case name == "packages.config":
+		return p.parsePackagesConfig(file)
+	case name == "packages.lock.json":
+		return p.parseLock(file)
+	case path.Ext(name) == ".sln":
+		return p.parseSolution(file), nil
+	default:
+		return p.parseProject(file)
+	}
+}
+
+// ParseDotNetProject 解析 SDK 风格或传统的 .NET 项目文件
+func ParseDotNetProject(content []byte) (*DotNetProject, error) {
+	root, err := utils.ParseXML(content)
+	if err != nil {
+		return nil, err
+	}
+	return newDotNetProject(root), nil
+}
+
+// newDotNetProject 从项目文件的 XML 根节点构建项目信息
+func newDotNetProject(root *utils.XMLNode) *DotNetProject {
+	project := &DotNetProject{
+		Sdk:        root.Attr("Sdk"),
+		Properties: make(map[string]string),
+	}
+	// <Sdk Name="..." /> 形式的 SDK 声明
+	if project.Sdk == "" {
+		if sdk := root.Child("Sdk"); sdk != nil {
+			project.Sdk = sdk.Attr("Name")
+		}
+	}
+	// 去除 SDK 中的版本后缀，如 Microsoft.NET.Sdk/8.0.100
+	if i := strings.Index(project.Sdk, "/"); i > 0 {
+		project.Sdk = project.Sdk[:i]
+	}
+	// 多个 SDK 时取第一个
+	if i := strings.Index(project.Sdk, ";"); i > 0 {
+		project.Sdk = project.Sdk[:i]
+	}
+
+	for _, group := range root.Children("PropertyGroup") {
+		for _, prop := range group.Nodes {
+			project.Properties[prop.Name()] = prop.Text()
+		}
+	}
+
+	// 目标框架
+	if tfms := project.Property("TargetFrameworks"); tfms != "" {
+		for _, tfm := range strings.Split(tfms, ";") {
+			if tfm = strings.TrimSpace(tfm); tfm != "" {
+				project.TargetFrameworks = append(project.TargetFrameworks, tfm)
+			}
+		}
+	} else if tfm := project.Property("TargetFramework"); tfm != "" {
+		project.TargetFrameworks = append(project.TargetFrameworks, tfm)
+	} else if version := project.Property("TargetFrameworkVersion"); version != "" {
+		// 传统项目：v4.7.2 -> net472
+		project.TargetFrameworks = append(project.TargetFrameworks, "net"+strings.ReplaceAll(strings.TrimPrefix(strings.ToLower(version), "v"), ".", ""))
+	}
+
+	// 隐式框架引用
+	for _, implicit := range [][2]string{{"UseWPF", FrameworkRefWPF}, {"UseWindowsForms", FrameworkRefWinForms}, {"UseMaui", FrameworkRefMaui}} {
+		if strings.EqualFold(project.Property(implicit[0]), "true") {
+			project.FrameworkReferences = append(project.FrameworkReferences, implicit[1])
+		}
+	}
+	for _, guid := range strings.Split(project.Property("ProjectTypeGuids"), ";") {
+		guid = strings.ToUpper(strings.Trim(strings.TrimSpace(guid), "{}"))
+		if ref, ok := projectTypeFrameworks[guid]; ok {
+			project.FrameworkReferences = append(project.FrameworkReferences, ref)
+		}
+	}
+
+	for _, group := range root.Children("ItemGroup") {
+		for _, ref := range group.Children("PackageReference") {
+			name := ref.Attr("Include")
+			if name == "" {
+				name = ref.Attr("Update")
+			}
+			if name == "" {
+				continue
+			}
+			version := ref.Attr("Version")
+			if version == "" {
+				version = ref.ChildText("Version")
+			}
+			if override := ref.Attr("VersionOverride"); override != "" {
+				version = override
+			}
+			privateAssets := ref.Attr("PrivateAssets")
+			if privateAssets == "" {
+				privateAssets = ref.ChildText("PrivateAssets")
+			}
+			project.PackageReferences = append(project.PackageReferences, DotNetPackageReference{
+				Name:    name,
+				Version: project.Expand(version),
+				Dev:     strings.EqualFold(privateAssets, "all"),
+			})
+		}
+		for _, ref := range group.Children("FrameworkReference") {
+			if name := ref.Attr("Include"); name != "" {
+				project.FrameworkReferences = append(project.FrameworkReferences, name)
+			}
+		}
+		for _, ref := range group.Children("Reference") {
+			if include := ref.Attr("Include"); include != "" {
+				project.AssemblyReferences = append(project.AssemblyReferences, [2]string{include, ref.ChildText("HintPath")})
+			}
+		}
+	}
+
+	for i, tfm := range project.TargetFrameworks {
+		project.TargetFrameworks[i] = project.Expand(tfm)
+	}
+	return project
+}
+
+// Property 返回项目属性值，并展开其中引用的其他属性
+func (proj *DotNetProject) Property(name string) string {
+	for key, value := range proj.Properties {
+		if strings.EqualFold(key, name) {
+			return proj.Expand(value)
+		}
+	}
+	return ""
+}
+
+// Expand 展开字符串中的 $(Name) 属性引用，无法解析的引用保持原样
+func (proj *DotNetProject) Expand(value string) string {
+	for depth := 0; depth < 5 && strings.Contains(value, "$("); depth++ {
+		expanded := msbuildPropertyRe.ReplaceAllStringFunc(value, func(ref string) string {
+			name := msbuildPropertyRe.FindStringSubmatch(ref)[1]
+			for key, v := range proj.Properties {
+				if strings.EqualFold(key, name) {
+					return v
+				}
+			}
+			return ref
+		})
+		if expanded == value {
+			break
+		}
+		value = expanded
+	}
+	return strings.TrimSpace(value)
+}
+
+// ParseDotNetSolution 解析 .sln 中声明的项目列表（路径统一为 "/" 分隔）
+func ParseDotNetSolution(content []byte) []DotNetSolutionProject {
+	var projects []DotNetSolutionProject
+	for _, m := range slnProjectRe.FindAllStringSubmatch(string(content), -1) {
+		projects = append(projects, DotNetSolutionProject{
+			TypeGUID: strings.ToUpper(m[1]),
+			Name:     m[2],
+			Path:     strings.ReplaceAll(m[3], "\\", "/"),
+		})
+	}
+	return projects
+}
+
+// parseSolution 根据 .sln 中的项目类型 GUID 输出框架引用（如传统 ASP.NET Web 应用、WPF 应用）
+func (p *NuGetParser) parseSolution(file ManifestFile) []model.Dependency {
+	seen := make(map[string]bool)
+	var deps []model.Dependency
+	for _, project := range ParseDotNetSolution(file.Content) {
+		ref, ok := projectTypeFrameworks[project.TypeGUID]
+		if !ok || seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		deps = append(deps, p.frameworkDependency(file.Path, ref, ""))
+	}
+	return deps
+}
+
+// parseProject 解析项目文件中的目标框架、SDK、框架引用与包引用
+func (p *NuGetParser) parseProject(file ManifestFile) ([]model.Dependency, error) {
+	project, err := ParseDotNetProject(file.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	var deps []model.Dependency
+	tfms := strings.Join(project.TargetFrameworks, ";")
+
+	// 目标框架：如 net8.0 -> .NETCoreApp 8.0
+	for _, tfm := range project.TargetFrameworks {
+		if family, version := ParseTargetFramework(tfm); family != "" {
+			deps = append(deps, p.frameworkDependency(file.Path, family, version))
+		}
+	}
+	if project.Sdk != "" {
+		deps = append(deps, p.frameworkDependency(file.Path, project.Sdk, tfms))
+	}
+	for _, ref := range project.FrameworkReferences {
+		deps = append(deps, p.frameworkDependency(file.Path, ref, tfms))
+	}
+
+	// 包引用：未声明版本时使用 Directory.Packages.props 中的中央版本
+	var central map[string]string
+	for _, ref := range project.PackageReferences {
+		version := ref.Version
+		if version == "" {
+			if central == nil {
+				central = p.centralVersions(file)
+			}
+			version = central[strings.ToLower(ref.Name)]
+		}
+		scope := model.ScopeRuntime
+		if ref.Dev {
+			scope = model.ScopeDev
+		}
+		deps = append(deps, model.Dependency{
+			Ecosystem: model.EcosystemNuGet,
+			Name:      ref.Name,
+			Version:   version,
+			Scope:     scope,
+			Direct:    true,
+			Manifest:  file.Path,
+		})
+	}
+
+	// 传统项目的程序集引用：packages 目录中的包或 GAC 中的框架程序集
+	hasPackagesConfig := file.Index != nil && file.Index.Contains(siblingPath(file.Path, "packages.config"))
+	for _, ref := range project.AssemblyReferences {
+		assembly := strings.TrimSpace(strings.Split(ref[0], ",")[0])
+		if m := hintPathPackageRe.FindStringSubmatch(ref[1]); m != nil {
+			if hasPackagesConfig {
+				continue
+			}
+			deps = append(deps, model.Dependency{
+				Ecosystem: model.EcosystemNuGet,
+				Name:      m[1],
+				Version:   m[2],
+				Scope:     model.ScopeRuntime,
+				Direct:    true,
+				Locked:    true,
+				Manifest:  file.Path,
+			})
+			continue
+		}
+		if framework, ok := gacFrameworks[strings.ToLower(assembly)]; ok && ref[1] == "" {
+			deps = append(deps, p.frameworkDependency(file.Path, framework, tfms))
+		}
+	}
+
+	return deps, nil
+}
+
+// centralVersions 查找距离项目最近的 Directory.Packages.props 并读取其中的 PackageVersion
+func (p *NuGetParser) centralVersions(file ManifestFile) map[string]string {
+	versions := make(map[string]string)
+	if file.Index == nil || file.Read == nil {
+		return versions
+	}
+
+	dir := path.Dir(file.Path)
+	for {
+		propsPath := joinPath(dir, "Directory.Packages.props")
+		if file.Index.Contains(propsPath) {
+			content, err := file.Read(propsPath)
+			if err != nil {
+				return versions
+			}
+			root, err := utils.ParseXML(content)
+			if err != nil {
+				return versions
+			}
+			props := newDotNetProject(root)
+			for _, pv := range root.FindAll("PackageVersion") {
+				name := pv.Attr("Include")
+				if name == "" {
+					name = pv.Attr("Update")
+				}
+				if name != "" {
+					versions[strings.ToLower(name)] = props.Expand(pv.Attr("Version"))
+				}
+			}
+			return versions
+		}
+		if dir == "." || dir == "/" || dir == "" {
+			return versions
+		}
+		dir = path.Dir(dir)
+	}
+}
+
+// parsePackagesConfig 解析传统项目的 packages.config
+func (p *NuGetParser) parsePackagesConfig(file ManifestFile) ([]model.Dependency, error) {
+	root, err := utils.ParseXML(file.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	var deps []model.Dependency
+	for _, pkg := range root.Children("package") {
+		scope := model.ScopeRuntime
+		if strings.EqualFold(pkg.Attr("developmentDependency"), "true") {
+			scope = model.ScopeDev
+		}
+		deps = append(deps, model.Dependency{
+			Ecosystem: model.EcosystemNuGet,
+			Name:      pkg.Attr("id"),
+			Version:   pkg.Attr("version"),
+			Scope:     scope,
+			Direct:    true,
+			Locked:    true,
+			Manifest:  file.Path,
+		})
+	}
+	return deps, nil
+}
+
+// nugetLockEntry packages.lock.json 中的单个包
+type nugetLockEntry struct {
+	Type     string `json:"type"`
+	Resolved string `json:"resolved"`
+}
+
+// parseLock 解析 packages.lock.json，忽略项目间引用
+func (p *NuGetParser) parseLock(file ManifestFile) ([]model.Dependency, error) {
+	var lock struct {
+		Dependencies map[string]map[string]nugetLockEntry `json:"dependencies"`
+	}
+	if err := json.Unmarshal(file.Content, &lock); err != nil {
+		return nil, err
+	}
+
+	// 多个目标框架中的同一个包只记录一次
+	seen := make(map[string]bool)
+	var deps []model.Dependency
+	for _, tfm := range sortedKeys(lock.Dependencies) {
+		packages := lock.Dependencies[tfm]
+		for _, name := range sortedKeys(packages) {
+			entry := packages[name]
+			if strings.EqualFold(entry.Type, "Project") {
+				continue
+			}
+			key := strings.ToLower(name) + "@" + entry.Resolved
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			deps = append(deps, model.Dependency{
+				Ecosystem: model.EcosystemNuGet,
+				Name:      name,
+				Version:   entry.Resolved,
+				Scope:     model.ScopeRuntime,
+				Direct:    strings.EqualFold(entry.Type, "Direct"),
+				Locked:    true,
+				Manifest:  file.Path,
+			})
+		}
+	}
+	return deps, nil
+}
+
+// frameworkDependency 生成 framework 作用域的依赖记录
+func (p *NuGetParser) frameworkDependency(manifest, name, version string) model.Dependency {
+	return model.Dependency{
+		Ecosystem: model.EcosystemNuGet,
+		Name:      name,
+		Version:   version,
+		Scope:     model.ScopeFramework,
+		Direct:    true,
+		Manifest:  manifest,
+	}
+}
+
+// ParseTargetFramework 将目标框架名称解析为框架族与版本
+// 例如：net8.0-windows -> (.NETCoreApp, 8.0)，net472 -> (.NETFramework, 4.7.2)，netstandard2.0 -> (.NETStandard, 2.0)
+func ParseTargetFramework(tfm string) (family, version string) {
+	m := tfmRe.FindStringSubmatch(strings.ToLower(strings.TrimSpace(tfm)))
+	if m == nil {
+		return "", ""
+	}
+	switch m[1] {
+	case "netcoreapp":
+		return DotNetCoreApp, m[2]
+	case "netstandard":
+		return DotNetStandard, m[2]
+	}
+	// net5.0 及以上为 .NET (Core)，net45/net472 等无点号的为 .NET Framework
+	if strings.Contains(m[2], ".") {
+		return DotNetCoreApp, m[2]
+	}
+	return DotNetFramework, strings.Join(strings.Split(m[2], ""), ".")
+}
+
+// sortedKeys 返回按字典序排序的映射键，保证输出顺序稳定
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
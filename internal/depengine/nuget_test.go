@@ -0,0 +1,195 @@
+package depengine
+
+import (
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestNuGetSdkProjectWithCentralVersions(t *testing.T) {
+	index, read := newTestIndex(map[string]string{
+		"Directory.Packages.props": `<Project>
+  <PropertyGroup>
+    <EfVersion>8.0.2</EfVersion>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageVersion Include="Microsoft.EntityFrameworkCore" Version="$(EfVersion)" />
+    <PackageVersion Include="Serilog.AspNetCore" Version="8.0.1" />
+  </ItemGroup>
+</Project>`,
+		"src/Web/Web.csproj": `<Project Sdk="Microsoft.NET.Sdk.Web">
+  <PropertyGroup>
+    <TargetFrameworks>net8.0;net6.0</TargetFrameworks>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageReference Include="Microsoft.EntityFrameworkCore" />
+    <PackageReference Include="Serilog.AspNetCore" VersionOverride="8.0.0" />
+    <PackageReference Include="StyleCop.Analyzers" Version="1.1.118">
+      <PrivateAssets>all</PrivateAssets>
+    </PackageReference>
+  </ItemGroup>
+</Project>`,
+		"src/Desktop/Desktop.csproj": `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <TargetFramework>net8.0-windows</TargetFramework>
+    <UseWPF>true</UseWPF>
+  </PropertyGroup>
+</Project>`,
+		"src/Web/bin/Debug/Web.csproj": `<Project Sdk="Microsoft.NET.Sdk" />`,
+	})
+
+	inventory := Collect(index, read, []Parser{&NuGetParser{}})
+
+	if len(inventory.Find("*")) == 0 {
+		t.Fatalf("expected dependencies to be collected")
+	}
+	for _, dep := range inventory.Dependencies {
+		if dep.Manifest == "src/Web/bin/Debug/Web.csproj" {
+			t.Errorf("build output under bin/ should be skipped: %+v", dep)
+		}
+	}
+
+	ef, ok := findDependency(inventory.Dependencies, "Microsoft.EntityFrameworkCore", "src/Web/Web.csproj")
+	if !ok || ef.Version != "8.0.2" || ef.Scope != model.ScopeRuntime || !ef.Direct {
+		t.Errorf("central version should be resolved from Directory.Packages.props: %+v", ef)
+	}
+	if serilog, _ := findDependency(inventory.Dependencies, "Serilog.AspNetCore", "src/Web/Web.csproj"); serilog.Version != "8.0.0" {
+		t.Errorf("VersionOverride should take precedence: %+v", serilog)
+	}
+	if stylecop, _ := findDependency(inventory.Dependencies, "StyleCop.Analyzers", "src/Web/Web.csproj"); stylecop.Scope != model.ScopeDev {
+		t.Errorf("PrivateAssets=all should be a dev dependency: %+v", stylecop)
+	}
+
+	sdk, ok := findDependency(inventory.Dependencies, "Microsoft.NET.Sdk.Web", "src/Web/Web.csproj")
+	if !ok || sdk.Scope != model.ScopeFramework || sdk.Version != "net8.0;net6.0" {
+		t.Errorf("unexpected SDK entry: %+v", sdk)
+	}
+	if version := inventory.Version(DotNetCoreApp); version != "8.0" {
+		t.Errorf("expected target framework version 8.0, got %q", version)
+	}
+	if !inventory.Has(FrameworkRefWPF) {
+		t.Errorf("UseWPF should produce an implicit framework reference")
+	}
+}
+
+func TestNuGetLegacyProjectAndPackagesConfig(t *testing.T) {
+	index, read := newTestIndex(map[string]string{
+		"Legacy/Legacy.csproj": `<?xml version="1.0" encoding="utf-8"?>
+<Project ToolsVersion="15.0" xmlns="http://schemas.microsoft.com/developer/msbuild/2003">
+  <PropertyGroup>
+    <TargetFrameworkVersion>v4.7.2</TargetFrameworkVersion>
+    <ProjectTypeGuids>{349c5851-65df-11da-9384-00065b846f21};{fae04ec0-301f-11d3-bf4b-00c04f79efbc}</ProjectTypeGuids>
+  </PropertyGroup>
+  <ItemGroup>
+    <Reference Include="System.Windows.Forms" />
+    <Reference Include="Newtonsoft.Json, Version=13.0.0.0, Culture=neutral">
+      <HintPath>..\packages\Newtonsoft.Json.13.0.3\lib\net45\Newtonsoft.Json.dll</HintPath>
+    </Reference>
+  </ItemGroup>
+</Project>`,
+		"Tools/Tools.csproj": `<Project xmlns="http://schemas.microsoft.com/developer/msbuild/2003">
+  <ItemGroup>
+    <Reference Include="Dapper">
+      <HintPath>..\packages\Dapper.2.1.28\lib\net461\Dapper.dll</HintPath>
+    </Reference>
+  </ItemGroup>
+</Project>`,
+		"Tools/packages.config": `<?xml version="1.0" encoding="utf-8"?>
+<packages>
+  <package id="Dapper" version="2.1.28" targetFramework="net472" />
+  <package id="Microsoft.CodeAnalysis.NetAnalyzers" version="8.0.0" developmentDependency="true" />
+</packages>`,
+		"packages/Dapper.2.1.28/Dapper.nuspec": `<package />`,
+	})
+
+	inventory := Collect(index, read, []Parser{&NuGetParser{}})
+
+	if version := inventory.Version(DotNetFramework); version != "4.7.2" {
+		t.Errorf("expected .NETFramework 4.7.2, got %q", version)
+	}
+	for _, ref := range []string{FrameworkRefAspNet, FrameworkRefWinForms} {
+		if !inventory.Has(ref) {
+			t.Errorf("expected framework reference %s", ref)
+		}
+	}
+
+	hinted, ok := findDependency(inventory.Dependencies, "Newtonsoft.Json", "Legacy/Legacy.csproj")
+	if !ok || hinted.Version != "13.0.3" || !hinted.Locked {
+		t.Errorf("package should be resolved from HintPath: %+v", hinted)
+	}
+	if _, ok := findDependency(inventory.Dependencies, "Dapper", "Tools/Tools.csproj"); ok {
+		t.Errorf("HintPath should be ignored when packages.config exists")
+	}
+
+	dapper, ok := findDependency(inventory.Dependencies, "Dapper", "Tools/packages.config")
+	if !ok || dapper.Version != "2.1.28" || !dapper.Locked || !dapper.Direct {
+		t.Errorf("unexpected packages.config entry: %+v", dapper)
+	}
+	if analyzers, _ := findDependency(inventory.Dependencies, "Microsoft.CodeAnalysis.NetAnalyzers", "Tools/packages.config"); analyzers.Scope != model.ScopeDev {
+		t.Errorf("developmentDependency should be a dev dependency: %+v", analyzers)
+	}
+}
+
+func TestNuGetLockAndSolution(t *testing.T) {
+	solution := `Microsoft Visual Studio Solution File, Format Version 12.00
+Project("{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}") = "App", "App\App.csproj", "{11111111-1111-1111-1111-111111111111}"
+EndProject
+Project("{60DC8134-EBA5-43B8-BCC9-BB4BC16C2548}") = "Viewer", "Viewer\Viewer.csproj", "{22222222-2222-2222-2222-222222222222}"
+EndProject
+`
+	index, read := newTestIndex(map[string]string{
+		"App/packages.lock.json": `{
+  "version": 1,
+  "dependencies": {
+    "net6.0": {
+      "Newtonsoft.Json": {"type": "Direct", "requested": "[13.0.1, )", "resolved": "13.0.1"},
+      "System.Memory": {"type": "Transitive", "resolved": "4.5.5"},
+      "Shared": {"type": "Project"}
+    },
+    "net8.0": {
+      "Newtonsoft.Json": {"type": "Direct", "requested": "[13.0.1, )", "resolved": "13.0.1"}
+    }
+  }
+}`,
+		"Demo.sln": solution,
+	})
+
+	inventory := Collect(index, read, []Parser{&NuGetParser{}})
+
+	if matches := inventory.Find("Newtonsoft.Json"); len(matches) != 1 || !matches[0].Direct || !matches[0].Locked {
+		t.Errorf("lock entries should be deduplicated across target frameworks: %+v", matches)
+	}
+	if transitive, _ := findDependency(inventory.Dependencies, "System.Memory", "App/packages.lock.json"); transitive.Direct {
+		t.Errorf("transitive package should not be direct: %+v", transitive)
+	}
+	if inventory.Has("Shared") {
+		t.Errorf("project references should be skipped")
+	}
+	if wpf, ok := findDependency(inventory.Dependencies, FrameworkRefWPF, "Demo.sln"); !ok || wpf.Scope != model.ScopeFramework {
+		t.Errorf("WPF project type GUID should produce a framework reference: %+v", wpf)
+	}
+
+	projects := ParseDotNetSolution([]byte(solution))
+	if len(projects) != 2 || projects[1].Path != "Viewer/Viewer.csproj" {
+		t.Errorf("unexpected solution projects: %+v", projects)
+	}
+}
+
+func TestParseTargetFramework(t *testing.T) {
+	cases := map[string][2]string{
+		"net8.0-windows":  {DotNetCoreApp, "8.0"},
+		"netcoreapp3.1":   {DotNetCoreApp, "3.1"},
+		"netstandard2.0":  {DotNetStandard, "2.0"},
+		"net472":          {DotNetFramework, "4.7.2"},
+		"net48":           {DotNetFramework, "4.8"},
+		"monoandroid":     {"", ""},
+		"$(TargetFwk)":    {"", ""},
+		" net6.0-android": {DotNetCoreApp, "6.0"},
+	}
+	for tfm, want := range cases {
+		family, version := ParseTargetFramework(tfm)
+		if family != want[0] || version != want[1] {
+			t.Errorf("ParseTargetFramework(%q) = (%q, %q), want (%q, %q)", tfm, family, version, want[0], want[1])
+		}
+	}
+}
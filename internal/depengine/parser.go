@@ -0,0 +1,298 @@
+// Package depengine 提供了 CodeCanvas 的依赖清单解析功能。
+package depengine
+
+import (
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/archivefs"
+	"github.com/winezer0/codecanvas/internal/logging"
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// ContentReader 根据相对路径读取文件内容
+type ContentReader func(relPath string) ([]byte, error)
+
+// ManifestFile 待解析的清单文件
+// - Path: 清单文件相对路径（使用 "/" 分隔）
+// - Content: 清单文件内容
+// - Index: 文件索引，用于判断同目录下的其他清单是否存在
+// - Read: 读取其他文件内容的方法
+type ManifestFile struct {
+	Path    string
+	Content []byte
+	Index   *model.FileIndex
+	Read    ContentReader
+}
+
+// Parser 依赖清单解析器接口，每个生态实现一个解析器
+type Parser interface {
+	// Ecosystem 返回解析器对应的依赖生态名称
+	Ecosystem() string
+	// Match 判断给定的相对路径是否为该解析器可处理的清单文件
+	Match(relPath string) bool
+	// Parse 解析清单文件并返回依赖记录
+	Parse(file ManifestFile) ([]model.Dependency, error)
+}
+
+// MemberMatcher 由解析压缩包成员的解析器实现；压缩包内的虚拟路径（如 "app.war!/WEB-INF/lib/x.jar"）
+// 只交给实现该接口的解析器，避免将依赖包自带的 pom.xml、package.json 等清单当作项目清单
+type MemberMatcher interface {
+	// MatchMember 判断给定的压缩包成员虚拟路径是否为该解析器可处理的文件
+	MatchMember(relPath string) bool
+}
+
+var defaultParsers []Parser
+
+// Register 注册一个默认解析器
+func Register(p Parser) {
+	defaultParsers = append(defaultParsers, p)
+}
+
+// DefaultParsers 返回所有已注册的默认解析器
+func DefaultParsers() []Parser {
+	return append([]Parser(nil), defaultParsers...)
+}
+
+// Collect 遍历文件索引，使用给定的解析器解析所有清单文件，生成依赖清单
+func Collect(index *model.FileIndex, read ContentReader, parsers []Parser) *Inventory {
+	inventory := NewInventory()
+	if index == nil {
+		return inventory
+	}
+
+	for _, relPath := range index.Files {
+		member := archivefs.IsMember(relPath)
+		for _, parser := range parsers {
+			if !member && !parser.Match(relPath) {
+				continue
+			}
+			if matcher, ok := parser.(MemberMatcher); member && (!ok || !matcher.MatchMember(relPath)) {
+				continue
+			}
+			content, err := read(relPath)
+			if err != nil {
+				logging.Debugf("read manifest %s failed: %v", relPath, err)
+				continue
+			}
+			deps, err := parser.Parse(ManifestFile{Path: relPath, Content: content, Index: index, Read: read})
+			if err != nil {
+				logging.Warnf("parse %s manifest %s failed: %v", parser.Ecosystem(), relPath, err)
+				continue
+			}
+			inventory.Add(deps...)
+		}
+	}
+
+	return inventory
+}
+
+// Inventory 依赖清单，保存所有清单文件中解析出的依赖
+// - Dependencies: 清单文件中解析出的依赖
+// - Undeclared: 源码中导入但未在清单中声明的依赖，由 AnalyzeUsage 生成
+type Inventory struct {
+	Dependencies []model.Dependency
+	Undeclared   []model.Dependency
+	byName       map[string][]int
+	// importers 生态与依赖名称（见 importerKey）到导入该依赖的源码文件集合
+	importers map[string]map[string]bool
+	// ecosystems 非空时只查找这些生态中的依赖，见 Scoped
+	ecosystems map[string]bool
+}
+
+// NewInventory 创建一个新的空依赖清单
+func NewInventory() *Inventory {
+	return &Inventory{
+		Dependencies: []model.Dependency{},
+		byName:       make(map[string][]int),
+		importers:    make(map[string]map[string]bool),
+	}
+}
+
+// Add 向依赖清单添加依赖记录
+func (inv *Inventory) Add(deps ...model.Dependency) {
+	for _, dep := range deps {
+		if dep.Name == "" {
+			continue
+		}
+		key := strings.ToLower(dep.Name)
+		inv.byName[key] = append(inv.byName[key], len(inv.Dependencies))
+		inv.Dependencies = append(inv.Dependencies, dep)
+	}
+}
+
+// Scoped 返回只查找指定生态中依赖的只读视图，用于避免不同生态中的同名包（如 RubyGems 与 npm 的 rack）互相匹配；
+// 未指定生态时返回依赖清单本身
+func (inv *Inventory) Scoped(ecosystems ...string) *Inventory {
+	if len(ecosystems) == 0 {
+		return inv
+	}
+	scoped := *inv
+	scoped.ecosystems = make(map[string]bool, len(ecosystems))
+	for _, ecosystem := range ecosystems {
+		scoped.ecosystems[ecosystem] = true
+	}
+	return &scoped
+}
+
+// inScope 判断生态是否在查找范围内
+func (inv *Inventory) inScope(ecosystem string) bool {
+	return inv.ecosystems == nil || inv.ecosystems[ecosystem]
+}
+
+// Find 查找名称匹配的依赖记录，pattern 支持 * 通配符（不区分大小写）
+func (inv *Inventory) Find(pattern string) []model.Dependency {
+	var results []model.Dependency
+	pattern = strings.ToLower(pattern)
+
+	if !strings.ContainsAny(pattern, "*?[") {
+		for _, idx := range inv.byName[pattern] {
+			if dep := inv.Dependencies[idx]; inv.inScope(dep.Ecosystem) {
+				results = append(results, dep)
+			}
+		}
+		return results
+	}
+
+	for _, dep := range inv.Dependencies {
+		if inv.inScope(dep.Ecosystem) && MatchName(pattern, dep.Name) {
+			results = append(results, dep)
+		}
+	}
+	return results
+}
+
+// MatchName 判断依赖名称是否匹配规则中的依赖名称，pattern 支持 * 通配符（不区分大小写）
+func MatchName(pattern, name string) bool {
+	pattern, name = strings.ToLower(pattern), strings.ToLower(name)
+	if !strings.ContainsAny(pattern, "*?[") {
+		return pattern == name
+	}
+	matched, _ := path.Match(pattern, name)
+	return matched
+}
+
+// Has 判断依赖清单中是否存在名称匹配的依赖
+func (inv *Inventory) Has(pattern string) bool {
+	return len(inv.Find(pattern)) > 0
+}
+
+// Version 返回名称匹配的依赖版本，优先使用锁文件中的精确版本，其次是直接依赖的声明版本
+func (inv *Inventory) Version(pattern string) string {
+	if dep := inv.Versioned(pattern); dep != nil {
+		return dep.Version
+	}
+	return ""
+}
+
+// Versioned 返回名称匹配且带版本号的依赖，优先级与 Version 相同，不存在时返回 nil
+func (inv *Inventory) Versioned(pattern string) *model.Dependency {
+	deps := inv.Find(pattern)
+	sort.SliceStable(deps, func(i, j int) bool {
+		if deps[i].Locked != deps[j].Locked {
+			return deps[i].Locked
+		}
+		return deps[i].Direct && !deps[j].Direct
+	})
+	for i := range deps {
+		if deps[i].Version != "" {
+			return &deps[i]
+		}
+	}
+	return nil
+}
+
+// UsageWeight 返回导入任一名称匹配的依赖的源码文件数量，同一文件只计数一次
+func (inv *Inventory) UsageWeight(patterns ...string) int {
+	files := make(map[string]bool)
+	for _, pattern := range patterns {
+		for key, importers := range inv.importers {
+			ecosystem, name, _ := strings.Cut(key, "\x00")
+			if !MatchName(pattern, name) || !inv.inScope(ecosystem) {
+				continue
+			}
+			for file := range importers {
+				files[file] = true
+			}
+		}
+	}
+	return len(files)
+}
+
+// Usage 返回名称匹配的依赖的使用情况，任一依赖被导入时为 declared-and-used；
+// 没有判断过使用情况的依赖返回空
+func (inv *Inventory) Usage(patterns ...string) string {
+	usage := ""
+	for _, pattern := range patterns {
+		for _, dep := range inv.Find(pattern) {
+			switch dep.Usage {
+			case model.UsageDeclaredAndUsed:
+				return dep.Usage
+			case model.UsageDeclaredOnly:
+				usage = dep.Usage
+			}
+		}
+	}
+	return usage
+}
+
+// normalizeVersion 去除锁文件版本号中的 "v" 前缀（如 "v5.4.1" -> "5.4.1"）
+func normalizeVersion(version string) string {
+	version = strings.TrimSpace(version)
+	if len(version) > 1 && (version[0] == 'v' || version[0] == 'V') && version[1] >= '0' && version[1] <= '9' {
+		return version[1:]
+	}
+	return version
+}
+
+// siblingPath 返回与 relPath 同目录下的文件相对路径
+func siblingPath(relPath, name string) string {
+	return joinPath(path.Dir(relPath), name)
+}
+
+// joinPath 拼接目录与文件名，根目录 "." 时直接返回文件名
+func joinPath(dir, name string) string {
+	if dir == "." || dir == "" {
+		return name
+	}
+	return dir + "/" + name
+}
+
+// inVendorDir 判断相对路径是否位于第三方依赖目录中
+func inVendorDir(relPath string, dirs ...string) bool {
+	for _, part := range strings.Split(relPath, "/") {
+		for _, dir := range dirs {
+			if strings.EqualFold(part, dir) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// requiredNames 合并锁文件条目中各类依赖映射的包名称，去重后按名称排序
+func requiredNames(groups ...map[string]string) []string {
+	var names []string
+	for _, group := range groups {
+		for name := range group {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// disjunctiveLicense 将可选的多个许可证合并为 OR 关系的 SPDX 表达式
+func disjunctiveLicense(licenses []string) string {
+	switch len(licenses) {
+	case 0:
+		return ""
+	case 1:
+		return licenses[0]
+	}
+	return "(" + strings.Join(licenses, " OR ") + ")"
+}
@@ -0,0 +1,330 @@
+package depengine
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/winezer0/codecanvas/internal/archivefs"
+	"github.com/winezer0/codecanvas/internal/logging"
+	"github.com/winezer0/codecanvas/internal/model"
+	"github.com/winezer0/codecanvas/internal/utils"
+)
+
+// projectManifests 标识项目根目录的清单文件（文件名小写）及其所属生态
+var projectManifests = map[string]string{
+	"go.mod":              model.EcosystemGo,
+	"go.work":             model.EcosystemGo,
+	"package.json":        model.EcosystemNpm,
+	"pnpm-workspace.yaml": model.EcosystemNpm,
+	"pom.xml":             model.EcosystemMaven,
+	"build.gradle":        model.EcosystemGradle,
+	"build.gradle.kts":    model.EcosystemGradle,
+	"settings.gradle":     model.EcosystemGradle,
+	"settings.gradle.kts": model.EcosystemGradle,
+	"cargo.toml":          model.EcosystemCargo,
+	"composer.json":       model.EcosystemComposer,
+	"pyproject.toml":      model.EcosystemPyPI,
+}
+
+// projectVendorDirs 第三方依赖、测试数据与构建输出目录，其中的清单不视为子项目
+var projectVendorDirs = []string{
+	"node_modules", "bower_components", "vendor", "target", "build", "dist", "testdata",
+	".venv", "venv", "site-packages", "Pods", "third_party",
+}
+
+var (
+	// gradleRootNameRe 匹配 settings.gradle 中的 rootProject.name = 'name'
+	gradleRootNameRe = regexp.MustCompile(`rootProject\.name\s*=\s*["']([^"']+)["']`)
+	// gradleIncludeRe 匹配 settings.gradle 中的 include 语句
+	gradleIncludeRe = regexp.MustCompile(`(?m)^\s*include\b\s*\(?(.*)$`)
+	// gradleQuotedRe 匹配 include 语句中的项目路径
+	gradleQuotedRe = regexp.MustCompile(`["']([^"']+)["']`)
+)
+
+// projectManifest 从单个清单中提取的项目信息
+// - Name: 清单声明的项目名称
+// - Members: 清单声明的工作区成员，相对于清单所在目录，可包含 * 与 ** 通配符
+type projectManifest struct {
+	Name    string
+	Members []string
+}
+
+// DiscoverProjects 根据清单文件与工作区声明发现代码库中的子项目：
+// 嵌套的 go.mod/go.work、npm/Yarn/pnpm workspaces、Maven modules、Gradle settings、Cargo workspace，
+// 以及独立的 package.json、composer.json、pyproject.toml 所在目录均视为一个项目。
+// 结果按路径排序，Parent 为最近的上级项目
+func DiscoverProjects(index *model.FileIndex, read ContentReader) []model.Project {
+	if index == nil {
+		return nil
+	}
+
+	projects := make(map[string]*model.Project)
+	dirs := make(map[string]bool)
+	for _, relPath := range index.Files {
+		for dir := path.Dir(relPath); dir != "."; dir = path.Dir(dir) {
+			dirs[dir] = true
+		}
+		kind, ok := projectManifests[strings.ToLower(path.Base(relPath))]
+		if !ok || inVendorDir(relPath, projectVendorDirs...) || archivefs.IsMember(relPath) {
+			continue
+		}
+		dir := path.Dir(relPath)
+		project := projects[dir]
+		if project == nil {
+			project = &model.Project{Path: dir}
+			projects[dir] = project
+		}
+		project.Manifests = append(project.Manifests, relPath)
+		if !slices.Contains(project.Kinds, kind) {
+			project.Kinds = append(project.Kinds, kind)
+		}
+	}
+
+	// 读取清单中的项目名称与工作区成员，成员目录即使没有清单也作为项目
+	manifestDirs := sortedProjectPaths(projects)
+	for _, dir := range manifestDirs {
+		project := projects[dir]
+		for _, manifest := range project.Manifests {
+			content, err := read(manifest)
+			if err != nil {
+				logging.Debugf("read project manifest %s failed: %v", manifest, err)
+				continue
+			}
+			info := parseProjectManifest(manifest, content)
+			if project.Name == "" {
+				project.Name = info.Name
+			}
+			kind := projectManifests[strings.ToLower(path.Base(manifest))]
+			for _, member := range resolveMembers(dir, info.Members, projects, dirs) {
+				project.Workspace = true
+				if projects[member] == nil {
+					projects[member] = &model.Project{Path: member, Kinds: []string{kind}, Manifests: []string{}}
+				}
+			}
+		}
+	}
+
+	var results []model.Project
+	for _, dir := range sortedProjectPaths(projects) {
+		project := projects[dir]
+		if project.Name == "" {
+			project.Name = path.Base(dir)
+			if dir == "." {
+				project.Name = path.Base(strings.ReplaceAll(index.RootDir, "\\", "/"))
+			}
+		}
+		for parent := dir; parent != "."; {
+			parent = path.Dir(parent)
+			if _, ok := projects[parent]; ok {
+				project.Parent = parent
+				break
+			}
+		}
+		results = append(results, *project)
+	}
+	return results
+}
+
+// parseProjectManifest 根据文件名解析清单中的项目名称与工作区成员
+func parseProjectManifest(manifest string, content []byte) projectManifest {
+	var info projectManifest
+	switch strings.ToLower(path.Base(manifest)) {
+	case "go.mod":
+		info.Name = goModulePath(content)
+	case "go.work":
+		info.Members = goWorkUses(content)
+	case "package.json", "composer.json":
+		var pkg struct {
+			Name       string `json:"name"`
+			Workspaces any    `json:"workspaces"`
+		}
+		if err := json.Unmarshal(content, &pkg); err != nil {
+			logging.Debugf("parse project manifest %s failed: %v", manifest, err)
+			return info
+		}
+		info.Name = pkg.Name
+		// workspaces 可以是路径数组，也可以是 Yarn 的 {"packages": [...]} 形式
+		workspaces := pkg.Workspaces
+		if object, ok := workspaces.(map[string]any); ok {
+			workspaces = object["packages"]
+		}
+		info.Members = stringList(workspaces)
+	case "pnpm-workspace.yaml":
+		var workspace struct {
+			Packages []string `yaml:"packages"`
+		}
+		if err := yaml.Unmarshal(content, &workspace); err != nil {
+			logging.Debugf("parse project manifest %s failed: %v", manifest, err)
+			return info
+		}
+		info.Members = workspace.Packages
+	case "pom.xml":
+		root, err := utils.ParseXML(content)
+		if err != nil {
+			logging.Debugf("parse project manifest %s failed: %v", manifest, err)
+			return info
+		}
+		info.Name = root.ChildText("artifactId")
+		if modules := root.Child("modules"); modules != nil {
+			for _, module := range modules.Children("module") {
+				info.Members = append(info.Members, strings.TrimSpace(module.Text()))
+			}
+		}
+	case "settings.gradle", "settings.gradle.kts":
+		if match := gradleRootNameRe.FindSubmatch(content); match != nil {
+			info.Name = string(match[1])
+		}
+		for _, include := range gradleIncludeRe.FindAllSubmatch(content, -1) {
+			for _, quoted := range gradleQuotedRe.FindAllSubmatch(include[1], -1) {
+				// Gradle 项目路径 ":a:b" 对应目录 a/b
+				member := strings.ReplaceAll(strings.TrimPrefix(string(quoted[1]), ":"), ":", "/")
+				info.Members = append(info.Members, member)
+			}
+		}
+	case "cargo.toml", "pyproject.toml":
+		doc, err := utils.ParseTOML(content)
+		if err != nil {
+			logging.Debugf("parse project manifest %s failed: %v", manifest, err)
+			return info
+		}
+		for _, key := range []string{"package.name", "project.name", "tool.poetry.name"} {
+			if values, _ := utils.SelectPath(doc, key); len(values) > 0 {
+				info.Name = utils.ValueString(values[0])
+				break
+			}
+		}
+		if values, _ := utils.SelectPath(doc, "workspace.members"); len(values) > 0 {
+			info.Members = stringList(values[0])
+		}
+	}
+	return info
+}
+
+// goModulePath 返回 go.mod 中 module 指令声明的模块路径
+func goModulePath(content []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`)
+		}
+	}
+	return ""
+}
+
+// goWorkUses 返回 go.work 中 use 指令引用的模块目录
+func goWorkUses(content []byte) []string {
+	var (
+		uses  []string
+		block bool
+	)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+		case block && fields[0] == ")":
+			block = false
+		case block:
+			uses = append(uses, strings.Trim(fields[0], `"`))
+		case fields[0] == "use" && len(fields) >= 2:
+			if fields[1] == "(" {
+				block = true
+				continue
+			}
+			uses = append(uses, strings.Trim(fields[1], `"`))
+		}
+	}
+	return uses
+}
+
+// resolveMembers 将工作区成员声明解析为项目目录：通配符仅匹配已有清单的项目目录，
+// 字面路径匹配索引中存在的目录；以 "!" 开头的排除声明被忽略
+func resolveMembers(dir string, members []string, projects map[string]*model.Project, dirs map[string]bool) []string {
+	var results []string
+	for _, member := range members {
+		if member == "" || strings.HasPrefix(member, "!") {
+			continue
+		}
+		pattern := path.Clean(joinPath(dir, strings.TrimPrefix(member, "./")))
+		if pattern == dir || strings.HasPrefix(pattern, "../") {
+			continue
+		}
+		if !strings.ContainsAny(pattern, "*?[") {
+			if dirs[pattern] {
+				results = append(results, pattern)
+			}
+			continue
+		}
+		for _, candidate := range sortedProjectPaths(projects) {
+			if candidate != dir && matchPathGlob(pattern, candidate) {
+				results = append(results, candidate)
+			}
+		}
+	}
+	return results
+}
+
+// matchPathGlob 按路径段匹配通配符，"**" 匹配任意层级的目录
+func matchPathGlob(pattern, name string) bool {
+	return matchGlobSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+// matchGlobSegments 逐段匹配路径通配符
+func matchGlobSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchGlobSegments(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	if matched, _ := path.Match(pattern[0], name[0]); !matched {
+		return false
+	}
+	return matchGlobSegments(pattern[1:], name[1:])
+}
+
+// sortedProjectPaths 返回排序后的项目目录，根目录 "." 排在最前
+func sortedProjectPaths(projects map[string]*model.Project) []string {
+	paths := make([]string, 0, len(projects))
+	for dir := range projects {
+		paths = append(paths, dir)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// stringList 将 JSON/TOML 中的字符串数组转换为字符串切片
+func stringList(value any) []string {
+	items, ok := value.([]any)
+	if !ok {
+		return nil
+	}
+	var results []string
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			results = append(results, s)
+		}
+	}
+	return results
+}
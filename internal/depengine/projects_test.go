@@ -0,0 +1,78 @@
+package depengine
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestDiscoverProjects(t *testing.T) {
+	index, read := newTestIndex(map[string]string{
+		"go.work":                             "go 1.22\n\nuse (\n\t./tools\n\t./services/api // api\n)\n",
+		"tools/main.go":                       "package main\n",
+		"services/api/go.mod":                 "module example.com/api\n\ngo 1.22\n",
+		"web/package.json":                    `{"name": "web-root", "private": true, "workspaces": {"packages": ["packages/*"]}}`,
+		"web/packages/ui/package.json":        `{"name": "@acme/ui"}`,
+		"web/packages/app/package.json":       `{"name": "@acme/app"}`,
+		"web/node_modules/react/package.json": `{"name": "react"}`,
+		"java/pom.xml":                        "<project><artifactId>parent</artifactId><modules><module>core</module></modules></project>",
+		"java/core/pom.xml":                   "<project><artifactId>core</artifactId></project>",
+		"android/settings.gradle":             "rootProject.name = 'mobile'\ninclude ':app', ':lib:common'\n",
+		"android/app/build.gradle":            "",
+		"android/lib/common/Common.kt":        "",
+		"rust/Cargo.toml":                     "[workspace]\nmembers = [\"crates/*\"]\n",
+		"rust/crates/cli/Cargo.toml":          "[package]\nname = \"acme-cli\"\n",
+		"py/pyproject.toml":                   "[project]\nname = \"acme-py\"\n",
+		"testdata/go.mod":                     "module fixture\n",
+	})
+
+	projects := DiscoverProjects(index, read)
+	got := make(map[string]model.Project)
+	var paths []string
+	for _, project := range projects {
+		got[project.Path] = project
+		paths = append(paths, project.Path)
+	}
+
+	wantPaths := []string{".", "android", "android/app", "android/lib/common", "java", "java/core", "py",
+		"rust", "rust/crates/cli", "services/api", "tools", "web", "web/packages/app", "web/packages/ui"}
+	if !reflect.DeepEqual(paths, wantPaths) {
+		t.Fatalf("DiscoverProjects() paths = %v, want %v", paths, wantPaths)
+	}
+
+	tests := []struct {
+		path      string
+		name      string
+		kinds     []string
+		workspace bool
+		parent    string
+	}{
+		{".", "", []string{model.EcosystemGo}, true, ""},
+		{"tools", "tools", []string{model.EcosystemGo}, false, "."},
+		{"services/api", "example.com/api", []string{model.EcosystemGo}, false, "."},
+		{"web", "web-root", []string{model.EcosystemNpm}, true, "."},
+		{"web/packages/ui", "@acme/ui", []string{model.EcosystemNpm}, false, "web"},
+		{"java", "parent", []string{model.EcosystemMaven}, true, "."},
+		{"java/core", "core", []string{model.EcosystemMaven}, false, "java"},
+		{"android", "mobile", []string{model.EcosystemGradle}, true, "."},
+		{"android/lib/common", "common", []string{model.EcosystemGradle}, false, "android"},
+		{"rust/crates/cli", "acme-cli", []string{model.EcosystemCargo}, false, "rust"},
+		{"py", "acme-py", []string{model.EcosystemPyPI}, false, "."},
+	}
+	for _, tt := range tests {
+		project := got[tt.path]
+		if tt.name != "" && project.Name != tt.name {
+			t.Errorf("%s: name = %q, want %q", tt.path, project.Name, tt.name)
+		}
+		if !reflect.DeepEqual(project.Kinds, tt.kinds) {
+			t.Errorf("%s: kinds = %v, want %v", tt.path, project.Kinds, tt.kinds)
+		}
+		if project.Workspace != tt.workspace {
+			t.Errorf("%s: workspace = %v, want %v", tt.path, project.Workspace, tt.workspace)
+		}
+		if project.Parent != tt.parent {
+			t.Errorf("%s: parent = %q, want %q", tt.path, project.Parent, tt.parent)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package depengine
+
+import (
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// PubParser 解析 Dart/Flutter 项目的 pubspec.yaml 与 pubspec.lock
+type PubParser struct{}
+
+func init() {
+	Register(&PubParser{})
+}
+
+// Dart 与 Flutter SDK 约束的依赖名称
+const (
+	DartSDK    = "Dart SDK"
+	FlutterSDK = "Flutter SDK"
+)
+
+// pubspecFile pubspec.yaml 中与依赖相关的字段
+type pubspecFile struct {
+	Environment     map[string]string    `yaml:"environment"`
+	Dependencies    map[string]yaml.Node `yaml:"dependencies"`
+	DevDependencies map[string]yaml.Node `yaml:"dev_dependencies"`
+}
+
+// pubspecLock pubspec.lock 中的已解析包
+type pubspecLock struct {
+	Packages map[string]struct {
+		Dependency string `yaml:"dependency"`
+		Source     string `yaml:"source"`
+		Version    string `yaml:"version"`
+	} `yaml:"packages"`
+}
+
+// Ecosystem 返回 Pub 生态名称
+func (p *PubParser) Ecosystem() string {
+	return model.EcosystemPub
+}
+
+// Match 匹配 pubspec.yaml 与 pubspec.lock，跳过工具缓存与构建目录
+func (p *PubParser) Match(relPath string) bool {
+	switch path.Base(relPath) {
+	case "pubspec.yaml", "pubspec.lock":
+		return !inVendorDir(relPath, ".dart_tool", ".pub-cache", "build", "ephemeral")
+	}
+	return false
+}
+
+// Parse 根据文件名分派到对应的解析逻辑
+func (p *PubParser) Parse(file ManifestFile) ([]model.Dependency, error) {
+	if path.Base(file.Path) == "pubspec.lock" {
+		return p.parseLock(file)
+	}
+	return p.parseManifest(file)
+}
+
+// parseManifest 解析 pubspec.yaml：environment 中的 SDK 约束为 sdk 作用域，
+// "sdk: flutter" 形式的依赖为 framework 作用域，dev_dependencies 为开发依赖
+func (p *PubParser) parseManifest(file ManifestFile) ([]model.Dependency, error) {
+	var spec pubspecFile
+	if err := yaml.Unmarshal(file.Content, &spec); err != nil {
+		return nil, err
+	}
+
+	var deps []model.Dependency
+	for _, sdk := range []struct{ key, name string }{{"sdk", DartSDK}, {"flutter", FlutterSDK}} {
+		if constraint := spec.Environment[sdk.key]; constraint != "" {
+			deps = append(deps, p.newDependency(file.Path, sdk.name, constraint, model.ScopeSDK, false))
+		}
+	}
+	for _, group := range []struct {
+		deps  map[string]yaml.Node
+		scope string
+	}{{spec.Dependencies, model.ScopeRuntime}, {spec.DevDependencies, model.ScopeDev}} {
+		for _, name := range sortedKeys(group.deps) {
+			node := group.deps[name]
+			version, scope := pubConstraint(&node), group.scope
+			if pubSDKDependency(&node) {
+				// SDK 依赖的版本由 Flutter SDK 约束决定
+				version = spec.Environment["flutter"]
+				if scope == model.ScopeRuntime {
+					scope = model.ScopeFramework
+				}
+			}
+			deps = append(deps, p.newDependency(file.Path, name, version, scope, false))
+		}
+	}
+	return deps, nil
+}
+
+// parseLock 解析 pubspec.lock 中的包版本，dependency 字段标明直接依赖与开发依赖
+func (p *PubParser) parseLock(file ManifestFile) ([]model.Dependency, error) {
+	var lock pubspecLock
+	if err := yaml.Unmarshal(file.Content, &lock); err != nil {
+		return nil, err
+	}
+
+	var deps []model.Dependency
+	for _, name := range sortedKeys(lock.Packages) {
+		pkg := lock.Packages[name]
+		// SDK 包的版本号恒为 0.0.0，不代表实际版本
+		if pkg.Source == "sdk" {
+			continue
+		}
+		scope := model.ScopeRuntime
+		if pkg.Dependency == "direct dev" {
+			scope = model.ScopeDev
+		}
+		dep := p.newDependency(file.Path, name, pkg.Version, scope, true)
+		dep.Direct = strings.HasPrefix(pkg.Dependency, "direct")
+		deps = append(deps, dep)
+	}
+	return deps, nil
+}
+
+// pubConstraint 返回依赖声明中的版本约束，支持 "name: ^1.0.0" 与 "name: {version: ^1.0.0}"
+func pubConstraint(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Value
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == "version" {
+				return node.Content[i+1].Value
+			}
+		}
+	}
+	return ""
+}
+
+// pubSDKDependency 判断依赖是否由 SDK 提供，如 flutter: {sdk: flutter}
+func pubSDKDependency(node *yaml.Node) bool {
+	if node.Kind != yaml.MappingNode {
+		return false
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == "sdk" {
+			return true
+		}
+	}
+	return false
+}
+
+// newDependency 生成 Pub 生态的依赖记录
+func (p *PubParser) newDependency(manifest, name, version, scope string, locked bool) model.Dependency {
+	return model.Dependency{
+		Ecosystem: model.EcosystemPub,
+		Name:      name,
+		Version:   version,
+		Scope:     scope,
+		Direct:    true,
+		Locked:    locked,
+		Manifest:  manifest,
+	}
+}
@@ -0,0 +1,84 @@
+package depengine
+
+import (
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestPubspecAndLock(t *testing.T) {
+	index, read := newTestIndex(map[string]string{
+		"pubspec.yaml": `name: demo
+environment:
+  sdk: '>=3.0.0 <4.0.0'
+  flutter: ">=3.10.0"
+dependencies:
+  flutter:
+    sdk: flutter
+  http: ^1.1.0
+  provider:
+    version: ^6.0.0
+  local_pkg:
+    path: ../local_pkg
+dev_dependencies:
+  flutter_test:
+    sdk: flutter
+  flutter_lints: ^2.0.0
+`,
+		"pubspec.lock": `packages:
+  flutter:
+    dependency: "direct main"
+    description: flutter
+    source: sdk
+    version: "0.0.0"
+  http:
+    dependency: "direct main"
+    description:
+      name: http
+      url: "https://pub.dev"
+    source: hosted
+    version: "1.1.2"
+  flutter_lints:
+    dependency: "direct dev"
+    source: hosted
+    version: "2.0.3"
+  meta:
+    dependency: transitive
+    source: hosted
+    version: "1.9.1"
+sdks:
+  dart: ">=3.0.0 <4.0.0"
+`,
+		".dart_tool/package_config/pubspec.yaml": "dependencies:\n  bogus: ^1.0.0\n",
+	})
+
+	inventory := Collect(index, read, []Parser{&PubParser{}})
+
+	if inventory.Has("bogus") {
+		t.Errorf(".dart_tool should be skipped")
+	}
+	if sdk, _ := findDependency(inventory.Dependencies, DartSDK, "pubspec.yaml"); sdk.Version != ">=3.0.0 <4.0.0" || sdk.Scope != model.ScopeSDK {
+		t.Errorf("unexpected Dart SDK constraint: %+v", sdk)
+	}
+	if flutter, _ := findDependency(inventory.Dependencies, "flutter", "pubspec.yaml"); flutter.Scope != model.ScopeFramework || flutter.Version != ">=3.10.0" {
+		t.Errorf("flutter SDK dependency should be a framework dependency: %+v", flutter)
+	}
+	if test, _ := findDependency(inventory.Dependencies, "flutter_test", "pubspec.yaml"); test.Scope != model.ScopeDev {
+		t.Errorf("unexpected flutter_test scope: %+v", test)
+	}
+	if provider, _ := findDependency(inventory.Dependencies, "provider", "pubspec.yaml"); provider.Version != "^6.0.0" {
+		t.Errorf("mapping constraint should be parsed: %+v", provider)
+	}
+	if _, ok := findDependency(inventory.Dependencies, "flutter", "pubspec.lock"); ok {
+		t.Errorf("sdk packages in the lock file should be skipped")
+	}
+	if inventory.Version("http") != "1.1.2" {
+		t.Errorf("expected locked http version, got %q", inventory.Version("http"))
+	}
+	if lints, _ := findDependency(inventory.Dependencies, "flutter_lints", "pubspec.lock"); lints.Scope != model.ScopeDev || !lints.Direct {
+		t.Errorf("unexpected dev package: %+v", lints)
+	}
+	if meta, _ := findDependency(inventory.Dependencies, "meta", "pubspec.lock"); meta.Direct {
+		t.Errorf("transitive package should not be direct: %+v", meta)
+	}
+}
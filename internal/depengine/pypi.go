@@ -0,0 +1,391 @@
+package depengine
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+	"github.com/winezer0/codecanvas/internal/utils"
+)
+
+// PyPIParser 解析 Python 项目的 requirements*.txt、pyproject.toml、Pipfile、setup.py/setup.cfg
+// 以及 Pipfile.lock、poetry.lock、uv.lock 锁文件
+type PyPIParser struct{}
+
+func init() {
+	Register(&PyPIParser{})
+}
+
+var (
+	// pep508Re 匹配 PEP 508 依赖描述，如 requests[socks]>=2.31; python_version>"3.8"
+	pep508Re = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(?:\[[^\]]*\])?\s*\(?([^;@()]*)\)?`)
+	// pipEggRe 匹配 -e git+https://...#egg=name 中的包名
+	pipEggRe = regexp.MustCompile(`#egg=([A-Za-z0-9._-]+)`)
+	// pyNameNormalizeRe PEP 503 名称规范化时需要替换的连续分隔符
+	pyNameNormalizeRe = regexp.MustCompile(`[-_.]+`)
+	// setupRequiresRe 匹配 setup.py 中 install_requires/tests_require 列表
+	setupRequiresRe = regexp.MustCompile(`(install_requires|tests_require|setup_requires)\s*=\s*\[([^\]]*)\]`)
+	// setupQuotedRe 匹配列表中的字符串元素
+	setupQuotedRe = regexp.MustCompile(`["']([^"']+)["']`)
+)
+
+// pyDevGroups 视为开发依赖的可选依赖组与依赖组名称
+var pyDevGroups = map[string]bool{
+	"dev": true, "develop": true, "test": true, "tests": true, "testing": true,
+	"lint": true, "docs": true, "doc": true, "typing": true,
+}
+
+// Ecosystem 返回 PyPI 生态名称
+func (p *PyPIParser) Ecosystem() string {
+	return model.EcosystemPyPI
+}
+
+// Match 匹配 Python 清单与锁文件，虚拟环境与 site-packages 目录除外
+func (p *PyPIParser) Match(relPath string) bool {
+	if inVendorDir(relPath, "venv", ".venv", "site-packages", ".tox", "node_modules") {
+		return false
+	}
+	base := strings.ToLower(path.Base(relPath))
+	switch base {
+	case "pyproject.toml", "pipfile", "pipfile.lock", "poetry.lock", "uv.lock", "setup.py", "setup.cfg":
+		return true
+	}
+	// requirements.txt、requirements-dev.txt、dev-requirements.txt 以及 requirements/ 目录下的 *.txt
+	if strings.HasSuffix(base, ".txt") {
+		return strings.Contains(base, "requirements") || path.Base(path.Dir(relPath)) == "requirements"
+	}
+	return false
+}
+
+// Parse 根据文件名分派到对应的解析逻辑
+func (p *PyPIParser) Parse(file ManifestFile) ([]model.Dependency, error) {
+	switch strings.ToLower(path.Base(file.Path)) {
+	case "pyproject.toml":
+		return p.parsePyproject(file)
+	case "pipfile":
+		return p.parsePipfile(file)
+	case "pipfile.lock":
+		return p.parsePipfileLock(file)
+	case "poetry.lock", "uv.lock":
+		return p.parsePackageLock(file)
+	case "setup.py":
+		return p.parseSetupPy(file), nil
+	case "setup.cfg":
+		return p.parseSetupCfg(file), nil
+	}
+	return p.parseRequirements(file), nil
+}
+
+// parseRequirements 解析 requirements 文件，文件名包含 dev/test 时视为开发依赖
+func (p *PyPIParser) parseRequirements(file ManifestFile) []model.Dependency {
+	scope := model.ScopeRuntime
+	if lower := strings.ToLower(file.Path); strings.Contains(path.Base(lower), "dev") || strings.Contains(path.Base(lower), "test") {
+		scope = model.ScopeDev
+	}
+
+	var deps []model.Dependency
+	scanner := bufio.NewScanner(bytes.NewReader(file.Content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		line = strings.TrimSuffix(line, "\\")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "-") {
+			// 可编辑安装只有 #egg= 指定的包名，-r/-c/--index-url 等选项忽略
+			if m := pipEggRe.FindStringSubmatch(line); m != nil && strings.HasPrefix(line, "-e") {
+				deps = append(deps, p.newDependency(file.Path, m[1], "", scope))
+			}
+			continue
+		}
+		if dep, ok := p.parseRequirement(file.Path, line, scope); ok {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+// parsePyproject 解析 pyproject.toml 中 PEP 621、PEP 735 与 Poetry 的依赖声明
+func (p *PyPIParser) parsePyproject(file ManifestFile) ([]model.Dependency, error) {
+	doc, err := utils.ParseTOML(file.Content)
+	if err != nil {
+		return nil, err
+	}
+	var deps []model.Dependency
+
+	project, _ := doc["project"].(map[string]any)
+	if requires, _ := project["requires-python"].(string); requires != "" {
+		deps = append(deps, p.newDependency(file.Path, "python", requires, model.ScopeSDK))
+	}
+	deps = append(deps, p.parseRequirementList(file.Path, project["dependencies"], model.ScopeRuntime)...)
+	optional, _ := project["optional-dependencies"].(map[string]any)
+	for _, group := range sortedKeys(optional) {
+		deps = append(deps, p.parseRequirementList(file.Path, optional[group], pyGroupScope(group))...)
+	}
+	groups, _ := doc["dependency-groups"].(map[string]any)
+	for _, group := range sortedKeys(groups) {
+		deps = append(deps, p.parseRequirementList(file.Path, groups[group], pyGroupScope(group))...)
+	}
+
+	tool, _ := doc["tool"].(map[string]any)
+	poetry, _ := tool["poetry"].(map[string]any)
+	deps = append(deps, p.parseDependencyTable(file.Path, poetry["dependencies"], model.ScopeRuntime)...)
+	deps = append(deps, p.parseDependencyTable(file.Path, poetry["dev-dependencies"], model.ScopeDev)...)
+	poetryGroups, _ := poetry["group"].(map[string]any)
+	for _, group := range sortedKeys(poetryGroups) {
+		table, _ := poetryGroups[group].(map[string]any)
+		deps = append(deps, p.parseDependencyTable(file.Path, table["dependencies"], model.ScopeDev)...)
+	}
+	return deps, nil
+}
+
+// parsePipfile 解析 Pipfile 中的 [packages] 与 [dev-packages]，[requires] 中的 Python 版本为 sdk 作用域
+func (p *PyPIParser) parsePipfile(file ManifestFile) ([]model.Dependency, error) {
+	doc, err := utils.ParseTOML(file.Content)
+	if err != nil {
+		return nil, err
+	}
+	var deps []model.Dependency
+	requires, _ := doc["requires"].(map[string]any)
+	if version, _ := requires["python_version"].(string); version != "" {
+		deps = append(deps, p.newDependency(file.Path, "python", version, model.ScopeSDK))
+	}
+	deps = append(deps, p.parseDependencyTable(file.Path, doc["packages"], model.ScopeRuntime)...)
+	deps = append(deps, p.parseDependencyTable(file.Path, doc["dev-packages"], model.ScopeDev)...)
+	return deps, nil
+}
+
+// parsePipfileLock 解析 Pipfile.lock 中 default 与 develop 的锁定版本，同目录 Pipfile 中声明的为直接依赖
+func (p *PyPIParser) parsePipfileLock(file ManifestFile) ([]model.Dependency, error) {
+	var lock map[string]map[string]struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(file.Content, &lock); err != nil {
+		return nil, err
+	}
+	declared := p.declared(file, "Pipfile")
+
+	var deps []model.Dependency
+	for _, section := range []struct{ key, scope string }{{"default", model.ScopeRuntime}, {"develop", model.ScopeDev}} {
+		entries := lock[section.key]
+		for _, name := range sortedKeys(entries) {
+			deps = append(deps, p.lockDependency(file.Path, name, strings.TrimPrefix(entries[name].Version, "=="), section.scope, declared))
+		}
+	}
+	return deps, nil
+}
+
+// parsePackageLock 解析 poetry.lock 与 uv.lock 中的 [[package]]，同目录 pyproject.toml 中声明的为直接依赖
+func (p *PyPIParser) parsePackageLock(file ManifestFile) ([]model.Dependency, error) {
+	doc, err := utils.ParseTOML(file.Content)
+	if err != nil {
+		return nil, err
+	}
+	declared := p.declared(file, "pyproject.toml")
+
+	var deps []model.Dependency
+	packages, _ := doc["package"].([]any)
+	for _, entry := range packages {
+		pkg, _ := entry.(map[string]any)
+		name, _ := pkg["name"].(string)
+		version, _ := pkg["version"].(string)
+		// uv.lock 中项目自身以 editable/virtual 来源出现
+		if source, ok := pkg["source"].(map[string]any); ok && (source["editable"] != nil || source["virtual"] != nil) {
+			continue
+		}
+		scope := model.ScopeRuntime
+		if category, _ := pkg["category"].(string); category == "dev" {
+			scope = model.ScopeDev
+		}
+		dep := p.lockDependency(file.Path, name, version, scope, declared)
+		dep.Requires = p.lockRequires(pkg["dependencies"])
+		deps = append(deps, dep)
+	}
+	return deps, nil
+}
+
+// lockRequires 返回锁文件条目依赖的包名称：poetry.lock 为 [package.dependencies] 表，
+// uv.lock 为 dependencies = [{ name = "..." }] 数组
+func (p *PyPIParser) lockRequires(value any) []string {
+	required := make(map[string]string)
+	switch entries := value.(type) {
+	case map[string]any:
+		for name := range entries {
+			required[pyNameNormalizeRe.ReplaceAllString(strings.ToLower(name), "-")] = name
+		}
+	case []any:
+		for _, entry := range entries {
+			if table, ok := entry.(map[string]any); ok {
+				if name, _ := table["name"].(string); name != "" {
+					required[pyNameNormalizeRe.ReplaceAllString(strings.ToLower(name), "-")] = name
+				}
+			}
+		}
+	}
+	return requiredNames(required)
+}
+
+// parseSetupPy 解析 setup.py 中 install_requires 等参数的字面量列表
+func (p *PyPIParser) parseSetupPy(file ManifestFile) []model.Dependency {
+	var deps []model.Dependency
+	for _, m := range setupRequiresRe.FindAllStringSubmatch(string(file.Content), -1) {
+		scope := model.ScopeRuntime
+		switch m[1] {
+		case "tests_require":
+			scope = model.ScopeDev
+		case "setup_requires":
+			scope = model.ScopeBuild
+		}
+		for _, q := range setupQuotedRe.FindAllStringSubmatch(m[2], -1) {
+			if dep, ok := p.parseRequirement(file.Path, q[1], scope); ok {
+				deps = append(deps, dep)
+			}
+		}
+	}
+	return deps
+}
+
+// parseSetupCfg 解析 setup.cfg [options] 中多行的 install_requires
+func (p *PyPIParser) parseSetupCfg(file ManifestFile) []model.Dependency {
+	var (
+		deps    []model.Dependency
+		section string
+		inList  bool
+	)
+	scanner := bufio.NewScanner(bytes.NewReader(file.Content))
+	for scanner.Scan() {
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+		if strings.HasPrefix(line, "[") {
+			section, inList = strings.Trim(line, "[]"), false
+			continue
+		}
+		if section != "options" {
+			continue
+		}
+		// 列表项为缩进的续行
+		if inList && line != "" && (raw[0] == ' ' || raw[0] == '\t') {
+			if dep, ok := p.parseRequirement(file.Path, line, model.ScopeRuntime); ok {
+				deps = append(deps, dep)
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		inList = ok && strings.TrimSpace(key) == "install_requires"
+		if inList && strings.TrimSpace(value) != "" {
+			if dep, ok := p.parseRequirement(file.Path, strings.TrimSpace(value), model.ScopeRuntime); ok {
+				deps = append(deps, dep)
+			}
+		}
+	}
+	return deps
+}
+
+// parseRequirementList 解析 PEP 508 字符串数组
+func (p *PyPIParser) parseRequirementList(manifest string, value any, scope string) []model.Dependency {
+	var deps []model.Dependency
+	items, _ := value.([]any)
+	for _, item := range items {
+		if spec, ok := item.(string); ok {
+			if dep, ok := p.parseRequirement(manifest, spec, scope); ok {
+				deps = append(deps, dep)
+			}
+		}
+	}
+	return deps
+}
+
+// parseDependencyTable 解析 Poetry/Pipfile 的依赖表，值可以是版本字符串或包含 version 的内联表；
+// Poetry 中的 python 条目为 sdk 作用域
+func (p *PyPIParser) parseDependencyTable(manifest string, value any, scope string) []model.Dependency {
+	var deps []model.Dependency
+	table, _ := value.(map[string]any)
+	for _, name := range sortedKeys(table) {
+		var version string
+		switch v := table[name].(type) {
+		case string:
+			version = v
+		case map[string]any:
+			version, _ = v["version"].(string)
+		}
+		if version == "*" {
+			version = ""
+		}
+		depScope := scope
+		if strings.EqualFold(name, "python") {
+			depScope = model.ScopeSDK
+		}
+		deps = append(deps, p.newDependency(manifest, name, pyExactVersion(version), depScope))
+	}
+	return deps
+}
+
+// parseRequirement 解析单条 PEP 508 依赖描述
+func (p *PyPIParser) parseRequirement(manifest, spec, scope string) (model.Dependency, bool) {
+	m := pep508Re.FindStringSubmatch(strings.TrimSpace(spec))
+	if m == nil {
+		return model.Dependency{}, false
+	}
+	return p.newDependency(manifest, m[1], pyExactVersion(strings.TrimSpace(m[2])), scope), true
+}
+
+// declared 读取锁文件同目录的清单，返回规范化后的直接依赖名称集合
+func (p *PyPIParser) declared(file ManifestFile, manifest string) map[string]bool {
+	declared := make(map[string]bool)
+	if file.Read == nil {
+		return declared
+	}
+	manifestPath := siblingPath(file.Path, manifest)
+	content, err := file.Read(manifestPath)
+	if err != nil {
+		return declared
+	}
+	deps, _ := p.Parse(ManifestFile{Path: manifestPath, Content: content, Index: file.Index, Read: file.Read})
+	for _, dep := range deps {
+		declared[dep.Name] = true
+	}
+	return declared
+}
+
+// lockDependency 生成锁文件中的依赖记录
+func (p *PyPIParser) lockDependency(manifest, name, version, scope string, declared map[string]bool) model.Dependency {
+	dep := p.newDependency(manifest, name, version, scope)
+	dep.Direct = declared[dep.Name]
+	dep.Locked = true
+	return dep
+}
+
+// newDependency 生成 PyPI 生态的依赖记录，包名按 PEP 503 规范化为小写并以 "-" 连接
+func (p *PyPIParser) newDependency(manifest, name, version, scope string) model.Dependency {
+	return model.Dependency{
+		Ecosystem: model.EcosystemPyPI,
+		Name:      pyNameNormalizeRe.ReplaceAllString(strings.ToLower(name), "-"),
+		Version:   version,
+		Scope:     scope,
+		Direct:    true,
+		Manifest:  manifest,
+	}
+}
+
+// pyGroupScope 根据依赖组名称判断作用域
+func pyGroupScope(group string) string {
+	if pyDevGroups[strings.ToLower(group)] {
+		return model.ScopeDev
+	}
+	return model.ScopeRuntime
+}
+
+// pyExactVersion 将单一的 ==x.y.z 精确约束转换为版本号，其他约束保持原样
+func pyExactVersion(spec string) string {
+	if strings.HasPrefix(spec, "==") && !strings.ContainsAny(spec, ",*") {
+		return strings.TrimSpace(spec[2:])
+	}
+	return spec
+}
@@ -0,0 +1,131 @@
+package depengine
+
+import (
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestPyPIManifests(t *testing.T) {
+	index, read := newTestIndex(map[string]string{
+		"requirements.txt": `# web
+Django==4.2.7
+requests[socks]>=2.31,<3  # http
+-r requirements-dev.txt
+-e git+https://github.com/org/tool.git#egg=internal_tool
+python-dateutil ; python_version >= "3.8"
+`,
+		"requirements-dev.txt": "pytest==7.4.3\n",
+		"pyproject.toml": `[project]
+name = "demo"
+requires-python = ">=3.9"
+dependencies = [
+    "fastapi>=0.100",
+    "Pydantic_Core==2.14.1",
+]
+
+[project.optional-dependencies]
+test = ["pytest-cov"]
+`,
+		"svc/Pipfile": `[packages]
+flask = "==3.0.0"
+gunicorn = "*"
+
+[dev-packages]
+black = {version = ">=23.0"}
+
+[requires]
+python_version = "3.11"
+`,
+		"svc/Pipfile.lock": `{
+  "_meta": {"hash": {"sha256": "x"}},
+  "default": {
+    "flask": {"version": "==3.0.0"},
+    "werkzeug": {"version": "==3.0.1"}
+  },
+  "develop": {
+    "black": {"version": "==23.11.0"}
+  }
+}`,
+		"poetry/pyproject.toml": `[tool.poetry.dependencies]
+python = "^3.10"
+sanic = "^23.6"
+
+[tool.poetry.group.dev.dependencies]
+mypy = "^1.7"
+`,
+		"poetry/poetry.lock": `[[package]]
+name = "sanic"
+version = "23.6.0"
+
+[package.dependencies]
+httptools = ">=0.0.10"
+"Sanic_Routing" = ">=23.6.0"
+
+[[package]]
+name = "httptools"
+version = "0.6.1"
+`,
+		"setup.py": `setup(
+    name="demo",
+    install_requires=["tornado>=6.0", 'click'],
+    tests_require=["nose"],
+)`,
+		".venv/lib/site-packages/pkg/requirements.txt": "bogus==1.0\n",
+	})
+
+	inventory := Collect(index, read, []Parser{&PyPIParser{}})
+
+	if inventory.Has("bogus") {
+		t.Errorf("virtual environments should be skipped")
+	}
+	if django, _ := findDependency(inventory.Dependencies, "django", "requirements.txt"); django.Version != "4.2.7" {
+		t.Errorf("exact pin should become the version: %+v", django)
+	}
+	if req, _ := findDependency(inventory.Dependencies, "requests", "requirements.txt"); req.Version != ">=2.31,<3" {
+		t.Errorf("unexpected constraint: %+v", req)
+	}
+	if _, ok := findDependency(inventory.Dependencies, "internal-tool", "requirements.txt"); !ok {
+		t.Errorf("editable egg should be collected")
+	}
+	if _, ok := findDependency(inventory.Dependencies, "python-dateutil", "requirements.txt"); !ok {
+		t.Errorf("requirement with marker should be collected")
+	}
+	if pytest, _ := findDependency(inventory.Dependencies, "pytest", "requirements-dev.txt"); pytest.Scope != model.ScopeDev {
+		t.Errorf("dev requirements file should be dev scope: %+v", pytest)
+	}
+	if python, _ := findDependency(inventory.Dependencies, "python", "pyproject.toml"); python.Version != ">=3.9" || python.Scope != model.ScopeSDK {
+		t.Errorf("unexpected requires-python: %+v", python)
+	}
+	if core, _ := findDependency(inventory.Dependencies, "pydantic-core", "pyproject.toml"); core.Version != "2.14.1" {
+		t.Errorf("names should be normalized: %+v", core)
+	}
+	if cov, _ := findDependency(inventory.Dependencies, "pytest-cov", "pyproject.toml"); cov.Scope != model.ScopeDev {
+		t.Errorf("test extra should be dev scope: %+v", cov)
+	}
+	if black, _ := findDependency(inventory.Dependencies, "black", "svc/Pipfile"); black.Scope != model.ScopeDev || black.Version != ">=23.0" {
+		t.Errorf("unexpected dev package: %+v", black)
+	}
+	if werkzeug, _ := findDependency(inventory.Dependencies, "werkzeug", "svc/Pipfile.lock"); werkzeug.Version != "3.0.1" || werkzeug.Direct || !werkzeug.Locked {
+		t.Errorf("unexpected transitive lock entry: %+v", werkzeug)
+	}
+	if flask, _ := findDependency(inventory.Dependencies, "flask", "svc/Pipfile.lock"); !flask.Direct {
+		t.Errorf("Pipfile packages should be direct in the lock: %+v", flask)
+	}
+	if python, _ := findDependency(inventory.Dependencies, "python", "poetry/pyproject.toml"); python.Scope != model.ScopeSDK {
+		t.Errorf("poetry python should be sdk scope: %+v", python)
+	}
+	if mypy, _ := findDependency(inventory.Dependencies, "mypy", "poetry/pyproject.toml"); mypy.Scope != model.ScopeDev {
+		t.Errorf("poetry groups should be dev scope: %+v", mypy)
+	}
+	if sanic, _ := findDependency(inventory.Dependencies, "sanic", "poetry/poetry.lock"); sanic.Version != "23.6.0" || !sanic.Direct ||
+		len(sanic.Requires) != 2 || sanic.Requires[1] != "sanic-routing" {
+		t.Errorf("unexpected poetry lock entry: %+v", sanic)
+	}
+	if nose, _ := findDependency(inventory.Dependencies, "nose", "setup.py"); nose.Scope != model.ScopeDev {
+		t.Errorf("tests_require should be dev scope: %+v", nose)
+	}
+	if _, ok := findDependency(inventory.Dependencies, "click", "setup.py"); !ok {
+		t.Errorf("install_requires should be collected")
+	}
+}
@@ -0,0 +1,235 @@
+package depengine
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// RubyGemsParser 解析 Ruby Bundler 的 Gemfile 和 Gemfile.lock
+type RubyGemsParser struct{}
+
+func init() {
+	Register(&RubyGemsParser{})
+}
+
+var (
+	// gemLineRe 匹配 Gemfile 中的 gem 声明，如 gem 'rails', '~> 7.0'
+	gemLineRe = regexp.MustCompile(`^\s*gem\s*\(?\s*["']([^"']+)["'](.*)$`)
+	// gemQuotedRe 匹配 gem 声明中的引号字符串
+	gemQuotedRe = regexp.MustCompile(`^\s*,\s*["']([^"']*)["']`)
+	// gemGroupRe 匹配 group 块或 group:/groups: 选项中的分组名
+	gemGroupRe = regexp.MustCompile(`:([a-z_]+)`)
+	// gemGroupOptionRe 匹配 gem 声明中的 group:/groups: 选项
+	gemGroupOptionRe = regexp.MustCompile(`groups?:\s*(\[[^\]]*\]|:[a-z_]+)`)
+	// gemSpecRe 匹配 Gemfile.lock specs 中的 "name (version)"
+	gemSpecRe = regexp.MustCompile(`^([^\s(]+)\s+\(([^)]+)\)`)
+)
+
+// Ecosystem 返回 RubyGems 生态名称
+func (p *RubyGemsParser) Ecosystem() string {
+	return model.EcosystemRubyGems
+}
+
+// Match 匹配项目中的 Gemfile 与 Gemfile.lock（以及 gems.rb / gems.locked）
+func (p *RubyGemsParser) Match(relPath string) bool {
+	switch strings.ToLower(path.Base(relPath)) {
+	case "gemfile", "gemfile.lock", "gems.rb", "gems.locked":
+		return !inVendorDir(relPath, "vendor")
+	}
+	return false
+}
+
+// Parse 根据文件名分派到对应的解析逻辑
+func (p *RubyGemsParser) Parse(file ManifestFile) ([]model.Dependency, error) {
+	switch strings.ToLower(path.Base(file.Path)) {
+	case "gemfile.lock", "gems.locked":
+		return p.parseLock(file), nil
+	default:
+		return p.parseGemfile(file.Path, file.Content), nil
+	}
+}
+
+// parseGemfile 逐行解析 Gemfile 中的 gem 声明，development/test 分组视为开发依赖
+func (p *RubyGemsParser) parseGemfile(manifest string, content []byte) []model.Dependency {
+	var deps []model.Dependency
+	// 记录当前所在的 do...end 块是否为开发分组
+	var blocks []bool
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(stripRubyComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+
+		if line == "end" {
+			if len(blocks) > 0 {
+				blocks = blocks[:len(blocks)-1]
+			}
+			continue
+		}
+
+		if strings.HasSuffix(line, " do") || strings.Contains(line, " do |") {
+			isDev := strings.HasPrefix(line, "group") && isRubyDevGroups(line)
+			blocks = append(blocks, isDev)
+			continue
+		}
+
+		matches := gemLineRe.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+
+		// 解析紧跟名称之后的版本约束字符串
+		rest := matches[2]
+		var constraints []string
+		for {
+			m := gemQuotedRe.FindStringSubmatchIndex(rest)
+			if m == nil {
+				break
+			}
+			constraints = append(constraints, rest[m[2]:m[3]])
+			rest = rest[m[1]:]
+		}
+
+		scope := model.ScopeRuntime
+		if len(blocks) > 0 && blocks[len(blocks)-1] {
+			scope = model.ScopeDev
+		}
+		if option := gemGroupOptionRe.FindStringSubmatch(rest); option != nil {
+			scope = model.ScopeRuntime
+			if isRubyDevGroups(option[1]) {
+				scope = model.ScopeDev
+			}
+		}
+
+		deps = append(deps, model.Dependency{
+			Ecosystem: model.EcosystemRubyGems,
+			Name:      matches[1],
+			Version:   strings.Join(constraints, ", "),
+			Scope:     scope,
+			Direct:    true,
+			Manifest:  manifest,
+		})
+	}
+	return deps
+}
+
+// parseLock 解析 Gemfile.lock 中 GEM/GIT 的 specs 以及 DEPENDENCIES
+func (p *RubyGemsParser) parseLock(file ManifestFile) []model.Dependency {
+	var (
+		section   string
+		inSpecs   bool
+		current   = -1
+		specs     []model.Dependency
+		lockNames = make(map[string]bool)
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(file.Content))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		// 顶格的行为分节标题
+		if line[0] != ' ' {
+			section = strings.TrimSpace(line)
+			inSpecs, current = false, -1
+			continue
+		}
+
+		switch section {
+		case "GEM", "GIT":
+			if strings.TrimSpace(line) == "specs:" {
+				inSpecs = true
+				continue
+			}
+			// 4 个空格缩进为已解析的 gem，6 个空格缩进为其依赖约束
+			if inSpecs && strings.HasPrefix(line, "    ") && !strings.HasPrefix(line, "     ") {
+				current = -1
+				if m := gemSpecRe.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+					current = len(specs)
+					specs = append(specs, model.Dependency{
+						Ecosystem: model.EcosystemRubyGems,
+						Name:      m[1],
+						Version:   m[2],
+						Scope:     model.ScopeRuntime,
+						Locked:    true,
+						Manifest:  file.Path,
+					})
+				}
+			} else if inSpecs && current >= 0 && strings.HasPrefix(line, "      ") {
+				name := strings.Fields(line)[0]
+				if !slices.Contains(specs[current].Requires, name) {
+					specs[current].Requires = append(specs[current].Requires, name)
+				}
+			}
+		case "DEPENDENCIES":
+			name := strings.Fields(strings.TrimSpace(line))[0]
+			lockNames[strings.TrimSuffix(name, "!")] = true
+		}
+	}
+
+	// 优先使用 Gemfile 中的声明判断直接依赖与开发分组
+	declared := make(map[string]model.Dependency)
+	if file.Read != nil {
+		for _, name := range []string{"Gemfile", "gems.rb"} {
+			gemfilePath := siblingPath(file.Path, name)
+			if content, err := file.Read(gemfilePath); err == nil {
+				for _, dep := range p.parseGemfile(gemfilePath, content) {
+					declared[dep.Name] = dep
+				}
+				break
+			}
+		}
+	}
+
+	for i := range specs {
+		if dep, ok := declared[specs[i].Name]; ok {
+			specs[i].Direct = true
+			specs[i].Scope = dep.Scope
+		} else {
+			specs[i].Direct = lockNames[specs[i].Name]
+		}
+	}
+	return specs
+}
+
+// isRubyDevGroups 判断分组声明是否只包含 development/test 分组
+func isRubyDevGroups(text string) bool {
+	groups := gemGroupRe.FindAllStringSubmatch(text, -1)
+	if len(groups) == 0 {
+		return false
+	}
+	for _, g := range groups {
+		if g[1] != "development" && g[1] != "test" {
+			return false
+		}
+	}
+	return true
+}
+
+// stripRubyComment 去除行尾的 # 注释（忽略引号内的 #）
+func stripRubyComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
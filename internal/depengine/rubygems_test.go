@@ -0,0 +1,87 @@
+package depengine
+
+import (
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestRubyGemsGemfileAndLock(t *testing.T) {
+	index, read := newTestIndex(map[string]string{
+		"Gemfile": `source "https://rubygems.org"
+
+gem "rails", "~> 7.1.2"
+gem 'puma', '>= 5.0' # web server
+gem "rspec-rails", group: [:development, :test]
+
+group :development, :test do
+  gem "debug", platforms: %i[ mri windows ]
+end
+
+group :production do
+  gem "pg"
+end
+`,
+		"Gemfile.lock": `GEM
+  remote: https://rubygems.org/
+  specs:
+    actionpack (7.1.2)
+      rack (>= 2.2.4)
+    debug (1.9.1)
+    pg (1.5.4)
+    puma (6.4.0)
+      nio4r (~> 2.0)
+    rack (3.0.8)
+    rails (7.1.2)
+      actionpack (= 7.1.2)
+    rspec-rails (6.1.0)
+
+PLATFORMS
+  x86_64-linux
+
+DEPENDENCIES
+  debug
+  pg
+  puma (>= 5.0)
+  rails (~> 7.1.2)
+  rspec-rails
+
+BUNDLED WITH
+   2.4.22
+`,
+	})
+
+	inventory := Collect(index, read, []Parser{&RubyGemsParser{}})
+
+	rails, ok := findDependency(inventory.Dependencies, "rails", "Gemfile")
+	if !ok || rails.Version != "~> 7.1.2" || rails.Scope != model.ScopeRuntime {
+		t.Errorf("unexpected rails declaration: %+v", rails)
+	}
+
+	for _, name := range []string{"rspec-rails", "debug"} {
+		dep, ok := findDependency(inventory.Dependencies, name, "Gemfile")
+		if !ok || dep.Scope != model.ScopeDev {
+			t.Errorf("%s should be a dev dependency: %+v", name, dep)
+		}
+	}
+
+	pg, ok := findDependency(inventory.Dependencies, "pg", "Gemfile")
+	if !ok || pg.Scope != model.ScopeRuntime {
+		t.Errorf("pg should be a runtime dependency: %+v", pg)
+	}
+
+	lockedRails, ok := findDependency(inventory.Dependencies, "rails", "Gemfile.lock")
+	if !ok || lockedRails.Version != "7.1.2" || !lockedRails.Direct || !lockedRails.Locked || len(lockedRails.Requires) != 1 || lockedRails.Requires[0] != "actionpack" {
+		t.Errorf("unexpected locked rails: %+v", lockedRails)
+	}
+
+	rack, ok := findDependency(inventory.Dependencies, "rack", "Gemfile.lock")
+	if !ok || rack.Direct || rack.Version != "3.0.8" {
+		t.Errorf("rack should be a transitive dependency: %+v", rack)
+	}
+
+	// 依赖约束行（6 个空格缩进）不应作为已解析的 gem
+	if inventory.Has("nio4r") {
+		t.Errorf("nested requirement lines should be skipped")
+	}
+}
@@ -0,0 +1,203 @@
+package depengine
+
+import (
+	"encoding/json"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// SwiftPMParser 解析 Swift Package Manager 的 Package.swift 与 Package.resolved
+type SwiftPMParser struct{}
+
+func init() {
+	Register(&SwiftPMParser{})
+}
+
+var (
+	// swiftPackageCallRe 匹配 .package( 调用的起始位置
+	swiftPackageCallRe = regexp.MustCompile(`\.package\s*\(`)
+	// swiftURLRe 匹配 .package 调用中的 url: "..."
+	swiftURLRe = regexp.MustCompile(`url\s*:\s*"([^"]+)"`)
+	// swiftRangeRe 匹配 "1.0.0"..<"2.0.0" 与 "1.0.0"..."1.5.0" 版本区间
+	swiftRangeRe = regexp.MustCompile(`"([^"]+)"\s*\.\.([.<])\s*"([^"]+)"`)
+	// swiftIOSPlatformRe 匹配 platforms 中的 .iOS(.v15) / .iOS(.v13_4) / .iOS("15.0")
+	swiftIOSPlatformRe = regexp.MustCompile(`\.iOS\s*\(\s*(?:\.v(\d+(?:_\d+)*)|"([^"]+)")\s*\)`)
+	// swiftRequirementRes 按优先级匹配 .package 调用中的版本要求与对应的约束前缀
+	swiftRequirementRes = []struct {
+		re     *regexp.Regexp
+		prefix string
+	}{
+		{regexp.MustCompile(`upToNextMinor\s*\(\s*from\s*:\s*"([^"]+)"`), "~"},
+		{regexp.MustCompile(`\bfrom\s*:\s*"([^"]+)"`), "^"},
+		{regexp.MustCompile(`exact\s*:\s*"([^"]+)"`), ""},
+	}
+)
+
+// Ecosystem 返回 SwiftPM 生态名称
+func (p *SwiftPMParser) Ecosystem() string {
+	return model.EcosystemSwiftPM
+}
+
+// Match 匹配 Package.swift 与 Package.resolved，跳过构建与第三方检出目录
+func (p *SwiftPMParser) Match(relPath string) bool {
+	switch path.Base(relPath) {
+	case "Package.swift", "Package.resolved":
+		return !inVendorDir(relPath, ".build", "Pods", "Carthage", "node_modules")
+	}
+	return false
+}
+
+// Parse 根据文件名分派到对应的解析逻辑
+func (p *SwiftPMParser) Parse(file ManifestFile) ([]model.Dependency, error) {
+	if path.Base(file.Path) == "Package.resolved" {
+		return p.parseResolved(file)
+	}
+	return p.parseManifest(file.Path, file.Content), nil
+}
+
+// parseManifest 解析 Package.swift 中的远程包依赖与 iOS 最低平台版本，本地 path 依赖不计入
+func (p *SwiftPMParser) parseManifest(manifest string, content []byte) []model.Dependency {
+	text := string(content)
+	var deps []model.Dependency
+
+	if m := swiftIOSPlatformRe.FindStringSubmatch(text); m != nil {
+		version := m[2]
+		if version == "" {
+			version = strings.ReplaceAll(m[1], "_", ".")
+			if !strings.Contains(version, ".") {
+				version += ".0"
+			}
+		}
+		deps = append(deps, model.Dependency{
+			Ecosystem: model.EcosystemSwiftPM,
+			Name:      IOSDeploymentTarget,
+			Version:   version,
+			Scope:     model.ScopeSDK,
+			Direct:    true,
+			Manifest:  manifest,
+		})
+	}
+
+	for _, loc := range swiftPackageCallRe.FindAllStringIndex(text, -1) {
+		args := balancedArgs(text[loc[1]:])
+		m := swiftURLRe.FindStringSubmatch(args)
+		if m == nil {
+			continue
+		}
+		deps = append(deps, model.Dependency{
+			Ecosystem: model.EcosystemSwiftPM,
+			Name:      swiftPackageName(m[1]),
+			Version:   swiftRequirement(args),
+			Scope:     model.ScopeRuntime,
+			Direct:    true,
+			Manifest:  manifest,
+		})
+	}
+	return deps
+}
+
+// swiftResolved Package.resolved 的结构，v1 的 pins 位于 object 下，v2/v3 位于顶层
+type swiftResolved struct {
+	Object struct {
+		Pins []swiftPin `json:"pins"`
+	} `json:"object"`
+	Pins []swiftPin `json:"pins"`
+}
+
+// swiftPin Package.resolved 中的单个已解析包
+type swiftPin struct {
+	RepositoryURL string `json:"repositoryURL"`
+	Location      string `json:"location"`
+	State         struct {
+		Version string `json:"version"`
+	} `json:"state"`
+}
+
+// parseResolved 解析 Package.resolved 中固定的包版本，同目录 Package.swift 中声明的为直接依赖
+func (p *SwiftPMParser) parseResolved(file ManifestFile) ([]model.Dependency, error) {
+	var resolved swiftResolved
+	if err := json.Unmarshal(file.Content, &resolved); err != nil {
+		return nil, err
+	}
+
+	direct := make(map[string]bool)
+	if file.Read != nil {
+		manifestPath := siblingPath(file.Path, "Package.swift")
+		if content, err := file.Read(manifestPath); err == nil {
+			for _, dep := range p.parseManifest(manifestPath, content) {
+				direct[strings.ToLower(dep.Name)] = true
+			}
+		}
+	}
+
+	var deps []model.Dependency
+	for _, pin := range append(resolved.Object.Pins, resolved.Pins...) {
+		url := pin.Location
+		if url == "" {
+			url = pin.RepositoryURL
+		}
+		name := swiftPackageName(url)
+		if name == "" {
+			continue
+		}
+		deps = append(deps, model.Dependency{
+			Ecosystem: model.EcosystemSwiftPM,
+			Name:      name,
+			Version:   pin.State.Version,
+			Scope:     model.ScopeRuntime,
+			Direct:    direct[strings.ToLower(name)],
+			Locked:    true,
+			Manifest:  file.Path,
+		})
+	}
+	return deps, nil
+}
+
+// swiftRequirement 将 .package 调用中的版本要求转换为约束表达式，分支与修订版本不含版本号
+func swiftRequirement(args string) string {
+	for _, req := range swiftRequirementRes {
+		if m := req.re.FindStringSubmatch(args); m != nil {
+			return req.prefix + m[1]
+		}
+	}
+	if m := swiftRangeRe.FindStringSubmatch(args); m != nil {
+		if m[2] == "<" {
+			return ">=" + m[1] + ", <" + m[3]
+		}
+		return ">=" + m[1] + ", <=" + m[3]
+	}
+	return ""
+}
+
+// swiftPackageName 从仓库地址中取包名，如 https://github.com/Alamofire/Alamofire.git -> Alamofire
+func swiftPackageName(url string) string {
+	url = strings.TrimSuffix(strings.TrimRight(url, "/"), ".git")
+	if i := strings.LastIndexAny(url, "/:"); i >= 0 {
+		url = url[i+1:]
+	}
+	return url
+}
+
+// balancedArgs 返回左括号之后到与之匹配的右括号之前的参数文本
+func balancedArgs(text string) string {
+	depth := 1
+	inString := false
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case c == '"' && (i == 0 || text[i-1] != '\\'):
+			inString = !inString
+		case inString:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return text[:i]
+			}
+		}
+	}
+	return text
+}
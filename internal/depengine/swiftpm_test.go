@@ -0,0 +1,69 @@
+package depengine
+
+import (
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestSwiftPMManifestAndResolved(t *testing.T) {
+	index, read := newTestIndex(map[string]string{
+		"Package.swift": `// swift-tools-version:5.9
+import PackageDescription
+
+let package = Package(
+    name: "Demo",
+    platforms: [.iOS(.v15), .macOS(.v12)],
+    dependencies: [
+        .package(url: "https://github.com/Alamofire/Alamofire.git", .upToNextMajor(from: "5.8.0")),
+        .package(url: "https://github.com/apple/swift-log", exact: "1.5.3"),
+        .package(url: "https://github.com/pointfreeco/swift-snapshot-testing", "1.10.0"..<"2.0.0"),
+        .package(url: "https://github.com/apple/swift-collections", .upToNextMinor(from: "1.0.4")),
+        .package(path: "../LocalKit"),
+    ]
+)`,
+		"Package.resolved": `{
+  "pins": [
+    {"identity": "alamofire", "kind": "remoteSourceControl", "location": "https://github.com/Alamofire/Alamofire.git", "state": {"revision": "abc", "version": "5.8.1"}},
+    {"identity": "swift-atomics", "kind": "remoteSourceControl", "location": "https://github.com/apple/swift-atomics.git", "state": {"revision": "def", "version": "1.2.0"}}
+  ],
+  "version": 2
+}`,
+		"App.xcodeproj/project.xcworkspace/xcshareddata/swiftpm/Package.resolved": `{
+  "object": {"pins": [{"package": "Kingfisher", "repositoryURL": "https://github.com/onevcat/Kingfisher.git", "state": {"version": "7.10.0"}}]},
+  "version": 1
+}`,
+		".build/checkouts/swift-log/Package.swift": `.package(url: "https://github.com/bogus/bogus.git", from: "1.0.0")`,
+	})
+
+	inventory := Collect(index, read, []Parser{&SwiftPMParser{}})
+
+	if inventory.Has("bogus") {
+		t.Errorf("checkouts should be skipped")
+	}
+	if target, _ := findDependency(inventory.Dependencies, IOSDeploymentTarget, "Package.swift"); target.Version != "15.0" || target.Scope != model.ScopeSDK {
+		t.Errorf("unexpected iOS platform: %+v", target)
+	}
+	for name, want := range map[string]string{
+		"Alamofire":              "^5.8.0",
+		"swift-log":              "1.5.3",
+		"swift-snapshot-testing": ">=1.10.0, <2.0.0",
+		"swift-collections":      "~1.0.4",
+	} {
+		if dep, _ := findDependency(inventory.Dependencies, name, "Package.swift"); dep.Version != want {
+			t.Errorf("%s: expected %q, got %+v", name, want, dep)
+		}
+	}
+	if inventory.Has("LocalKit") {
+		t.Errorf("local path packages should be skipped")
+	}
+	if af, _ := findDependency(inventory.Dependencies, "Alamofire", "Package.resolved"); af.Version != "5.8.1" || !af.Locked || !af.Direct {
+		t.Errorf("unexpected resolved pin: %+v", af)
+	}
+	if atomics, _ := findDependency(inventory.Dependencies, "swift-atomics", "Package.resolved"); atomics.Direct {
+		t.Errorf("transitive pin should not be direct: %+v", atomics)
+	}
+	if kf, _ := findDependency(inventory.Dependencies, "Kingfisher", "App.xcodeproj/project.xcworkspace/xcshareddata/swiftpm/Package.resolved"); kf.Version != "7.10.0" {
+		t.Errorf("v1 pins should be parsed: %+v", kf)
+	}
+}
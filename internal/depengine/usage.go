@@ -0,0 +1,552 @@
+package depengine
+
+import (
+	"encoding/json"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// importEcosystems 源码语言到依赖生态的映射
+var importEcosystems = map[string]string{
+	"Go":         model.EcosystemGo,
+	"Java":       model.EcosystemMaven,
+	"Python":     model.EcosystemPyPI,
+	"PHP":        model.EcosystemComposer,
+	"JavaScript": model.EcosystemNpm,
+	"Node.js":    model.EcosystemNpm,
+	"TypeScript": model.EcosystemNpm,
+	"JSX":        model.EcosystemNpm,
+	"TSX":        model.EcosystemNpm,
+	"Vue":        model.EcosystemNpm,
+}
+
+// importResolver 将源码中导入的模块解析为依赖
+type importResolver interface {
+	// resolve 返回导入模块对应的已声明依赖名称；未声明时返回用于报告的依赖名称，
+	// 标准库、内置模块与项目自身的模块两者均返回空
+	resolve(module string) (declared []string, undeclared string)
+}
+
+// AnalyzeUsage 根据文件索引中的导入信息判断依赖的使用情况：
+// 直接的运行时与开发依赖标记为 declared-and-used 或 declared-only，并记录导入文件数量；
+// 被导入但未声明的依赖以 used-undeclared 记录到 Undeclared 中。
+// 只有项目中存在对应语言的源码文件时才判断该生态的依赖
+func AnalyzeUsage(inv *Inventory, index *model.FileIndex, read ContentReader) {
+	if inv == nil || index == nil || index.Imports == nil {
+		return
+	}
+
+	declared := make(map[string]map[string]bool)
+	for _, dep := range inv.Dependencies {
+		if !usageEligible(dep) {
+			continue
+		}
+		if declared[dep.Ecosystem] == nil {
+			declared[dep.Ecosystem] = make(map[string]bool)
+		}
+		declared[dep.Ecosystem][strings.ToLower(dep.Name)] = true
+	}
+
+	resolvers := make(map[string]importResolver)
+	scanned := make(map[string]bool)
+	used := make(map[string]map[string]bool)
+	undeclared := make(map[string]map[string]bool)
+
+	relPaths := make([]string, 0, len(index.Imports))
+	for relPath := range index.Imports {
+		relPaths = append(relPaths, relPath)
+	}
+	sort.Strings(relPaths)
+
+	for _, relPath := range relPaths {
+		imports := index.Imports[relPath]
+		ecosystem := importEcosystems[imports.Language]
+		if ecosystem == "" {
+			continue
+		}
+		scanned[ecosystem] = true
+		resolver, ok := resolvers[ecosystem]
+		if !ok {
+			resolver = newImportResolver(ecosystem, declared[ecosystem], index, read)
+			resolvers[ecosystem] = resolver
+		}
+		for _, module := range imports.Modules {
+			names, missing := resolver.resolve(module)
+			for _, name := range names {
+				key := importerKey(ecosystem, name)
+				if used[key] == nil {
+					used[key] = make(map[string]bool)
+				}
+				used[key][relPath] = true
+			}
+			if missing != "" {
+				key := ecosystem + "\x00" + missing
+				if undeclared[key] == nil {
+					undeclared[key] = make(map[string]bool)
+				}
+				undeclared[key][relPath] = true
+			}
+		}
+	}
+
+	for key, files := range used {
+		inv.importers[key] = files
+	}
+	for i := range inv.Dependencies {
+		dep := &inv.Dependencies[i]
+		if !scanned[dep.Ecosystem] || !usageEligible(*dep) {
+			continue
+		}
+		dep.Importers = len(used[importerKey(dep.Ecosystem, dep.Name)])
+		dep.Usage = model.UsageDeclaredOnly
+		if dep.Importers > 0 {
+			dep.Usage = model.UsageDeclaredAndUsed
+		}
+	}
+
+	keys := make([]string, 0, len(undeclared))
+	for key := range undeclared {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		ecosystem, name, _ := strings.Cut(key, "\x00")
+		inv.Undeclared = append(inv.Undeclared, model.Dependency{
+			Ecosystem: ecosystem,
+			Name:      name,
+			Scope:     model.ScopeRuntime,
+			Usage:     model.UsageUsedUndeclared,
+			Importers: len(undeclared[key]),
+		})
+		if inv.importers[importerKey(ecosystem, name)] == nil {
+			inv.importers[importerKey(ecosystem, name)] = undeclared[key]
+		}
+	}
+}
+
+// importerKey 返回导入记录的键：生态与小写的依赖名称，不同生态中的同名依赖分别记录
+func importerKey(ecosystem, name string) string {
+	return ecosystem + "\x00" + strings.ToLower(name)
+}
+
+// usageEligible 判断依赖是否参与使用情况判断：直接声明的运行时或开发依赖
+func usageEligible(dep model.Dependency) bool {
+	return dep.Direct && (dep.Scope == model.ScopeRuntime || dep.Scope == model.ScopeDev)
+}
+
+// newImportResolver 创建生态对应的导入解析器
+func newImportResolver(ecosystem string, declared map[string]bool, index *model.FileIndex, read ContentReader) importResolver {
+	switch ecosystem {
+	case model.EcosystemGo:
+		return newGoResolver(declared, index, read)
+	case model.EcosystemMaven:
+		return newJavaResolver(declared, index)
+	case model.EcosystemPyPI:
+		return newPythonResolver(declared, index)
+	case model.EcosystemComposer:
+		return newPHPResolver(declared, index, read)
+	default:
+		return newJSResolver(declared, index, read)
+	}
+}
+
+// readIndexFiles 读取索引中指定文件名（不区分大小写）的所有文件，跳过第三方依赖目录
+func readIndexFiles(index *model.FileIndex, read ContentReader, name string, vendorDirs ...string) map[string][]byte {
+	files := make(map[string][]byte)
+	if read == nil {
+		return files
+	}
+	for _, idx := range index.NameMap[strings.ToLower(name)] {
+		relPath := index.Files[idx]
+		if inVendorDir(relPath, vendorDirs...) {
+			continue
+		}
+		if content, err := read(relPath); err == nil {
+			files[relPath] = content
+		}
+	}
+	return files
+}
+
+// goResolver 按模块路径前缀匹配 go.mod 中声明的模块
+type goResolver struct {
+	declared []string
+	own      []string
+}
+
+func newGoResolver(declared map[string]bool, index *model.FileIndex, read ContentReader) *goResolver {
+	r := &goResolver{}
+	for name := range declared {
+		r.declared = append(r.declared, name)
+	}
+	for _, content := range readIndexFiles(index, read, "go.mod", "vendor") {
+		for _, line := range strings.Split(string(content), "\n") {
+			if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "module" {
+				r.own = append(r.own, strings.ToLower(strings.Trim(fields[1], `"`)))
+			}
+		}
+	}
+	return r
+}
+
+func (r *goResolver) resolve(module string) ([]string, string) {
+	lower := strings.ToLower(module)
+	// 标准库的首段路径不包含 "."
+	if !strings.Contains(strings.SplitN(lower, "/", 2)[0], ".") {
+		return nil, ""
+	}
+	for _, own := range r.own {
+		if hasPathPrefix(lower, own, "/") {
+			return nil, ""
+		}
+	}
+	// 选择最长的匹配模块，避免 a/b 与 a/b/v2 混淆
+	var best string
+	for _, name := range r.declared {
+		if hasPathPrefix(lower, name, "/") && len(name) > len(best) {
+			best = name
+		}
+	}
+	if best != "" {
+		return []string{best}, ""
+	}
+	parts := strings.Split(module, "/")
+	depth := 3
+	if parts[0] == "gopkg.in" {
+		depth = 2
+	}
+	if len(parts) > depth {
+		parts = parts[:depth]
+	}
+	return nil, strings.Join(parts, "/")
+}
+
+// javaBuiltinPrefixes JDK 自带的包前缀
+var javaBuiltinPrefixes = []string{"java.", "javax.", "jdk.", "sun.", "com.sun.", "org.w3c.", "org.xml.", "org.ietf.", "org.omg."}
+
+// javaResolver 按 groupId 包前缀匹配 Maven/Gradle 依赖，同一 groupId 下的所有依赖均视为被导入
+type javaResolver struct {
+	// prefixes 包前缀到依赖名称（groupId:artifactId）的映射
+	prefixes map[string][]string
+	// packages 项目源码所在的目录（以 "/" 分隔的包路径后缀）
+	packages map[string]bool
+}
+
+func newJavaResolver(declared map[string]bool, index *model.FileIndex) *javaResolver {
+	r := &javaResolver{prefixes: make(map[string][]string), packages: make(map[string]bool)}
+	for name := range declared {
+		groupID, artifactID, ok := strings.Cut(name, ":")
+		if !ok {
+			continue
+		}
+		candidates := []string{groupID}
+		// 如 com.fasterxml.jackson.core:jackson-databind 的包为 com.fasterxml.jackson.databind
+		if i := strings.LastIndex(groupID, "."); i > 0 && strings.Count(groupID, ".") >= 2 {
+			tokens := strings.Split(artifactID, "-")
+			candidates = append(candidates, groupID[:i]+"."+tokens[len(tokens)-1])
+		}
+		for _, prefix := range candidates {
+			r.prefixes[prefix] = append(r.prefixes[prefix], name)
+		}
+	}
+	for _, names := range r.prefixes {
+		sort.Strings(names)
+	}
+	for _, idx := range index.ExtensionMap[".java"] {
+		parts := strings.Split(path.Dir(index.Files[idx]), "/")
+		for i := range parts {
+			r.packages[strings.ToLower(strings.Join(parts[i:], "/"))] = true
+		}
+	}
+	return r
+}
+
+func (r *javaResolver) resolve(module string) ([]string, string) {
+	lower := strings.ToLower(module)
+	var best string
+	for prefix := range r.prefixes {
+		if hasPathPrefix(lower, prefix, ".") && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best != "" {
+		return r.prefixes[best], ""
+	}
+	for _, prefix := range javaBuiltinPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return nil, ""
+		}
+	}
+	// 导入的类或其所在包位于项目源码中时为项目自身的代码
+	parts := strings.Split(lower, ".")
+	for i := len(parts); i >= 2; i-- {
+		if r.packages[strings.Join(parts[:i], "/")] {
+			return nil, ""
+		}
+	}
+	// 以首个大写开头的类名之前的包路径（最多三段）作为依赖名称
+	var pkg []string
+	for _, part := range strings.Split(module, ".") {
+		if part == "" || len(pkg) == 3 || (part[0] >= 'A' && part[0] <= 'Z') {
+			break
+		}
+		pkg = append(pkg, part)
+	}
+	if len(pkg) < 2 {
+		return nil, ""
+	}
+	return nil, strings.Join(pkg, ".")
+}
+
+// pythonImportAliases 导入名称与 PyPI 包名不一致的常见包
+var pythonImportAliases = map[string]string{
+	"yaml":      "pyyaml",
+	"pil":       "pillow",
+	"bs4":       "beautifulsoup4",
+	"sklearn":   "scikit-learn",
+	"cv2":       "opencv-python",
+	"dateutil":  "python-dateutil",
+	"jwt":       "pyjwt",
+	"dotenv":    "python-dotenv",
+	"attr":      "attrs",
+	"serial":    "pyserial",
+	"crypto":    "pycryptodome",
+	"magic":     "python-magic",
+	"mysqldb":   "mysqlclient",
+	"git":       "gitpython",
+	"openssl":   "pyopenssl",
+	"zmq":       "pyzmq",
+	"docx":      "python-docx",
+	"multipart": "python-multipart",
+}
+
+// pythonStdlib Python 3 的标准库模块
+var pythonStdlib = toSet(
+	"__future__", "abc", "argparse", "array", "ast", "asyncio", "atexit", "base64", "binascii", "bisect",
+	"builtins", "bz2", "calendar", "cgi", "cmath", "codecs", "collections", "colorsys", "concurrent",
+	"configparser", "contextlib", "contextvars", "copy", "copyreg", "csv", "ctypes", "curses", "dataclasses",
+	"datetime", "dbm", "decimal", "difflib", "dis", "doctest", "email", "encodings", "enum", "errno",
+	"faulthandler", "fcntl", "filecmp", "fileinput", "fnmatch", "fractions", "ftplib", "functools", "gc",
+	"getopt", "getpass", "gettext", "glob", "graphlib", "grp", "gzip", "hashlib", "heapq", "hmac", "html",
+	"http", "imaplib", "importlib", "inspect", "io", "ipaddress", "itertools", "json", "keyword", "linecache",
+	"locale", "logging", "lzma", "mailbox", "marshal", "math", "mimetypes", "mmap", "multiprocessing",
+	"netrc", "numbers", "operator", "optparse", "os", "pathlib", "pdb", "pickle", "pkgutil", "platform",
+	"plistlib", "poplib", "posixpath", "pprint", "profile", "pstats", "pty", "pwd", "py_compile", "queue",
+	"quopri", "random", "re", "readline", "reprlib", "resource", "sched", "secrets", "select", "selectors",
+	"shelve", "shlex", "shutil", "signal", "site", "smtplib", "socket", "socketserver", "sqlite3", "ssl",
+	"stat", "statistics", "string", "stringprep", "struct", "subprocess", "symtable", "sys", "sysconfig",
+	"syslog", "tarfile", "tempfile", "termios", "textwrap", "threading", "time", "timeit", "tkinter",
+	"token", "tokenize", "tomllib", "trace", "traceback", "tracemalloc", "tty", "turtle", "types", "typing",
+	"unicodedata", "unittest", "urllib", "uuid", "venv", "warnings", "wave", "weakref", "webbrowser",
+	"winreg", "wsgiref", "xml", "xmlrpc", "zipapp", "zipfile", "zipimport", "zlib", "zoneinfo",
+)
+
+// pythonResolver 按顶层模块名匹配 PyPI 依赖
+type pythonResolver struct {
+	declared map[string]bool
+	// local 项目中的顶层模块名称（目录名与 .py 文件名）
+	local map[string]bool
+}
+
+func newPythonResolver(declared map[string]bool, index *model.FileIndex) *pythonResolver {
+	r := &pythonResolver{declared: declared, local: make(map[string]bool)}
+	for _, relPath := range index.Files {
+		if inVendorDir(relPath, "venv", ".venv", "site-packages", ".tox", "node_modules") {
+			continue
+		}
+		parts := strings.Split(relPath, "/")
+		for _, dir := range parts[:len(parts)-1] {
+			r.local[strings.ToLower(dir)] = true
+		}
+		if base := parts[len(parts)-1]; strings.HasSuffix(base, ".py") {
+			r.local[strings.ToLower(strings.TrimSuffix(base, ".py"))] = true
+		}
+	}
+	return r
+}
+
+func (r *pythonResolver) resolve(module string) ([]string, string) {
+	top := strings.ToLower(strings.SplitN(module, ".", 2)[0])
+	if top == "" || pythonStdlib[top] {
+		return nil, ""
+	}
+	name := pyNameNormalizeRe.ReplaceAllString(top, "-")
+	if alias, ok := pythonImportAliases[top]; ok {
+		name = alias
+	}
+	if r.declared[name] {
+		return []string{name}, ""
+	}
+	if r.local[top] {
+		return nil, ""
+	}
+	return nil, name
+}
+
+// nodeBuiltins Node.js 内置模块
+var nodeBuiltins = toSet(
+	"assert", "async_hooks", "buffer", "child_process", "cluster", "console", "constants", "crypto", "dgram",
+	"diagnostics_channel", "dns", "domain", "events", "fs", "http", "http2", "https", "inspector", "module",
+	"net", "os", "path", "perf_hooks", "process", "punycode", "querystring", "readline", "repl", "stream",
+	"string_decoder", "sys", "timers", "tls", "trace_events", "tty", "url", "util", "v8", "vm", "wasi",
+	"worker_threads", "zlib",
+)
+
+// jsResolver 按包名匹配 npm 依赖
+type jsResolver struct {
+	declared map[string]bool
+	// workspace 项目内各 package.json 声明的包名
+	workspace map[string]bool
+}
+
+func newJSResolver(declared map[string]bool, index *model.FileIndex, read ContentReader) *jsResolver {
+	r := &jsResolver{declared: declared, workspace: make(map[string]bool)}
+	for _, content := range readIndexFiles(index, read, "package.json", "node_modules", "bower_components") {
+		var pkg struct {
+			Name string `json:"name"`
+		}
+		if json.Unmarshal(content, &pkg) == nil && pkg.Name != "" {
+			r.workspace[strings.ToLower(pkg.Name)] = true
+		}
+	}
+	return r
+}
+
+func (r *jsResolver) resolve(module string) ([]string, string) {
+	// 相对路径、绝对路径、node: 内置模块、URL 以及 @/、~/、#x 等路径别名
+	if module == "" || strings.ContainsAny(module[:1], "./~#") || strings.Contains(module, ":") ||
+		strings.HasPrefix(module, "@/") {
+		return nil, ""
+	}
+	parts := strings.Split(module, "/")
+	name := parts[0]
+	if strings.HasPrefix(name, "@") && len(parts) > 1 {
+		name += "/" + parts[1]
+	}
+	name = strings.ToLower(name)
+	if r.declared[name] {
+		return []string{name}, ""
+	}
+	if nodeBuiltins[name] || r.workspace[name] {
+		return nil, ""
+	}
+	return nil, name
+}
+
+// phpResolver 按 Composer 包的 autoload 命名空间匹配依赖，缺少锁文件时按命名空间首段与 vendor 名称匹配
+type phpResolver struct {
+	declared map[string]bool
+	// namespaces 命名空间前缀（小写，以 "\" 结尾）到包名的映射
+	namespaces map[string]string
+	// own 项目自身 composer.json 中 autoload 的命名空间前缀
+	own []string
+}
+
+func newPHPResolver(declared map[string]bool, index *model.FileIndex, read ContentReader) *phpResolver {
+	r := &phpResolver{declared: declared, namespaces: make(map[string]string)}
+	type autoloadPackage struct {
+		Name     string `json:"name"`
+		Autoload struct {
+			PSR4 map[string]any `json:"psr-4"`
+			PSR0 map[string]any `json:"psr-0"`
+		} `json:"autoload"`
+	}
+	addNamespaces := func(pkg autoloadPackage, add func(prefix string)) {
+		for _, mapping := range []map[string]any{pkg.Autoload.PSR4, pkg.Autoload.PSR0} {
+			for prefix := range mapping {
+				if prefix = strings.ToLower(strings.Trim(prefix, `\`)); prefix != "" {
+					add(prefix + `\`)
+				}
+			}
+		}
+	}
+
+	for _, name := range []string{"composer.lock", "installed.json"} {
+		for _, content := range readIndexFiles(index, read, name) {
+			var lock struct {
+				Packages    []autoloadPackage `json:"packages"`
+				PackagesDev []autoloadPackage `json:"packages-dev"`
+			}
+			if json.Unmarshal(content, &lock) != nil {
+				// Composer 1 格式的 installed.json 为包数组
+				if json.Unmarshal(content, &lock.Packages) != nil {
+					continue
+				}
+			}
+			for _, pkg := range append(lock.Packages, lock.PackagesDev...) {
+				pkgName := strings.ToLower(pkg.Name)
+				addNamespaces(pkg, func(prefix string) { r.namespaces[prefix] = pkgName })
+			}
+		}
+	}
+	for _, content := range readIndexFiles(index, read, "composer.json", "vendor") {
+		var pkg autoloadPackage
+		if json.Unmarshal(content, &pkg) == nil {
+			addNamespaces(pkg, func(prefix string) { r.own = append(r.own, prefix) })
+		}
+	}
+	return r
+}
+
+func (r *phpResolver) resolve(module string) ([]string, string) {
+	lower := strings.ToLower(strings.TrimPrefix(module, `\`))
+	parts := strings.Split(lower, `\`)
+	// 没有命名空间的类（如 Exception）为内置类或全局类
+	if len(parts) < 2 {
+		return nil, ""
+	}
+	for _, prefix := range r.own {
+		if strings.HasPrefix(lower+`\`, prefix) {
+			return nil, ""
+		}
+	}
+
+	var best string
+	for prefix := range r.namespaces {
+		if strings.HasPrefix(lower+`\`, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best != "" {
+		name := r.namespaces[best]
+		if r.declared[name] {
+			return []string{name}, ""
+		}
+		return nil, name
+	}
+
+	// 命名空间首段与 vendor 名称一致，优先匹配包名与第二段一致的依赖
+	var names []string
+	for name := range r.declared {
+		vendor, pkg, _ := strings.Cut(name, "/")
+		if vendor != parts[0] {
+			continue
+		}
+		if pkg == parts[1] {
+			return []string{name}, ""
+		}
+		names = append(names, name)
+	}
+	if len(names) > 0 {
+		sort.Strings(names)
+		return names, ""
+	}
+	return nil, strings.Split(strings.TrimPrefix(module, `\`), `\`)[0]
+}
+
+// hasPathPrefix 判断 value 是否等于 prefix 或以 prefix 加分隔符开头
+func hasPathPrefix(value, prefix, sep string) bool {
+	return value == prefix || strings.HasPrefix(value, prefix+sep)
+}
+
+// toSet 将字符串列表转换为集合
+func toSet(values ...string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
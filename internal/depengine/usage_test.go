@@ -0,0 +1,169 @@
+package depengine
+
+import (
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestAnalyzeUsage(t *testing.T) {
+	index, read := newTestIndex(map[string]string{
+		"go.mod":        "module example.com/demo\n",
+		"main.go":       "",
+		"composer.json": `{"autoload": {"psr-4": {"App\\": "src/"}}}`,
+		"composer.lock": `{"packages": [
+  {"name": "laravel/framework", "autoload": {"psr-4": {"Illuminate\\": "src/Illuminate/"}}},
+  {"name": "nesbot/carbon", "autoload": {"psr-4": {"Carbon\\": "src/Carbon/"}}}
+]}`,
+		"src/Http/Kernel.php":  "",
+		"web/package.json":     `{"name": "@acme/web"}`,
+		"web/index.js":         "",
+		"py/app.py":            "",
+		"py/mypkg/__init__.py": "",
+	})
+	index.Imports = map[string]model.FileImports{
+		"main.go":             {Language: "Go", Modules: []string{"fmt", "example.com/demo/internal/x", "github.com/gin-gonic/gin/binding", "github.com/vendored/lib/sub"}},
+		"src/Http/Kernel.php": {Language: "PHP", Modules: []string{`App\Models\User`, `Illuminate\Support\Str`, `Carbon\Carbon`, `Monolog\Logger`, "Exception"}},
+		"web/index.js":        {Language: "JavaScript", Modules: []string{"react", "react/jsx-runtime", "./util", "node:fs", "path", "@acme/web", "lodash"}},
+		"py/app.py":           {Language: "Python", Modules: []string{"os", "yaml", "mypkg.helper", "numpy"}},
+	}
+
+	inventory := NewInventory()
+	inventory.Add(
+		model.Dependency{Ecosystem: model.EcosystemGo, Name: "github.com/gin-gonic/gin", Scope: model.ScopeRuntime, Direct: true},
+		model.Dependency{Ecosystem: model.EcosystemGo, Name: "go", Scope: model.ScopeSDK, Direct: true},
+		model.Dependency{Ecosystem: model.EcosystemComposer, Name: "laravel/framework", Scope: model.ScopeRuntime, Direct: true},
+		model.Dependency{Ecosystem: model.EcosystemComposer, Name: "monolog/monolog", Scope: model.ScopeRuntime, Direct: true},
+		model.Dependency{Ecosystem: model.EcosystemNpm, Name: "react", Scope: model.ScopeRuntime, Direct: true},
+		model.Dependency{Ecosystem: model.EcosystemNpm, Name: "vite", Scope: model.ScopeDev, Direct: true},
+		model.Dependency{Ecosystem: model.EcosystemPyPI, Name: "pyyaml", Scope: model.ScopeRuntime, Direct: true},
+		model.Dependency{Ecosystem: model.EcosystemMaven, Name: "junit:junit", Scope: model.ScopeDev, Direct: true},
+	)
+	AnalyzeUsage(inventory, index, read)
+
+	usage := make(map[string]model.Dependency)
+	for _, dep := range inventory.Dependencies {
+		usage[dep.Name] = dep
+	}
+	for name, expected := range map[string]string{
+		"github.com/gin-gonic/gin": model.UsageDeclaredAndUsed,
+		"go":                       "",
+		"laravel/framework":        model.UsageDeclaredAndUsed,
+		"monolog/monolog":          model.UsageDeclaredAndUsed,
+		"react":                    model.UsageDeclaredAndUsed,
+		"vite":                     model.UsageDeclaredOnly,
+		"pyyaml":                   model.UsageDeclaredAndUsed,
+		// 没有 Java 源码时不判断 Maven 依赖
+		"junit:junit": "",
+	} {
+		if got := usage[name].Usage; got != expected {
+			t.Errorf("Expected %s usage %q, got %q", name, expected, got)
+		}
+	}
+	if usage["react"].Importers != 1 {
+		t.Errorf("Expected react to be imported by 1 file, got %d", usage["react"].Importers)
+	}
+
+	undeclared := make(map[string]string)
+	for _, dep := range inventory.Undeclared {
+		if dep.Usage != model.UsageUsedUndeclared || dep.Importers != 1 {
+			t.Errorf("Unexpected undeclared dependency: %+v", dep)
+		}
+		undeclared[dep.Name] = dep.Ecosystem
+	}
+	expected := map[string]string{
+		"github.com/vendored/lib": model.EcosystemGo,
+		"nesbot/carbon":           model.EcosystemComposer,
+		"lodash":                  model.EcosystemNpm,
+		"numpy":                   model.EcosystemPyPI,
+	}
+	if len(undeclared) != len(expected) {
+		t.Errorf("Expected undeclared %v, got %v", expected, undeclared)
+	}
+	for name, ecosystem := range expected {
+		if undeclared[name] != ecosystem {
+			t.Errorf("Expected %s to be undeclared in %s, got %v", name, ecosystem, undeclared)
+		}
+	}
+
+	if weight := inventory.UsageWeight("react", "github.com/gin-gonic/*"); weight != 2 {
+		t.Errorf("Expected usage weight 2, got %d", weight)
+	}
+	if got := inventory.Usage("vite"); got != model.UsageDeclaredOnly {
+		t.Errorf("Expected vite usage declared-only, got %q", got)
+	}
+}
+
+func TestAnalyzeUsageByEcosystem(t *testing.T) {
+	index, read := newTestIndex(map[string]string{
+		"app.py":    "",
+		"server.js": "",
+	})
+	index.Imports = map[string]model.FileImports{
+		"app.py":    {Language: "Python", Modules: []string{"requests"}},
+		"server.js": {Language: "Node.js", Modules: []string{"express"}},
+	}
+
+	// npm 中的 requests 与 Python 导入的 requests 同名，但不是同一个依赖
+	inventory := NewInventory()
+	inventory.Add(
+		model.Dependency{Ecosystem: model.EcosystemNpm, Name: "requests", Scope: model.ScopeRuntime, Direct: true},
+		model.Dependency{Ecosystem: model.EcosystemNpm, Name: "express", Scope: model.ScopeRuntime, Direct: true},
+		model.Dependency{Ecosystem: model.EcosystemPyPI, Name: "requests", Scope: model.ScopeRuntime, Direct: true},
+	)
+	AnalyzeUsage(inventory, index, read)
+
+	usage := make(map[string]string)
+	for _, dep := range inventory.Dependencies {
+		usage[dep.Ecosystem+":"+dep.Name] = dep.Usage
+	}
+	for key, expected := range map[string]string{
+		"npm:requests":  model.UsageDeclaredOnly,
+		"npm:express":   model.UsageDeclaredAndUsed,
+		"pypi:requests": model.UsageDeclaredAndUsed,
+	} {
+		if usage[key] != expected {
+			t.Errorf("Expected %s usage %q, got %q", key, expected, usage[key])
+		}
+	}
+	if weight := inventory.Scoped(model.EcosystemNpm).UsageWeight("requests"); weight != 0 {
+		t.Errorf("Expected npm requests usage weight 0, got %d", weight)
+	}
+	if weight := inventory.Scoped(model.EcosystemPyPI).UsageWeight("requests"); weight != 1 {
+		t.Errorf("Expected pypi requests usage weight 1, got %d", weight)
+	}
+}
+
+func TestJavaImportResolver(t *testing.T) {
+	index, _ := newTestIndex(map[string]string{
+		"src/main/java/com/acme/app/Main.java":      "",
+		"src/main/java/com/acme/app/util/Util.java": "",
+	})
+	resolver := newJavaResolver(map[string]bool{
+		"com.fasterxml.jackson.core:jackson-databind":      true,
+		"org.springframework.boot:spring-boot-starter-web": true,
+	}, index)
+
+	tests := []struct {
+		module     string
+		declared   string
+		undeclared string
+	}{
+		{"com.fasterxml.jackson.databind.ObjectMapper", "com.fasterxml.jackson.core:jackson-databind", ""},
+		{"org.springframework.boot.SpringApplication", "org.springframework.boot:spring-boot-starter-web", ""},
+		{"java.util.List", "", ""},
+		{"com.acme.app.util.Util", "", ""},
+		{"com.google.common.collect.Lists", "", "com.google.common"},
+		{"org.junit.Assert.assertTrue", "", "org.junit"},
+	}
+	for _, tt := range tests {
+		declared, undeclared := resolver.resolve(tt.module)
+		got := ""
+		if len(declared) > 0 {
+			got = declared[0]
+		}
+		if got != tt.declared || undeclared != tt.undeclared {
+			t.Errorf("resolve(%s) = %v, %q; expected %q, %q", tt.module, declared, undeclared, tt.declared, tt.undeclared)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package depengine
+
+import (
+	"encoding/json"
+	"path"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// VcpkgParser 解析 vcpkg 清单模式的 vcpkg.json
+type VcpkgParser struct{}
+
+func init() {
+	Register(&VcpkgParser{})
+}
+
+// vcpkgDependency vcpkg.json 中对象形式的依赖声明
+type vcpkgDependency struct {
+	Name       string `json:"name"`
+	MinVersion string `json:"version>="`
+	Host       bool   `json:"host"`
+}
+
+// vcpkgOverride vcpkg.json 中的版本覆盖，版本字段按方案不同而不同
+type vcpkgOverride struct {
+	Name          string `json:"name"`
+	Version       string `json:"version"`
+	VersionSemver string `json:"version-semver"`
+	VersionDate   string `json:"version-date"`
+	VersionString string `json:"version-string"`
+}
+
+// Ecosystem 返回 vcpkg 生态名称
+func (p *VcpkgParser) Ecosystem() string {
+	return model.EcosystemVcpkg
+}
+
+// Match 匹配项目中的 vcpkg.json
+func (p *VcpkgParser) Match(relPath string) bool {
+	return strings.EqualFold(path.Base(relPath), "vcpkg.json") && !inVendorDir(relPath, cVendorDirs...)
+}
+
+// Parse 解析 vcpkg.json 中的 dependencies，overrides 中的固定版本优先于 version>= 最低版本
+func (p *VcpkgParser) Parse(file ManifestFile) ([]model.Dependency, error) {
+	var manifest struct {
+		Dependencies []json.RawMessage `json:"dependencies"`
+		Overrides    []vcpkgOverride   `json:"overrides"`
+	}
+	if err := json.Unmarshal(file.Content, &manifest); err != nil {
+		return nil, err
+	}
+
+	overrides := make(map[string]string)
+	for _, o := range manifest.Overrides {
+		for _, version := range []string{o.Version, o.VersionSemver, o.VersionDate, o.VersionString} {
+			if version != "" {
+				overrides[o.Name] = version
+				break
+			}
+		}
+	}
+
+	var deps []model.Dependency
+	for _, raw := range manifest.Dependencies {
+		var dep vcpkgDependency
+		var name string
+		if err := json.Unmarshal(raw, &name); err == nil {
+			dep.Name = name
+		} else if err := json.Unmarshal(raw, &dep); err != nil {
+			continue
+		}
+		if dep.Name == "" {
+			continue
+		}
+
+		version := ""
+		locked := false
+		if v, ok := overrides[dep.Name]; ok {
+			version, locked = v, true
+		} else if dep.MinVersion != "" {
+			version = ">=" + dep.MinVersion
+		}
+		scope := model.ScopeRuntime
+		if dep.Host {
+			scope = model.ScopeBuild
+		}
+		deps = append(deps, model.Dependency{
+			Ecosystem: model.EcosystemVcpkg,
+			Name:      dep.Name,
+			Version:   version,
+			Scope:     scope,
+			Direct:    true,
+			Locked:    locked,
+			Manifest:  file.Path,
+		})
+	}
+	return deps, nil
+}
@@ -0,0 +1,46 @@
+package depengine
+
+import (
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestVcpkgManifest(t *testing.T) {
+	index, read := newTestIndex(map[string]string{
+		"vcpkg.json": `{
+  "name": "demo",
+  "version": "1.0.0",
+  "builtin-baseline": "3426db05b996481ca31e95fff3734cf23e0f51bc",
+  "dependencies": [
+    "zlib",
+    {"name": "curl", "features": ["ssl"], "version>=": "8.4.0"},
+    {"name": "vcpkg-cmake", "host": true},
+    {"name": "boost-asio"}
+  ],
+  "overrides": [
+    {"name": "zlib", "version": "1.2.13"}
+  ]
+}`,
+		"vcpkg_installed/x64-linux/share/zlib/vcpkg.json": `{"dependencies": ["bogus"]}`,
+	})
+
+	inventory := Collect(index, read, []Parser{&VcpkgParser{}})
+
+	if inventory.Has("bogus") {
+		t.Errorf("installed ports should be skipped")
+	}
+	zlib, ok := findDependency(inventory.Dependencies, "zlib", "vcpkg.json")
+	if !ok || zlib.Version != "1.2.13" || !zlib.Locked {
+		t.Errorf("override should pin the version: %+v", zlib)
+	}
+	if curl, _ := findDependency(inventory.Dependencies, "curl", "vcpkg.json"); curl.Version != ">=8.4.0" || curl.Locked {
+		t.Errorf("unexpected curl declaration: %+v", curl)
+	}
+	if tool, _ := findDependency(inventory.Dependencies, "vcpkg-cmake", "vcpkg.json"); tool.Scope != model.ScopeBuild {
+		t.Errorf("host dependency should be a build dependency: %+v", tool)
+	}
+	if !inventory.Has("boost-*") {
+		t.Errorf("object dependency without version should be collected")
+	}
+}
@@ -0,0 +1,118 @@
+package depengine
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// XcodeParser 解析 Xcode 工程文件 *.xcodeproj/project.pbxproj，
+// 输出 iOS 最低部署版本（sdk 作用域）与工程内引用的 Swift Package
+type XcodeParser struct{}
+
+func init() {
+	Register(&XcodeParser{})
+}
+
+// IOSDeploymentTarget iOS 最低部署版本的依赖名称，Podfile、Package.swift 与 Xcode 工程共用
+const IOSDeploymentTarget = "iOS Deployment Target"
+
+var (
+	// pbxDeploymentTargetRe 匹配构建配置中的 IPHONEOS_DEPLOYMENT_TARGET = 15.0;
+	pbxDeploymentTargetRe = regexp.MustCompile(`IPHONEOS_DEPLOYMENT_TARGET\s*=\s*"?([0-9.]+)"?\s*;`)
+	// pbxPackageRefRe 匹配 XCRemoteSwiftPackageReference 对象的仓库地址与版本要求
+	pbxPackageRefRe = regexp.MustCompile(`isa\s*=\s*XCRemoteSwiftPackageReference;\s*repositoryURL\s*=\s*"?([^";]+)"?;\s*requirement\s*=\s*\{([^}]*)\}`)
+	// pbxFieldRe 匹配 requirement 中的 key = value; 字段
+	pbxFieldRe = regexp.MustCompile(`(\w+)\s*=\s*"?([^";]+)"?\s*;`)
+)
+
+// Ecosystem 返回 Xcode 生态名称
+func (p *XcodeParser) Ecosystem() string {
+	return model.EcosystemXcode
+}
+
+// Match 匹配 .xcodeproj 目录中的 project.pbxproj，CocoaPods 生成的 Pods 工程除外
+func (p *XcodeParser) Match(relPath string) bool {
+	if path.Base(relPath) != "project.pbxproj" || !strings.HasSuffix(path.Dir(relPath), ".xcodeproj") {
+		return false
+	}
+	return !inVendorDir(relPath, "Pods", "node_modules", "Carthage")
+}
+
+// Parse 解析工程中的最低部署版本（多个 target 时取最低值）与 Swift Package 引用
+func (p *XcodeParser) Parse(file ManifestFile) ([]model.Dependency, error) {
+	text := string(file.Content)
+	var deps []model.Dependency
+
+	target := ""
+	for _, m := range pbxDeploymentTargetRe.FindAllStringSubmatch(text, -1) {
+		if target == "" || compareDotted(m[1], target) < 0 {
+			target = m[1]
+		}
+	}
+	if target != "" {
+		deps = append(deps, model.Dependency{
+			Ecosystem: model.EcosystemXcode,
+			Name:      IOSDeploymentTarget,
+			Version:   target,
+			Scope:     model.ScopeSDK,
+			Direct:    true,
+			Manifest:  file.Path,
+		})
+	}
+
+	for _, m := range pbxPackageRefRe.FindAllStringSubmatch(text, -1) {
+		fields := make(map[string]string)
+		for _, f := range pbxFieldRe.FindAllStringSubmatch(m[2], -1) {
+			fields[f[1]] = f[2]
+		}
+		deps = append(deps, model.Dependency{
+			Ecosystem: model.EcosystemSwiftPM,
+			Name:      swiftPackageName(m[1]),
+			Version:   pbxRequirement(fields),
+			Scope:     model.ScopeRuntime,
+			Direct:    true,
+			Manifest:  file.Path,
+		})
+	}
+	return deps, nil
+}
+
+// pbxRequirement 将 Xcode 的 Swift Package 版本要求转换为约束表达式，分支与修订版本不含版本号
+func pbxRequirement(fields map[string]string) string {
+	switch fields["kind"] {
+	case "upToNextMajorVersion":
+		return "^" + fields["minimumVersion"]
+	case "upToNextMinorVersion":
+		return "~" + fields["minimumVersion"]
+	case "exactVersion":
+		return fields["version"]
+	case "versionRange":
+		return ">=" + fields["minimumVersion"] + ", <" + fields["maximumVersion"]
+	}
+	return ""
+}
+
+// compareDotted 按数字逐段比较以 "." 分隔的版本号，如 "9.0" < "12.0"
+func compareDotted(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
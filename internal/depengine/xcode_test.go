@@ -0,0 +1,59 @@
+package depengine
+
+import (
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestXcodeProject(t *testing.T) {
+	index, read := newTestIndex(map[string]string{
+		"App.xcodeproj/project.pbxproj": `// !$*UTF8*$!
+{
+	objects = {
+		1A2B /* Debug */ = {
+			isa = XCBuildConfiguration;
+			buildSettings = {
+				IPHONEOS_DEPLOYMENT_TARGET = 15.0;
+			};
+		};
+		1A2C /* Widget Debug */ = {
+			isa = XCBuildConfiguration;
+			buildSettings = {
+				IPHONEOS_DEPLOYMENT_TARGET = 9.3;
+			};
+		};
+		8F1A /* XCRemoteSwiftPackageReference "Kingfisher" */ = {
+			isa = XCRemoteSwiftPackageReference;
+			repositoryURL = "https://github.com/onevcat/Kingfisher.git";
+			requirement = {
+				kind = upToNextMajorVersion;
+				minimumVersion = 7.0.0;
+			};
+		};
+		8F1B /* XCRemoteSwiftPackageReference "swift-log" */ = {
+			isa = XCRemoteSwiftPackageReference;
+			repositoryURL = "https://github.com/apple/swift-log";
+			requirement = {
+				branch = main;
+				kind = branch;
+			};
+		};
+	};
+}`,
+		"Pods/Pods.xcodeproj/project.pbxproj": `IPHONEOS_DEPLOYMENT_TARGET = 8.0;`,
+	})
+
+	inventory := Collect(index, read, []Parser{&XcodeParser{}})
+
+	deps := inventory.Find(IOSDeploymentTarget)
+	if len(deps) != 1 || deps[0].Version != "9.3" || deps[0].Scope != model.ScopeSDK {
+		t.Errorf("expected the lowest deployment target outside Pods, got %+v", deps)
+	}
+	if kf, _ := findDependency(inventory.Dependencies, "Kingfisher", "App.xcodeproj/project.pbxproj"); kf.Version != "^7.0.0" || kf.Ecosystem != model.EcosystemSwiftPM {
+		t.Errorf("unexpected package reference: %+v", kf)
+	}
+	if log, ok := findDependency(inventory.Dependencies, "swift-log", "App.xcodeproj/project.pbxproj"); !ok || log.Version != "" {
+		t.Errorf("branch requirement should have no version: %+v", log)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/winezer0/codecanvas/internal/model"
 	"gopkg.in/yaml.v3"
 )
 
@@ -37,13 +38,22 @@ func TestAllYamlFiles(t *testing.T) {
 				Language string `yaml:"language"`
 				Category string `yaml:"category"`
 				Rules    []struct {
-					Paths        []string            `yaml:"paths"`
-					FileContents map[string][]string `yaml:"file_contents"`
+					Paths        []string                   `yaml:"paths"`
+					FileContents map[string][]string        `yaml:"file_contents"`
+					Dependencies []string                   `yaml:"dependencies"`
+					Imports      []string                   `yaml:"imports"`
+					JSON         model.StructuredConditions `yaml:"json"`
+					YAML         model.StructuredConditions `yaml:"yaml"`
+					TOML         model.StructuredConditions `yaml:"toml"`
+					XML          model.StructuredConditions `yaml:"xml"`
 				} `yaml:"rules"`
 				// Version extraction rules at the framework level
 				Versions []struct {
-					FilePattern string   `yaml:"file_pattern"`
-					Patterns    []string `yaml:"patterns"`
+					FilePattern string                     `yaml:"file_pattern"`
+					Patterns    []string                   `yaml:"patterns"`
+					Dependency  string                     `yaml:"dependency"`
+					JSON        model.StructuredConditions `yaml:"json"`
+					XML         model.StructuredConditions `yaml:"xml"`
 				} `yaml:"version"`
 			}
 
@@ -57,13 +67,22 @@ func TestAllYamlFiles(t *testing.T) {
 						Language string `yaml:"language"`
 						Category string `yaml:"category"`
 						Rules    []struct {
-							Paths        []string            `yaml:"paths"`
-							FileContents map[string][]string `yaml:"file_contents"`
+							Paths        []string                   `yaml:"paths"`
+							FileContents map[string][]string        `yaml:"file_contents"`
+							Dependencies []string                   `yaml:"dependencies"`
+							Imports      []string                   `yaml:"imports"`
+							JSON         model.StructuredConditions `yaml:"json"`
+							YAML         model.StructuredConditions `yaml:"yaml"`
+							TOML         model.StructuredConditions `yaml:"toml"`
+							XML          model.StructuredConditions `yaml:"xml"`
 						} `yaml:"rules"`
 						// Version extraction rules at the framework level
 						Versions []struct {
-							FilePattern string   `yaml:"file_pattern"`
-							Patterns    []string `yaml:"patterns"`
+							FilePattern string                     `yaml:"file_pattern"`
+							Patterns    []string                   `yaml:"patterns"`
+							Dependency  string                     `yaml:"dependency"`
+							JSON        model.StructuredConditions `yaml:"json"`
+							XML         model.StructuredConditions `yaml:"xml"`
 						} `yaml:"version"`
 					}
 
@@ -5,31 +5,24 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"sort"
-	"strings"
 
+	"github.com/winezer0/codecanvas/internal/archivefs"
+	"github.com/winezer0/codecanvas/internal/depengine"
 	"github.com/winezer0/codecanvas/internal/model"
+	"github.com/winezer0/codecanvas/internal/version"
 )
 
-// formatVersion 格式化版本号，去除常见前缀和多余字符
-func formatVersion(version string) string {
-	if version == "" {
-		return ""
-	}
-
-	// 去除常见的版本前缀
-	version = strings.TrimPrefix(version, "^")
-	version = strings.TrimPrefix(version, "~")
-	version = strings.TrimPrefix(version, "=")
-	version = strings.TrimSpace(version)
-
-	// 去除构建元数据
-	if idx := strings.Index(version, "+"); idx > 0 {
-		version = version[:idx]
-	}
-
-	return version
+// applyVersion 解析原始版本字符串，分别填充检测结果的版本号、声明约束、规范化版本与未解析的占位符
+func applyVersion(item *model.DetectedItem, raw, scheme string) {
+	info := version.Resolve(raw, scheme)
+	item.Version = info.Version
+	item.DeclaredConstraint = info.Constraint
+	item.NormalizedVersion = info.Normalized
+	item.UnresolvedVersion = info.Unresolved
 }
 
 // CanvasEngine 实现框架和组件检测功能。
@@ -37,6 +30,8 @@ type CanvasEngine struct {
 	rules          []*model.Framework
 	frameworkRules map[string]*model.Framework
 	componentRules map[string]*model.Framework
+	// parsers 依赖清单解析器，默认为 depengine 中注册的全部解析器
+	parsers []depengine.Parser
 }
 
 // NewCanvasEngine 创建一个新的规则引擎实例，默认加载嵌入式规则。
@@ -47,6 +42,7 @@ func NewCanvasEngine(rulesDir string) (*CanvasEngine, error) {
 		rules:          []*model.Framework{},
 		frameworkRules: make(map[string]*model.Framework),
 		componentRules: make(map[string]*model.Framework),
+		parsers:        depengine.DefaultParsers(),
 	}
 
 	// 首先加载嵌入式规则
@@ -63,16 +59,56 @@ func NewCanvasEngine(rulesDir string) (*CanvasEngine, error) {
 	return engine, nil
 }
 
+// RegisterParser 为当前引擎注册额外的依赖清单解析器，用于支持内置解析器未覆盖的生态
+func (e *CanvasEngine) RegisterParser(parser depengine.Parser) {
+	e.parsers = append(e.parsers, parser)
+}
+
+// CollectDependencies 使用引擎注册的解析器解析索引中的所有清单文件，生成依赖清单，
+// 索引中包含导入信息时同时判断依赖的使用情况
+func (e *CanvasEngine) CollectDependencies(index *model.FileIndex) *depengine.Inventory {
+	fileContentCache := make(map[string][]byte)
+	archives := archivefs.NewCache(index.FS)
+	defer archives.Close()
+	read := func(relPath string) ([]byte, error) {
+		return readIndexFile(index, relPath, fileContentCache, archives)
+	}
+	inventory := depengine.Collect(index, read, e.parsers)
+	depengine.AnalyzeUsage(inventory, index, read)
+	return inventory
+}
+
+// DiscoverProjects 根据清单文件与工作区声明发现文件索引中的子项目
+func (e *CanvasEngine) DiscoverProjects(index *model.FileIndex) []model.Project {
+	fileContentCache := make(map[string][]byte)
+	archives := archivefs.NewCache(index.FS)
+	defer archives.Close()
+	read := func(relPath string) ([]byte, error) {
+		return readIndexFile(index, relPath, fileContentCache, archives)
+	}
+	return depengine.DiscoverProjects(index, read)
+}
+
 // DetectFrameworks 根据加载的规则检测给定目录中的框架和组件。
 // 使用文件索引进行加速。
 func (e *CanvasEngine) DetectFrameworks(ctx context.Context, index *model.FileIndex, languages []string) (*model.DetectionInfo, error) {
+	return e.DetectFrameworksWithInventory(ctx, index, languages, e.CollectDependencies(index))
+}
+
+// DetectFrameworksWithInventory 使用已收集的依赖清单检测框架和组件，
+// 便于调用方在报告中复用同一份依赖清单
+func (e *CanvasEngine) DetectFrameworksWithInventory(ctx context.Context, index *model.FileIndex, languages []string, inventory *depengine.Inventory) (*model.DetectionInfo, error) {
 	result := &model.DetectionInfo{
 		Frameworks: []model.DetectedItem{},
 		Components: []model.DetectedItem{},
+		// 识别项目使用的构建系统
+		BuildSystems: depengine.DetectBuildSystems(index),
 	}
 
-	// 创建索引匹配器
+	// 创建索引匹配器，压缩包在本次检测中只打开一次
 	matcher := NewIndexMatcher(index)
+	matcher.archives = archivefs.NewCache(index.FS)
+	defer matcher.archives.Close()
 
 	// 按检测到的语言过滤规则
 	filteredRules := e.filterRulesByLanguages(languages)
@@ -82,19 +118,30 @@ func (e *CanvasEngine) DetectFrameworks(ctx context.Context, index *model.FileIn
 
 	// 遍历所有规则，对每个框架进行检测
 	for _, framework := range filteredRules {
+		// 规则只在其语言对应生态的依赖中查找，避免不同生态中的同名包互相匹配
+		scoped := ruleInventory(inventory, framework.Language)
 		// 遍历框架的所有规则（OR关系）
-		if matchFrame(matcher, framework.Rules, fileContentCache) {
-			// 提取版本信息
-			version := extractorVersion(matcher, framework.Versions, fileContentCache)
+		if matchFrame(matcher, framework.Rules, fileContentCache, scoped) {
 			// 规则匹配成功，创建检测结果
 			item := model.DetectedItem{
 				Name:     framework.Name,
 				Type:     framework.Type,
 				Language: framework.Language,
-				Version:  version,
 				Category: framework.Category,
 				Evidence: fmt.Sprintf("FrameRule matched for %s", framework.Name),
 			}
+			// 提取版本信息
+			raw, scheme := extractorVersion(matcher, framework.Versions, fileContentCache, scoped)
+			applyVersion(&item, raw, scheme)
+			// 记录各模块中的位置与版本，用于发现多模块间的版本差异
+			item.Occurrences = itemOccurrences(matcher, framework.Versions, fileContentCache, scoped)
+			// 记录检测项对应的依赖包，用于漏洞匹配与软件物料清单
+			item.Ecosystem, item.Package = itemPackage(framework, scoped)
+			// 根据规则引用的依赖计算使用情况与权重
+			if patterns := ruleDependencies(framework); len(patterns) > 0 && scoped != nil {
+				item.Usage = scoped.Usage(patterns...)
+				item.Weight = scoped.UsageWeight(patterns...)
+			}
 			// 根据规则类型添加到结果
 			switch framework.Type {
 			case model.RuleTypeFramework:
@@ -105,17 +152,67 @@ func (e *CanvasEngine) DetectFrameworks(ctx context.Context, index *model.FileIn
 		}
 	}
 
+	// 移动端的 SDK 级别、Pods 与 Pub 包作为组件输出
+	result.Components = append(result.Components, mobileComponents(inventory, languages)...)
+	// 根文件系统中的基础操作系统与已安装的系统软件包作为组件输出
+	result.Components = append(result.Components, osComponents(index, inventory, fileContentCache, matcher.archives)...)
+
 	return result, nil
 }
 
 // GetFileContentWithCache 读取文件内容，带缓存和大文件截断（最大 5MB，只读前 1MB）
 // cache 是外部传入的 map[string][]byte，用于跨调用共享缓存
 func GetFileContentWithCache(path string, cache map[string][]byte) ([]byte, error) {
+	return readFileWithCache(nil, path, cache, nil)
+}
+
+// GetFSFileContentWithCache 与 GetFileContentWithCache 相同，从文件系统 fsys 中读取相对路径对应的文件
+func GetFSFileContentWithCache(fsys fs.FS, relPath string, cache map[string][]byte) ([]byte, error) {
+	return readFileWithCache(fsys, relPath, cache, nil)
+}
+
+// readIndexFile 读取文件索引中相对路径对应的文件，索引未关联文件系统时从磁盘上的根目录读取；
+// archives 为基于 index.FS 创建的压缩包缓存
+func readIndexFile(index *model.FileIndex, relPath string, cache map[string][]byte, archives *archivefs.Cache) ([]byte, error) {
+	if index.FS == nil {
+		return readFileWithCache(nil, filepath.Join(index.RootDir, relPath), cache, archives)
+	}
+	return readFileWithCache(index.FS, relPath, cache, archives)
+}
+
+// readFileWithCache 从文件系统 fsys 中读取文件，fsys 为 nil 时 path 为磁盘路径。
+// 压缩包成员从 archives 中读取，archives 为 nil 时每次读取都重新打开压缩包
+func readFileWithCache(fsys fs.FS, path string, cache map[string][]byte, archives *archivefs.Cache) ([]byte, error) {
 	if content, ok := cache[path]; ok {
 		return content, nil
 	}
+	// 压缩包成员的虚拟路径从压缩包中读取
+	if archivefs.IsMember(path) {
+		var (
+			content []byte
+			err     error
+		)
+		if archives != nil {
+			content, err = archives.ReadFile(filepath.ToSlash(path))
+		} else {
+			content, err = archivefs.ReadFileFS(fsys, filepath.ToSlash(path))
+		}
+		if err != nil {
+			return nil, err
+		}
+		cache[path] = content
+		return content, nil
+	}
 
-	f, err := os.Open(path)
+	var (
+		f   fs.File
+		err error
+	)
+	if fsys == nil {
+		f, err = os.Open(path)
+	} else {
+		f, err = fsys.Open(path)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -2,6 +2,7 @@ package frameengine
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -159,5 +160,174 @@ func setupTestEnv(t *testing.T, dir string, framework *model.Framework) bool {
 		}
 	}
 
+	// 处理结构化条件
+	for format, conditions := range map[string]model.StructuredConditions{
+		formatJSON: rule.JSON, formatYAML: rule.YAML, formatTOML: rule.TOML, formatXML: rule.XML,
+	} {
+		for _, condition := range conditions {
+			if !writeStructuredFile(t, dir, format, condition) {
+				return false
+			}
+		}
+	}
+
+	// 处理 Dependencies
+	if len(rule.Dependencies) > 0 {
+		if !writeDependencyManifest(t, dir, framework.Language, rule.Dependencies) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// writeStructuredFile 根据结构化条件生成包含对应键路径或 XPath 节点的文件
+func writeStructuredFile(t *testing.T, dir string, format string, condition model.StructuredCondition) bool {
+	if condition.Value != "" {
+		return false
+	}
+	var content string
+	if format == formatXML {
+		content = xpathDocument(condition.Path)
+	} else {
+		keys := strings.Split(strings.ReplaceAll(condition.Path, "*", "dependencies"), ".")
+		switch format {
+		case formatJSON:
+			var value any = "1.0.0"
+			for i := len(keys) - 1; i >= 0; i-- {
+				value = map[string]any{keys[i]: value}
+			}
+			data, _ := json.Marshal(value)
+			content = string(data)
+		case formatYAML:
+			for i, key := range keys {
+				content += strings.Repeat("  ", i) + key + ":"
+				if i == len(keys)-1 {
+					content += " 1.0.0"
+				}
+				content += "\n"
+			}
+		case formatTOML:
+			if len(keys) > 1 {
+				content = "[" + strings.Join(keys[:len(keys)-1], ".") + "]\n"
+			}
+			content += fmt.Sprintf("%q = \"1.0.0\"\n", keys[len(keys)-1])
+		}
+	}
+	if content == "" {
+		return false
+	}
+
+	fullPath := filepath.Join(dir, strings.TrimPrefix(strings.ReplaceAll(condition.File, "*", "_"), "/"))
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		t.Errorf("Failed to create dirs: %v", err)
+		return false
+	}
+	if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+		t.Errorf("Failed to write file: %v", err)
+		return false
+	}
+	return true
+}
+
+// xpathDocument 根据形如 //a/b[c='v'] 的简单 XPath 生成包含对应节点的 XML 文档
+func xpathDocument(xpath string) string {
+	var open, closing []string
+	if strings.HasPrefix(xpath, "//") || !strings.HasPrefix(xpath, "/") {
+		open, closing = append(open, "<project>"), append(closing, "</project>")
+	}
+	for _, step := range strings.Split(strings.Trim(xpath, "/"), "/") {
+		if step == "" || step == "text()" || strings.HasPrefix(step, "@") {
+			continue
+		}
+		name, predicate, _ := strings.Cut(step, "[")
+		attrs, children := "", ""
+		if key, value, ok := strings.Cut(strings.TrimSuffix(predicate, "]"), "="); ok {
+			value = strings.Trim(value, "'\"")
+			if attr, isAttr := strings.CutPrefix(key, "@"); isAttr {
+				attrs = fmt.Sprintf(" %s=%q", attr, value)
+			} else {
+				children = fmt.Sprintf("<%s>%s</%s>", key, value, key)
+			}
+		}
+		open = append(open, fmt.Sprintf("<%s%s>%s", name, attrs, children))
+		closing = append([]string{"</" + name + ">"}, closing...)
+	}
+	return strings.Join(open, "") + strings.Join(closing, "")
+}
+
+// writeDependencyManifest 根据规则语言生成包含指定依赖的清单文件
+func writeDependencyManifest(t *testing.T, dir string, language string, dependencies []string) bool {
+	var names []string
+	for _, dep := range dependencies {
+		names = append(names, strings.ReplaceAll(dep, "*", "test_file"))
+	}
+
+	var fileName, content string
+	switch language {
+	case "PHP":
+		var requires []string
+		for _, name := range names {
+			requires = append(requires, fmt.Sprintf("%q: \"1.0.0\"", name))
+		}
+		fileName = "composer.json"
+		content = fmt.Sprintf("{\"require\": {%s}}", strings.Join(requires, ", "))
+	case "Rust":
+		var lines []string
+		for _, name := range names {
+			lines = append(lines, fmt.Sprintf("%s = \"1.0.0\"", name))
+		}
+		fileName = "Cargo.toml"
+		content = "[dependencies]\n" + strings.Join(lines, "\n") + "\n"
+	case "Ruby":
+		var lines []string
+		for _, name := range names {
+			lines = append(lines, fmt.Sprintf("gem '%s', '1.0.0'", name))
+		}
+		fileName = "Gemfile"
+		content = strings.Join(lines, "\n") + "\n"
+	case "Elixir":
+		var tuples []string
+		for _, name := range names {
+			tuples = append(tuples, fmt.Sprintf("{:%s, \"~> 1.0\"}", name))
+		}
+		fileName = "mix.exs"
+		content = fmt.Sprintf("defp deps do\n  [%s]\nend\n", strings.Join(tuples, ", "))
+	case "C#":
+		var refs []string
+		for _, name := range names {
+			refs = append(refs, fmt.Sprintf("<PackageReference Include=%q Version=\"1.0.0\" />", name))
+		}
+		fileName = "app.csproj"
+		content = fmt.Sprintf("<Project Sdk=\"Microsoft.NET.Sdk\"><ItemGroup>%s</ItemGroup></Project>", strings.Join(refs, ""))
+	case "C", "C++":
+		var lines []string
+		for _, name := range names {
+			lines = append(lines, fmt.Sprintf("find_package(%s 1.0.0 REQUIRED)", name))
+		}
+		fileName = "CMakeLists.txt"
+		content = strings.Join(lines, "\n") + "\n"
+	case "Java":
+		var lines []string
+		for _, name := range names {
+			lines = append(lines, fmt.Sprintf("    id '%s' version '1.0.0'", name))
+		}
+		fileName = "build.gradle"
+		content = "plugins {\n" + strings.Join(lines, "\n") + "\n}\n"
+	case "Dart":
+		var lines []string
+		for _, name := range names {
+			lines = append(lines, fmt.Sprintf("  %s: 1.0.0", name))
+		}
+		fileName = "pubspec.yaml"
+		content = "dependencies:\n" + strings.Join(lines, "\n") + "\n"
+	default:
+		return false
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, fileName), []byte(content), 0644); err != nil {
+		t.Errorf("Failed to write manifest: %v", err)
+		return false
+	}
 	return true
 }
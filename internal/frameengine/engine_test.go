@@ -4,8 +4,10 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/winezer0/codecanvas/internal/depengine"
 	"github.com/winezer0/codecanvas/internal/model"
 )
 
@@ -321,6 +323,165 @@ func TestDetectVersion(t *testing.T) {
 	}
 }
 
+// TestDetectVersionConstraintAndPlaceholder tests that declared constraints and unresolved placeholders are reported separately.
+func TestDetectVersionConstraintAndPlaceholder(t *testing.T) {
+	projectDir := t.TempDir()
+	files := map[string]string{
+		"package.json": `{"dependencies": {"express": "^4.18.2"}}`,
+		"pom.xml": `<project><dependencies>
+  <dependency><groupId>rome</groupId><artifactId>rome</artifactId><version>${rome.version}</version></dependency>
+</dependencies></project>`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(projectDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	index, err := buildTestIndex(projectDir)
+	if err != nil {
+		t.Fatalf("Failed to build file index: %v", err)
+	}
+	ruleEngine, err := NewCanvasEngine("")
+	if err != nil {
+		t.Fatalf("Failed to create rule engine: %v", err)
+	}
+	result, err := ruleEngine.DetectFrameworks(context.Background(), index, []string{"JavaScript", "Java"})
+	if err != nil {
+		t.Fatalf("Failed to detect frameworks: %v", err)
+	}
+
+	items := make(map[string]model.DetectedItem)
+	for _, item := range append(result.Frameworks, result.Components...) {
+		items[item.Name] = item
+	}
+	express := items["Express"]
+	if express.Version != "" || express.DeclaredConstraint != "^4.18.2" || express.NormalizedVersion != "" {
+		t.Errorf("Unexpected Express version fields: %+v", express)
+	}
+	rome, ok := items["rome"]
+	if !ok {
+		t.Fatalf("Expected rome to be detected")
+	}
+	if rome.Version != "" || rome.UnresolvedVersion != "${rome.version}" {
+		t.Errorf("Expected rome placeholder to be reported as unresolved, got %+v", rome)
+	}
+}
+
+// TestDetectVersionFromComposerLock tests that PHP frameworks report the locked version from composer.lock.
+func TestDetectVersionFromComposerLock(t *testing.T) {
+	projectDir, err := os.MkdirTemp("", "test_composer_project")
+	if err != nil {
+		t.Fatalf("Failed to create test project directory: %v", err)
+	}
+	defer os.RemoveAll(projectDir)
+
+	files := map[string]string{
+		"composer.json": `{"require": {"laravel/framework": "^10.0", "guzzlehttp/guzzle": "^7.2"}}`,
+		"composer.lock": `{"packages": [
+  {"name": "laravel/framework", "version": "v10.48.4"},
+  {"name": "guzzlehttp/guzzle", "version": "7.8.1"},
+  {"name": "symfony/console", "version": "v6.4.4"}
+]}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(projectDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	ruleEngine, err := NewCanvasEngine("")
+	if err != nil {
+		t.Fatalf("Failed to create rule engine: %v", err)
+	}
+
+	index, err := buildTestIndex(projectDir)
+	if err != nil {
+		t.Fatalf("Failed to build file index: %v", err)
+	}
+
+	result, err := ruleEngine.DetectFrameworks(context.Background(), index, []string{"PHP"})
+	if err != nil {
+		t.Fatalf("Failed to detect frameworks: %v", err)
+	}
+
+	expected := map[string]string{
+		"Laravel": "10.48.4",
+		"guzzle":  "7.8.1",
+		"symfony": "6.4.4",
+	}
+	found := make(map[string]string)
+	for _, item := range append(result.Frameworks, result.Components...) {
+		found[item.Name] = item.Version
+	}
+	for name, version := range expected {
+		got, ok := found[name]
+		if !ok {
+			t.Errorf("Expected %s to be detected", name)
+			continue
+		}
+		if got != version {
+			t.Errorf("Expected %s version '%s', got '%s'", name, version, got)
+		}
+	}
+}
+
+func TestDetectCppBuildFiles(t *testing.T) {
+	projectDir := t.TempDir()
+	files := map[string]string{
+		"CMakeLists.txt": `find_package(Boost 1.83.0 REQUIRED COMPONENTS system)
+find_package(Qt6 6.6 REQUIRED COMPONENTS Widgets)
+target_link_libraries(app PRIVATE Qt6::Widgets ZLIB::ZLIB)
+`,
+		"vcpkg.json": `{"dependencies": ["curl"], "overrides": [{"name": "curl", "version": "8.6.0"}]}`,
+		"Makefile":   "LDLIBS += -lssl -lcrypto\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(projectDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	ruleEngine, err := NewCanvasEngine("")
+	if err != nil {
+		t.Fatalf("Failed to create rule engine: %v", err)
+	}
+	index, err := buildTestIndex(projectDir)
+	if err != nil {
+		t.Fatalf("Failed to build file index: %v", err)
+	}
+
+	result, err := ruleEngine.DetectFrameworks(context.Background(), index, []string{"C++", "C"})
+	if err != nil {
+		t.Fatalf("Failed to detect frameworks: %v", err)
+	}
+
+	expected := map[string]string{
+		"Boost":   "1.83.0",
+		"Qt":      "6.6",
+		"zlib":    "",
+		"libcurl": "8.6.0",
+		"OpenSSL": "",
+	}
+	found := make(map[string]string)
+	for _, item := range result.Components {
+		found[item.Name] = item.Version
+	}
+	for name, version := range expected {
+		got, ok := found[name]
+		if !ok {
+			t.Errorf("Expected %s to be detected", name)
+			continue
+		}
+		if got != version {
+			t.Errorf("Expected %s version '%s', got '%s'", name, version, got)
+		}
+	}
+
+	if len(result.BuildSystems) != 2 || result.BuildSystems[0] != "CMake" || result.BuildSystems[1] != "Make" {
+		t.Errorf("Expected build systems [CMake Make], got %v", result.BuildSystems)
+	}
+}
+
 // TestEmbeddedRulesLoad tests that embedded rules are correctly loaded
 func TestEmbeddedRulesLoad(t *testing.T) {
 	// Create a new rule engine without any custom rules (should use embedded only)
@@ -390,3 +551,312 @@ func buildTestIndex(rootDir string) (*model.FileIndex, error) {
 	})
 	return index, err
 }
+
+func TestDetectMobileProject(t *testing.T) {
+	projectDir := t.TempDir()
+	files := map[string]string{
+		"pubspec.yaml": `environment:
+  sdk: ">=3.0.0 <4.0.0"
+  flutter: ">=3.10.0"
+dependencies:
+  flutter:
+    sdk: flutter
+  http: ^1.1.0
+`,
+		"pubspec.lock": "packages:\n  http:\n    dependency: \"direct main\"\n    source: hosted\n    version: \"1.1.2\"\n",
+		"ios/Podfile":  "platform :ios, '12.0'\ntarget 'Runner' do\n  pod 'Firebase/Analytics', '~> 10.0'\nend\n",
+		"android/app/build.gradle": `plugins {
+    id "com.android.application"
+}
+android {
+    compileSdk 34
+    defaultConfig {
+        minSdk 21
+    }
+}`,
+	}
+	for name, content := range files {
+		fullPath := filepath.Join(projectDir, name)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	ruleEngine, err := NewCanvasEngine("")
+	if err != nil {
+		t.Fatalf("Failed to create rule engine: %v", err)
+	}
+	index, err := buildTestIndex(projectDir)
+	if err != nil {
+		t.Fatalf("Failed to build file index: %v", err)
+	}
+
+	result, err := ruleEngine.DetectFrameworks(context.Background(), index, []string{"Dart", "Kotlin", "Java", "Swift", "Objective-C"})
+	if err != nil {
+		t.Fatalf("Failed to detect frameworks: %v", err)
+	}
+
+	frameworks := make(map[string]string)
+	for _, item := range result.Frameworks {
+		frameworks[item.Name] = item.Version
+	}
+	// 未锁定的约束不作为版本输出，约束原文在 declared_constraint 中
+	for name, version := range map[string]string{"Flutter": "", "Android": "", "iOS": "12.0"} {
+		got, ok := frameworks[name]
+		if !ok {
+			t.Errorf("Expected framework %s to be detected", name)
+		} else if got != version {
+			t.Errorf("Expected %s version '%s', got '%s'", name, version, got)
+		}
+	}
+
+	components := make(map[string]model.DetectedItem)
+	for _, item := range result.Components {
+		components[item.Name] = item
+	}
+	expected := map[string][2]string{
+		"http":                  {"Dart", "1.1.2"},
+		"Firebase/Analytics":    {"Swift", ""},
+		"iOS Deployment Target": {"Swift", "12.0"},
+		"Android minSdk":        {"Kotlin", "21"},
+		"Android compileSdk":    {"Kotlin", "34"},
+		"Dart SDK":              {"Dart", ""},
+	}
+	for name, want := range expected {
+		item, ok := components[name]
+		if !ok {
+			t.Errorf("Expected component %s to be detected", name)
+			continue
+		}
+		if item.Language != want[0] || item.Version != want[1] || item.Category != model.CategoryMobile {
+			t.Errorf("Unexpected component %s: %+v", name, item)
+		}
+	}
+	if got := components["Dart SDK"].DeclaredConstraint; got != ">=3.0.0 <4.0.0" {
+		t.Errorf("Expected Dart SDK declared constraint '>=3.0.0 <4.0.0', got '%s'", got)
+	}
+	if _, ok := components["flutter"]; ok {
+		t.Errorf("flutter SDK dependency should be reported as a framework only")
+	}
+}
+
+// testParser 测试用的自定义依赖解析器，将 deps.txt 中的每一行作为依赖名称
+type testParser struct{}
+
+func (p *testParser) Ecosystem() string { return "test" }
+
+func (p *testParser) Match(relPath string) bool { return filepath.Base(relPath) == "deps.txt" }
+
+func (p *testParser) Parse(file depengine.ManifestFile) ([]model.Dependency, error) {
+	var deps []model.Dependency
+	for _, line := range strings.Fields(string(file.Content)) {
+		deps = append(deps, model.Dependency{Ecosystem: "test", Name: line, Direct: true, Manifest: file.Path})
+	}
+	return deps, nil
+}
+
+func TestCollectDependencies(t *testing.T) {
+	projectDir := t.TempDir()
+	files := map[string]string{
+		"pom.xml": `<project>
+  <dependencies>
+    <dependency>
+      <groupId>org.springframework.boot</groupId>
+      <artifactId>spring-boot-starter-web</artifactId>
+      <version>3.1.5</version>
+    </dependency>
+  </dependencies>
+</project>`,
+		"deps.txt": "custom-lib\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(projectDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	ruleEngine, err := NewCanvasEngine("")
+	if err != nil {
+		t.Fatalf("Failed to create rule engine: %v", err)
+	}
+	ruleEngine.RegisterParser(&testParser{})
+	index, err := buildTestIndex(projectDir)
+	if err != nil {
+		t.Fatalf("Failed to build file index: %v", err)
+	}
+
+	inventory := ruleEngine.CollectDependencies(index)
+	if !inventory.Has("custom-lib") {
+		t.Errorf("Expected dependency from registered parser")
+	}
+	if got := inventory.Version("org.springframework.boot:spring-boot-starter-web"); got != "3.1.5" {
+		t.Errorf("Expected maven dependency version 3.1.5, got '%s'", got)
+	}
+
+	result, err := ruleEngine.DetectFrameworksWithInventory(context.Background(), index, []string{"Java"}, inventory)
+	if err != nil {
+		t.Fatalf("Failed to detect frameworks: %v", err)
+	}
+	found := false
+	for _, item := range result.Frameworks {
+		if item.Name == "Spring Boot" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected Spring Boot to be detected from pom.xml dependencies")
+	}
+}
+
+func TestUnclassifiedDependencies(t *testing.T) {
+	ruleEngine, err := NewCanvasEngine("")
+	if err != nil {
+		t.Fatalf("Failed to create rule engine: %v", err)
+	}
+
+	inventory := depengine.NewInventory()
+	inventory.Add(
+		model.Dependency{Ecosystem: model.EcosystemGo, Name: "github.com/gin-gonic/gin", Direct: true},
+		model.Dependency{Ecosystem: model.EcosystemGo, Name: "github.com/foo/bar", Direct: true},
+		model.Dependency{Ecosystem: model.EcosystemGo, Name: "github.com/foo/bar", Direct: true, Locked: true},
+		model.Dependency{Ecosystem: model.EcosystemGo, Name: "go", Scope: model.ScopeSDK, Direct: true},
+		model.Dependency{Ecosystem: model.EcosystemNpm, Name: "left-pad", Direct: true},
+		model.Dependency{Ecosystem: model.EcosystemPub, Name: "http", Direct: true},
+	)
+	detection := &model.DetectionInfo{Components: []model.DetectedItem{{Name: "http"}}}
+
+	unclassified := ruleEngine.UnclassifiedDependencies(inventory, detection)
+	if got := unclassified[model.EcosystemGo]; len(got) != 1 || got[0] != "github.com/foo/bar" {
+		t.Errorf("Expected only github.com/foo/bar to be unclassified in go, got %v", got)
+	}
+	if got := unclassified[model.EcosystemNpm]; len(got) != 1 || got[0] != "left-pad" {
+		t.Errorf("Expected left-pad to be unclassified in npm, got %v", got)
+	}
+	if _, ok := unclassified[model.EcosystemPub]; ok {
+		t.Errorf("Dependencies reported as components should be classified")
+	}
+}
+
+func TestDetectScopesDependenciesByEcosystem(t *testing.T) {
+	ruleEngine, err := NewCanvasEngine("")
+	if err != nil {
+		t.Fatalf("Failed to create rule engine: %v", err)
+	}
+
+	// npm 中存在同名的 rack 包，Ruby 规则只应匹配 rubygems 中的 rack
+	inventory := depengine.NewInventory()
+	inventory.Add(
+		model.Dependency{Ecosystem: model.EcosystemNpm, Name: "rack", Version: "0.0.1", Direct: true, Locked: true},
+		model.Dependency{Ecosystem: model.EcosystemRubyGems, Name: "rack", Version: "2.2.8", Direct: true, Locked: true},
+	)
+	index := model.NewFileIndex("/virtual")
+
+	result, err := ruleEngine.DetectFrameworksWithInventory(context.Background(), index, []string{"Ruby", "JavaScript"}, inventory)
+	if err != nil {
+		t.Fatalf("Failed to detect frameworks: %v", err)
+	}
+	var rack *model.DetectedItem
+	for i := range result.Components {
+		if result.Components[i].Name == "rack" {
+			rack = &result.Components[i]
+		}
+	}
+	if rack == nil {
+		t.Fatalf("Expected rack to be detected")
+	}
+	if rack.Version != "2.2.8" || rack.Ecosystem != model.EcosystemRubyGems {
+		t.Errorf("Expected rubygems rack 2.2.8, got version '%s' ecosystem '%s'", rack.Version, rack.Ecosystem)
+	}
+
+	unclassified := ruleEngine.UnclassifiedDependencies(inventory, result)
+	if got := unclassified[model.EcosystemNpm]; len(got) != 1 || got[0] != "rack" {
+		t.Errorf("Expected npm rack to be unclassified, got %v", got)
+	}
+	if _, ok := unclassified[model.EcosystemRubyGems]; ok {
+		t.Errorf("Expected rubygems rack to be classified")
+	}
+}
+
+func TestDetectNodeRuleScopesDependenciesToNpm(t *testing.T) {
+	rulesDir := t.TempDir()
+	rule := `- name: Dotenv
+  type: component
+  language: Node.js
+  category: backend
+  rules:
+    - dependencies:
+        - "dotenv"
+  version:
+    - dependency: "dotenv"
+`
+	if err := os.WriteFile(filepath.Join(rulesDir, "node.yml"), []byte(rule), 0644); err != nil {
+		t.Fatalf("Failed to write custom rule file: %v", err)
+	}
+	ruleEngine, err := NewCanvasEngine(rulesDir)
+	if err != nil {
+		t.Fatalf("Failed to create rule engine: %v", err)
+	}
+
+	// PyPI 中存在同名的 dotenv 包，Node.js 规则只应匹配 npm 中的 dotenv
+	inventory := depengine.NewInventory()
+	inventory.Add(
+		model.Dependency{Ecosystem: model.EcosystemPyPI, Name: "dotenv", Version: "0.9.9", Direct: true, Locked: true},
+		model.Dependency{Ecosystem: model.EcosystemNpm, Name: "dotenv", Version: "16.4.5", Direct: true, Locked: true},
+	)
+	result, err := ruleEngine.DetectFrameworksWithInventory(context.Background(), model.NewFileIndex("/virtual"), []string{"Node.js"}, inventory)
+	if err != nil {
+		t.Fatalf("Failed to detect frameworks: %v", err)
+	}
+	var dotenv *model.DetectedItem
+	for i := range result.Components {
+		if result.Components[i].Name == "Dotenv" {
+			dotenv = &result.Components[i]
+		}
+	}
+	if dotenv == nil {
+		t.Fatalf("Expected Dotenv to be detected")
+	}
+	if dotenv.Version != "16.4.5" || dotenv.Ecosystem != model.EcosystemNpm || dotenv.Package != "dotenv" {
+		t.Errorf("Expected npm dotenv 16.4.5, got %+v", *dotenv)
+	}
+
+	// 只有 PyPI 中存在 dotenv 时不应检测到 Node.js 规则
+	pypiOnly := depengine.NewInventory()
+	pypiOnly.Add(model.Dependency{Ecosystem: model.EcosystemPyPI, Name: "dotenv", Version: "0.9.9", Direct: true, Locked: true})
+	result, err = ruleEngine.DetectFrameworksWithInventory(context.Background(), model.NewFileIndex("/virtual"), []string{"Node.js"}, pypiOnly)
+	if err != nil {
+		t.Fatalf("Failed to detect frameworks: %v", err)
+	}
+	for _, item := range result.Components {
+		if item.Name == "Dotenv" {
+			t.Errorf("Expected Dotenv not to be detected from a PyPI dependency, got %+v", item)
+		}
+	}
+}
+
+func TestDetectedItemPackageFromWildcardDependency(t *testing.T) {
+	ruleEngine, err := NewCanvasEngine("")
+	if err != nil {
+		t.Fatalf("Failed to create rule engine: %v", err)
+	}
+
+	// Hibernate ORM 规则通过通配符 org.hibernate*:hibernate-core 引用依赖，依赖包取清单中匹配的名称
+	inventory := depengine.NewInventory()
+	inventory.Add(model.Dependency{Ecosystem: model.EcosystemMaven, Name: "org.hibernate:hibernate-core", Version: "5.6.15.Final", Direct: true})
+	result, err := ruleEngine.DetectFrameworksWithInventory(context.Background(), model.NewFileIndex("/virtual"), []string{"Java"}, inventory)
+	if err != nil {
+		t.Fatalf("Failed to detect frameworks: %v", err)
+	}
+	for _, item := range result.Frameworks {
+		if item.Name == "Hibernate ORM" {
+			if item.Ecosystem != model.EcosystemMaven || item.Package != "org.hibernate:hibernate-core" {
+				t.Errorf("Expected maven org.hibernate:hibernate-core, got ecosystem '%s' package '%s'", item.Ecosystem, item.Package)
+			}
+			return
+		}
+	}
+	t.Errorf("Expected Hibernate ORM to be detected")
+}
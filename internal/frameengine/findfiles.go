@@ -5,12 +5,19 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/winezer0/codecanvas/internal/archivefs"
 	"github.com/winezer0/codecanvas/internal/model"
 )
 
 // IndexMatcher 提供基于索引的文件查找功能
 type IndexMatcher struct {
 	Index *model.FileIndex
+	// imports 导入索引，首次使用 imports 条件时构建
+	imports *ImportIndex
+	// documents 已解析的结构化文件（键为 格式:绝对路径），解析失败时缓存 nil
+	documents map[string]any
+	// archives 读取压缩包成员时使用的压缩包缓存，为 nil 时每次读取都重新打开压缩包
+	archives *archivefs.Cache
 }
 
 // NewIndexMatcher 创建一个新的索引匹配器
@@ -18,6 +25,14 @@ func NewIndexMatcher(index *model.FileIndex) *IndexMatcher {
 	return &IndexMatcher{Index: index}
 }
 
+// HasImport 判断项目源码是否导入了匹配的模块
+func (m *IndexMatcher) HasImport(pattern string) bool {
+	if m.imports == nil {
+		m.imports = NewImportIndex(m.Index)
+	}
+	return m.imports.Has(pattern)
+}
+
 // FindFiles 使用索引查找匹配的文件。
 // pattern 支持:
 // 1. 精确相对路径 (e.g., "/package.json")
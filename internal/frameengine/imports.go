@@ -0,0 +1,78 @@
+package frameengine
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// ImportIndex 导入索引，保存项目所有源码文件导入的模块（去重排序），每次扫描只构建一次
+type ImportIndex struct {
+	modules []string
+	// cache 已查询过的导入条件结果
+	cache map[string]bool
+}
+
+// NewImportIndex 根据文件索引中的导入信息构建导入索引
+func NewImportIndex(index *model.FileIndex) *ImportIndex {
+	seen := make(map[string]bool)
+	var modules []string
+	if index != nil {
+		for _, imports := range index.Imports {
+			for _, module := range imports.Modules {
+				if !seen[module] {
+					seen[module] = true
+					modules = append(modules, module)
+				}
+			}
+		}
+	}
+	sort.Strings(modules)
+	return &ImportIndex{modules: modules, cache: make(map[string]bool)}
+}
+
+// Has 判断是否有源码导入了匹配的模块。pattern 可以是：
+// 1. 完整的导入路径或包前缀，匹配该模块及其子包（如 "github.com/gin-gonic/gin"、"flask"、"react"）
+// 2. 以 "."、"/" 或 "\" 结尾的包前缀（如 "org.springframework."）
+// 3. 包含 * 通配符的模式（如 "@angular/*"）
+func (ii *ImportIndex) Has(pattern string) bool {
+	if matched, ok := ii.cache[pattern]; ok {
+		return matched
+	}
+	matched := ii.match(pattern)
+	ii.cache[pattern] = matched
+	return matched
+}
+
+// match 在排序后的模块列表中查找匹配的模块，前缀匹配使用二分查找
+func (ii *ImportIndex) match(pattern string) bool {
+	if pattern == "" {
+		return false
+	}
+	if strings.ContainsAny(pattern, "*?[") {
+		for _, module := range ii.modules {
+			if matched, _ := path.Match(pattern, module); matched {
+				return true
+			}
+		}
+		return false
+	}
+
+	start := sort.SearchStrings(ii.modules, pattern)
+	for _, module := range ii.modules[start:] {
+		if !strings.HasPrefix(module, pattern) {
+			break
+		}
+		if len(module) == len(pattern) || isImportSeparator(pattern[len(pattern)-1]) || isImportSeparator(module[len(pattern)]) {
+			return true
+		}
+	}
+	return false
+}
+
+// isImportSeparator 判断是否为导入路径的分隔符
+func isImportSeparator(c byte) bool {
+	return c == '.' || c == '/' || c == '\\'
+}
@@ -0,0 +1,95 @@
+package frameengine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/analyzer"
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestImportIndex(t *testing.T) {
+	index := model.NewFileIndex("")
+	index.Imports = map[string]model.FileImports{
+		"main.go":      {Language: "Go", Modules: []string{"fmt", "github.com/gin-gonic/gin/binding"}},
+		"App.java":     {Language: "Java", Modules: []string{"org.springframework.boot.SpringApplication"}},
+		"app.py":       {Language: "Python", Modules: []string{"flask"}},
+		"index.js":     {Language: "JavaScript", Modules: []string{"react-dom/client", "@angular/core"}},
+		"Kernel.php":   {Language: "PHP", Modules: []string{`Illuminate\Support\Str`}},
+		"empty.go":     {Language: "Go"},
+		"README.md":    {},
+		"other/app.py": {Language: "Python", Modules: []string{"flask"}},
+	}
+	imports := NewImportIndex(index)
+
+	tests := []struct {
+		pattern  string
+		expected bool
+	}{
+		{"github.com/gin-gonic/gin", true},
+		{"github.com/gin-gonic/gin/binding", true},
+		{"github.com/gin-gonic/gi", false},
+		{"org.springframework.", true},
+		{"org.springframework.boot", true},
+		{"org.spring", false},
+		{"flask", true},
+		{"react", false},
+		{"react-dom", true},
+		{"@angular/*", true},
+		{`Illuminate\`, true},
+		{"fmt", true},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := imports.Has(tt.pattern); got != tt.expected {
+			t.Errorf("Has(%q) = %v, expected %v", tt.pattern, got, tt.expected)
+		}
+	}
+}
+
+func TestDetectFrameworksByImports(t *testing.T) {
+	projectDir := t.TempDir()
+	files := map[string]string{
+		// 导入语句位于非入口文件中
+		"web/server.py": "from flask import Flask\n\napp = Flask(__name__)\n",
+		// 注释与字符串中的文本不是导入语句
+		"tools/notes.py": "# import tornado\nprint('import sanic')\n",
+	}
+	for name, content := range files {
+		fullPath := filepath.Join(projectDir, name)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	_, index, err := analyzer.NewCodeAnalyzer().AnalyzeCodeProfile(projectDir)
+	if err != nil {
+		t.Fatalf("Failed to analyze project: %v", err)
+	}
+	ruleEngine, err := NewCanvasEngine("")
+	if err != nil {
+		t.Fatalf("Failed to create rule engine: %v", err)
+	}
+	result, err := ruleEngine.DetectFrameworks(context.Background(), index, []string{"Python"})
+	if err != nil {
+		t.Fatalf("Failed to detect frameworks: %v", err)
+	}
+
+	detected := make(map[string]bool)
+	for _, item := range result.Frameworks {
+		detected[item.Name] = true
+	}
+	if !detected["Flask"] {
+		t.Errorf("Expected Flask to be detected from its import statement")
+	}
+	for _, name := range []string{"Tornado", "Sanic"} {
+		if detected[name] {
+			t.Errorf("Expected %s not to be detected from comments or strings", name)
+		}
+	}
+}
@@ -5,9 +5,11 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/winezer0/codecanvas/internal/depengine"
 	"github.com/winezer0/codecanvas/internal/logging"
 	"github.com/winezer0/codecanvas/internal/model"
 	"github.com/winezer0/codecanvas/internal/utils"
+	"github.com/winezer0/codecanvas/internal/version"
 )
 
 // containsAllKeywords 检查文件内容是否包含所有必需的关键字。
@@ -50,11 +52,12 @@ func extractVersion(content []byte, patterns []string) string {
 }
 
 // matchFrame 检查 rules 中是否有任意一条规则被满足。
-// 规则满足条件 = 所有 Paths 存在 AND 所有 FileContents 条件满足。
+// 规则满足条件 = 所有 Paths 存在 AND 所有 FileContents 条件满足 AND 所有 Dependencies 存在于依赖清单
+// AND 所有 Imports 被源码导入 AND 所有 JSON/YAML/TOML/XML 结构化条件满足。
 // 返回 true 表示至少有一条规则匹配成功。
-func matchFrame(matcher *IndexMatcher, rules []model.FrameRule, fileContentCache map[string][]byte) bool {
+func matchFrame(matcher *IndexMatcher, rules []model.FrameRule, fileContentCache map[string][]byte, inventory *depengine.Inventory) bool {
 	for _, rule := range rules {
-		if len(rule.Paths) == 0 && len(rule.FileContents) == 0 {
+		if len(rule.Paths) == 0 && len(rule.FileContents) == 0 && len(rule.Dependencies) == 0 && len(rule.Imports) == 0 && !hasStructuredConditions(rule) {
 			logging.Errorf("match rules not has any match content: %s", utils.ToJson(rule))
 			continue
 		}
@@ -77,7 +80,22 @@ func matchFrame(matcher *IndexMatcher, rules []model.FrameRule, fileContentCache
 			}
 		}
 
-		// 2. 检查 FileContents（每个 pattern 必须有至少一个文件包含其所有关键字，AND across patterns）
+		// 2. 检查 Dependencies（所有依赖必须存在于依赖清单中，AND）
+		if !matchDependencies(inventory, rule.Dependencies) {
+			continue
+		}
+
+		// 3. 检查 Imports（所有模块必须被源码导入，AND）
+		if !matchImports(matcher, rule.Imports) {
+			continue
+		}
+
+		// 4. 检查结构化条件（所有键路径或 XPath 必须选中节点，AND）
+		if !matchStructured(matcher, rule, fileContentCache) {
+			continue
+		}
+
+		// 5. 检查 FileContents（每个 pattern 必须有至少一个文件包含其所有关键字，AND across patterns）
 		fileMatch := true // 假设全部满足
 		if len(rule.FileContents) > 0 {
 			for filePattern, fileKeys := range rule.FileContents {
@@ -90,7 +108,7 @@ func matchFrame(matcher *IndexMatcher, rules []model.FrameRule, fileContentCache
 				// 检查是否存在至少一个文件包含所有关键字
 				oneFileMatches := false
 				for _, path := range findFiles {
-					content, err := GetFileContentWithCache(path, fileContentCache)
+					content, err := matcher.readFile(path, fileContentCache)
 					if err != nil {
 						continue
 					}
@@ -108,7 +126,7 @@ func matchFrame(matcher *IndexMatcher, rules []model.FrameRule, fileContentCache
 			}
 		}
 
-		// 如果当前规则完全匹配（Paths + FileContents），立即返回 true
+		// 如果当前规则完全匹配（Paths + Dependencies + Imports + 结构化条件 + FileContents），立即返回 true
 		if pathsMatch && fileMatch {
 			return true
 		}
@@ -118,75 +136,98 @@ func matchFrame(matcher *IndexMatcher, rules []model.FrameRule, fileContentCache
 	return false
 }
 
-func extractorVersion(matcher *IndexMatcher, versionExtractors []model.VersionExtractor, fileContentCache map[string][]byte) string {
-	version := ""
+// matchImports 检查所有模块是否都被项目源码导入
+func matchImports(matcher *IndexMatcher, imports []string) bool {
+	for _, module := range imports {
+		if !matcher.HasImport(module) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchDependencies 检查所有依赖名称是否都存在于依赖清单中
+func matchDependencies(inventory *depengine.Inventory, dependencies []string) bool {
+	for _, dep := range dependencies {
+		if inventory == nil || !inventory.Has(dep) {
+			return false
+		}
+	}
+	return true
+}
+
+// extractorVersion 按顺序尝试版本提取规则，返回原始版本字符串及其约束语法（见 version.Scheme 常量）。
+// 占位符等无法解析的值不会终止查找，所有规则都没有提取到具体版本时才返回第一个占位符
+func extractorVersion(matcher *IndexMatcher, versionExtractors []model.VersionExtractor, fileContentCache map[string][]byte, inventory *depengine.Inventory) (string, string) {
+	unresolved, unresolvedScheme := "", ""
 	// 使用框架/组件级版本提取规则
 	for _, versionExtractor := range versionExtractors {
-		// 找到所有匹配该模式的文件
-		findFiles, _ := matcher.FindFiles(versionExtractor.FilePattern)
-		if len(findFiles) == 0 {
-			// 没有匹配的文件，跳过此提取规则
+		raw, scheme := extractorCandidate(matcher, versionExtractor, fileContentCache, inventory)
+		if raw == "" {
 			continue
 		}
+		if !version.IsUnresolved(raw) {
+			return raw, scheme
+		}
+		if unresolved == "" {
+			unresolved, unresolvedScheme = raw, scheme
+		}
+	}
+	return unresolved, unresolvedScheme
+}
 
-		// 检查所有匹配的文件，直到找到版本号
-		for _, path := range findFiles {
-			content, err := GetFileContentWithCache(path, fileContentCache)
-			if err != nil {
-				// 无法读取文件，尝试下一个文件
-				continue
-			}
+// extractorCandidate 使用单条版本提取规则提取原始版本字符串
+func extractorCandidate(matcher *IndexMatcher, versionExtractor model.VersionExtractor, fileContentCache map[string][]byte, inventory *depengine.Inventory) (string, string) {
+	// 优先从依赖清单读取版本，约束语法由依赖所属生态决定
+	if versionExtractor.Dependency != "" && inventory != nil {
+		if dep := inventory.Versioned(versionExtractor.Dependency); dep != nil {
+			return strings.TrimSpace(dep.Version), version.SchemeForEcosystem(dep.Ecosystem)
+		}
+	}
+	// 其次使用结构化选择器，未选中或只选中占位符时回退到正则提取
+	selected := structuredVersion(matcher, versionExtractor, fileContentCache)
+	if selected != "" && !version.IsUnresolved(selected) {
+		return selected, ""
+	}
+	if versionExtractor.FilePattern == "" {
+		return selected, ""
+	}
 
-			// 按顺序尝试每个正则表达式，直到找到匹配的版本号
-			for _, pattern := range versionExtractor.Patterns {
-				// 使用正则表达式提取版本号
-				re, err := regexp.Compile(pattern)
-				if err != nil {
-					// 正则表达式无效，尝试下一个
-					continue
-				}
+	// 找到所有匹配该模式的文件
+	findFiles, _ := matcher.FindFiles(versionExtractor.FilePattern)
 
-				matches := re.FindSubmatch(content)
-				if len(matches) > 1 {
-					// 找到匹配 并格式化版本号
-					version = formatVersion(string(matches[1]))
-					if len(version) > 0 {
-						// 停止遍历匹配方法
-						break
-					}
-				}
-			}
+	// 检查所有匹配的文件，直到找到版本号
+	for _, path := range findFiles {
+		if raw := matcher.fileVersion(path, versionExtractor.Patterns, fileContentCache); raw != "" {
+			return raw, ""
+		}
+	}
+	return selected, ""
+}
 
-			// 如果从文件内容未找到版本号，尝试从文件名提取
-			for _, pattern := range versionExtractor.Patterns {
-				// 使用正则表达式从文件名提取版本号
-				re, err := regexp.Compile(pattern)
-				if err != nil {
-					// 正则表达式无效，尝试下一个
-					continue
-				}
+// fileVersion 使用正则表达式从文件内容中提取版本号，内容中未找到时尝试从文件名提取
+func (m *IndexMatcher) fileVersion(path string, patterns []string, fileContentCache map[string][]byte) string {
+	content, err := m.readFile(path, fileContentCache)
+	if err != nil {
+		// 无法读取文件
+		return ""
+	}
 
-				matches := re.FindStringSubmatch(path)
-				if len(matches) > 1 {
-					// 找到匹配 并 格式化版本号，去除 ^、~、= 等前缀和空格
-					version = formatVersion(matches[1])
-					if len(version) > 0 {
-						// 停止遍历匹配方法
-						break
-					}
-				}
-			}
+	// 按顺序尝试每个正则表达式，直到找到匹配的版本号
+	if raw := strings.TrimSpace(extractVersion(content, patterns)); raw != "" {
+		return raw
+	}
 
-			// 如果找到版本号，停止遍历
-			if len(version) > 0 {
-				break
-			}
+	// 如果从文件内容未找到版本号，尝试从文件名提取
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			// 正则表达式无效，尝试下一个
+			continue
 		}
-
-		// 如果找到版本号，停止遍历
-		if len(version) > 0 {
-			break
+		if matches := re.FindStringSubmatch(path); len(matches) > 1 && strings.TrimSpace(matches[1]) != "" {
+			return strings.TrimSpace(matches[1])
 		}
 	}
-	return version
+	return ""
 }
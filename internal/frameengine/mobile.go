@@ -0,0 +1,88 @@
+package frameengine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/depengine"
+	"github.com/winezer0/codecanvas/internal/model"
+	"github.com/winezer0/codecanvas/internal/version"
+)
+
+// mobileComponents 根据依赖清单生成移动端组件：各平台的 SDK 级别，以及 CocoaPods、Pub、
+// 和 iOS 项目中 SwiftPM 的直接运行时依赖。组件语言需在检测到的语言中才会输出
+func mobileComponents(inventory *depengine.Inventory, languages []string) []model.DetectedItem {
+	langSet := make(map[string]bool, len(languages))
+	for _, lang := range languages {
+		langSet[lang] = true
+	}
+	iosProject := inventory.Has(depengine.IOSDeploymentTarget)
+
+	var items []model.DetectedItem
+	seen := make(map[string]bool)
+	for _, dep := range inventory.Dependencies {
+		switch {
+		case dep.Scope == model.ScopeSDK:
+		case !dep.Direct || dep.Scope != model.ScopeRuntime:
+			continue
+		case dep.Ecosystem == model.EcosystemCocoaPods, dep.Ecosystem == model.EcosystemPub:
+		case dep.Ecosystem == model.EcosystemSwiftPM && iosProject:
+		default:
+			continue
+		}
+
+		language := mobileLanguage(dep.Ecosystem, langSet)
+		if language == "" || !langSet[language] {
+			continue
+		}
+		// SDK 级别可能同时出现在多个清单中（如 Podfile 与 Xcode 工程），按名称去重
+		key := strings.ToLower(dep.Name)
+		if dep.Scope != model.ScopeSDK {
+			key = dep.Ecosystem + ":" + key
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		item := model.DetectedItem{
+			Name:     dep.Name,
+			Type:     model.RuleTypeComponent,
+			Language: language,
+			Category: model.CategoryMobile,
+			Evidence: fmt.Sprintf("Declared in %s", dep.Manifest),
+			Usage:    dep.Usage,
+			Weight:   inventory.UsageWeight(dep.Name),
+		}
+		if dep.Scope != model.ScopeSDK {
+			item.Ecosystem, item.Package = dep.Ecosystem, dep.Name
+		}
+		if versioned := inventory.Versioned(dep.Name); versioned != nil {
+			applyVersion(&item, versioned.Version, version.SchemeForEcosystem(versioned.Ecosystem))
+		}
+		occurrences := newOccurrenceSet()
+		occurrences.addDependencies(inventory, dep.Name)
+		item.Occurrences = occurrences.list()
+		items = append(items, item)
+	}
+	return items
+}
+
+// mobileLanguage 返回依赖生态对应的组件语言，iOS 优先 Swift，Android 优先 Kotlin
+func mobileLanguage(ecosystem string, langSet map[string]bool) string {
+	switch ecosystem {
+	case model.EcosystemPub:
+		return "Dart"
+	case model.EcosystemCocoaPods, model.EcosystemSwiftPM, model.EcosystemXcode:
+		if langSet["Swift"] {
+			return "Swift"
+		}
+		return "Objective-C"
+	case model.EcosystemAndroid:
+		if langSet["Kotlin"] {
+			return "Kotlin"
+		}
+		return "Java"
+	}
+	return ""
+}
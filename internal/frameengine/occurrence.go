@@ -0,0 +1,122 @@
+package frameengine
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/winezer0/codecanvas/internal/depengine"
+	"github.com/winezer0/codecanvas/internal/model"
+	"github.com/winezer0/codecanvas/internal/version"
+)
+
+// occurrenceSet 按模块收集检测项的出现位置，每个模块保留一条记录
+type occurrenceSet struct {
+	byModule map[string]model.Occurrence
+}
+
+func newOccurrenceSet() *occurrenceSet {
+	return &occurrenceSet{byModule: make(map[string]model.Occurrence)}
+}
+
+// add 添加一处出现位置。模块已有可解析的版本时忽略，已有记录只有占位符或没有版本时由可解析的版本替换
+func (s *occurrenceSet) add(relPath, raw, scheme string) {
+	module := path.Dir(relPath)
+	info := version.Resolve(raw, scheme)
+	occurrence := model.Occurrence{
+		Path:               relPath,
+		Module:             module,
+		Version:            info.Version,
+		DeclaredConstraint: info.Constraint,
+		UnresolvedVersion:  info.Unresolved,
+	}
+	if existing, ok := s.byModule[module]; ok && (resolved(existing) || !resolved(occurrence)) {
+		return
+	}
+	s.byModule[module] = occurrence
+}
+
+// resolved 判断出现位置是否带有可解析的版本或版本约束
+func resolved(occurrence model.Occurrence) bool {
+	return occurrence.Version != "" || occurrence.DeclaredConstraint != ""
+}
+
+// list 返回按模块排序的出现位置
+func (s *occurrenceSet) list() []model.Occurrence {
+	if len(s.byModule) == 0 {
+		return nil
+	}
+	result := make([]model.Occurrence, 0, len(s.byModule))
+	for _, occurrence := range s.byModule {
+		result = append(result, occurrence)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Module < result[j].Module
+	})
+	return result
+}
+
+// addDependencies 添加依赖清单中名称匹配的依赖，同一模块优先使用锁文件中的精确版本
+func (s *occurrenceSet) addDependencies(inventory *depengine.Inventory, pattern string) {
+	if inventory == nil {
+		return
+	}
+	deps := inventory.Find(pattern)
+	sort.SliceStable(deps, func(i, j int) bool {
+		if deps[i].Locked != deps[j].Locked {
+			return deps[i].Locked
+		}
+		return deps[i].Direct && !deps[j].Direct
+	})
+	for _, dep := range deps {
+		s.add(dep.Manifest, dep.Version, version.SchemeForEcosystem(dep.Ecosystem))
+	}
+}
+
+// itemOccurrences 按版本提取规则的顺序收集检测项在各模块中的位置与版本，
+// 依赖清单中的记录以清单所在目录为模块，文件提取的版本以文件所在目录为模块
+func itemOccurrences(matcher *IndexMatcher, versionExtractors []model.VersionExtractor, fileContentCache map[string][]byte, inventory *depengine.Inventory) []model.Occurrence {
+	occurrences := newOccurrenceSet()
+	for _, versionExtractor := range versionExtractors {
+		if versionExtractor.Dependency != "" {
+			occurrences.addDependencies(inventory, versionExtractor.Dependency)
+		}
+		for _, group := range structuredConditions(versionExtractor.JSON, versionExtractor.YAML, versionExtractor.TOML, versionExtractor.XML) {
+			for _, condition := range group.conditions {
+				findFiles, _ := matcher.FindFiles(condition.File)
+				for _, file := range findFiles {
+					if raw := matcher.structuredFileVersion(group.format, file, condition, fileContentCache); raw != "" {
+						occurrences.add(matcher.relPath(file), raw, "")
+					}
+				}
+			}
+		}
+		if versionExtractor.FilePattern == "" {
+			continue
+		}
+		findFiles, _ := matcher.FindFiles(versionExtractor.FilePattern)
+		for _, file := range findFiles {
+			if raw := matcher.fileVersion(file, versionExtractor.Patterns, fileContentCache); raw != "" {
+				occurrences.add(matcher.relPath(file), raw, "")
+			}
+		}
+	}
+	return occurrences.list()
+}
+
+// readFile 读取 FindFiles 返回的文件，索引关联文件系统时转换为相对路径后从中读取
+func (m *IndexMatcher) readFile(absPath string, fileContentCache map[string][]byte) ([]byte, error) {
+	if m.Index.FS == nil {
+		return readFileWithCache(nil, absPath, fileContentCache, m.archives)
+	}
+	return readFileWithCache(m.Index.FS, m.relPath(absPath), fileContentCache, m.archives)
+}
+
+// relPath 将 FindFiles 返回的绝对路径转换为相对于索引根目录的路径
+func (m *IndexMatcher) relPath(absPath string) string {
+	rel, err := filepath.Rel(m.Index.RootDir, absPath)
+	if err != nil {
+		return filepath.ToSlash(absPath)
+	}
+	return filepath.ToSlash(rel)
+}
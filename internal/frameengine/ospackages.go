@@ -0,0 +1,93 @@
+package frameengine
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/archivefs"
+	"github.com/winezer0/codecanvas/internal/depengine"
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// osReleaseFiles 描述基础操作系统的文件，按优先级排列（相对于根文件系统）
+var osReleaseFiles = []string{"etc/os-release", "usr/lib/os-release"}
+
+// osComponents 根据根文件系统（如容器镜像）中的 os-release 识别基础操作系统，
+// 并将 dpkg、apk 数据库中已安装的系统软件包作为组件输出
+func osComponents(index *model.FileIndex, inventory *depengine.Inventory, cache map[string][]byte, archives *archivefs.Cache) []model.DetectedItem {
+	var items []model.DetectedItem
+	for _, relPath := range osReleaseFiles {
+		if !index.Contains(relPath) {
+			continue
+		}
+		content, err := readIndexFile(index, relPath, cache, archives)
+		if err != nil {
+			continue
+		}
+		release := parseOSRelease(content)
+		name := release["NAME"]
+		if name == "" {
+			name = release["ID"]
+		}
+		if name == "" {
+			continue
+		}
+		evidence := fmt.Sprintf("Declared in %s", relPath)
+		if pretty := release["PRETTY_NAME"]; pretty != "" {
+			evidence = fmt.Sprintf("%s: %s", evidence, pretty)
+		}
+		items = append(items, model.DetectedItem{
+			Name:     name,
+			Type:     model.RuleTypeComponent,
+			Version:  release["VERSION_ID"],
+			Category: model.CategoryOther,
+			Evidence: evidence,
+		})
+		break
+	}
+
+	if inventory == nil {
+		return items
+	}
+	seen := make(map[string]bool)
+	for _, dep := range inventory.Dependencies {
+		if dep.Ecosystem != model.EcosystemDeb && dep.Ecosystem != model.EcosystemApk {
+			continue
+		}
+		key := dep.Ecosystem + ":" + dep.Name
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		items = append(items, model.DetectedItem{
+			Name:      dep.Name,
+			Type:      model.RuleTypeComponent,
+			Version:   dep.Version,
+			Category:  model.CategoryOther,
+			Evidence:  fmt.Sprintf("Installed %s package in %s", dep.Ecosystem, dep.Manifest),
+			Ecosystem: dep.Ecosystem,
+			Package:   dep.Name,
+		})
+	}
+	return items
+}
+
+// parseOSRelease 解析 os-release 中的 KEY=VALUE 行，去除值两端的引号
+func parseOSRelease(content []byte) map[string]string {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		values[key] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+	return values
+}
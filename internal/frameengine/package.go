@@ -0,0 +1,84 @@
+package frameengine
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/depengine"
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// languageEcosystems 规则语言对应的依赖生态，规则只在这些生态的依赖中查找其引用的依赖包；
+// 第一个为默认生态，依赖清单中没有规则引用的依赖时用于确定包所属生态。未列出的语言不限制生态
+var languageEcosystems = map[string][]string{
+	"Java":        {model.EcosystemMaven, model.EcosystemGradle, model.EcosystemAndroid},
+	"Kotlin":      {model.EcosystemMaven, model.EcosystemGradle, model.EcosystemAndroid},
+	"Scala":       {model.EcosystemMaven, model.EcosystemGradle},
+	"Go":          {model.EcosystemGo},
+	"Python":      {model.EcosystemPyPI},
+	"JavaScript":  {model.EcosystemNpm},
+	"Node.js":     {model.EcosystemNpm},
+	"TypeScript":  {model.EcosystemNpm},
+	"PHP":         {model.EcosystemComposer},
+	"Rust":        {model.EcosystemCargo},
+	"Ruby":        {model.EcosystemRubyGems},
+	"C#":          {model.EcosystemNuGet},
+	"Dart":        {model.EcosystemPub},
+	"Elixir":      {model.EcosystemHex},
+	"C":           {model.EcosystemConan, model.EcosystemVcpkg, model.EcosystemCMake, model.EcosystemMeson, model.EcosystemSystem},
+	"C++":         {model.EcosystemConan, model.EcosystemVcpkg, model.EcosystemCMake, model.EcosystemMeson, model.EcosystemSystem},
+	"Objective-C": {model.EcosystemCocoaPods, model.EcosystemSwiftPM, model.EcosystemXcode},
+	"Swift":       {model.EcosystemSwiftPM, model.EcosystemCocoaPods, model.EcosystemXcode},
+}
+
+// ruleInventory 返回规则语言对应生态范围内的依赖清单视图
+func ruleInventory(inventory *depengine.Inventory, language string) *depengine.Inventory {
+	if inventory == nil {
+		return nil
+	}
+	return inventory.Scoped(languageEcosystems[language]...)
+}
+
+// ruleCoversEcosystem 判断规则语言的生态范围是否包含依赖所属生态
+func ruleCoversEcosystem(language, ecosystem string) bool {
+	ecosystems := languageEcosystems[language]
+	return len(ecosystems) == 0 || slices.Contains(ecosystems, ecosystem)
+}
+
+// itemPackage 返回检测项对应的依赖包：优先使用版本提取中引用的依赖，其次是规则 dependencies 条件中的依赖。
+// 依赖清单（已限定为规则语言的生态）中存在匹配的依赖时使用清单中的生态与名称（名称支持通配符），
+// 否则使用规则语言的默认生态与第一个不含通配符的依赖名称
+func itemPackage(framework *model.Framework, inventory *depengine.Inventory) (ecosystem, name string) {
+	var patterns []string
+	for _, extractor := range framework.Versions {
+		if extractor.Dependency != "" {
+			patterns = append(patterns, extractor.Dependency)
+		}
+	}
+	for _, rule := range framework.Rules {
+		patterns = append(patterns, rule.Dependencies...)
+	}
+
+	if inventory != nil {
+		for _, pattern := range patterns {
+			if dep := inventory.Versioned(pattern); dep != nil {
+				return dep.Ecosystem, dep.Name
+			}
+		}
+		for _, pattern := range patterns {
+			if deps := inventory.Find(pattern); len(deps) > 0 {
+				return deps[0].Ecosystem, deps[0].Name
+			}
+		}
+	}
+	var exact []string
+	for _, pattern := range patterns {
+		if !strings.ContainsAny(pattern, "*?[") {
+			exact = append(exact, pattern)
+		}
+	}
+	if ecosystems := languageEcosystems[framework.Language]; len(ecosystems) > 0 && len(exact) > 0 {
+		return ecosystems[0], exact[0]
+	}
+	return "", ""
+}
@@ -0,0 +1,189 @@
+package frameengine
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/logging"
+	"github.com/winezer0/codecanvas/internal/model"
+	"github.com/winezer0/codecanvas/internal/utils"
+	"github.com/winezer0/codecanvas/internal/version"
+	"gopkg.in/yaml.v3"
+)
+
+// 结构化条件支持的文件格式
+const (
+	formatJSON = "json"
+	formatYAML = "yaml"
+	formatTOML = "toml"
+	formatXML  = "xml"
+)
+
+// formatConditions 某一格式的结构化条件
+type formatConditions struct {
+	format     string
+	conditions model.StructuredConditions
+}
+
+// structuredConditions 返回按格式分组的结构化条件，顺序固定为 JSON、YAML、TOML、XML
+func structuredConditions(json, yaml, toml, xml model.StructuredConditions) []formatConditions {
+	return []formatConditions{{formatJSON, json}, {formatYAML, yaml}, {formatTOML, toml}, {formatXML, xml}}
+}
+
+// hasStructuredConditions 判断规则是否包含结构化条件
+func hasStructuredConditions(rule model.FrameRule) bool {
+	return len(rule.JSON) > 0 || len(rule.YAML) > 0 || len(rule.TOML) > 0 || len(rule.XML) > 0
+}
+
+// matchStructured 检查所有结构化条件是否都满足（AND），
+// 单个条件满足 = 至少一个匹配的文件中选中了节点，且设置 Value 时选中值匹配该正则
+func matchStructured(matcher *IndexMatcher, rule model.FrameRule, fileContentCache map[string][]byte) bool {
+	for _, group := range structuredConditions(rule.JSON, rule.YAML, rule.TOML, rule.XML) {
+		for _, condition := range group.conditions {
+			if !matchStructuredCondition(matcher, group.format, condition, fileContentCache) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// matchStructuredCondition 检查单个结构化条件
+func matchStructuredCondition(matcher *IndexMatcher, format string, condition model.StructuredCondition, fileContentCache map[string][]byte) bool {
+	var valueRe *regexp.Regexp
+	if condition.Value != "" {
+		re, err := regexp.Compile(condition.Value)
+		if err != nil {
+			logging.Errorf("invalid structured condition value %q: %v", condition.Value, err)
+			return false
+		}
+		valueRe = re
+	}
+	findFiles, _ := matcher.FindFiles(condition.File)
+	for _, path := range findFiles {
+		values, ok := matcher.selectStructured(format, path, condition.Path, fileContentCache)
+		if !ok {
+			continue
+		}
+		if valueRe == nil {
+			return true
+		}
+		for _, value := range values {
+			if valueRe.MatchString(value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// structuredVersion 使用结构化选择器提取原始版本字符串，设置 Value 时取其第一个捕获组，否则直接使用选中值。
+// 优先返回可解析的版本，只选中占位符（如 ${rome.version}）时返回第一个占位符
+func structuredVersion(matcher *IndexMatcher, extractor model.VersionExtractor, fileContentCache map[string][]byte) string {
+	unresolved := ""
+	for _, group := range structuredConditions(extractor.JSON, extractor.YAML, extractor.TOML, extractor.XML) {
+		for _, condition := range group.conditions {
+			findFiles, _ := matcher.FindFiles(condition.File)
+			for _, path := range findFiles {
+				value := matcher.structuredFileVersion(group.format, path, condition, fileContentCache)
+				if value != "" && !version.IsUnresolved(value) {
+					return value
+				}
+				if unresolved == "" {
+					unresolved = value
+				}
+			}
+		}
+	}
+	return unresolved
+}
+
+// structuredFileVersion 从单个文件的选中值中提取原始版本字符串，优先返回可解析的版本
+func (m *IndexMatcher) structuredFileVersion(format, path string, condition model.StructuredCondition, fileContentCache map[string][]byte) string {
+	unresolved := ""
+	values, _ := m.selectStructured(format, path, condition.Path, fileContentCache)
+	for _, value := range values {
+		if condition.Value != "" {
+			value = extractVersion([]byte(value), []string{condition.Value})
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if !version.IsUnresolved(value) {
+			return value
+		}
+		if unresolved == "" {
+			unresolved = value
+		}
+	}
+	return unresolved
+}
+
+// selectStructured 在指定文件中按路径选择节点，返回选中值的字符串形式（对象与数组为空字符串）。
+// 第二个返回值表示是否选中了至少一个节点
+func (m *IndexMatcher) selectStructured(format, path, selector string, fileContentCache map[string][]byte) ([]string, bool) {
+	doc := m.document(format, path, fileContentCache)
+	if doc == nil {
+		return nil, false
+	}
+	if node, ok := doc.(*utils.XMLNode); ok {
+		values, err := node.SelectXPath(selector)
+		if err != nil {
+			logging.Errorf("invalid xpath %q: %v", selector, err)
+		}
+		return values, len(values) > 0
+	}
+	selected, err := utils.SelectPath(doc, selector)
+	if err != nil {
+		logging.Errorf("invalid key path %q: %v", selector, err)
+	}
+	values := make([]string, 0, len(selected))
+	for _, value := range selected {
+		values = append(values, utils.ValueString(value))
+	}
+	return values, len(selected) > 0
+}
+
+// document 读取并解析结构化文件，结果在本次扫描中缓存
+func (m *IndexMatcher) document(format, path string, fileContentCache map[string][]byte) any {
+	key := format + ":" + path
+	if doc, ok := m.documents[key]; ok {
+		return doc
+	}
+	if m.documents == nil {
+		m.documents = make(map[string]any)
+	}
+	var doc any
+	if content, err := m.readFile(path, fileContentCache); err == nil {
+		doc = parseDocument(format, content)
+	}
+	m.documents[key] = doc
+	return doc
+}
+
+// parseDocument 按格式解析文件内容，解析失败返回 nil
+func parseDocument(format string, content []byte) any {
+	switch format {
+	case formatJSON:
+		var doc any
+		if json.Unmarshal(content, &doc) == nil {
+			return doc
+		}
+	case formatYAML:
+		var doc any
+		if yaml.Unmarshal(content, &doc) == nil && doc != nil {
+			return doc
+		}
+	case formatTOML:
+		if doc, err := utils.ParseTOML(content); err == nil {
+			return doc
+		}
+	case formatXML:
+		if doc, err := utils.ParseXML(content); err == nil {
+			return doc
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package frameengine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+func TestSelectStructured(t *testing.T) {
+	projectDir := t.TempDir()
+	files := map[string]string{
+		"package.json": `{"name": "demo", "description": "react demo", "devDependencies": {"react": "^18.2.0"},
+			"workspaces": ["a", "b"], "config": {"app.port": 8080}}`,
+		"config/settings.yml": "spring:\n  datasource:\n    url: jdbc:mysql://db/app\nprofiles:\n  - dev\n  - prod\n",
+		"pyproject.toml":      "[tool.poetry.dependencies]\npython = \"^3.10\"\ndjango = \"4.2.1\"\n",
+		"pom.xml": `<?xml version="1.0"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <properties><rome.version>1.0</rome.version></properties>
+  <dependencies>
+    <dependency><groupId>rome</groupId><artifactId>rome</artifactId><version>${rome.version}</version></dependency>
+    <dependency><groupId>org.codehaus.groovy</groupId><artifactId>groovy</artifactId><version>2.4.7</version></dependency>
+  </dependencies>
+</project>`,
+		"app.csproj": `<Project Sdk="Microsoft.NET.Sdk.Web"><ItemGroup>
+  <PackageReference Include="Newtonsoft.Json" Version="13.0.1" />
+</ItemGroup></Project>`,
+	}
+	for name, content := range files {
+		fullPath := filepath.Join(projectDir, name)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	index, err := buildTestIndex(projectDir)
+	if err != nil {
+		t.Fatalf("Failed to build file index: %v", err)
+	}
+	matcher := NewIndexMatcher(index)
+	cache := make(map[string][]byte)
+
+	tests := []struct {
+		format    string
+		condition model.StructuredCondition
+		expected  bool
+	}{
+		{formatJSON, model.StructuredCondition{File: "package.json", Path: "*.react"}, true},
+		{formatJSON, model.StructuredCondition{File: "package.json", Path: "dependencies.react"}, false},
+		{formatJSON, model.StructuredCondition{File: "package.json", Path: "description.react"}, false},
+		{formatJSON, model.StructuredCondition{File: "package.json", Path: "workspaces[1]", Value: "^b$"}, true},
+		{formatJSON, model.StructuredCondition{File: "package.json", Path: `config."app.port"`, Value: "^8080$"}, true},
+		{formatYAML, model.StructuredCondition{File: "*.yml", Path: "spring.datasource.url", Value: "^jdbc:mysql:"}, true},
+		{formatYAML, model.StructuredCondition{File: "*.yml", Path: "profiles[*]", Value: "^prod$"}, true},
+		{formatTOML, model.StructuredCondition{File: "pyproject.toml", Path: "tool.poetry.dependencies.django"}, true},
+		{formatTOML, model.StructuredCondition{File: "pyproject.toml", Path: "tool.poetry.dependencies.flask"}, false},
+		{formatXML, model.StructuredCondition{File: "pom.xml", Path: "/project/dependencies/dependency[artifactId='rome']"}, true},
+		{formatXML, model.StructuredCondition{File: "pom.xml", Path: "/dependencies/dependency"}, false},
+		{formatXML, model.StructuredCondition{File: "pom.xml", Path: "//dependency[groupId='rome'][artifactId='groovy']"}, false},
+		{formatXML, model.StructuredCondition{File: "pom.xml", Path: "dependency[2]/artifactId", Value: "^groovy$"}, true},
+		{formatXML, model.StructuredCondition{File: "*.csproj", Path: "/Project/@Sdk", Value: `\.Web$`}, true},
+		{formatXML, model.StructuredCondition{File: "*.csproj", Path: "//PackageReference[@Include='Newtonsoft.Json']"}, true},
+		{formatXML, model.StructuredCondition{File: "*.csproj", Path: "//PackageReference[@Include='Serilog']"}, false},
+	}
+	for _, tt := range tests {
+		if got := matchStructuredCondition(matcher, tt.format, tt.condition, cache); got != tt.expected {
+			t.Errorf("%s %s in %s = %v, expected %v", tt.format, tt.condition.Path, tt.condition.File, got, tt.expected)
+		}
+	}
+
+	versions := []struct {
+		extractor model.VersionExtractor
+		expected  string
+	}{
+		{model.VersionExtractor{JSON: model.StructuredConditions{{File: "package.json", Path: "*.react"}}}, "^18.2.0"},
+		{model.VersionExtractor{TOML: model.StructuredConditions{{File: "pyproject.toml", Path: "tool.poetry.dependencies.python", Value: `(\d+\.\d+)`}}}, "3.10"},
+		{model.VersionExtractor{XML: model.StructuredConditions{{File: "pom.xml", Path: "//dependency[artifactId='groovy']/version"}}}, "2.4.7"},
+		{model.VersionExtractor{XML: model.StructuredConditions{{File: "*.csproj", Path: "//PackageReference[@Include='Newtonsoft.Json']/@Version"}}}, "13.0.1"},
+		// 属性引用无法直接使用，回退到正则提取
+		{model.VersionExtractor{
+			XML:         model.StructuredConditions{{File: "pom.xml", Path: "//dependency[artifactId='rome']/version"}},
+			FilePattern: "pom.xml",
+			Patterns:    []string{`<rome.version>([^<]+)</rome.version>`},
+		}, "1.0"},
+	}
+	for _, tt := range versions {
+		if got, _ := extractorVersion(matcher, []model.VersionExtractor{tt.extractor}, cache, nil); got != tt.expected {
+			t.Errorf("extractorVersion(%+v) = %q, expected %q", tt.extractor, got, tt.expected)
+		}
+	}
+}
+
+func TestDetectFrameworksByStructuredConditions(t *testing.T) {
+	projectDir := t.TempDir()
+	files := map[string]string{
+		// 描述与脚本中出现 react/express 字样，但依赖中只有 vue
+		"package.json": `{"description": "migrated from react", "scripts": {"serve": "express-like dev server"},
+			"dependencies": {"vue": "3.3.4"}}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(projectDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	index, err := buildTestIndex(projectDir)
+	if err != nil {
+		t.Fatalf("Failed to build file index: %v", err)
+	}
+	ruleEngine, err := NewCanvasEngine("")
+	if err != nil {
+		t.Fatalf("Failed to create rule engine: %v", err)
+	}
+	result, err := ruleEngine.DetectFrameworks(context.Background(), index, []string{"JavaScript"})
+	if err != nil {
+		t.Fatalf("Failed to detect frameworks: %v", err)
+	}
+
+	detected := make(map[string]string)
+	for _, item := range result.Frameworks {
+		detected[item.Name] = item.Version
+	}
+	if version, ok := detected["Vue.js"]; !ok || version != "3.3.4" {
+		t.Errorf("Expected Vue.js 3.3.4 to be detected, got %v", detected)
+	}
+	for _, name := range []string{"React", "Express"} {
+		if _, ok := detected[name]; ok {
+			t.Errorf("Expected %s not to be detected from non-dependency fields", name)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package frameengine
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/depengine"
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// UnclassifiedDependencies 返回未被任何已加载规则（嵌入式与用户规则）识别的依赖，
+// 按生态分组并去重排序。规则的 dependencies 条件或版本提取中的 dependency 引用该依赖（只识别规则语言对应生态中的依赖）、
+// 或依赖已作为组件输出（如移动端的 Pods 与 Pub 包）时视为已识别；sdk 作用域的平台版本不计入
+func (e *CanvasEngine) UnclassifiedDependencies(inventory *depengine.Inventory, detection *model.DetectionInfo) map[string][]string {
+	result := make(map[string][]string)
+	if inventory == nil {
+		return result
+	}
+
+	detected := make(map[string]bool)
+	if detection != nil {
+		// 记录了依赖包的组件只识别对应生态中的同名依赖
+		for _, item := range detection.Components {
+			key := strings.ToLower(item.Name)
+			if item.Ecosystem != "" {
+				key = item.Ecosystem + ":" + key
+			}
+			detected[key] = true
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, dep := range inventory.Dependencies {
+		key := dep.Ecosystem + ":" + strings.ToLower(dep.Name)
+		if dep.Scope == model.ScopeSDK || seen[key] || detected[key] || detected[strings.ToLower(dep.Name)] {
+			continue
+		}
+		seen[key] = true
+		if !e.classified(dep) {
+			result[dep.Ecosystem] = append(result[dep.Ecosystem], dep.Name)
+		}
+	}
+	for _, names := range result {
+		sort.Strings(names)
+	}
+	return result
+}
+
+// classified 判断依赖是否被任意一个规则引用，规则只识别其语言对应生态中的依赖
+func (e *CanvasEngine) classified(dep model.Dependency) bool {
+	for _, framework := range e.rules {
+		if !ruleCoversEcosystem(framework.Language, dep.Ecosystem) {
+			continue
+		}
+		for _, pattern := range ruleDependencies(framework) {
+			if depengine.MatchName(pattern, dep.Name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ruleDependencies 返回规则中 dependencies 条件与版本提取中 dependency 引用的依赖名称
+func ruleDependencies(framework *model.Framework) []string {
+	var patterns []string
+	for _, rule := range framework.Rules {
+		patterns = append(patterns, rule.Dependencies...)
+	}
+	for _, extractor := range framework.Versions {
+		if extractor.Dependency != "" {
+			patterns = append(patterns, extractor.Dependency)
+		}
+	}
+	return patterns
+}
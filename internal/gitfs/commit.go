@@ -0,0 +1,409 @@
+package gitfs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxTagDepth 剥离嵌套标签对象时的最大层数
+const maxTagDepth = 10
+
+// Commit 提交对象
+// - Hash: 提交哈希
+// - Tree: 根目录树对象的哈希
+// - Parents: 父提交哈希，合并提交有多个父提交
+// - Author: 作者，格式为 "Name <email>"
+// - Date: 提交时间（committer 时间，保留提交时的时区）
+// - Message: 提交说明
+type Commit struct {
+	Hash    string
+	Tree    string
+	Parents []string
+	Author  string
+	Date    time.Time
+	Message string
+}
+
+// Subject 返回提交说明的第一行
+func (c *Commit) Subject() string {
+	subject, _, _ := strings.Cut(c.Message, "\n")
+	return strings.TrimSpace(subject)
+}
+
+// Commit 读取并解析提交对象
+func (r *Repository) Commit(hash string) (*Commit, error) {
+	data, err := r.readTypedObject(hash, objCommit)
+	if err != nil {
+		return nil, err
+	}
+	commit := &Commit{Hash: hash}
+	header, message, _ := bytes.Cut(data, []byte("\n\n"))
+	commit.Message = string(message)
+	scanner := bufio.NewScanner(bytes.NewReader(header))
+	scanner.Buffer(make([]byte, 64*1024), len(header)+1)
+	for scanner.Scan() {
+		// 以空格开头的行是多行头（如 gpgsig）的续行
+		key, value, _ := strings.Cut(scanner.Text(), " ")
+		switch key {
+		case "tree":
+			commit.Tree = value
+		case "parent":
+			commit.Parents = append(commit.Parents, value)
+		case "author":
+			commit.Author, _ = splitSignature(value)
+		case "committer":
+			_, commit.Date = splitSignature(value)
+		}
+	}
+	if commit.Tree == "" {
+		return nil, fmt.Errorf("commit %s has no tree", hash)
+	}
+	return commit, nil
+}
+
+// splitSignature 将 "Name <email> 1700000000 +0800" 拆分为身份与时间
+func splitSignature(signature string) (string, time.Time) {
+	end := strings.LastIndex(signature, ">")
+	if end < 0 {
+		return signature, time.Time{}
+	}
+	identity := signature[:end+1]
+	fields := strings.Fields(signature[end+1:])
+	if len(fields) == 0 {
+		return identity, time.Time{}
+	}
+	seconds, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return identity, time.Time{}
+	}
+	date := time.Unix(seconds, 0).UTC()
+	if len(fields) > 1 && len(fields[1]) == 5 {
+		hours, errH := strconv.Atoi(fields[1][1:3])
+		minutes, errM := strconv.Atoi(fields[1][3:5])
+		if errH == nil && errM == nil {
+			offset := (hours*60 + minutes) * 60
+			if fields[1][0] == '-' {
+				offset = -offset
+			}
+			date = date.In(time.FixedZone(fields[1], offset))
+		}
+	}
+	return identity, date
+}
+
+// ResolveRevision 将修订解析为提交，返回提交哈希与匹配的完整引用名称。
+// rev 可以是 HEAD、分支、标签、远程分支、完整引用名称或（缩写的）提交哈希，为空时使用 HEAD，
+// 并可带有 "~N"（第 N 代首个父提交）与 "^N"（第 N 个父提交）后缀；附注标签被剥离到其指向的提交。
+// 直接指定哈希、带有后缀或 HEAD 处于分离状态时引用名称为空
+func (r *Repository) ResolveRevision(rev string) (string, string, error) {
+	if rev == "" {
+		rev = "HEAD"
+	}
+	name, suffix := rev, ""
+	if i := strings.IndexAny(rev, "~^"); i > 0 {
+		name, suffix = rev[:i], rev[i:]
+	}
+	hash, ref, err := r.resolveName(name)
+	if err != nil {
+		return "", "", err
+	}
+	hash, err = r.peelToCommit(hash)
+	if err != nil {
+		return "", "", fmt.Errorf("revision %s: %v", rev, err)
+	}
+	if suffix == "" {
+		return hash, ref, nil
+	}
+	if hash, err = r.walkAncestors(hash, suffix); err != nil {
+		return "", "", fmt.Errorf("revision %s: %v", rev, err)
+	}
+	return hash, "", nil
+}
+
+// walkAncestors 依次应用 "~N" 与 "^N" 后缀，N 省略时为 1，"^0" 表示提交本身
+func (r *Repository) walkAncestors(hash, suffix string) (string, error) {
+	for suffix != "" {
+		op := suffix[0]
+		digits := len(suffix[1:]) - len(strings.TrimLeft(suffix[1:], "0123456789"))
+		n := 1
+		if digits > 0 {
+			var err error
+			if n, err = strconv.Atoi(suffix[1 : 1+digits]); err != nil {
+				return "", err
+			}
+		}
+		suffix = suffix[1+digits:]
+		switch op {
+		case '~':
+			for ; n > 0; n-- {
+				commit, err := r.Commit(hash)
+				if err != nil {
+					return "", err
+				}
+				if len(commit.Parents) == 0 {
+					return "", fmt.Errorf("commit %s has no parent", hash)
+				}
+				hash = commit.Parents[0]
+			}
+		case '^':
+			if n == 0 {
+				continue
+			}
+			commit, err := r.Commit(hash)
+			if err != nil {
+				return "", err
+			}
+			if n > len(commit.Parents) {
+				return "", fmt.Errorf("commit %s has no parent %d", hash, n)
+			}
+			hash = commit.Parents[n-1]
+		default:
+			return "", fmt.Errorf("unsupported revision suffix %q", suffix)
+		}
+	}
+	return hash, nil
+}
+
+// resolveName 按 git rev-parse 的顺序将名称匹配为引用，未匹配任何引用时作为对象哈希解析
+func (r *Repository) resolveName(rev string) (string, string, error) {
+	candidates := []string{rev, "refs/" + rev, "refs/tags/" + rev, "refs/heads/" + rev, "refs/remotes/" + rev, "refs/remotes/" + rev + "/HEAD"}
+	for _, name := range candidates {
+		if name != "HEAD" && !strings.HasPrefix(name, "refs/") {
+			continue
+		}
+		hash, ref, err := r.resolveRef(name, 0)
+		if err == nil {
+			return hash, ref, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", "", err
+		}
+	}
+	if isHex(rev) && len(rev) >= 4 {
+		hash, err := r.expandHash(strings.ToLower(rev))
+		return hash, "", err
+	}
+	return "", "", fmt.Errorf("unknown revision %s", rev)
+}
+
+// resolveRef 读取引用（松散引用文件或 packed-refs）指向的对象，符号引用继续解析；
+// 返回对象哈希与最终的引用名称，HEAD 处于分离状态时引用名称为空
+func (r *Repository) resolveRef(name string, depth int) (string, string, error) {
+	if depth > maxRefDepth {
+		return "", "", fmt.Errorf("symbolic ref %s nested too deeply", name)
+	}
+	// 引用名称不能包含 ".." 等越出仓库目录的路径
+	if strings.Contains(name, "..") || strings.Contains(name, "\\") || path.Clean(name) != name {
+		return "", "", os.ErrNotExist
+	}
+	dir := r.commonDir
+	if name == "HEAD" {
+		dir = r.gitDir
+	}
+	content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+	if err == nil {
+		value := strings.TrimSpace(string(content))
+		if target, ok := strings.CutPrefix(value, "ref:"); ok {
+			return r.resolveRef(strings.TrimSpace(target), depth+1)
+		}
+		if !r.isHash(value) {
+			return "", "", fmt.Errorf("invalid ref %s", name)
+		}
+		if name == "HEAD" {
+			return value, "", nil
+		}
+		return value, name, nil
+	}
+	if hash, ok := r.packedRefs()[name]; ok {
+		return hash, name, nil
+	}
+	return "", "", os.ErrNotExist
+}
+
+// packedRefs 读取 packed-refs 中的引用，忽略注释与剥离标签的 "^" 行
+func (r *Repository) packedRefs() map[string]string {
+	refs := make(map[string]string)
+	content, err := os.ReadFile(filepath.Join(r.commonDir, "packed-refs"))
+	if err != nil {
+		return refs
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' || line[0] == '^' {
+			continue
+		}
+		if hash, name, ok := strings.Cut(line, " "); ok && r.isHash(hash) {
+			refs[name] = hash
+		}
+	}
+	return refs
+}
+
+// Tags 返回全部标签名称（不含 refs/tags/ 前缀）到其指向的提交哈希的映射，不指向提交的标签被忽略
+func (r *Repository) Tags() (map[string]string, error) {
+	names := make(map[string]bool)
+	for name := range r.packedRefs() {
+		if tag, ok := strings.CutPrefix(name, "refs/tags/"); ok {
+			names[tag] = true
+		}
+	}
+	tagsDir := filepath.Join(r.commonDir, "refs", "tags")
+	filepath.WalkDir(tagsDir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			if rel, err := filepath.Rel(tagsDir, path); err == nil {
+				names[filepath.ToSlash(rel)] = true
+			}
+		}
+		return nil
+	})
+
+	tags := make(map[string]string, len(names))
+	for name := range names {
+		hash, _, err := r.resolveRef("refs/tags/"+name, 0)
+		if err != nil {
+			continue
+		}
+		if commit, err := r.peelToCommit(hash); err == nil {
+			tags[name] = commit
+		}
+	}
+	return tags, nil
+}
+
+// peelToCommit 将附注标签剥离到其指向的对象，最终对象必须为提交
+func (r *Repository) peelToCommit(hash string) (string, error) {
+	for depth := 0; depth < maxTagDepth; depth++ {
+		objType, data, err := r.readObject(hash)
+		if err != nil {
+			return "", err
+		}
+		switch objType {
+		case objCommit:
+			return hash, nil
+		case objTag:
+			target, ok := tagTarget(data)
+			if !ok {
+				return "", fmt.Errorf("tag %s has no target object", hash)
+			}
+			hash = target
+		default:
+			return "", fmt.Errorf("object %s is a %s, not a commit", hash, typeName(objType))
+		}
+	}
+	return "", fmt.Errorf("tag %s nested too deeply", hash)
+}
+
+// tagTarget 读取标签对象头中 "object" 行指向的对象哈希
+func tagTarget(data []byte) (string, bool) {
+	for _, line := range strings.Split(string(data), "\n") {
+		if line == "" {
+			break
+		}
+		if target, ok := strings.CutPrefix(line, "object "); ok {
+			return target, true
+		}
+	}
+	return "", false
+}
+
+// expandHash 将完整或缩写的哈希扩展为对象库中唯一的对象哈希
+func (r *Repository) expandHash(prefix string) (string, error) {
+	if len(prefix) > r.hashSize*2 {
+		return "", fmt.Errorf("unknown revision %s", prefix)
+	}
+	matches := make(map[string]bool)
+	for _, dir := range r.objectDirs {
+		entries, _ := os.ReadDir(filepath.Join(dir, prefix[:2]))
+		for _, entry := range entries {
+			if hash := prefix[:2] + entry.Name(); r.isHash(hash) && strings.HasPrefix(hash, prefix) {
+				matches[hash] = true
+			}
+		}
+	}
+	packs, err := r.loadPacks()
+	if err != nil {
+		return "", err
+	}
+	for _, pack := range packs {
+		for _, hash := range pack.withPrefix(prefix, r.hashSize) {
+			matches[hash] = true
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("unknown revision %s", prefix)
+	case 1:
+		for hash := range matches {
+			return hash, nil
+		}
+	}
+	candidates := make([]string, 0, len(matches))
+	for hash := range matches {
+		candidates = append(candidates, hash)
+	}
+	sort.Strings(candidates)
+	return "", fmt.Errorf("short object id %s is ambiguous: %s", prefix, strings.Join(candidates, ", "))
+}
+
+// withPrefix 返回索引中以十六进制前缀开头的对象哈希
+func (p *packFile) withPrefix(prefix string, hashSize int) []string {
+	first, err := strconv.ParseUint(prefix[:2], 16, 8)
+	if err != nil {
+		return nil
+	}
+	lo := 0
+	if first > 0 {
+		lo = int(p.fanout[first-1])
+	}
+	var hashes []string
+	for i := lo; i < int(p.fanout[first]); i++ {
+		if hash := hex.EncodeToString(p.name(i, hashSize)); strings.HasPrefix(hash, prefix) {
+			hashes = append(hashes, hash)
+		}
+	}
+	return hashes
+}
+
+// isHash 判断字符串是否为完整的十六进制对象哈希
+func (r *Repository) isHash(s string) bool {
+	return len(s) == r.hashSize*2 && isHex(s)
+}
+
+// isHex 判断字符串是否只包含十六进制字符
+func isHex(s string) bool {
+	for _, c := range s {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// FirstParents 沿首个父提交从 hash 向前遍历历史，返回从新到旧排列的提交，limit 大于 0 时最多返回 limit 个
+func (r *Repository) FirstParents(hash string, limit int) ([]*Commit, error) {
+	var commits []*Commit
+	seen := make(map[string]bool)
+	for hash != "" && !seen[hash] && (limit <= 0 || len(commits) < limit) {
+		seen[hash] = true
+		commit, err := r.Commit(hash)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, commit)
+		hash = ""
+		if len(commit.Parents) > 0 {
+			hash = commit.Parents[0]
+		}
+	}
+	return commits, nil
+}
@@ -0,0 +1,196 @@
+package gitfs
+
+import (
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// runGit 在目录中执行 git 命令，提交时间固定以便断言
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=tester", "GIT_AUTHOR_EMAIL=tester@example.com",
+		"GIT_COMMITTER_NAME=tester", "GIT_COMMITTER_EMAIL=tester@example.com",
+		"GIT_AUTHOR_DATE=2024-03-01T10:00:00+08:00", "GIT_COMMITTER_DATE=2024-03-01T10:00:00+08:00",
+		"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1",
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s 失败: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// writeFiles 写入工作区文件
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("无法创建目录: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("无法写入 %s: %v", name, err)
+		}
+	}
+}
+
+// newTestRepo 创建包含两个提交的仓库：v1.0.0 为附注标签，v1.1.0 为轻量标签
+func newTestRepo(t *testing.T) (dir, first, second string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git 不可用")
+	}
+	dir = t.TempDir()
+	runGit(t, dir, "init", "-q", "-b", "main")
+
+	// 较大的相似文件使 gc 时生成增量对象
+	var lines []string
+	for i := 0; i < 500; i++ {
+		lines = append(lines, "line "+strings.Repeat("x", i%40))
+	}
+	writeFiles(t, dir, map[string]string{
+		"go.mod":      "module example.com/app\n\nrequire github.com/gin-gonic/gin v1.9.0\n",
+		"main.go":     "package main\n",
+		"docs/big.md": strings.Join(lines, "\n"),
+	})
+	if err := os.Symlink("../go.mod", filepath.Join(dir, "docs", "go.mod.link")); err != nil {
+		t.Fatalf("无法创建符号链接: %v", err)
+	}
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, "commit", "-q", "-m", "first")
+	runGit(t, dir, "tag", "-a", "v1.0.0", "-m", "release 1.0.0")
+	first = runGit(t, dir, "rev-parse", "HEAD")
+
+	writeFiles(t, dir, map[string]string{
+		"go.mod":      "module example.com/app\n\nrequire github.com/gin-gonic/gin v1.9.1\n",
+		"docs/big.md": strings.Join(lines, "\n") + "\nappended\n",
+	})
+	runGit(t, dir, "commit", "-q", "-am", "second")
+	runGit(t, dir, "tag", "v1.1.0")
+	second = runGit(t, dir, "rev-parse", "HEAD")
+	return dir, first, second
+}
+
+// checkRepository 验证修订解析与文件树读取
+func checkRepository(t *testing.T, dir, first, second string) {
+	t.Helper()
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open 失败: %v", err)
+	}
+	defer repo.Close()
+
+	revisions := []struct {
+		rev, hash, ref string
+	}{
+		{"", second, "refs/heads/main"},
+		{"main", second, "refs/heads/main"},
+		{"v1.0.0", first, "refs/tags/v1.0.0"},
+		{"refs/tags/v1.1.0", second, "refs/tags/v1.1.0"},
+		{first[:8], first, ""},
+		{"HEAD~1", first, ""},
+		{"v1.1.0^", first, ""},
+		{"main^0", second, ""},
+	}
+	for _, tt := range revisions {
+		hash, ref, err := repo.ResolveRevision(tt.rev)
+		if err != nil {
+			t.Fatalf("ResolveRevision(%q) 失败: %v", tt.rev, err)
+		}
+		if hash != tt.hash || ref != tt.ref {
+			t.Errorf("ResolveRevision(%q) = %s %s，期望 %s %s", tt.rev, hash, ref, tt.hash, tt.ref)
+		}
+	}
+	if _, _, err := repo.ResolveRevision("v9.9.9"); err == nil {
+		t.Errorf("不存在的修订应返回错误")
+	}
+
+	commit, err := repo.Commit(first)
+	if err != nil {
+		t.Fatalf("Commit 失败: %v", err)
+	}
+	if commit.Subject() != "first" || commit.Author != "tester <tester@example.com>" {
+		t.Errorf("提交信息错误: %+v", commit)
+	}
+	if want := time.Date(2024, 3, 1, 2, 0, 0, 0, time.UTC); !commit.Date.Equal(want) {
+		t.Errorf("提交时间应为 %v，实际为 %v", want, commit.Date)
+	}
+
+	fsys := repo.TreeFS(commit)
+	if err := fstest.TestFS(fsys, "go.mod", "main.go", "docs/big.md", "docs/go.mod.link"); err != nil {
+		t.Errorf("TestFS 失败: %v", err)
+	}
+	content, err := fs.ReadFile(fsys, "docs/go.mod.link")
+	if err != nil || !strings.Contains(string(content), "gin v1.9.0") {
+		t.Errorf("符号链接应解析为第一个提交的 go.mod，实际为 %q (%v)", content, err)
+	}
+	if target, err := fsys.ReadLink("docs/go.mod.link"); err != nil || target != "../go.mod" {
+		t.Errorf("ReadLink 错误: %q (%v)", target, err)
+	}
+
+	latest, err := repo.Commit(second)
+	if err != nil {
+		t.Fatalf("Commit 失败: %v", err)
+	}
+	latestFS := repo.TreeFS(latest)
+	content, err = fs.ReadFile(latestFS, "docs/big.md")
+	if err != nil || !strings.HasSuffix(string(content), "\nappended\n") {
+		t.Errorf("第二个提交的 docs/big.md 内容错误 (%v)", err)
+	}
+	// 内容未变化的文件在两个提交中 blob 哈希相同
+	before, _ := fsys.BlobHash("main.go")
+	after, _ := latestFS.BlobHash("main.go")
+	if before == "" || before != after {
+		t.Errorf("main.go 的 blob 哈希应保持不变: %s %s", before, after)
+	}
+	if changed, _ := latestFS.BlobHash("go.mod"); changed == "" || changed == before {
+		t.Errorf("go.mod 的 blob 哈希错误: %s", changed)
+	}
+}
+
+func TestLooseObjects(t *testing.T) {
+	dir, first, second := newTestRepo(t)
+	checkRepository(t, dir, first, second)
+}
+
+func TestPackedObjects(t *testing.T) {
+	dir, first, second := newTestRepo(t)
+	// gc 将对象打包为带增量的 packfile，并将引用写入 packed-refs
+	runGit(t, dir, "gc", "-q", "--aggressive", "--prune=now")
+	if loose, _ := filepath.Glob(filepath.Join(dir, ".git", "objects", "??", "*")); len(loose) > 0 {
+		t.Fatalf("gc 后不应存在松散对象: %v", loose)
+	}
+	checkRepository(t, dir, first, second)
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open 失败: %v", err)
+	}
+	defer repo.Close()
+	tags, err := repo.Tags()
+	if err != nil || tags["v1.0.0"] != first || tags["v1.1.0"] != second {
+		t.Errorf("Tags 错误: %v (%v)", tags, err)
+	}
+}
+
+func TestApplyDelta(t *testing.T) {
+	base := []byte("hello world")
+	// 源大小 11，目标大小 14：复制 "hello "，插入 "gitfs!"，复制 "ld"
+	delta := []byte{11, 14, 0x90, 6, 6, 'g', 'i', 't', 'f', 's', '!', 0x91, 9, 2}
+	out, err := applyDelta(base, delta)
+	if err != nil || string(out) != "hello gitfs!ld" {
+		t.Errorf("applyDelta = %q (%v)", out, err)
+	}
+	if _, err := applyDelta(base, []byte{11, 4, 0x91, 10, 4}); err == nil {
+		t.Errorf("越界的复制指令应返回错误")
+	}
+}
@@ -0,0 +1,464 @@
+package gitfs
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 对象类型，数值与 packfile 中的类型编码一致
+const (
+	objCommit   = 1
+	objTree     = 2
+	objBlob     = 3
+	objTag      = 4
+	objOfsDelta = 6
+	objRefDelta = 7
+)
+
+const (
+	// maxDeltaDepth 解析增量对象时的最大基础对象层数
+	maxDeltaDepth = 64
+	// maxCacheBytes 增量基础对象缓存的最大字节数
+	maxCacheBytes = 64 * 1024 * 1024
+)
+
+// objectTypeNames 对象类型名称，用于松散对象头
+var objectTypeNames = map[string]int{"commit": objCommit, "tree": objTree, "blob": objBlob, "tag": objTag}
+
+// packFile 一个 packfile 及其 .idx 索引
+// - names: 按哈希排序的对象哈希，每个 hashSize 字节
+// - offsets: 与 names 一一对应的对象在 packfile 中的偏移
+type packFile struct {
+	path    string
+	file    *os.File
+	fanout  [256]uint32
+	names   []byte
+	offsets []int64
+}
+
+// readObject 读取对象的类型与内容，先查找松散对象再查找 packfile
+func (r *Repository) readObject(hash string) (int, []byte, error) {
+	if len(hash) != r.hashSize*2 {
+		return 0, nil, fmt.Errorf("invalid object hash %q", hash)
+	}
+	for _, dir := range r.objectDirs {
+		objType, data, err := readLooseObject(filepath.Join(dir, hash[:2], hash[2:]))
+		if err == nil {
+			return objType, data, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return 0, nil, fmt.Errorf("read loose object %s failed: %v", hash, err)
+		}
+	}
+
+	raw, err := hex.DecodeString(hash)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid object hash %q", hash)
+	}
+	packs, err := r.loadPacks()
+	if err != nil {
+		return 0, nil, err
+	}
+	for _, pack := range packs {
+		if offset, ok := pack.find(raw, r.hashSize); ok {
+			objType, data, err := r.readPacked(pack, offset, 0)
+			if err != nil {
+				return 0, nil, fmt.Errorf("read object %s from %s failed: %v", hash, filepath.Base(pack.path), err)
+			}
+			return objType, data, nil
+		}
+	}
+	return 0, nil, fmt.Errorf("%w: %s", ErrObjectNotFound, hash)
+}
+
+// readTypedObject 读取对象并检查其类型
+func (r *Repository) readTypedObject(hash string, want int) ([]byte, error) {
+	objType, data, err := r.readObject(hash)
+	if err != nil {
+		return nil, err
+	}
+	if objType != want {
+		return nil, fmt.Errorf("object %s is a %s, not a %s", hash, typeName(objType), typeName(want))
+	}
+	return data, nil
+}
+
+// typeName 返回对象类型的名称
+func typeName(objType int) string {
+	for name, t := range objectTypeNames {
+		if t == objType {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+// readLooseObject 读取 zlib 压缩的松散对象，对象头格式为 "<type> <size>\x00"
+func readLooseObject(objectPath string) (int, []byte, error) {
+	file, err := os.Open(objectPath)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer file.Close()
+	zr, err := zlib.NewReader(bufio.NewReader(file))
+	if err != nil {
+		return 0, nil, err
+	}
+	defer zr.Close()
+	content, err := io.ReadAll(zr)
+	if err != nil {
+		return 0, nil, err
+	}
+	header, data, ok := bytes.Cut(content, []byte{0})
+	if !ok {
+		return 0, nil, errors.New("missing object header")
+	}
+	name, size, _ := strings.Cut(string(header), " ")
+	objType, ok := objectTypeNames[name]
+	if !ok {
+		return 0, nil, fmt.Errorf("unknown object type %q", name)
+	}
+	if n, err := strconv.Atoi(size); err != nil || n != len(data) {
+		return 0, nil, fmt.Errorf("object size mismatch")
+	}
+	return objType, data, nil
+}
+
+// loadPacks 打开全部对象目录中的 packfile 索引，仅在首次需要时加载
+func (r *Repository) loadPacks() ([]*packFile, error) {
+	r.packsOnce.Do(func() {
+		for _, dir := range r.objectDirs {
+			indexes, _ := filepath.Glob(filepath.Join(dir, "pack", "*.idx"))
+			sort.Strings(indexes)
+			for _, index := range indexes {
+				pack, err := openPack(index, r.hashSize)
+				if err != nil {
+					r.packsErr = fmt.Errorf("open pack index %s failed: %v", index, err)
+					return
+				}
+				r.packs = append(r.packs, pack)
+			}
+		}
+	})
+	return r.packs, r.packsErr
+}
+
+// openPack 读取 .idx 索引（支持 v1 与 v2 格式）并打开对应的 .pack 文件
+func openPack(indexPath string, hashSize int) (*packFile, error) {
+	content, err := os.ReadFile(indexPath)
+	if err != nil {
+		return nil, err
+	}
+	pack := &packFile{path: strings.TrimSuffix(indexPath, ".idx") + ".pack"}
+
+	v2 := len(content) >= 8 && bytes.Equal(content[:4], []byte{0xff, 't', 'O', 'c'})
+	body := content
+	if v2 {
+		if version := binary.BigEndian.Uint32(content[4:8]); version != 2 {
+			return nil, fmt.Errorf("unsupported pack index version %d", version)
+		}
+		body = content[8:]
+	}
+	if len(body) < 256*4 {
+		return nil, errors.New("truncated pack index")
+	}
+	for i := range pack.fanout {
+		pack.fanout[i] = binary.BigEndian.Uint32(body[i*4:])
+	}
+	count := int(pack.fanout[255])
+	body = body[256*4:]
+
+	if v2 {
+		// v2: 哈希表、CRC32 表、4 字节偏移表，最高位置位的偏移指向 8 字节的大偏移表
+		if len(body) < count*(hashSize+8) {
+			return nil, errors.New("truncated pack index")
+		}
+		pack.names = body[:count*hashSize]
+		small := body[count*(hashSize+4):]
+		large := small[count*4:]
+		pack.offsets = make([]int64, count)
+		for i := 0; i < count; i++ {
+			offset := binary.BigEndian.Uint32(small[i*4:])
+			if offset&0x80000000 == 0 {
+				pack.offsets[i] = int64(offset)
+				continue
+			}
+			at := int(offset&0x7fffffff) * 8
+			if at+8 > len(large) {
+				return nil, errors.New("truncated pack index")
+			}
+			pack.offsets[i] = int64(binary.BigEndian.Uint64(large[at:]))
+		}
+	} else {
+		// v1: 每个条目为 4 字节偏移加对象哈希
+		entrySize := 4 + hashSize
+		if len(body) < count*entrySize {
+			return nil, errors.New("truncated pack index")
+		}
+		pack.names = make([]byte, 0, count*hashSize)
+		pack.offsets = make([]int64, count)
+		for i := 0; i < count; i++ {
+			entry := body[i*entrySize:]
+			pack.offsets[i] = int64(binary.BigEndian.Uint32(entry))
+			pack.names = append(pack.names, entry[4:entrySize]...)
+		}
+	}
+
+	pack.file, err = os.Open(pack.path)
+	if err != nil {
+		return nil, err
+	}
+	return pack, nil
+}
+
+// find 在索引中二分查找对象哈希，返回其在 packfile 中的偏移
+func (p *packFile) find(hash []byte, hashSize int) (int64, bool) {
+	lo := 0
+	if hash[0] > 0 {
+		lo = int(p.fanout[hash[0]-1])
+	}
+	hi := int(p.fanout[hash[0]])
+	i := lo + sort.Search(hi-lo, func(i int) bool {
+		return bytes.Compare(p.name(lo+i, hashSize), hash) >= 0
+	})
+	if i < hi && bytes.Equal(p.name(i, hashSize), hash) {
+		return p.offsets[i], true
+	}
+	return 0, false
+}
+
+// name 返回索引中第 i 个对象的哈希
+func (p *packFile) name(i, hashSize int) []byte {
+	return p.names[i*hashSize : (i+1)*hashSize]
+}
+
+// readPacked 读取 packfile 中指定偏移的对象，增量对象递归读取基础对象后应用增量
+func (r *Repository) readPacked(pack *packFile, offset int64, depth int) (int, []byte, error) {
+	if depth > maxDeltaDepth {
+		return 0, nil, errors.New("delta chain too deep")
+	}
+	key := pack.path + "@" + strconv.FormatInt(offset, 10)
+	if objType, data, ok := r.bases.get(key); ok {
+		return objType, data, nil
+	}
+
+	reader := bufio.NewReader(io.NewSectionReader(pack.file, offset, 1<<62))
+	objType, size, err := readEntryHeader(reader)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var data []byte
+	switch objType {
+	case objCommit, objTree, objBlob, objTag:
+		data, err = inflate(reader, size)
+		if err != nil {
+			return 0, nil, err
+		}
+	case objOfsDelta, objRefDelta:
+		var (
+			baseType int
+			base     []byte
+		)
+		if objType == objOfsDelta {
+			distance, err := readOffsetDistance(reader)
+			if err != nil {
+				return 0, nil, err
+			}
+			if distance <= 0 || distance > offset {
+				return 0, nil, errors.New("invalid delta base offset")
+			}
+			baseType, base, err = r.readPacked(pack, offset-distance, depth+1)
+			if err != nil {
+				return 0, nil, err
+			}
+		} else {
+			baseHash := make([]byte, r.hashSize)
+			if _, err := io.ReadFull(reader, baseHash); err != nil {
+				return 0, nil, err
+			}
+			baseType, base, err = r.readObject(hex.EncodeToString(baseHash))
+			if err != nil {
+				return 0, nil, err
+			}
+		}
+		delta, err := inflate(reader, size)
+		if err != nil {
+			return 0, nil, err
+		}
+		if data, err = applyDelta(base, delta); err != nil {
+			return 0, nil, err
+		}
+		objType = baseType
+	default:
+		return 0, nil, fmt.Errorf("unknown pack object type %d", objType)
+	}
+	// 只缓存作为增量基础对象读取的对象
+	if depth > 0 {
+		r.bases.put(key, objType, data)
+	}
+	return objType, data, nil
+}
+
+// readEntryHeader 读取 packfile 对象头：首字节的 4-6 位为类型，其余各字节的低 7 位依次拼接为解压后的大小
+func readEntryHeader(reader io.ByteReader) (int, int64, error) {
+	c, err := reader.ReadByte()
+	if err != nil {
+		return 0, 0, err
+	}
+	objType := int(c>>4) & 7
+	size := int64(c & 0x0f)
+	for shift := 4; c&0x80 != 0; shift += 7 {
+		if c, err = reader.ReadByte(); err != nil {
+			return 0, 0, err
+		}
+		size |= int64(c&0x7f) << shift
+	}
+	return objType, size, nil
+}
+
+// readOffsetDistance 读取 ofs-delta 中基础对象相对于当前对象的距离
+func readOffsetDistance(reader io.ByteReader) (int64, error) {
+	c, err := reader.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	distance := int64(c & 0x7f)
+	for c&0x80 != 0 {
+		if c, err = reader.ReadByte(); err != nil {
+			return 0, err
+		}
+		distance = ((distance + 1) << 7) | int64(c&0x7f)
+	}
+	return distance, nil
+}
+
+// inflate 解压 zlib 数据并检查解压后的大小
+func inflate(reader io.Reader, size int64) ([]byte, error) {
+	zr, err := zlib.NewReader(reader)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	data := make([]byte, size)
+	if _, err := io.ReadFull(zr, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// applyDelta 将增量指令应用到基础对象：最高位置位的指令从基础对象复制一段数据，其余指令插入其后的字面数据
+func applyDelta(base, delta []byte) ([]byte, error) {
+	errCorrupt := errors.New("corrupt delta")
+	srcSize, n := binary.Uvarint(delta)
+	if n <= 0 || srcSize != uint64(len(base)) {
+		return nil, errCorrupt
+	}
+	delta = delta[n:]
+	dstSize, n := binary.Uvarint(delta)
+	if n <= 0 {
+		return nil, errCorrupt
+	}
+	delta = delta[n:]
+
+	// 目标大小来自对象数据，预分配的容量不超过基础对象与增量数据之和的上限
+	out := make([]byte, 0, min(dstSize, uint64(len(base)+len(delta)*0x10000)))
+	for len(delta) > 0 {
+		op := delta[0]
+		delta = delta[1:]
+		switch {
+		case op&0x80 != 0:
+			var offset, size uint64
+			for i := 0; i < 7; i++ {
+				if op&(1<<i) == 0 {
+					continue
+				}
+				if len(delta) == 0 {
+					return nil, errCorrupt
+				}
+				if i < 4 {
+					offset |= uint64(delta[0]) << (8 * i)
+				} else {
+					size |= uint64(delta[0]) << (8 * (i - 4))
+				}
+				delta = delta[1:]
+			}
+			if size == 0 {
+				size = 0x10000
+			}
+			if offset+size > uint64(len(base)) {
+				return nil, errCorrupt
+			}
+			out = append(out, base[offset:offset+size]...)
+		case op != 0:
+			if int(op) > len(delta) {
+				return nil, errCorrupt
+			}
+			out = append(out, delta[:op]...)
+			delta = delta[op:]
+		default:
+			return nil, errCorrupt
+		}
+	}
+	if uint64(len(out)) != dstSize {
+		return nil, errCorrupt
+	}
+	return out, nil
+}
+
+// objectCache 按插入顺序淘汰的对象缓存，缓存总字节数不超过 maxBytes
+type objectCache struct {
+	mu       sync.Mutex
+	maxBytes int
+	size     int
+	keys     []string
+	entries  map[string]cachedObject
+}
+
+// cachedObject 缓存的对象类型与内容
+type cachedObject struct {
+	objType int
+	data    []byte
+}
+
+func newObjectCache(maxBytes int) *objectCache {
+	return &objectCache{maxBytes: maxBytes, entries: make(map[string]cachedObject)}
+}
+
+// get 读取缓存的对象
+func (c *objectCache) get(key string) (int, []byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	return entry.objType, entry.data, ok
+}
+
+// put 缓存对象，超出容量时淘汰最早缓存的对象
+func (c *objectCache) put(key string, objType int, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; ok || len(data) > c.maxBytes {
+		return
+	}
+	for c.size+len(data) > c.maxBytes && len(c.keys) > 0 {
+		oldest := c.keys[0]
+		c.keys = c.keys[1:]
+		c.size -= len(c.entries[oldest].data)
+		delete(c.entries, oldest)
+	}
+	c.keys = append(c.keys, key)
+	c.entries[key] = cachedObject{objType: objType, data: data}
+	c.size += len(data)
+}
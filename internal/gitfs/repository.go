@@ -0,0 +1,176 @@
+// Package gitfs 直接读取本地 git 仓库的对象库（松散对象与 packfile），
+// 将任意提交的文件树以只读的 io/fs.FS 提供给分析流程，不需要检出工作区。
+package gitfs
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ErrObjectNotFound 对象库中不存在指定的对象
+var ErrObjectNotFound = errors.New("object not found")
+
+// maxRefDepth 解析符号引用时的最大跳转次数
+const maxRefDepth = 10
+
+// Repository 本地 git 仓库
+// - Path: 工作区或裸仓库的绝对路径
+// - gitDir: 仓库目录（通常为 .git），HEAD 从中读取
+// - commonDir: 对象与引用所在的目录，链接工作区中指向主仓库的 .git 目录，其余情况与 gitDir 相同
+// - hashSize: 对象哈希的字节数，SHA-1 仓库为 20，SHA-256 仓库为 32
+type Repository struct {
+	Path       string
+	gitDir     string
+	commonDir  string
+	objectDirs []string
+	hashSize   int
+
+	packsOnce sync.Once
+	packs     []*packFile
+	packsErr  error
+
+	mu    sync.Mutex
+	trees map[string]*tree
+	bases *objectCache
+}
+
+// Open 打开 path 处的 git 仓库，path 可以是工作区根目录（包含 .git 目录或 gitdir 文件）或裸仓库目录
+func Open(path string) (*Repository, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	gitDir, err := findGitDir(absPath)
+	if err != nil {
+		return nil, err
+	}
+	repo := &Repository{
+		Path:      absPath,
+		gitDir:    gitDir,
+		commonDir: gitDir,
+		hashSize:  20,
+		trees:     make(map[string]*tree),
+		bases:     newObjectCache(maxCacheBytes),
+	}
+	// 链接工作区（git worktree）的对象与引用位于主仓库
+	if content, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		common := strings.TrimSpace(string(content))
+		if !filepath.IsAbs(common) {
+			common = filepath.Join(gitDir, common)
+		}
+		repo.commonDir = filepath.Clean(common)
+	}
+	if format := configValue(filepath.Join(repo.commonDir, "config"), "extensions", "objectformat"); strings.EqualFold(format, "sha256") {
+		repo.hashSize = 32
+	}
+	repo.objectDirs = objectDirs(filepath.Join(repo.commonDir, "objects"))
+	return repo, nil
+}
+
+// Close 关闭已打开的 packfile
+func (r *Repository) Close() error {
+	var firstErr error
+	for _, pack := range r.packs {
+		if err := pack.file.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// findGitDir 查找工作区的 .git 目录或 gitdir 文件指向的目录，path 本身为裸仓库时返回 path
+func findGitDir(path string) (string, error) {
+	dotGit := filepath.Join(path, ".git")
+	if info, err := os.Stat(dotGit); err == nil {
+		if info.IsDir() {
+			return dotGit, nil
+		}
+		// 子模块与链接工作区的 .git 为 "gitdir: <path>" 格式的文件
+		content, err := os.ReadFile(dotGit)
+		if err != nil {
+			return "", err
+		}
+		dir, ok := strings.CutPrefix(strings.TrimSpace(string(content)), "gitdir:")
+		if !ok {
+			return "", fmt.Errorf("%s is not a valid gitdir file", dotGit)
+		}
+		dir = strings.TrimSpace(dir)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(path, dir)
+		}
+		return filepath.Clean(dir), nil
+	}
+	if isGitDir(path) {
+		return path, nil
+	}
+	return "", fmt.Errorf("%s is not a git repository", path)
+}
+
+// isGitDir 判断目录是否包含 HEAD 文件与 objects 目录
+func isGitDir(dir string) bool {
+	if _, err := os.Stat(filepath.Join(dir, "HEAD")); err != nil {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(dir, "objects"))
+	return err == nil && info.IsDir()
+}
+
+// objectDirs 返回对象目录及 objects/info/alternates 中声明的备用对象目录
+func objectDirs(objectsDir string) []string {
+	dirs := []string{objectsDir}
+	seen := map[string]bool{objectsDir: true}
+	for i := 0; i < len(dirs); i++ {
+		content, err := os.ReadFile(filepath.Join(dirs[i], "info", "alternates"))
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(content), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || line[0] == '#' {
+				continue
+			}
+			if !filepath.IsAbs(line) {
+				line = filepath.Join(dirs[i], line)
+			}
+			line = filepath.Clean(line)
+			if !seen[line] {
+				seen[line] = true
+				dirs = append(dirs, line)
+			}
+		}
+	}
+	return dirs
+}
+
+// configValue 读取 git 配置文件中指定节的键值，节名与键名不区分大小写，未找到时返回空字符串
+func configValue(configPath, section, key string) string {
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return ""
+	}
+	var current string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			// 子节 [remote "origin"] 只比较节名
+			name, _, _ := strings.Cut(strings.TrimSpace(strings.Trim(line, "[]")), " ")
+			current = strings.ToLower(name)
+			continue
+		}
+		name, value, _ := strings.Cut(line, "=")
+		if current == section && strings.EqualFold(strings.TrimSpace(name), key) {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,392 @@
+package gitfs
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxSymlinkHops 在文件树中解析符号链接时的最大跳转次数
+const maxSymlinkHops = 40
+
+// 树条目的文件模式
+const (
+	modeTree       = 0o040000
+	modeBlob       = 0o100644
+	modeExecutable = 0o100755
+	modeSymlink    = 0o120000
+	modeGitlink    = 0o160000
+)
+
+// treeEntry 树对象中的一个条目
+type treeEntry struct {
+	name string
+	mode uint32
+	hash string
+}
+
+// tree 解析后的树对象，条目按名称排序
+type tree struct {
+	entries []treeEntry
+	byName  map[string]int
+}
+
+// TreeFS 以只读的 io/fs.FS 访问某个提交的文件树，同时实现 fs.ReadDirFS、fs.ReadFileFS、fs.StatFS 与 fs.ReadLinkFS。
+// 文件的修改时间为提交时间，FileInfo.Sys() 返回文件的 blob 哈希；子模块不出现在文件树中；
+// 符号链接在 Open、ReadFile 与 Stat 时于文件树内解析，指向文件树之外的链接视为不存在
+type TreeFS struct {
+	repo    *Repository
+	root    string
+	modTime time.Time
+}
+
+// TreeFS 返回提交的文件树
+func (r *Repository) TreeFS(commit *Commit) *TreeFS {
+	return &TreeFS{repo: r, root: commit.Tree, modTime: commit.Date}
+}
+
+// readTree 读取并缓存树对象
+func (r *Repository) readTree(hash string) (*tree, error) {
+	r.mu.Lock()
+	cached, ok := r.trees[hash]
+	r.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	data, err := r.readTypedObject(hash, objTree)
+	if err != nil {
+		return nil, err
+	}
+	parsed := &tree{byName: make(map[string]int)}
+	// 每个条目为 "<八进制模式> <名称>\x00<哈希>"
+	for len(data) > 0 {
+		header, rest, ok := bytes.Cut(data, []byte{0})
+		if !ok || len(rest) < r.hashSize {
+			return nil, fmt.Errorf("corrupt tree %s", hash)
+		}
+		modeText, name, ok := strings.Cut(string(header), " ")
+		if !ok {
+			return nil, fmt.Errorf("corrupt tree %s", hash)
+		}
+		var mode uint32
+		if _, err := fmt.Sscanf(modeText, "%o", &mode); err != nil {
+			return nil, fmt.Errorf("corrupt tree %s: %v", hash, err)
+		}
+		entry := treeEntry{name: name, mode: mode, hash: hex.EncodeToString(rest[:r.hashSize])}
+		data = rest[r.hashSize:]
+		// 子模块指向其他仓库的提交，以及名称不合法的条目不出现在文件树中
+		if mode == modeGitlink || name == "" || name == "." || name == ".." || strings.Contains(name, "/") {
+			continue
+		}
+		parsed.entries = append(parsed.entries, entry)
+	}
+	sort.Slice(parsed.entries, func(i, j int) bool { return parsed.entries[i].name < parsed.entries[j].name })
+	for i, entry := range parsed.entries {
+		parsed.byName[entry.name] = i
+	}
+
+	r.mu.Lock()
+	r.trees[hash] = parsed
+	r.mu.Unlock()
+	return parsed, nil
+}
+
+// lookup 查找路径对应的条目，路径中间的符号链接总是解析，follow 为 true 时同时解析最后一级的符号链接
+func (t *TreeFS) lookup(name string, follow bool) (treeEntry, error) {
+	root := treeEntry{name: ".", mode: modeTree, hash: t.root}
+	if name == "." {
+		return root, nil
+	}
+	parts := strings.Split(name, "/")
+	for hops := 0; hops <= maxSymlinkHops; hops++ {
+		entry, rest, err := t.walk(root, parts, follow)
+		if err != nil {
+			return treeEntry{}, err
+		}
+		if rest == nil {
+			return entry, nil
+		}
+		parts = rest
+	}
+	return treeEntry{}, errors.New("too many levels of symbolic links")
+}
+
+// walk 逐级查找路径，遇到需要解析的符号链接时返回替换后的完整路径
+func (t *TreeFS) walk(root treeEntry, parts []string, follow bool) (treeEntry, []string, error) {
+	current := root
+	for i, part := range parts {
+		if current.mode != modeTree {
+			return treeEntry{}, nil, fs.ErrNotExist
+		}
+		dir, err := t.repo.readTree(current.hash)
+		if err != nil {
+			return treeEntry{}, nil, err
+		}
+		idx, ok := dir.byName[part]
+		if !ok {
+			return treeEntry{}, nil, fs.ErrNotExist
+		}
+		current = dir.entries[idx]
+		last := i == len(parts)-1
+		if current.mode != modeSymlink || (last && !follow) {
+			continue
+		}
+		target, err := t.repo.readTypedObject(current.hash, objBlob)
+		if err != nil {
+			return treeEntry{}, nil, err
+		}
+		// 相对链接相对于链接所在目录解析，绝对链接与越出文件树的链接无法在提交中解析
+		resolved := path.Join(path.Join(parts[:i]...), string(target))
+		if path.IsAbs(string(target)) || resolved == ".." || strings.HasPrefix(resolved, "../") {
+			return treeEntry{}, nil, fs.ErrNotExist
+		}
+		next := append(strings.Split(resolved, "/"), parts[i+1:]...)
+		if resolved == "." {
+			next = parts[i+1:]
+			if len(next) == 0 {
+				return root, nil, nil
+			}
+		}
+		return treeEntry{}, next, nil
+	}
+	return current, nil, nil
+}
+
+// Open 打开文件或目录，符号链接被解析为其指向的条目
+func (t *TreeFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	entry, err := t.lookup(name, true)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	if entry.mode == modeTree {
+		entries, err := t.readDir(entry)
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+		return &dirFile{info: t.fileInfo(path.Base(name), entry, 0), entries: entries}, nil
+	}
+	data, err := t.repo.readTypedObject(entry.hash, objBlob)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &blobFile{info: t.fileInfo(path.Base(name), entry, int64(len(data))), Reader: bytes.NewReader(data)}, nil
+}
+
+// ReadFile 读取文件内容
+func (t *TreeFS) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrInvalid}
+	}
+	entry, err := t.lookup(name, true)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: err}
+	}
+	if entry.mode == modeTree {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: errors.New("is a directory")}
+	}
+	data, err := t.repo.readTypedObject(entry.hash, objBlob)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: err}
+	}
+	return data, nil
+}
+
+// ReadDir 读取目录中的条目，条目按名称排序，符号链接不被解析
+func (t *TreeFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	entry, err := t.lookup(name, true)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	if entry.mode != modeTree {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+	entries, err := t.readDir(entry)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	return entries, nil
+}
+
+// Stat 返回文件信息，符号链接被解析为其指向的条目
+func (t *TreeFS) Stat(name string) (fs.FileInfo, error) {
+	return t.stat("stat", name, true)
+}
+
+// Lstat 返回文件信息，不解析最后一级的符号链接
+func (t *TreeFS) Lstat(name string) (fs.FileInfo, error) {
+	return t.stat("lstat", name, false)
+}
+
+// ReadLink 返回符号链接的目标
+func (t *TreeFS) ReadLink(name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+	entry, err := t.lookup(name, false)
+	if err != nil {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: err}
+	}
+	if entry.mode != modeSymlink {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+	target, err := t.repo.readTypedObject(entry.hash, objBlob)
+	if err != nil {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: err}
+	}
+	return string(target), nil
+}
+
+// BlobHash 返回文件的 blob 哈希，内容相同的文件在不同提交中哈希相同
+func (t *TreeFS) BlobHash(name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+	entry, err := t.lookup(name, true)
+	if err != nil {
+		return "", &fs.PathError{Op: "stat", Path: name, Err: err}
+	}
+	if entry.mode == modeTree {
+		return "", &fs.PathError{Op: "stat", Path: name, Err: errors.New("is a directory")}
+	}
+	return entry.hash, nil
+}
+
+// stat 查找条目并生成文件信息，文件大小需要读取 blob
+func (t *TreeFS) stat(op, name string, follow bool) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	entry, err := t.lookup(name, follow)
+	if err != nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	info, err := t.entryInfo(path.Base(name), entry)
+	if err != nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	return info, nil
+}
+
+// readDir 生成目录条目列表
+func (t *TreeFS) readDir(entry treeEntry) ([]fs.DirEntry, error) {
+	dir, err := t.repo.readTree(entry.hash)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]fs.DirEntry, 0, len(dir.entries))
+	for _, child := range dir.entries {
+		entries = append(entries, &dirEntry{fsys: t, entry: child})
+	}
+	return entries, nil
+}
+
+// entryInfo 生成条目的文件信息，非目录条目读取 blob 获得大小
+func (t *TreeFS) entryInfo(name string, entry treeEntry) (fs.FileInfo, error) {
+	var size int64
+	if entry.mode != modeTree {
+		data, err := t.repo.readTypedObject(entry.hash, objBlob)
+		if err != nil {
+			return nil, err
+		}
+		size = int64(len(data))
+	}
+	return t.fileInfo(name, entry, size), nil
+}
+
+// fileInfo 生成文件信息
+func (t *TreeFS) fileInfo(name string, entry treeEntry, size int64) *fileInfo {
+	return &fileInfo{name: name, size: size, mode: fileMode(entry.mode), modTime: t.modTime, hash: entry.hash}
+}
+
+// fileMode 将树条目的模式转换为 fs.FileMode
+func fileMode(mode uint32) fs.FileMode {
+	switch mode {
+	case modeTree:
+		return fs.ModeDir | 0o555
+	case modeSymlink:
+		return fs.ModeSymlink | 0o777
+	case modeExecutable:
+		return 0o555
+	}
+	return 0o444
+}
+
+// fileInfo 树条目的文件信息
+type fileInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+	hash    string
+}
+
+func (i *fileInfo) Name() string       { return i.name }
+func (i *fileInfo) Size() int64        { return i.size }
+func (i *fileInfo) Mode() fs.FileMode  { return i.mode }
+func (i *fileInfo) ModTime() time.Time { return i.modTime }
+func (i *fileInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i *fileInfo) Sys() any           { return i.hash }
+
+// dirEntry 目录中的条目，文件信息在需要时读取
+type dirEntry struct {
+	fsys  *TreeFS
+	entry treeEntry
+}
+
+func (e *dirEntry) Name() string               { return e.entry.name }
+func (e *dirEntry) IsDir() bool                { return e.entry.mode == modeTree }
+func (e *dirEntry) Type() fs.FileMode          { return fileMode(e.entry.mode).Type() }
+func (e *dirEntry) Info() (fs.FileInfo, error) { return e.fsys.entryInfo(e.entry.name, e.entry) }
+func (e *dirEntry) String() string             { return fs.FormatDirEntry(e) }
+
+// blobFile 打开的文件，内容已读入内存，支持随机读取
+type blobFile struct {
+	info *fileInfo
+	*bytes.Reader
+}
+
+func (f *blobFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *blobFile) Close() error               { return nil }
+
+// dirFile 打开的目录
+type dirFile struct {
+	info    *fileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *dirFile) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *dirFile) Close() error               { return nil }
+
+func (d *dirFile) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: errors.New("is a directory")}
+}
+
+// ReadDir 按 fs.ReadDirFile 的约定分批返回目录条目
+func (d *dirFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(remaining))
+	d.offset += n
+	return remaining[:n], nil
+}
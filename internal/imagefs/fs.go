@@ -0,0 +1,152 @@
+package imagefs
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"time"
+)
+
+// FS 合并各层后的镜像文件系统，以只读的 io/fs.FS 访问，同时实现 fs.ReadDirFS、fs.ReadFileFS 与 fs.StatFS。
+// 指向镜像内普通文件的符号链接与硬链接表现为普通文件，其他符号链接以及设备文件等特殊文件不出现在文件系统中；
+// 文件内容在读取时从镜像压缩包中按偏移读取，可以并发读取
+type FS struct {
+	root   *node
+	closer io.Closer
+}
+
+// Close 关闭镜像压缩包，之后无法再读取文件内容
+func (f *FS) Close() error {
+	return f.closer.Close()
+}
+
+// lookup 查找路径对应的条目
+func (f *FS) lookup(op, name string) (*node, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	entry := lookupNode(f.root, name)
+	if entry == nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	return entry, nil
+}
+
+// Open 打开文件或目录
+func (f *FS) Open(name string) (fs.File, error) {
+	entry, err := f.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+	info := &fileInfo{name: path.Base(name), node: entry}
+	if entry.mode.IsDir() {
+		return &dirFile{info: info, entries: readDir(entry)}, nil
+	}
+	return &regularFile{info: info, SectionReader: io.NewSectionReader(entry.content, entry.offset, entry.size)}, nil
+}
+
+// ReadFile 读取文件内容
+func (f *FS) ReadFile(name string) ([]byte, error) {
+	entry, err := f.lookup("readfile", name)
+	if err != nil {
+		return nil, err
+	}
+	if entry.mode.IsDir() {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: errors.New("is a directory")}
+	}
+	data := make([]byte, entry.size)
+	if _, err := io.ReadFull(io.NewSectionReader(entry.content, entry.offset, entry.size), data); err != nil {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: err}
+	}
+	return data, nil
+}
+
+// ReadDir 读取目录中的条目，条目按名称排序
+func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entry, err := f.lookup("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if !entry.mode.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+	return readDir(entry), nil
+}
+
+// Stat 返回文件信息
+func (f *FS) Stat(name string) (fs.FileInfo, error) {
+	entry, err := f.lookup("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return &fileInfo{name: path.Base(name), node: entry}, nil
+}
+
+// readDir 生成按名称排序的目录条目列表
+func readDir(dir *node) []fs.DirEntry {
+	entries := make([]fs.DirEntry, 0, len(dir.children))
+	for name, child := range dir.children {
+		entries = append(entries, fs.FileInfoToDirEntry(&fileInfo{name: name, node: child}))
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries
+}
+
+// fileInfo 条目的文件信息，镜像层不保留修改时间
+type fileInfo struct {
+	name string
+	node *node
+}
+
+func (i *fileInfo) Name() string       { return i.name }
+func (i *fileInfo) Size() int64        { return i.node.size }
+func (i *fileInfo) ModTime() time.Time { return time.Time{} }
+func (i *fileInfo) IsDir() bool        { return i.node.mode.IsDir() }
+func (i *fileInfo) Sys() any           { return nil }
+
+func (i *fileInfo) Mode() fs.FileMode {
+	if i.node.mode.IsDir() {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
+
+// regularFile 打开的文件，支持随机读取
+type regularFile struct {
+	info *fileInfo
+	*io.SectionReader
+}
+
+func (f *regularFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *regularFile) Close() error               { return nil }
+
+// dirFile 打开的目录
+type dirFile struct {
+	info    *fileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *dirFile) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *dirFile) Close() error               { return nil }
+
+func (d *dirFile) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: errors.New("is a directory")}
+}
+
+// ReadDir 按 fs.ReadDirFile 的约定分批返回目录条目
+func (d *dirFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(remaining))
+	d.offset += n
+	return remaining[:n], nil
+}
@@ -0,0 +1,319 @@
+// Package imagefs 离线读取 docker save 或 OCI 格式的容器镜像压缩包，
+// 在内存中按顺序合并各层并处理 whiteout，以只读的 io/fs.FS 提供镜像的最终文件系统，不将镜像内容写到磁盘。
+package imagefs
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// 镜像压缩包格式
+const (
+	FormatDockerArchive = "docker-archive"
+	FormatOCI           = "oci"
+)
+
+// dockerManifest docker save 生成的 manifest.json 中的条目
+type dockerManifest struct {
+	Config   string   `json:"Config"`
+	RepoTags []string `json:"RepoTags"`
+	Layers   []string `json:"Layers"`
+}
+
+// ociDescriptor OCI 内容描述符
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Annotations map[string]string `json:"annotations"`
+	Platform    *struct {
+		OS           string `json:"os"`
+		Architecture string `json:"architecture"`
+	} `json:"platform"`
+}
+
+// ociIndex OCI 镜像索引，index.json 与多架构镜像的清单列表均使用该结构
+type ociIndex struct {
+	Manifests []ociDescriptor `json:"manifests"`
+}
+
+// ociManifest OCI 镜像清单
+type ociManifest struct {
+	Config ociDescriptor   `json:"config"`
+	Layers []ociDescriptor `json:"layers"`
+}
+
+// imageConfig 镜像配置中与平台相关的字段
+type imageConfig struct {
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
+}
+
+// blob 镜像压缩包中的一个文件，内容位于 r 中 offset 开始的 size 个字节
+type blob struct {
+	r      io.ReaderAt
+	offset int64
+	size   int64
+}
+
+// section 返回可随机读取的文件内容
+func (b blob) section() *io.SectionReader {
+	return io.NewSectionReader(b.r, b.offset, b.size)
+}
+
+// archive 按路径索引的镜像压缩包中的文件
+type archive struct {
+	file  *os.File
+	blobs map[string]blob
+}
+
+// Open 读取镜像压缩包（可为 gzip 压缩），按层合并后返回镜像的文件系统与镜像信息，文件系统使用完毕后需要 Close。
+// 合并只在内存中记录各文件所在的层与偏移，未压缩的镜像压缩包中未压缩的层按偏移直接读取，不产生任何副本
+func Open(imagePath string) (*FS, *model.ImageInfo, error) {
+	a, err := readArchive(imagePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read image archive %s failed: %v", imagePath, err)
+	}
+	fsys, info, err := a.merge()
+	if err != nil {
+		a.file.Close()
+		return nil, nil, err
+	}
+	return fsys, info, nil
+}
+
+// merge 按清单顺序合并各层
+func (a *archive) merge() (*FS, *model.ImageInfo, error) {
+	info, layers, err := a.readManifest()
+	if err != nil {
+		return nil, nil, err
+	}
+	merger := newLayerMerger()
+	for _, layer := range layers {
+		b, ok := a.blobs[layer]
+		if !ok {
+			return nil, nil, fmt.Errorf("apply layer %s failed: layer not found in image", layer)
+		}
+		if err := merger.apply(b.section()); err != nil {
+			return nil, nil, fmt.Errorf("apply layer %s failed: %v", layer, err)
+		}
+	}
+	merger.linkSymlinks()
+	info.Layers = len(layers)
+	return &FS{root: merger.root, closer: a.file}, info, nil
+}
+
+// readArchive 索引镜像压缩包中的普通文件。镜像压缩包中的层按任意顺序存放，索引后再按清单顺序读取；
+// 未压缩的压缩包记录各文件的偏移，gzip 压缩的压缩包无法随机读取，文件内容读入内存
+func readArchive(imagePath string) (*archive, error) {
+	file, err := os.Open(imagePath)
+	if err != nil {
+		return nil, err
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	a := &archive{file: file, blobs: make(map[string]blob)}
+
+	magic := make([]byte, 4)
+	n, _ := file.ReadAt(magic, 0)
+	var (
+		reader  io.Reader
+		section *io.SectionReader
+	)
+	if isCompressed(magic[:n]) {
+		if reader, err = decompress(file); err != nil {
+			file.Close()
+			return nil, err
+		}
+	} else {
+		section = io.NewSectionReader(file, 0, stat.Size())
+		reader = section
+	}
+
+	tr := tar.NewReader(reader)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return a, nil
+		}
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		name := cleanName(header.Name)
+		if name == "" || header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if section == nil {
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				file.Close()
+				return nil, err
+			}
+			a.blobs[name] = blob{r: bytes.NewReader(data), size: int64(len(data))}
+			continue
+		}
+		// tar 读取器不预读，读取文件头后的位置即为文件内容的偏移
+		offset, err := section.Seek(0, io.SeekCurrent)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		a.blobs[name] = blob{r: file, offset: offset, size: header.Size}
+	}
+}
+
+// readFile 读取镜像压缩包中的文件
+func (a *archive) readFile(name string) ([]byte, error) {
+	b, ok := a.blobs[name]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return io.ReadAll(b.section())
+}
+
+// readManifest 根据 manifest.json 或 index.json 确定镜像格式、引用名称与按顺序排列的层
+func (a *archive) readManifest() (*model.ImageInfo, []string, error) {
+	if content, err := a.readFile("manifest.json"); err == nil {
+		var manifests []dockerManifest
+		if err := json.Unmarshal(content, &manifests); err != nil {
+			return nil, nil, fmt.Errorf("parse manifest.json failed: %v", err)
+		}
+		if len(manifests) == 0 {
+			return nil, nil, errors.New("manifest.json contains no image")
+		}
+		manifest := manifests[0]
+		info := &model.ImageInfo{Format: FormatDockerArchive}
+		if len(manifest.RepoTags) > 0 {
+			info.Reference = manifest.RepoTags[0]
+		}
+		a.readConfig(cleanName(manifest.Config), info)
+		var layers []string
+		for _, layer := range manifest.Layers {
+			layers = append(layers, cleanName(layer))
+		}
+		return info, layers, nil
+	}
+
+	content, err := a.readFile("index.json")
+	if err != nil {
+		return nil, nil, errors.New("neither manifest.json nor index.json found, not a docker-archive or OCI image")
+	}
+	var index ociIndex
+	if err := json.Unmarshal(content, &index); err != nil {
+		return nil, nil, fmt.Errorf("parse index.json failed: %v", err)
+	}
+	info := &model.ImageInfo{Format: FormatOCI}
+	// 多架构镜像的索引可以嵌套，逐层选择 linux 平台的第一个清单
+	for depth := 0; depth < 4; depth++ {
+		descriptor, ok := selectManifest(index.Manifests)
+		if !ok {
+			return nil, nil, errors.New("index.json contains no image manifest")
+		}
+		if ref := descriptor.Annotations["org.opencontainers.image.ref.name"]; ref != "" && info.Reference == "" {
+			info.Reference = ref
+		}
+		content, err := a.readFile(blobPath(descriptor.Digest))
+		if err != nil {
+			return nil, nil, fmt.Errorf("read manifest %s failed: %v", descriptor.Digest, err)
+		}
+		if strings.Contains(descriptor.MediaType, "index") || strings.Contains(descriptor.MediaType, "manifest.list") {
+			if err := json.Unmarshal(content, &index); err != nil {
+				return nil, nil, fmt.Errorf("parse image index %s failed: %v", descriptor.Digest, err)
+			}
+			continue
+		}
+		var manifest ociManifest
+		if err := json.Unmarshal(content, &manifest); err != nil {
+			return nil, nil, fmt.Errorf("parse image manifest %s failed: %v", descriptor.Digest, err)
+		}
+		a.readConfig(blobPath(manifest.Config.Digest), info)
+		var layers []string
+		for _, layer := range manifest.Layers {
+			layers = append(layers, blobPath(layer.Digest))
+		}
+		return info, layers, nil
+	}
+	return nil, nil, errors.New("image index nested too deeply")
+}
+
+// selectManifest 选择 linux 平台的第一个清单，没有平台信息时选择第一个清单
+func selectManifest(manifests []ociDescriptor) (ociDescriptor, bool) {
+	for _, descriptor := range manifests {
+		if descriptor.Platform == nil || descriptor.Platform.OS == "linux" {
+			return descriptor, true
+		}
+	}
+	if len(manifests) > 0 {
+		return manifests[0], true
+	}
+	return ociDescriptor{}, false
+}
+
+// readConfig 从镜像配置中读取操作系统与架构，配置缺失时忽略
+func (a *archive) readConfig(name string, info *model.ImageInfo) {
+	content, err := a.readFile(name)
+	if err != nil {
+		return
+	}
+	var config imageConfig
+	if json.Unmarshal(content, &config) == nil {
+		info.OS = config.OS
+		info.Architecture = config.Architecture
+	}
+}
+
+// blobPath 返回 OCI 摘要在镜像压缩包中的路径，如 sha256:abc 对应 blobs/sha256/abc
+func blobPath(digest string) string {
+	algorithm, hex, _ := strings.Cut(digest, ":")
+	return path.Join("blobs", cleanName(algorithm), cleanName(hex))
+}
+
+// isGzip 根据文件头判断是否为 gzip 压缩
+func isGzip(magic []byte) bool {
+	return len(magic) >= 2 && magic[0] == 0x1f && magic[1] == 0x8b
+}
+
+// isZstd 根据文件头判断是否为 zstd 压缩
+func isZstd(magic []byte) bool {
+	return len(magic) >= 4 && magic[0] == 0x28 && magic[1] == 0xb5 && magic[2] == 0x2f && magic[3] == 0xfd
+}
+
+// isCompressed 根据文件头判断内容是否经过压缩
+func isCompressed(magic []byte) bool {
+	return isGzip(magic) || isZstd(magic)
+}
+
+// decompress 根据文件头识别 gzip 压缩，未压缩的 tar 原样返回
+func decompress(r io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(r)
+	magic, err := buffered.Peek(4)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	switch {
+	case isGzip(magic):
+		return gzip.NewReader(buffered)
+	case isZstd(magic):
+		return nil, errors.New("zstd compressed layers are not supported")
+	}
+	return buffered, nil
+}
+
+// cleanName 规范化压缩包中的路径并去除前导的 "/"，确保路径不会越出镜像根目录
+func cleanName(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(name, "\\", "/")), "/")
+}
@@ -0,0 +1,178 @@
+package imagefs
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// tarEntry 测试用的 tar 条目，Link 非空时为符号链接
+type tarEntry struct {
+	Name    string
+	Content string
+	Link    string
+	Dir     bool
+}
+
+// buildTar 生成 tar 内容，gzipped 为 true 时使用 gzip 压缩
+func buildTar(t *testing.T, entries []tarEntry, gzipped bool) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var tw *tar.Writer
+	var gz *gzip.Writer
+	if gzipped {
+		gz = gzip.NewWriter(&buf)
+		tw = tar.NewWriter(gz)
+	} else {
+		tw = tar.NewWriter(&buf)
+	}
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.Name, Mode: 0644, Typeflag: tar.TypeReg, Size: int64(len(entry.Content))}
+		switch {
+		case entry.Dir:
+			header = &tar.Header{Name: entry.Name, Mode: 0755, Typeflag: tar.TypeDir}
+		case entry.Link != "":
+			header = &tar.Header{Name: entry.Name, Mode: 0777, Typeflag: tar.TypeSymlink, Linkname: entry.Link}
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatalf("write tar header failed: %v", err)
+		}
+		if header.Typeflag == tar.TypeReg {
+			if _, err := tw.Write([]byte(entry.Content)); err != nil {
+				t.Fatalf("write tar content failed: %v", err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("close tar failed: %v", err)
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			t.Fatalf("close gzip failed: %v", err)
+		}
+	}
+	return buf.Bytes()
+}
+
+// testLayers 两层镜像：第二层删除 app/old.txt、将 cache 目录（包括本层写入的子目录）设为不透明并覆盖 app/config.json
+func testLayers(t *testing.T) [][]byte {
+	base := buildTar(t, []tarEntry{
+		{Name: "etc/", Dir: true},
+		{Name: "etc/os-release", Link: "../usr/lib/os-release"},
+		{Name: "usr/lib/os-release", Content: "NAME=\"Debian GNU/Linux\"\nVERSION_ID=\"12\"\n"},
+		{Name: "app/old.txt", Content: "old"},
+		{Name: "app/config.json", Content: `{"v": 1}`},
+		{Name: "cache/stale.bin", Content: "stale"},
+		{Name: "cache/sub/stale.bin", Content: "stale"},
+		{Name: "dev/null", Link: "/proc/self/fd/0"},
+	}, false)
+	top := buildTar(t, []tarEntry{
+		{Name: "app/.wh.old.txt"},
+		{Name: "app/config.json", Content: `{"v": 2}`},
+		{Name: "cache/fresh.bin", Content: "fresh"},
+		{Name: "cache/sub/fresh.bin", Content: "fresh"},
+		{Name: "cache/.wh..wh..opq"},
+		{Name: "../../escape.txt", Content: "clamped"},
+	}, true)
+	return [][]byte{base, top}
+}
+
+func TestOpenDockerArchive(t *testing.T) {
+	layers := testLayers(t)
+	manifest, _ := json.Marshal([]map[string]any{{
+		"Config":   "config.json",
+		"RepoTags": []string{"acme/app:1.0"},
+		"Layers":   []string{"l1/layer.tar", "l2/layer.tar"},
+	}})
+	archive := buildTar(t, []tarEntry{
+		{Name: "l2/layer.tar", Content: string(layers[1])},
+		{Name: "l1/layer.tar", Content: string(layers[0])},
+		{Name: "config.json", Content: `{"os": "linux", "architecture": "amd64"}`},
+		{Name: "manifest.json", Content: string(manifest)},
+	}, false)
+
+	info, fsys := openTestImage(t, archive)
+	if info.Format != FormatDockerArchive || info.Reference != "acme/app:1.0" || info.Layers != 2 ||
+		info.OS != "linux" || info.Architecture != "amd64" {
+		t.Errorf("unexpected image info: %+v", info)
+	}
+	assertMergedTree(t, fsys)
+}
+
+func TestOpenOCILayout(t *testing.T) {
+	layers := testLayers(t)
+	manifest, _ := json.Marshal(map[string]any{
+		"config": map[string]string{"digest": "sha256:cfg"},
+		"layers": []map[string]string{{"digest": "sha256:aaa"}, {"digest": "sha256:bbb"}},
+	})
+	index, _ := json.Marshal(map[string]any{
+		"manifests": []map[string]any{{
+			"mediaType":   "application/vnd.oci.image.manifest.v1+json",
+			"digest":      "sha256:man",
+			"annotations": map[string]string{"org.opencontainers.image.ref.name": "1.0"},
+		}},
+	})
+	archive := buildTar(t, []tarEntry{
+		{Name: "oci-layout", Content: `{"imageLayoutVersion": "1.0.0"}`},
+		{Name: "index.json", Content: string(index)},
+		{Name: "blobs/sha256/man", Content: string(manifest)},
+		{Name: "blobs/sha256/cfg", Content: `{"os": "linux", "architecture": "arm64"}`},
+		{Name: "blobs/sha256/aaa", Content: string(layers[0])},
+		{Name: "blobs/sha256/bbb", Content: string(layers[1])},
+	}, true)
+
+	info, fsys := openTestImage(t, archive)
+	if info.Format != FormatOCI || info.Reference != "1.0" || info.Layers != 2 || info.Architecture != "arm64" {
+		t.Errorf("unexpected image info: %+v", info)
+	}
+	assertMergedTree(t, fsys)
+}
+
+// openTestImage 写入镜像压缩包并打开合并后的文件系统
+func openTestImage(t *testing.T, archive []byte) (*model.ImageInfo, *FS) {
+	t.Helper()
+	imagePath := filepath.Join(t.TempDir(), "image.tar")
+	if err := os.WriteFile(imagePath, archive, 0644); err != nil {
+		t.Fatalf("write image failed: %v", err)
+	}
+	fsys, info, err := Open(imagePath)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	t.Cleanup(func() { fsys.Close() })
+	return info, fsys
+}
+
+// assertMergedTree 检查合并后的文件系统
+func assertMergedTree(t *testing.T, fsys fs.FS) {
+	t.Helper()
+	files := map[string]string{
+		"app/config.json":     `{"v": 2}`,
+		"etc/os-release":      "NAME=\"Debian GNU/Linux\"\nVERSION_ID=\"12\"\n",
+		"cache/fresh.bin":     "fresh",
+		"cache/sub/fresh.bin": "fresh",
+		"escape.txt":          "clamped",
+	}
+	for name, want := range files {
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil || string(content) != want {
+			t.Errorf("%s = %q, %v; want %q", name, content, err, want)
+		}
+	}
+	for _, name := range []string{"app/old.txt", "cache/stale.bin", "cache/sub/stale.bin", "dev/null"} {
+		if _, err := fs.Stat(fsys, name); err == nil {
+			t.Errorf("%s should not exist in the merged tree", name)
+		}
+	}
+	if err := fstest.TestFS(fsys, "app/config.json", "etc/os-release", "cache/fresh.bin", "escape.txt", "usr/lib/os-release"); err != nil {
+		t.Errorf("merged tree is not a valid fs.FS: %v", err)
+	}
+}
@@ -0,0 +1,274 @@
+package imagefs
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+const (
+	// whiteoutPrefix 标记删除下层同名文件的 whiteout 文件前缀
+	whiteoutPrefix = ".wh."
+	// opaqueWhiteout 标记目录为不透明的 whiteout 文件，下层目录中的内容全部隐藏
+	opaqueWhiteout = ".wh..wh..opq"
+	// maxSymlinkHops 解析符号链接时的最大跳转次数
+	maxSymlinkHops = 40
+)
+
+// node 合并后文件树中的文件、目录或符号链接
+// - mode: 目录为 fs.ModeDir，符号链接为 fs.ModeSymlink，普通文件为 0
+// - children: 目录中的条目
+// - link: 符号链接的目标
+// - content/offset/size: 普通文件的内容位于 content 中 offset 开始的 size 个字节
+// - layer: 写入该条目或在其下写入内容的层序号，不透明目录只隐藏更早的层写入的内容
+type node struct {
+	mode     fs.FileMode
+	children map[string]*node
+	link     string
+	content  io.ReaderAt
+	offset   int64
+	size     int64
+	layer    int
+}
+
+func newDir(layer int) *node {
+	return &node{mode: fs.ModeDir, children: make(map[string]*node), layer: layer}
+}
+
+// layerMerger 将镜像各层依次合并到内存中的文件树
+type layerMerger struct {
+	root  *node
+	layer int
+}
+
+func newLayerMerger() *layerMerger {
+	return &layerMerger{root: newDir(0)}
+}
+
+// apply 合并一个层：whiteout 删除下层内容，其余条目覆盖同名路径。
+// 未压缩的层中的文件记录其在层中的偏移，gzip 压缩的层无法随机读取，文件内容读入内存
+func (m *layerMerger) apply(layer *io.SectionReader) error {
+	m.layer++
+	magic := make([]byte, 4)
+	n, _ := layer.ReadAt(magic, 0)
+	var reader io.Reader = layer
+	inMemory := isCompressed(magic[:n])
+	if inMemory {
+		decompressed, err := decompress(layer)
+		if err != nil {
+			return err
+		}
+		reader = decompressed
+	}
+
+	tr := tar.NewReader(reader)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := cleanName(header.Name)
+		if name == "" {
+			continue
+		}
+		dir, base := path.Dir(name), path.Base(name)
+
+		switch {
+		case base == opaqueWhiteout:
+			m.clearDir(m.lookup(dir))
+			continue
+		case strings.HasPrefix(base, whiteoutPrefix):
+			m.remove(joinName(dir, strings.TrimPrefix(base, whiteoutPrefix)))
+			continue
+		}
+
+		var entry *node
+		switch header.Typeflag {
+		case tar.TypeDir:
+			entry = newDir(m.layer)
+		case tar.TypeReg:
+			entry = &node{size: header.Size}
+			if inMemory {
+				data, err := io.ReadAll(tr)
+				if err != nil {
+					return err
+				}
+				entry.content = bytes.NewReader(data)
+			} else {
+				// tar 读取器不预读，读取文件头后的位置即为文件内容的偏移
+				if entry.offset, err = layer.Seek(0, io.SeekCurrent); err != nil {
+					return err
+				}
+				entry.content = layer
+			}
+		case tar.TypeLink:
+			source := m.lookup(cleanName(header.Linkname))
+			if source == nil || source.mode != 0 {
+				m.remove(name)
+				continue
+			}
+			linked := *source
+			entry = &linked
+		case tar.TypeSymlink:
+			entry = &node{mode: fs.ModeSymlink, link: header.Linkname}
+		default:
+			// 设备文件、FIFO 等特殊文件不参与分析
+			continue
+		}
+		m.put(name, entry)
+	}
+}
+
+// put 写入条目，必要时创建上级目录；已存在的目录被目录条目覆盖时保留其中的内容
+func (m *layerMerger) put(name string, entry *node) {
+	parent := m.root
+	parent.layer = m.layer
+	parts := strings.Split(name, "/")
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := parent.children[part]
+		if !ok || !child.mode.IsDir() {
+			child = newDir(m.layer)
+			parent.children[part] = child
+		}
+		child.layer = m.layer
+		parent = child
+	}
+	base := parts[len(parts)-1]
+	if existing, ok := parent.children[base]; ok && existing.mode.IsDir() && entry.mode.IsDir() {
+		existing.layer = m.layer
+		return
+	}
+	entry.layer = m.layer
+	parent.children[base] = entry
+}
+
+// lookup 查找路径对应的条目，不解析符号链接，不存在时返回 nil
+func (m *layerMerger) lookup(name string) *node {
+	return lookupNode(m.root, name)
+}
+
+// clearDir 删除目录中更早的层写入的内容，保留本层已写入的路径；本层写入过内容的子目录同样清除其中下层的内容
+func (m *layerMerger) clearDir(dir *node) {
+	if dir == nil || !dir.mode.IsDir() {
+		return
+	}
+	for name, child := range dir.children {
+		switch {
+		case child.layer < m.layer:
+			delete(dir.children, name)
+		case child.mode.IsDir():
+			m.clearDir(child)
+		}
+	}
+}
+
+// remove 删除路径及其下的全部内容
+func (m *layerMerger) remove(name string) {
+	if parent := m.lookup(path.Dir(name)); parent != nil && parent.mode.IsDir() {
+		delete(parent.children, path.Base(name))
+	}
+}
+
+// linkSymlinks 将指向镜像内普通文件的符号链接替换为该文件，使 /etc/os-release 等链接文件可被读取；
+// 指向目录、镜像外部或不存在的文件的符号链接被删除
+func (m *layerMerger) linkSymlinks() {
+	symlinks := make(map[string]string)
+	collectSymlinks(m.root, "", symlinks)
+	names := make([]string, 0, len(symlinks))
+	for name := range symlinks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// 先解析全部链接再替换，避免替换顺序影响链接链的解析
+	targets := make(map[string]*node)
+	for _, name := range names {
+		if resolved, ok := resolveSymlink(symlinks, name); ok {
+			if target := m.lookup(resolved); target != nil && target.mode == 0 {
+				targets[name] = target
+			}
+		}
+	}
+	for _, name := range names {
+		parent := m.lookup(path.Dir(name))
+		if target, ok := targets[name]; ok {
+			linked := *target
+			parent.children[path.Base(name)] = &linked
+		} else {
+			delete(parent.children, path.Base(name))
+		}
+	}
+}
+
+// collectSymlinks 收集文件树中的符号链接，镜像内路径到链接目标的映射
+func collectSymlinks(dir *node, prefix string, symlinks map[string]string) {
+	for name, child := range dir.children {
+		childPath := joinName(prefix, name)
+		switch {
+		case child.mode == fs.ModeSymlink:
+			symlinks[childPath] = child.link
+		case child.mode.IsDir():
+			collectSymlinks(child, childPath, symlinks)
+		}
+	}
+}
+
+// resolveSymlink 在镜像内解析符号链接（包括路径中间的目录链接），返回最终的镜像内路径
+func resolveSymlink(symlinks map[string]string, name string) (string, bool) {
+	for hops := 0; hops < maxSymlinkHops; hops++ {
+		changed := false
+		parts := strings.Split(name, "/")
+		for i := range parts {
+			prefix := strings.Join(parts[:i+1], "/")
+			link, ok := symlinks[prefix]
+			if !ok {
+				continue
+			}
+			// 绝对链接相对于镜像根目录，相对链接相对于链接所在目录
+			if !strings.HasPrefix(link, "/") {
+				link = path.Join(path.Dir(prefix), link)
+			}
+			name = cleanName(path.Join(append([]string{link}, parts[i+1:]...)...))
+			changed = true
+			break
+		}
+		if !changed {
+			return name, name != ""
+		}
+	}
+	return "", false
+}
+
+// lookupNode 从目录 root 开始查找相对路径对应的条目，不解析符号链接，不存在时返回 nil
+func lookupNode(root *node, name string) *node {
+	current := root
+	if name == "." || name == "" {
+		return current
+	}
+	for _, part := range strings.Split(name, "/") {
+		if current.children == nil {
+			return nil
+		}
+		child, ok := current.children[part]
+		if !ok {
+			return nil
+		}
+		current = child
+	}
+	return current
+}
+
+// joinName 拼接镜像内的目录与文件名
+func joinName(dir, name string) string {
+	if dir == "." || dir == "" {
+		return name
+	}
+	return dir + "/" + name
+}
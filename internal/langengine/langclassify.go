@@ -1,6 +1,8 @@
 package langengine
 
 import (
+	"io/fs"
+	"os"
 	"strings"
 
 	"github.com/winezer0/codecanvas/internal/embeds"
@@ -28,7 +30,7 @@ func NewLangClassifier() *LangClassify {
 	return c
 }
 
-// DetectCategories 检测给定语言的分类（前端/后端/桌面）
+// DetectCategories 检测给定语言的分类（前端/后端/桌面/移动端）
 // 参数:
 // - root: 项目根目录路径
 // - langs: 语言信息列表
@@ -36,16 +38,29 @@ func NewLangClassifier() *LangClassify {
 // - frontend: 前端语言列表
 // - backend: 后端语言列表
 // - desktop: 桌面语言列表
+// - mobile: 移动端语言列表
 // - other: 其他语言列表
 // - all: 所有语言列表（去重）
-func (c *LangClassify) DetectCategories(root string, langs []model.LangInfo) (frontend, backend, desktop, other, all, expand []string) {
+func (c *LangClassify) DetectCategories(root string, langs []model.LangInfo) (frontend, backend, desktop, mobile, other, all, expand []string) {
+	return c.DetectCategoriesFS(os.DirFS(root), langs)
+}
+
+// DetectCategoriesFS 与 DetectCategories 相同，项目根目录以文件系统 fsys 表示
+func (c *LangClassify) DetectCategoriesFS(fsys fs.FS, langs []model.LangInfo) (frontend, backend, desktop, mobile, other, all, expand []string) {
 	frontedSet := make(map[string]bool)
 	backendSet := make(map[string]bool)
 	desktopSet := make(map[string]bool)
+	mobileSet := make(map[string]bool)
 	otherSet := make(map[string]bool)
 	allSet := make(map[string]bool) // 用于去重所有语言
 
-	deps := readPackageJSONDeps(root)
+	deps := readPackageJSONDeps(fsys)
+	for dep := range readDotNetDeps(fsys) {
+		deps[dep] = true
+	}
+	for dep := range readPubspecDeps(fsys) {
+		deps[dep] = true
+	}
 	for _, langInfo := range langs {
 		name := strings.ToLower(langInfo.Name)
 		allSet[langInfo.Name] = true
@@ -57,7 +72,7 @@ func (c *LangClassify) DetectCategories(root string, langs []model.LangInfo) (fr
 			continue
 		} else {
 			// 应用动态分类规则
-			cats := ApplyDynamicHeuristics(root, langRule, deps)
+			cats := ApplyDynamicHeuristics(fsys, langRule, deps)
 			for _, cat := range cats {
 				// 根据分类结果添加到相应的集合
 				switch cat {
@@ -67,6 +82,8 @@ func (c *LangClassify) DetectCategories(root string, langs []model.LangInfo) (fr
 					backendSet[langInfo.Name] = true
 				case model.CategoryDesktop:
 					desktopSet[langInfo.Name] = true
+				case model.CategoryMobile:
+					mobileSet[langInfo.Name] = true
 				default:
 					otherSet[langInfo.Name] = true
 				}
@@ -78,6 +95,7 @@ func (c *LangClassify) DetectCategories(root string, langs []model.LangInfo) (fr
 	frontend = utils.Mapkeys(frontedSet)
 	backend = utils.Mapkeys(backendSet)
 	desktop = utils.Mapkeys(desktopSet)
+	mobile = utils.Mapkeys(mobileSet)
 	other = utils.Mapkeys(otherSet)
 	all = utils.Mapkeys(allSet)
 	expand = ExpandLanguages(all)
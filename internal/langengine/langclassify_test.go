@@ -79,7 +79,7 @@ func TestDetectCategories(t *testing.T) {
 				os.Remove(filepath.Join(tmpDir, "package.json"))
 			}
 
-			frontend, backend, _, _, _, _ := c.DetectCategories(tmpDir, tt.languages)
+			frontend, backend, _, _, _, _, _ := c.DetectCategories(tmpDir, tt.languages)
 
 			checkList(t, "Frontend", frontend, tt.wantFrontend)
 			checkList(t, "Backend", backend, tt.wantBackend)
@@ -102,3 +102,54 @@ func checkList(t *testing.T, cat string, got, want []string) {
 		}
 	}
 }
+
+func TestDetectCategoriesDotNetDesktop(t *testing.T) {
+	tmpDir := t.TempDir()
+	project := `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <TargetFramework>net8.0-windows</TargetFramework>
+    <UseWPF>true</UseWPF>
+  </PropertyGroup>
+</Project>`
+	if err := os.MkdirAll(filepath.Join(tmpDir, "src", "App"), 0755); err != nil {
+		t.Fatalf("Failed to create project dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "src", "App", "App.csproj"), []byte(project), 0644); err != nil {
+		t.Fatalf("Failed to write csproj: %v", err)
+	}
+
+	deps := readDotNetDeps(os.DirFS(tmpDir))
+	if !deps["wpf"] {
+		t.Errorf("UseWPF should be mapped to wpf, got %v", deps)
+	}
+
+	_, _, desktop, _, _, _, expand := NewLangClassifier().DetectCategories(tmpDir, []model.LangInfo{{Name: "C#"}})
+	checkList(t, "Desktop", desktop, []string{"C#"})
+	if len(expand) != 1 || expand[0] != "C#" {
+		t.Errorf("unexpected expanded languages: %v", expand)
+	}
+}
+
+func TestDetectCategoriesMobile(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"pubspec.yaml": "dependencies:\n  flutter:\n    sdk: flutter\n  shelf: ^1.4.0\n",
+		"package.json": `{"dependencies": {"react": "18.2.0", "react-native": "0.72.6"}}`,
+		"android/app/src/main/AndroidManifest.xml": `<manifest package="com.example" />`,
+	}
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(tmpDir, name)), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	languages := []model.LangInfo{{Name: "Dart"}, {Name: "JavaScript"}, {Name: "Kotlin"}, {Name: "Swift"}}
+	frontend, backend, _, mobile, _, _, expand := NewLangClassifier().DetectCategories(tmpDir, languages)
+	checkList(t, "Mobile", mobile, []string{"Dart", "JavaScript", "Kotlin", "Swift"})
+	checkList(t, "Frontend", frontend, []string{"JavaScript"})
+	checkList(t, "Backend", backend, []string{"Dart", "Kotlin"})
+	checkList(t, "Expand", expand, []string{"Dart", "JavaScript", "Kotlin", "Swift", "Objective-C", "Java"})
+}
@@ -2,21 +2,23 @@ package langengine
 
 import (
 	"encoding/json"
-	"os"
-	"path/filepath"
+	"io/fs"
+	"path"
 	"strings"
 
+	"github.com/winezer0/codecanvas/internal/depengine"
 	"github.com/winezer0/codecanvas/internal/model"
+	"gopkg.in/yaml.v3"
 )
 
 // ApplyDynamicHeuristics 应用动态分类规则对语言进行分类
 // 参数:
-// - root: 项目根目录路径
+// - fsys: 项目根目录对应的文件系统
 // - lang: 统一语言模型
 // - deps: 项目依赖映射
 // 返回值:
 // - string: 分类结果（frontend/backend/desktop/other）
-func ApplyDynamicHeuristics(root string, lang model.Language, deps map[string]bool) []string {
+func ApplyDynamicHeuristics(fsys fs.FS, lang model.Language, deps map[string]bool) []string {
 	baseRes := []string{lang.Category}
 	if len(lang.Dynamic) == 0 {
 		return baseRes
@@ -35,7 +37,7 @@ func ApplyDynamicHeuristics(root string, lang model.Language, deps map[string]bo
 		// 检查文件模式条件
 		if len(dynamic.FilePatterns) > 0 {
 			for _, pattern := range dynamic.FilePatterns {
-				matches, _ := filepath.Glob(filepath.Join(root, pattern))
+				matches, _ := fs.Glob(fsys, path.Clean(pattern))
 				if len(matches) > 0 {
 					baseRes = append(baseRes, dynamic.Category)
 				}
@@ -49,13 +51,12 @@ func ApplyDynamicHeuristics(root string, lang model.Language, deps map[string]bo
 
 // readPackageJSONDeps 从package.json读取项目依赖，用于JavaScript/TypeScript分类
 // 参数:
-// - root: 项目根目录路径
+// - fsys: 项目根目录对应的文件系统
 // 返回值:
 // - map[string]bool: 依赖包名称映射（小写）
-func readPackageJSONDeps(root string) map[string]bool {
+func readPackageJSONDeps(fsys fs.FS) map[string]bool {
 	res := map[string]bool{}
-	p := filepath.Join(root, "package.json")
-	b, err := os.ReadFile(p)
+	b, err := fs.ReadFile(fsys, "package.json")
 	if err != nil {
 		return res
 	}
@@ -74,12 +75,91 @@ func readPackageJSONDeps(root string) map[string]bool {
 	return res
 }
 
+// readPubspecDeps 从 pubspec.yaml 读取项目依赖，用于 Dart 分类（包含 flutter 等 SDK 依赖）
+// 参数:
+// - fsys: 项目根目录对应的文件系统
+// 返回值:
+// - map[string]bool: 依赖包名称映射（小写）
+func readPubspecDeps(fsys fs.FS) map[string]bool {
+	res := map[string]bool{}
+	b, err := fs.ReadFile(fsys, "pubspec.yaml")
+	if err != nil {
+		return res
+	}
+	var m map[string]any
+	_ = yaml.Unmarshal(b, &m)
+	for _, key := range []string{"dependencies", "dev_dependencies"} {
+		mm, _ := m[key].(map[string]any)
+		for k := range mm {
+			res[strings.ToLower(k)] = true
+		}
+	}
+	return res
+}
+
+// dotNetFrameworkAliases .NET 框架引用到动态分类依赖别名的映射
+var dotNetFrameworkAliases = map[string]string{
+	strings.ToLower(depengine.FrameworkRefWPF):      "wpf",
+	strings.ToLower(depengine.FrameworkRefWinForms): "winforms",
+	strings.ToLower(depengine.FrameworkRefMaui):     "maui",
+}
+
+// readDotNetDeps 从 .NET 项目文件（.csproj/.fsproj/.vbproj）读取包引用与框架引用，用于 C# 等语言的分类
+// UseWPF/UseWindowsForms 等框架引用会额外映射为 "wpf"/"winforms" 别名
+// 参数:
+// - fsys: 项目根目录对应的文件系统
+// 返回值:
+// - map[string]bool: 依赖名称映射（小写）
+func readDotNetDeps(fsys fs.FS) map[string]bool {
+	res := map[string]bool{}
+	const maxDepth = 4
+	_ = fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			base := strings.ToLower(d.Name())
+			if name != "." && (strings.HasPrefix(base, ".") || base == "bin" || base == "obj" || base == "node_modules" || base == "packages" ||
+				strings.Count(name, "/") >= maxDepth) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		switch strings.ToLower(path.Ext(name)) {
+		case ".csproj", ".fsproj", ".vbproj":
+		default:
+			return nil
+		}
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil
+		}
+		project, err := depengine.ParseDotNetProject(content)
+		if err != nil {
+			return nil
+		}
+		for _, ref := range project.PackageReferences {
+			res[strings.ToLower(ref.Name)] = true
+		}
+		for _, ref := range project.FrameworkReferences {
+			res[strings.ToLower(ref)] = true
+			if alias, ok := dotNetFrameworkAliases[strings.ToLower(ref)]; ok {
+				res[alias] = true
+			}
+		}
+		return nil
+	})
+	return res
+}
+
 // ExpandLanguages 在给定的语言列表中，自动补充关联语言，以确保语义完整性。
 // 例如：
 // - TypeScript/TSX/JSX/Vue -> JavaScript (确保能匹配 JS 生态的规则)
 // - SCSS/Less -> CSS (确保能匹配 CSS 规则)
 // - Kotlin -> Java (确保能匹配 Java/JVM 生态规则)
 // - C++ -> C (C++ 项目通常也包含 C 代码或库)
+// - Swift -> Objective-C (确保能匹配 iOS 生态规则)
+// - .NET -> C# (确保能匹配 .NET 生态规则)
 func ExpandLanguages(langs []string) []string {
 	seen := make(map[string]bool)
 	for _, l := range langs {
@@ -118,5 +198,17 @@ func ExpandLanguages(langs []string) []string {
 		add("C")
 	}
 
+	// 5. Apple 生态系统
+	// Swift 项目通常与 Objective-C 运行时及 CocoaPods 生态共存
+	if seen["Swift"] {
+		add("Objective-C")
+	}
+
+	// 6. .NET 生态系统
+	// .vb 文件识别为 .NET，规则统一使用 C#
+	if seen[".NET"] {
+		add("C#")
+	}
+
 	return langs
 }
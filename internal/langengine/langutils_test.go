@@ -52,6 +52,16 @@ func TestExpandLanguages(t *testing.T) {
 			input:    []string{"C++"},
 			expected: []string{"C++", "C"},
 		},
+		{
+			name:     "Expand Swift to Objective-C",
+			input:    []string{"Swift"},
+			expected: []string{"Swift", "Objective-C"},
+		},
+		{
+			name:     "Expand .NET to C#",
+			input:    []string{".NET"},
+			expected: []string{".NET", "C#"},
+		},
 		{
 			name:     "Mixed expansion",
 			input:    []string{"TypeScript", "SCSS", "Kotlin"},
@@ -29,6 +29,7 @@ type LogConfig struct {
 	Level         string // 日志级别: debug/info/warn/error/fatal
 	LogFile       string // 日志文件路径，空串表示不输出到文件
 	ConsoleFormat string // 控制台格式: 空串或"off"表示关闭，支持"T(时间)L(级别)C(调用者)M(消息)"
+	Stderr        bool   // 控制台日志写到标准错误，标准输出需要保持为纯报告内容时使用
 }
 
 // NewLogConfig 创建日志配置实例，提供默认值
@@ -70,9 +71,13 @@ func (l *Logger) init() error {
 	// 控制台输出
 	if l.config.ConsoleFormat != "" && l.config.ConsoleFormat != "off" {
 		encoder := newConsoleEncoder(l.config.ConsoleFormat)
+		console := os.Stdout
+		if l.config.Stderr {
+			console = os.Stderr
+		}
 		cores = append(cores, zapcore.NewCore(
 			encoder,
-			zapcore.Lock(os.Stdout),
+			zapcore.Lock(console),
 			level,
 		))
 	}
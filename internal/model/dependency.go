@@ -0,0 +1,92 @@
+package model
+
+// 依赖生态常量
+const (
+	EcosystemNpm       = "npm"
+	EcosystemMaven     = "maven"
+	EcosystemGradle    = "gradle"
+	EcosystemGo        = "go"
+	EcosystemPyPI      = "pypi"
+	EcosystemComposer  = "composer"
+	EcosystemCargo     = "cargo"
+	EcosystemRubyGems  = "rubygems"
+	EcosystemHex       = "hex"
+	EcosystemNuGet     = "nuget"
+	EcosystemCMake     = "cmake"
+	EcosystemConan     = "conan"
+	EcosystemVcpkg     = "vcpkg"
+	EcosystemMeson     = "meson"
+	EcosystemAndroid   = "android"
+	EcosystemCocoaPods = "cocoapods"
+	EcosystemSwiftPM   = "swiftpm"
+	EcosystemXcode     = "xcode"
+	EcosystemPub       = "pub"
+	// EcosystemDeb/EcosystemApk 容器镜像或根文件系统中已安装的 Debian/Ubuntu 与 Alpine 系统软件包
+	EcosystemDeb = "deb"
+	EcosystemApk = "apk"
+	// EcosystemSystem 系统库（Makefile 链接参数 -l 与 pkg-config 模块）
+	EcosystemSystem = "system"
+)
+
+// 依赖作用域常量
+const (
+	ScopeRuntime = "runtime"
+	ScopeDev     = "dev"
+	ScopeBuild   = "build"
+	// ScopeFramework 目标框架、SDK 与框架引用等平台依赖
+	ScopeFramework = "framework"
+	// ScopeSDK 平台 SDK 级别，如 Android minSdk、iOS 最低部署版本、Dart SDK 约束
+	ScopeSDK = "sdk"
+)
+
+// 依赖使用情况常量，根据源码中的导入语句判断
+const (
+	// UsageDeclaredAndUsed 在清单中声明且被源码导入
+	UsageDeclaredAndUsed = "declared-and-used"
+	// UsageDeclaredOnly 在清单中声明但没有源码导入
+	UsageDeclaredOnly = "declared-only"
+	// UsageUsedUndeclared 被源码导入但未在清单中声明（如随项目提交的第三方代码）
+	UsageUsedUndeclared = "used-undeclared"
+)
+
+// Dependency 依赖清单中的一条依赖记录
+// - Ecosystem: 依赖所属生态（如 "composer"）
+// - Name: 依赖包名称（如 "laravel/framework"）
+// - Version: 版本号，来自锁文件时为精确版本，来自清单时为声明的约束
+// - Scope: 作用域（runtime/dev/build/framework/sdk）
+// - Direct: 是否为项目直接声明的依赖
+// - Locked: 是否来自锁文件或已安装元数据（版本精确）
+// - Manifest: 来源清单文件的相对路径
+// - Usage: 使用情况（declared-and-used/declared-only/used-undeclared），仅对直接的运行时与开发依赖判断
+// - Importers: 导入该依赖的源码文件数量
+// - Requires: 锁文件中记录的该依赖所依赖的包名称，锁文件不提供依赖关系时为空
+// - License: 锁文件或已安装元数据中声明的许可证（如 "MIT"、"(MIT OR Apache-2.0)"）
+// - Checksum: 锁文件中记录的包文件摘要，形如 "sha256:<十六进制>"
+// - Line: 来源清单中声明该依赖的行号（从 1 开始），无法确定时为 0
+type Dependency struct {
+	Ecosystem string   `json:"ecosystem"`
+	Name      string   `json:"name"`
+	Version   string   `json:"version"`
+	Scope     string   `json:"scope"`
+	Direct    bool     `json:"direct"`
+	Locked    bool     `json:"locked"`
+	Manifest  string   `json:"manifest"`
+	Usage     string   `json:"usage,omitempty"`
+	Importers int      `json:"importers,omitempty"`
+	Requires  []string `json:"requires,omitempty"`
+	License   string   `json:"license,omitempty"`
+	Checksum  string   `json:"checksum,omitempty"`
+	Line      int      `json:"line,omitempty"`
+}
+
+// DependencyGap 多份报告中未被规则识别的依赖统计，用于指导规则编写
+// - Ecosystem: 依赖所属生态
+// - Name: 依赖包名称
+// - Count: 出现该依赖的报告数量
+// - Projects: 出现该依赖的项目路径
+type DependencyGap struct {
+	Ecosystem string   `json:"ecosystem"`
+	Name      string   `json:"name"`
+	Count     int      `json:"count"`
+	Projects  []string `json:"projects"`
+}
@@ -0,0 +1,69 @@
+package model
+
+import "time"
+
+// 报告差异中的变化类型
+const (
+	DiffAdded         = "added"
+	DiffRemoved       = "removed"
+	DiffChanged       = "changed"
+	DiffUpgraded      = "upgraded"
+	DiffDowngraded    = "downgraded"
+	DiffRecategorized = "recategorized"
+)
+
+// ReportDiff 两份分析报告之间的差异
+// - Old/New: 参与比较的两份报告的来源信息
+// - Languages: 新增、移除以及代码行数变化的语言，按名称排序
+// - Frameworks/Components: 新增、移除、版本或分类发生变化的框架与组件，按名称排序
+type ReportDiff struct {
+	Old        DiffSource     `json:"old"`
+	New        DiffSource     `json:"new"`
+	TotalLines LineDelta      `json:"total_lines"`
+	Languages  []LanguageDiff `json:"languages"`
+	Frameworks []ItemDiff     `json:"frameworks"`
+	Components []ItemDiff     `json:"components"`
+}
+
+// DiffSource 参与比较的报告的来源
+// - Commit: 报告来自 git 修订时的提交哈希
+type DiffSource struct {
+	Path      string    `json:"path"`
+	Commit    string    `json:"commit,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// LineDelta 代码行数的变化
+type LineDelta struct {
+	Old   int `json:"old"`
+	New   int `json:"new"`
+	Delta int `json:"delta"`
+}
+
+// LanguageDiff 某一语言的变化
+// - Change: "added"、"removed" 或 "changed"（代码行数变化）
+type LanguageDiff struct {
+	Name   string `json:"name"`
+	Change string `json:"change"`
+	LineDelta
+}
+
+// ItemDiff 框架或组件的变化
+// - Change: "added"、"removed"、"upgraded"、"downgraded"、"changed"（版本无法比较大小）或 "recategorized"（仅分类变化）
+// - OldVersion/NewVersion: 变化前后的版本，新增时 OldVersion 为空，移除时 NewVersion 为空
+// - OldCategory/NewCategory: 变化前后的分类，新增时 OldCategory 为空，移除时 NewCategory 为空；两者不同表示分类发生变化
+type ItemDiff struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Language    string `json:"language"`
+	Change      string `json:"change"`
+	OldVersion  string `json:"old_version,omitempty"`
+	NewVersion  string `json:"new_version,omitempty"`
+	OldCategory string `json:"old_category,omitempty"`
+	NewCategory string `json:"new_category,omitempty"`
+}
+
+// Empty 两份报告之间没有语言、框架与组件的变化时返回 true
+func (d *ReportDiff) Empty() bool {
+	return len(d.Languages) == 0 && len(d.Frameworks) == 0 && len(d.Components) == 0
+}
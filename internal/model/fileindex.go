@@ -1,17 +1,49 @@
 package model
 
-import "strings"
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
 
 // FileIndex 存储代码库的文件索引结构，用于加速查找。
 type FileIndex struct {
-	// RootDir 是被索引的根目录绝对路径
+	// RootDir 是被索引的根目录绝对路径；从 FS 建立的索引中仅作为报告中展示的根路径
 	RootDir string
+	// FS 被索引的文件系统（如内存文件树、embed.FS、git 对象库），文件路径为其中的相对路径；为 nil 时从磁盘上的 RootDir 读取
+	FS fs.FS
 	// Files 存储所有文件的相对路径列表
 	Files []string
 	// NameMap 映射文件名到 Files 切片中的索引列表 (例如: "package.json" -> [0, 5, 10])
 	NameMap map[string][]int
 	// ExtensionMap 映射文件扩展名到 Files 切片中的索引列表 (例如: ".go" -> [1, 2, 3])
 	ExtensionMap map[string][]int
+	// Imports 映射源码文件相对路径到文件中导入的模块，由代码画像分析在统计行数时提取，未分析时为 nil
+	Imports map[string]FileImports
+	// Stats 映射已识别语言的文件相对路径到其行数统计，用于生成子项目的代码画像，未分析时为 nil
+	Stats map[string]FileStat
+}
+
+// FileStat 单个文件的行数统计
+// - Language: 文件语言
+// - Code/Comment/Blank: 代码、注释与空白行数
+// - Error: 文件是否处理失败
+type FileStat struct {
+	Language string
+	Code     int64
+	Comment  int64
+	Blank    int64
+	Error    bool
+}
+
+// FileImports 单个源码文件的导入信息
+// - Language: 文件语言（如 "Go"、"Java"、"TypeScript"）
+// - Modules: import/require/use 语句中导入的模块（如 "github.com/gin-gonic/gin"、"react"）
+type FileImports struct {
+	Language string
+	Modules  []string
 }
 
 // NewFileIndex 创建一个新的空索引
@@ -33,3 +65,75 @@ func (fi *FileIndex) AddFile(relPath string, fileName string, ext string) {
 	fi.NameMap[strings.ToLower(fileName)] = append(fi.NameMap[strings.ToLower(fileName)], idx)
 	fi.ExtensionMap[strings.ToLower(ext)] = append(fi.ExtensionMap[strings.ToLower(ext)], idx)
 }
+
+// Contains 判断索引中是否存在指定的相对路径（不区分大小写）
+func (fi *FileIndex) Contains(relPath string) bool {
+	relPath = strings.TrimPrefix(relPath, "/")
+	base := relPath
+	if i := strings.LastIndex(relPath, "/"); i >= 0 {
+		base = relPath[i+1:]
+	}
+	for _, idx := range fi.NameMap[strings.ToLower(base)] {
+		if strings.EqualFold(fi.Files[idx], relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// Subset 返回以 dir 为根目录的子索引，路径均相对于 dir；位于 exclude 中任一目录下的文件不包含在内。
+// dir 为 "." 时以原根目录为根，仅排除 exclude 中的目录
+func (fi *FileIndex) Subset(dir string, exclude []string) *FileIndex {
+	prefix := ""
+	rootDir := fi.RootDir
+	if dir != "." && dir != "" {
+		prefix = strings.TrimSuffix(dir, "/") + "/"
+		rootDir = filepath.Join(fi.RootDir, filepath.FromSlash(dir))
+	}
+	sub := NewFileIndex(rootDir)
+	sub.FS = fi.FS
+	if fi.FS != nil && prefix != "" {
+		if subFS, err := fs.Sub(fi.FS, strings.TrimSuffix(prefix, "/")); err == nil {
+			sub.FS = subFS
+		}
+	}
+	if fi.Imports != nil {
+		sub.Imports = make(map[string]FileImports)
+	}
+	if fi.Stats != nil {
+		sub.Stats = make(map[string]FileStat)
+	}
+	for _, relPath := range fi.Files {
+		if !strings.HasPrefix(relPath, prefix) || underAny(relPath, exclude) {
+			continue
+		}
+		subPath := strings.TrimPrefix(relPath, prefix)
+		name := path.Base(subPath)
+		sub.AddFile(subPath, name, path.Ext(name))
+		if imports, ok := fi.Imports[relPath]; ok {
+			sub.Imports[subPath] = imports
+		}
+		if stat, ok := fi.Stats[relPath]; ok {
+			sub.Stats[subPath] = stat
+		}
+	}
+	return sub
+}
+
+// FileSystem 返回被索引的文件系统，索引未关联 FS 时返回磁盘上 RootDir 对应的文件系统
+func (fi *FileIndex) FileSystem() fs.FS {
+	if fi.FS != nil {
+		return fi.FS
+	}
+	return os.DirFS(fi.RootDir)
+}
+
+// underAny 判断相对路径是否位于任一目录之下
+func underAny(relPath string, dirs []string) bool {
+	for _, dir := range dirs {
+		if dir != "." && dir != "" && strings.HasPrefix(relPath, strings.TrimSuffix(dir, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -1,5 +1,7 @@
 package model
 
+import "gopkg.in/yaml.v3"
+
 // FrameRule 匹配组件/框架的信息 判断组件或框架是否存在
 type FrameRule struct {
 	// Paths: 必须存在的路径（文件或目录），全部都要存在
@@ -8,14 +10,69 @@ type FrameRule struct {
 	// FileContents: 文件路径 -> 必须包含的关键字列表
 	// 每个文件必须存在，且内容包含所有对应的关键字
 	FileContents map[string][]string `yaml:"file_contents,omitempty"`
+
+	// Dependencies: 必须存在于依赖清单中的依赖包名称，全部都要存在（支持 * 通配，如 "symfony/*"）
+	Dependencies []string `yaml:"dependencies,omitempty"`
+
+	// Imports: 必须被项目源码导入的模块或包前缀，全部都要存在（如 "github.com/gin-gonic/gin"、"org.springframework."）
+	// 与导入语句解析结果匹配，不受注释与字符串中相同文本的影响
+	Imports []string `yaml:"imports,omitempty"`
+
+	// JSON/YAML/TOML: 结构化文件中必须存在的键路径（如 file: package.json, path: dependencies.react），全部都要满足
+	JSON StructuredConditions `yaml:"json,omitempty"`
+	YAML StructuredConditions `yaml:"yaml,omitempty"`
+	TOML StructuredConditions `yaml:"toml,omitempty"`
+
+	// XML: 使用简化 XPath 在 XML 文件中选择的节点（如 pom.xml、*.csproj），全部都要满足
+	XML StructuredConditions `yaml:"xml,omitempty"`
+}
+
+// StructuredCondition 结构化文件条件：在 File 匹配的文件中按 Path 选择节点
+type StructuredCondition struct {
+	// File: 文件模式，与 file_contents 的文件模式规则相同
+	File string `yaml:"file"`
+	// Path: JSON/YAML/TOML 为点分键路径（如 dependencies.react），XML 为 XPath（如 /project/dependencies/dependency[artifactId='rome']）
+	Path string `yaml:"path"`
+	// Value: 可选的正则表达式，所选节点的值必须匹配；作为版本提取器时，第一个捕获组为版本号
+	Value string `yaml:"value,omitempty"`
+}
+
+// StructuredConditions 结构化文件条件列表，YAML 中可写作单个对象或列表
+type StructuredConditions []StructuredCondition
+
+// UnmarshalYAML 同时支持单个条件对象与条件列表两种写法
+func (sc *StructuredConditions) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode {
+		var condition StructuredCondition
+		if err := node.Decode(&condition); err != nil {
+			return err
+		}
+		*sc = StructuredConditions{condition}
+		return nil
+	}
+	var conditions []StructuredCondition
+	if err := node.Decode(&conditions); err != nil {
+		return err
+	}
+	*sc = conditions
+	return nil
 }
 
 // VersionExtractor 表示一条完整的版本提取规则
 type VersionExtractor struct {
 	// FilePattern: 匹配的文件模式
-	FilePattern string `yaml:"file_pattern"` // 匹配的文件模式
+	FilePattern string `yaml:"file_pattern,omitempty"` // 匹配的文件模式
 	// Patterns: 版本提取正则表达式列表
 	Patterns []string `yaml:"patterns"` // 版本提取正则表达式列表
+	// Dependency: 从依赖清单中读取该依赖包的版本（优先锁文件中的精确版本），设置后可省略 FilePattern
+	Dependency string `yaml:"dependency,omitempty"`
+
+	// JSON/YAML/TOML/XML: 使用结构化选择器读取版本，设置 Value 时取其第一个捕获组，否则直接使用选中值；
+	// 未选中时继续尝试 FilePattern 的正则提取
+	JSON StructuredConditions `yaml:"json,omitempty"`
+	YAML StructuredConditions `yaml:"yaml,omitempty"`
+	TOML StructuredConditions `yaml:"toml,omitempty"`
+	XML  StructuredConditions `yaml:"xml,omitempty"`
 }
 
 // Framework 内部规则模型（对应 YAML 规则文件）定义了如何检测框架或组件。在启动时从 YAML 规则文件中加载。
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// GitInfo 直接从 git 对象库分析的提交信息
+// - Repository: 仓库路径
+// - Revision: 用户指定的修订，如 "v1.2.0"、"main" 或提交哈希，未指定时为 "HEAD"
+// - Ref: 修订解析到的完整引用名称，如 "refs/tags/v1.2.0"，直接指定提交哈希或 HEAD 处于分离状态时为空
+// - Commit: 提交哈希
+// - Date: 提交时间（committer 时间）
+type GitInfo struct {
+	Repository string    `json:"repository"`
+	Revision   string    `json:"revision"`
+	Ref        string    `json:"ref,omitempty"`
+	Commit     string    `json:"commit"`
+	Date       time.Time `json:"date"`
+}
@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+// 技术栈时间线事件类型
+const (
+	TimelineAdded   = "added"
+	TimelineRemoved = "removed"
+	TimelineChanged = "changed"
+)
+
+// HistoryReport 沿 git 历史采样多个提交分析得到的技术栈时间线
+// - Repository: 仓库路径
+// - Mode: 采样方式，"every" 表示每隔 N 个提交采样，"tags" 表示采样带标签的发布
+// - Samples: 按提交时间从早到晚排列的采样结果
+// - Events: 框架与组件出现、版本变化与消失的事件，按采样顺序排列
+// - ReusedFiles/AnalyzedFiles: 按 blob 哈希复用统计结果的文件数与实际统计的文件数
+type HistoryReport struct {
+	Repository    string          `json:"repository"`
+	Mode          string          `json:"mode"`
+	Samples       []HistorySample `json:"samples"`
+	Events        []TimelineEvent `json:"events"`
+	ReusedFiles   int64           `json:"reused_files"`
+	AnalyzedFiles int64           `json:"analyzed_files"`
+	Timestamp     time.Time       `json:"timestamp"`
+}
+
+// HistorySample 单个采样提交的分析摘要
+// - Refs: 指向该提交的标签名称
+// - Languages: 各语言的文件与行数统计
+// - Items: 检测到的框架与组件
+type HistorySample struct {
+	Commit    string        `json:"commit"`
+	Date      time.Time     `json:"date"`
+	Refs      []string      `json:"refs,omitempty"`
+	Subject   string        `json:"subject"`
+	Languages []LangInfo    `json:"languages"`
+	Items     []HistoryItem `json:"items"`
+}
+
+// HistoryItem 采样提交中检测到的框架或组件
+type HistoryItem struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// TimelineEvent 框架或组件在某个采样提交中的变化
+// - Kind: "added"、"removed" 或 "changed"
+// - From/To: 变化前后的版本，出现时 From 为空，消失时 To 为空
+type TimelineEvent struct {
+	Commit string    `json:"commit"`
+	Date   time.Time `json:"date"`
+	Refs   []string  `json:"refs,omitempty"`
+	Kind   string    `json:"kind"`
+	Type   string    `json:"type"`
+	Name   string    `json:"name"`
+	From   string    `json:"from,omitempty"`
+	To     string    `json:"to,omitempty"`
+}
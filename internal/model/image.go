@@ -0,0 +1,14 @@
+package model
+
+// ImageInfo 被分析的容器镜像信息
+// - Reference: 镜像引用名称，如 "nginx:1.25"，压缩包未记录时为空
+// - Format: 镜像压缩包格式，"docker-archive" 或 "oci"
+// - Layers: 合并的层数
+// - OS/Architecture: 镜像配置中声明的操作系统与架构，如 "linux"、"amd64"
+type ImageInfo struct {
+	Reference    string `json:"reference,omitempty"`
+	Format       string `json:"format"`
+	Layers       int    `json:"layers"`
+	OS           string `json:"os,omitempty"`
+	Architecture string `json:"architecture,omitempty"`
+}
@@ -5,9 +5,9 @@ package model
 // - FilePatterns: 关联文件模式（满足任一即可）
 // - Dependencies: 关联依赖包（满足任一即可）
 type DynamicCategory struct {
-	Category     string   `json:"category"`
-	FilePatterns []string `json:"file_patterns"`
-	Dependencies []string `json:"dependencies"`
+	Category     string   `json:"category" yaml:"category"`
+	FilePatterns []string `json:"file_patterns" yaml:"file_patterns"`
+	Dependencies []string `json:"dependencies" yaml:"dependencies"`
 }
 
 // Language 统一语言模型，整合语言特征和分类规则
@@ -19,11 +19,11 @@ type DynamicCategory struct {
 // - Category: 默认分类（frontend/backend/desktop/other）
 // - Dynamic: 动态分类规则列表
 type Language struct {
-	Name         string            `json:"name"`
-	LineComments []string          `json:"line_comments"`
-	MultiLine    [][]string        `json:"multi_line"`
-	Extensions   []string          `json:"extensions"`
-	Filenames    []string          `json:"filenames"`
-	Category     string            `json:"category"`
-	Dynamic      []DynamicCategory `json:"dynamic"`
+	Name         string            `json:"name" yaml:"name"`
+	LineComments []string          `json:"line_comments" yaml:"line_comments"`
+	MultiLine    [][]string        `json:"multi_line" yaml:"multi_line"`
+	Extensions   []string          `json:"extensions" yaml:"extensions"`
+	Filenames    []string          `json:"filenames" yaml:"filenames"`
+	Category     string            `json:"category" yaml:"category"`
+	Dynamic      []DynamicCategory `json:"dynamic" yaml:"dynamic"`
 }
@@ -0,0 +1,93 @@
+package model
+
+// 策略违规的严重程度，由低到高
+const (
+	SeverityLow      = "low"
+	SeverityMedium   = "medium"
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+// AllSeverity 全部严重程度，由低到高排列
+var AllSeverity = []string{SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}
+
+// 策略规则类型
+const (
+	PolicyDeny           = "deny"
+	PolicyAllowFramework = "allow-framework"
+	PolicyMinVersion     = "min-version"
+	PolicyDenyLanguage   = "deny-language"
+	PolicyAllowLanguage  = "allow-language"
+)
+
+// Policy 策略文件，对分析报告中的框架、组件与语言进行约束
+// - Deny: 禁止使用的框架或组件，可限定版本范围
+// - Allow: 允许使用的框架列表，检测到列表之外的框架即违规
+// - Require: 框架或组件的最低版本要求，未检测到时不检查
+// - Languages: 语言限制
+type Policy struct {
+	Deny      []DenyRule    `yaml:"deny,omitempty"`
+	Allow     AllowRule     `yaml:"allow,omitempty"`
+	Require   []RequireRule `yaml:"require,omitempty"`
+	Languages LanguageRule  `yaml:"languages,omitempty"`
+}
+
+// DenyRule 禁止使用的框架或组件
+// - Name: 框架或组件名称，不区分大小写
+// - Version: 可选的版本约束（如 "< 1.2.83"、">= 2.0 <2.17.1"、"[2.0,2.17.1)"），为空时任意版本均违规；
+// 版本未知时不视为违规
+// - Severity: 严重程度，默认为 high
+// - Message: 违规说明
+type DenyRule struct {
+	Name     string `yaml:"name"`
+	Version  string `yaml:"version,omitempty"`
+	Severity string `yaml:"severity,omitempty"`
+	Message  string `yaml:"message,omitempty"`
+}
+
+// AllowRule 允许使用的框架列表，Severity 默认为 medium
+type AllowRule struct {
+	Frameworks []string `yaml:"frameworks,omitempty"`
+	Severity   string   `yaml:"severity,omitempty"`
+}
+
+// RequireRule 框架或组件的最低版本要求
+// - MinVersion: 最低版本，低于该版本即违规
+// - Severity: 严重程度，默认为 medium
+type RequireRule struct {
+	Name       string `yaml:"name"`
+	MinVersion string `yaml:"min_version"`
+	Severity   string `yaml:"severity,omitempty"`
+	Message    string `yaml:"message,omitempty"`
+}
+
+// LanguageRule 语言限制
+// - Allow: 允许使用的语言，只约束前端、后端、桌面与移动端语言，JSON、YAML 等其他语言不受限制
+// - Deny: 禁止使用的语言
+// - Severity: 严重程度，默认为 low
+type LanguageRule struct {
+	Allow    []string `yaml:"allow,omitempty"`
+	Deny     []string `yaml:"deny,omitempty"`
+	Severity string   `yaml:"severity,omitempty"`
+}
+
+// PolicyViolation 一条策略违规
+// - Rule: 违反的规则类型（deny/allow-framework/min-version/deny-language/allow-language）
+// - Type: 违规对象的类型，"framework"、"component" 或 "language"
+// - Version: 违规的版本，语言违规时为空
+// - Constraint: 策略中的版本约束或最低版本
+// - Path: 提供违规版本的文件相对路径，无法确定时为空
+// - Line: Path 中声明违规依赖的行号，无法确定时为 0
+// - Evidence: 检测依据
+type PolicyViolation struct {
+	Rule       string `json:"rule"`
+	Severity   string `json:"severity"`
+	Type       string `json:"type"`
+	Name       string `json:"name"`
+	Version    string `json:"version,omitempty"`
+	Constraint string `json:"constraint,omitempty"`
+	Path       string `json:"path,omitempty"`
+	Line       int    `json:"line,omitempty"`
+	Message    string `json:"message"`
+	Evidence   string `json:"evidence,omitempty"`
+}
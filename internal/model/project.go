@@ -0,0 +1,26 @@
+package model
+
+// Project 在代码库中发现的子项目
+// - Name: 项目名称，取自清单文件（如 go.mod 的 module、package.json 的 name），缺失时使用目录名
+// - Path: 项目根目录的相对路径，代码库根目录为 "."
+// - Kinds: 项目清单所属的生态，如 ["go"]、["npm", "maven"]
+// - Manifests: 标识该项目的清单文件相对路径
+// - Workspace: 项目是否声明了工作区或多模块成员（go.work、npm workspaces、Maven modules 等）
+// - Parent: 最近的上级项目路径，根项目为空
+type Project struct {
+	Name      string   `json:"name"`
+	Path      string   `json:"path"`
+	Kinds     []string `json:"kinds"`
+	Manifests []string `json:"manifests"`
+	Workspace bool     `json:"workspace,omitempty"`
+	Parent    string   `json:"-"`
+}
+
+// ProjectReport 单个子项目的分析结果，代码画像与检测结果仅包含该项目自身目录中的文件，
+// 嵌套的子项目在 Children 中单独报告
+type ProjectReport struct {
+	Project
+	CodeProfile CodeProfile     `json:"code_profile"`
+	Detection   DetectionInfo   `json:"detection"`
+	Children    []ProjectReport `json:"children,omitempty"`
+}
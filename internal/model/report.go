@@ -15,17 +15,32 @@ const (
 	CategoryFrontend = "frontend"
 	CategoryBackend  = "backend"
 	CategoryDesktop  = "desktop"
+	CategoryMobile   = "mobile"
 	CategoryOther    = "other"
 )
 
-var AllCategory = []string{CategoryFrontend, CategoryBackend, CategoryDesktop, CategoryOther}
+var AllCategory = []string{CategoryFrontend, CategoryBackend, CategoryDesktop, CategoryMobile, CategoryOther}
 
 // CanvasReport 最终分析报告
 type CanvasReport struct {
 	CodeProfile CodeProfile   `json:"code_profile"`
 	Detection   DetectionInfo `json:"detection"`
-	Timestamp   time.Time     `json:"timestamp"`
-	Version     string        `json:"version"`
+	// Dependencies 从清单与锁文件中解析出的全部依赖
+	Dependencies []Dependency `json:"dependencies"`
+	// UnclassifiedDependencies 未被任何规则识别的依赖，生态名称到依赖包名称列表的映射
+	UnclassifiedDependencies map[string][]string `json:"unclassified_dependencies"`
+	// Projects 发现多个子项目时按目录层级组织的项目树，顶层的代码画像与检测结果为全部项目的汇总
+	Projects []ProjectReport `json:"projects,omitempty"`
+	// Image 分析容器镜像时的镜像信息
+	Image *ImageInfo `json:"image,omitempty"`
+	// Git 直接分析 git 仓库中的提交时的提交信息
+	Git *GitInfo `json:"git,omitempty"`
+	// Vulnerabilities 与离线公告数据匹配得到的漏洞摘要
+	Vulnerabilities *VulnerabilitySummary `json:"vulnerabilities,omitempty"`
+	// PolicyViolations 按策略文件检查报告得到的违规项
+	PolicyViolations []PolicyViolation `json:"policy_violations,omitempty"`
+	Timestamp        time.Time         `json:"timestamp"`
+	Version          string            `json:"version"`
 }
 type CodeProfile struct {
 	Path              string     `json:"path"`
@@ -36,6 +51,7 @@ type CodeProfile struct {
 	FrontendLanguages []string   `json:"frontend_languages"` // 例如: ["TypeScript", "JavaScript"]
 	BackendLanguages  []string   `json:"backend_languages"`  // 例如: ["Java", "Go"]
 	DesktopLanguages  []string   `json:"desktop_languages"`  // 例如: ["C#", "C++"]
+	MobileLanguages   []string   `json:"mobile_languages"`   // 例如: ["Swift", "Dart"]
 	OtherLanguages    []string   `json:"other_languages"`    // 例如: ["JSON", "YAML"]
 	Languages         []string   `json:"languages"`
 	Expands           []string   `json:"expands"`
@@ -43,18 +59,55 @@ type CodeProfile struct {
 
 // DetectionInfo 框架与组件识别结果 包含已检测到的框架和组件的列表。
 type DetectionInfo struct {
-	Frameworks []DetectedItem `json:"frameworks"`
-	Components []DetectedItem `json:"components"`
+	Frameworks   []DetectedItem `json:"frameworks"`
+	Components   []DetectedItem `json:"components"`
+	BuildSystems []string       `json:"build_systems"` // 例如: ["CMake", "Make"]
 }
 
 // DetectedItem  框架与组件识别结果代表了一项已检测到的技术项目（框架或组件）。
 type DetectedItem struct {
-	Name     string `json:"name"`     // 例如: "gin", "log4j-core", "wails"
-	Type     string `json:"type"`     // "framework" 或 "component"
-	Language string `json:"language"` // 例如: "Go", "Java", "JavaScript"
-	Version  string `json:"version"`  // 版本字符串，可能为空
-	Category string `json:"category"` // "frontend" | "backend" | "desktop"
-	Evidence string `json:"evidence"` // 人类可读的检测原因
+	Name     string `json:"name"`            // 例如: "gin", "log4j-core", "wails"
+	Type     string `json:"type"`            // "framework" 或 "component"
+	Language string `json:"language"`        // 例如: "Go", "Java", "JavaScript"
+	Version  string `json:"version"`         // 具体版本号，可能为空；版本约束只固定唯一版本（如 "=1.2.3"）时为该版本，否则为空
+	Category string `json:"category"`        // "frontend" | "backend" | "desktop" | "mobile"
+	Evidence string `json:"evidence"`        // 人类可读的检测原因
+	Usage    string `json:"usage,omitempty"` // 规则引用的依赖的使用情况，如 "declared-and-used"
+	Weight   int    `json:"weight"`          // 使用权重：导入规则所引用依赖的源码文件数量
+
+	DeclaredConstraint string `json:"declared_constraint,omitempty"` // 声明的版本约束原文，如 "^18.2.0"、">=2.0,<3"
+	NormalizedVersion  string `json:"normalized_version,omitempty"`  // 规范化的可比较版本，如 "1.0.0-rc.1"
+	UnresolvedVersion  string `json:"unresolved_version,omitempty"`  // 无法解析为版本的占位符，如 "${project.version}"、"latest"
+
+	Occurrences []Occurrence `json:"occurrences,omitempty"` // 检测项在各模块中的位置与版本，每个模块一条
+
+	Ecosystem       string          `json:"ecosystem,omitempty"`       // 提供版本的依赖所属生态，如 "maven"
+	Package         string          `json:"package,omitempty"`         // 提供版本的依赖包名称，如 "com.alibaba:fastjson"
+	Vulnerabilities []Vulnerability `json:"vulnerabilities,omitempty"` // 与检测项版本匹配的已知漏洞
+}
+
+// Occurrence 检测项在项目中的一处出现位置
+// - Path: 提供版本信息的文件相对路径（如 "services/order/pom.xml"）
+// - Module: 模块根目录的相对路径，项目根目录为 "."
+// - Line: Path 中声明提供版本的依赖包的行号（从 1 开始），无法确定时为 0
+// - Version/DeclaredConstraint/UnresolvedVersion: 与 DetectedItem 中的版本字段含义相同
+type Occurrence struct {
+	Path               string `json:"path"`
+	Line               int    `json:"line,omitempty"`
+	Module             string `json:"module"`
+	Version            string `json:"version"`
+	DeclaredConstraint string `json:"declared_constraint,omitempty"`
+	UnresolvedVersion  string `json:"unresolved_version,omitempty"`
+}
+
+// VersionSkew 同一框架或组件在不同模块中使用了不同版本
+// - Name: 框架或组件名称
+// - Type: "framework" 或 "component"
+// - Versions: 版本号到使用该版本的模块列表的映射
+type VersionSkew struct {
+	Name     string              `json:"name"`
+	Type     string              `json:"type"`
+	Versions map[string][]string `json:"versions"`
 }
 
 // LangInfo  某一编程语言或标记语言的详细统计数据。
@@ -78,16 +131,26 @@ type AnalysisResult struct {
 	BackendLanguages []string `json:"backend_languages"`
 	// 桌面语言列表
 	DesktopLanguages []string `json:"desktop_languages"`
+	// 移动端语言列表
+	MobileLanguages []string `json:"mobile_languages"`
 	// 其他语言列表
 	OtherLanguages []string `json:"other_languages"`
 	// 主要后端语言列表 (Top 3)
 	MainBackendLanguages []string `json:"main_backend_languages"`
 	// 主要前端语言列表 (Top 3)
 	MainFrontendLanguages []string `json:"main_frontend_languages"`
-	// 框架信息列表，名称到版本的映射
+	// 框架信息列表，名称到版本的映射，只声明了版本约束时为约束原文
 	Frameworks map[string]string `json:"frameworks"`
-	// 组件信息列表，名称到版本的映射
+	// 组件信息列表，名称到版本的映射，只声明了版本约束时为约束原文
 	Components map[string]string `json:"components"`
+	// 框架在各模块中出现的全部版本，名称到去重排序后版本列表的映射
+	FrameworkVersions map[string][]string `json:"framework_versions"`
+	// 组件在各模块中出现的全部版本，名称到去重排序后版本列表的映射
+	ComponentVersions map[string][]string `json:"component_versions"`
+	// 不同模块使用不同版本的框架与组件
+	VersionSkew []VersionSkew `json:"version_skew"`
+	// 构建系统列表
+	BuildSystems []string `json:"build_systems"`
 }
 
 // FrameworkMetadata 支持列表元数据 描述了 CodeCanvas 能够识别的一种框架。
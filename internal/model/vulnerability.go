@@ -0,0 +1,45 @@
+package model
+
+// SeverityUnknown 无法从公告中确定严重程度
+const SeverityUnknown = "unknown"
+
+// Vulnerability 与组件或依赖版本匹配的安全公告
+// - ID: 公告编号，如 "GHSA-xxxx-xxxx-xxxx"
+// - Aliases: 公告别名，如 CVE 编号
+// - Severity: 严重程度（low/medium/high/critical/unknown）
+// - Score: CVSS v3 基础分数，公告未提供 CVSS v3 向量时为 0
+// - Fixed: 修复该问题的版本
+type Vulnerability struct {
+	ID       string   `json:"id"`
+	Aliases  []string `json:"aliases,omitempty"`
+	Summary  string   `json:"summary,omitempty"`
+	Severity string   `json:"severity"`
+	Score    float64  `json:"score,omitempty"`
+	Fixed    []string `json:"fixed,omitempty"`
+}
+
+// VulnerableDependency 存在已知漏洞的依赖记录，Line 为清单中声明该依赖的行号，无法确定时为 0
+type VulnerableDependency struct {
+	Ecosystem       string          `json:"ecosystem"`
+	Name            string          `json:"name"`
+	Version         string          `json:"version"`
+	Manifest        string          `json:"manifest"`
+	Line            int             `json:"line,omitempty"`
+	Vulnerabilities []Vulnerability `json:"vulnerabilities"`
+}
+
+// VulnerabilitySummary 报告的漏洞匹配结果
+// - Database: 离线公告数据的路径
+// - Advisories: 数据中加载的公告数量
+// - Total: 匹配到的去重公告数量
+// - BySeverity: 匹配到的去重公告按严重程度的数量
+// - Components: 存在已知漏洞的框架与组件数量
+// - Dependencies: 存在已知漏洞的依赖记录，按生态、名称与版本排序
+type VulnerabilitySummary struct {
+	Database     string                 `json:"database"`
+	Advisories   int                    `json:"advisories"`
+	Total        int                    `json:"total"`
+	BySeverity   map[string]int         `json:"by_severity"`
+	Components   int                    `json:"components"`
+	Dependencies []VulnerableDependency `json:"dependencies"`
+}
@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SelectPath 按点分键路径从 JSON/YAML/TOML 解析结果中选择节点，返回所有匹配的值。
+// 路径语法：
+// - a.b.c：逐级选择对象的键
+// - "a.b" 或 'a.b'：包含 "." 等特殊字符的键使用引号
+// - a[0]、[0]：选择数组元素
+// - *、a[*]：选择对象的所有值或数组的所有元素
+func SelectPath(root any, path string) ([]any, error) {
+	segments, err := splitKeyPath(path)
+	if err != nil {
+		return nil, err
+	}
+	current := []any{root}
+	for _, segment := range segments {
+		var next []any
+		for _, value := range current {
+			next = append(next, selectSegment(value, segment)...)
+		}
+		if len(next) == 0 {
+			return nil, nil
+		}
+		current = next
+	}
+	return current, nil
+}
+
+// pathSegment 键路径中的一级：键名或数组下标
+type pathSegment struct {
+	key   string
+	index int
+	// isIndex 是否为数组下标（[n] 或 [*]）
+	isIndex bool
+	// wildcard 是否为通配（* 或 [*]）
+	wildcard bool
+}
+
+// splitKeyPath 将键路径拆分为各级，支持引号键与数组下标
+func splitKeyPath(path string) ([]pathSegment, error) {
+	var segments []pathSegment
+	i := 0
+	for i < len(path) {
+		switch c := path[i]; {
+		case c == '.':
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(path[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quoted key in path %q", path)
+			}
+			segments = append(segments, pathSegment{key: path[i+1 : i+1+end]})
+			i += end + 2
+		case c == '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated index in path %q", path)
+			}
+			inner := strings.TrimSpace(path[i+1 : i+end])
+			if inner == "" {
+				return nil, fmt.Errorf("empty index in path %q", path)
+			}
+			if inner == "*" {
+				segments = append(segments, pathSegment{isIndex: true, wildcard: true})
+			} else if n, err := strconv.Atoi(inner); err == nil {
+				segments = append(segments, pathSegment{isIndex: true, index: n})
+			} else {
+				// ["a.b"] 形式的引号键
+				segments = append(segments, pathSegment{key: strings.Trim(inner, `"'`)})
+			}
+			i += end + 1
+		default:
+			end := strings.IndexAny(path[i:], ".[")
+			if end < 0 {
+				end = len(path) - i
+			}
+			key := path[i : i+end]
+			segments = append(segments, pathSegment{key: key, wildcard: key == "*"})
+			i += end
+		}
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("empty path")
+	}
+	return segments, nil
+}
+
+// selectSegment 在单个值上应用一级路径
+func selectSegment(value any, segment pathSegment) []any {
+	switch v := value.(type) {
+	case map[string]any:
+		if segment.isIndex && !segment.wildcard {
+			return nil
+		}
+		if segment.wildcard {
+			var result []any
+			for _, key := range sortedMapKeys(v) {
+				result = append(result, v[key])
+			}
+			return result
+		}
+		if child, ok := v[segment.key]; ok {
+			return []any{child}
+		}
+	case map[any]any:
+		converted := make(map[string]any, len(v))
+		for key, child := range v {
+			converted[fmt.Sprint(key)] = child
+		}
+		return selectSegment(converted, segment)
+	case []any:
+		if segment.wildcard {
+			return v
+		}
+		if segment.isIndex && segment.index >= 0 && segment.index < len(v) {
+			return []any{v[segment.index]}
+		}
+	}
+	return nil
+}
+
+// ValueString 将选择到的标量值转换为字符串，对象与数组返回空字符串
+func ValueString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int, int64, bool:
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+// sortedMapKeys 返回排序后的 map 键，保证通配选择的顺序稳定
+func sortedMapKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
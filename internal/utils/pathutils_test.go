@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSelectPath(t *testing.T) {
+	doc := map[string]any{
+		"name": "app",
+		"dependencies": map[string]any{
+			"react":       "^18.2.0",
+			"@types/node": "20.1.0",
+		},
+		"exports": map[string]any{"./feature.js": "dist/feature.js"},
+		"workspace": map[string]any{
+			"members": []any{"crates/a", "crates/b"},
+		},
+		"package": []any{
+			map[string]any{"name": "a", "version": "1.0"},
+			map[string]any{"name": "b", "version": "2.0"},
+		},
+		"yaml": map[any]any{"key": "from yaml", 1: "numeric key"},
+		"port": float64(8080),
+	}
+
+	tests := []struct {
+		path string
+		want []any
+	}{
+		{"name", []any{"app"}},
+		{"dependencies.react", []any{"^18.2.0"}},
+		{`dependencies."@types/node"`, []any{"20.1.0"}},
+		{`exports."./feature.js"`, []any{"dist/feature.js"}},
+		{`exports['./feature.js']`, []any{"dist/feature.js"}},
+		{"workspace.members[1]", []any{"crates/b"}},
+		{"workspace.members[*]", []any{"crates/a", "crates/b"}},
+		{"package[*].version", []any{"1.0", "2.0"}},
+		{"package.*.name", []any{"a", "b"}},
+		{"dependencies.*", []any{"20.1.0", "^18.2.0"}},
+		{"yaml.key", []any{"from yaml"}},
+		{"yaml.1", []any{"numeric key"}},
+		{"port", []any{float64(8080)}},
+		{"workspace.members[2]", nil},
+		{"workspace.members[-1]", nil},
+		{"name[0]", nil},
+		{"dependencies[0]", nil},
+		{"missing.key", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := SelectPath(doc, tt.path)
+			if err != nil {
+				t.Fatalf("SelectPath(%q) returned error: %v", tt.path, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SelectPath(%q) = %#v, expected %#v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectPathInvalid(t *testing.T) {
+	for _, path := range []string{"", ".", "..", `dependencies."react`, "members[0", "members[]"} {
+		if got, err := SelectPath(map[string]any{}, path); err == nil {
+			t.Errorf("SelectPath(%q) = %#v, expected error", path, got)
+		}
+	}
+}
+
+func TestValueString(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{"1.0", "1.0"},
+		{float64(8080), "8080"},
+		{1.5, "1.5"},
+		{int64(3), "3"},
+		{7, "7"},
+		{true, "true"},
+		{nil, ""},
+		{map[string]any{"a": 1}, ""},
+		{[]any{"a"}, ""},
+	}
+	for _, tt := range tests {
+		if got := ValueString(tt.value); got != tt.want {
+			t.Errorf("ValueString(%#v) = %q, expected %q", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,435 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// tomlDateRe 匹配日期部分，如 1979-05-27
+	tomlDateRe = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	// tomlTimeRe 匹配以空格分隔的时间部分的开头，如 " 07:32"
+	tomlTimeRe = regexp.MustCompile(`^ \d{2}:\d{2}`)
+)
+
+// ParseTOML 将 TOML 文本解析为嵌套的 map 结构
+// 支持常用语法子集：表 [a.b]、表数组 [[a]]、点分键、字符串（含多行）、数字、布尔、数组和内联表。
+// 日期时间等其他类型按原始字符串保留。
+func ParseTOML(data []byte) (map[string]any, error) {
+	p := &tomlParser{src: string(data)}
+	return p.parse()
+}
+
+// tomlParser TOML 解析器状态
+type tomlParser struct {
+	src  string
+	pos  int
+	line int
+}
+
+func (p *tomlParser) parse() (map[string]any, error) {
+	root := make(map[string]any)
+	current := root
+
+	for {
+		p.skipBlank(true)
+		if p.eof() {
+			return root, nil
+		}
+
+		if p.peek() == '[' {
+			isArray := strings.HasPrefix(p.src[p.pos:], "[[")
+			if isArray {
+				p.pos += 2
+			} else {
+				p.pos++
+			}
+			keys, err := p.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			p.skipBlank(false)
+			closing := "]"
+			if isArray {
+				closing = "]]"
+			}
+			if !strings.HasPrefix(p.src[p.pos:], closing) {
+				return nil, p.errorf("expected %s", closing)
+			}
+			p.pos += len(closing)
+
+			if isArray {
+				parent, err := tomlTable(root, keys[:len(keys)-1])
+				if err != nil {
+					return nil, p.errorf("%v", err)
+				}
+				last := keys[len(keys)-1]
+				table := make(map[string]any)
+				arr, _ := parent[last].([]any)
+				parent[last] = append(arr, table)
+				current = table
+			} else {
+				table, err := tomlTable(root, keys)
+				if err != nil {
+					return nil, p.errorf("%v", err)
+				}
+				current = table
+			}
+		} else {
+			keys, err := p.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			p.skipBlank(false)
+			if p.eof() || p.peek() != '=' {
+				return nil, p.errorf("expected '=' after key")
+			}
+			p.pos++
+			p.skipBlank(false)
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			table, err := tomlTable(current, keys[:len(keys)-1])
+			if err != nil {
+				return nil, p.errorf("%v", err)
+			}
+			table[keys[len(keys)-1]] = value
+		}
+
+		// 行尾只允许空白和注释
+		p.skipBlank(false)
+		if !p.eof() && p.peek() != '\n' && p.peek() != '\r' {
+			return nil, p.errorf("unexpected character %q", p.peek())
+		}
+	}
+}
+
+// tomlTable 沿键路径查找或创建表，遇到表数组时使用最后一个元素
+func tomlTable(root map[string]any, keys []string) (map[string]any, error) {
+	table := root
+	for _, key := range keys {
+		switch v := table[key].(type) {
+		case nil:
+			next := make(map[string]any)
+			table[key] = next
+			table = next
+		case map[string]any:
+			table = v
+		case []any:
+			if len(v) == 0 {
+				return nil, fmt.Errorf("key %s is an empty array", key)
+			}
+			next, ok := v[len(v)-1].(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("key %s is not a table", key)
+			}
+			table = next
+		default:
+			return nil, fmt.Errorf("key %s is not a table", key)
+		}
+	}
+	return table, nil
+}
+
+// parseKey 解析点分键（支持裸键与引号键）
+func (p *tomlParser) parseKey() ([]string, error) {
+	var keys []string
+	for {
+		p.skipBlank(false)
+		if p.eof() {
+			return nil, p.errorf("unexpected end of input in key")
+		}
+		var key string
+		switch p.peek() {
+		case '"', '\'':
+			s, err := p.parseString()
+			if err != nil {
+				return nil, err
+			}
+			key = s
+		default:
+			start := p.pos
+			for !p.eof() && isTomlBareKeyChar(p.peek()) {
+				p.pos++
+			}
+			if start == p.pos {
+				return nil, p.errorf("invalid key character %q", p.peek())
+			}
+			key = p.src[start:p.pos]
+		}
+		keys = append(keys, key)
+
+		p.skipBlank(false)
+		if p.eof() || p.peek() != '.' {
+			return keys, nil
+		}
+		p.pos++
+	}
+}
+
+// parseValue 解析任意 TOML 值
+func (p *tomlParser) parseValue() (any, error) {
+	if p.eof() {
+		return nil, p.errorf("missing value")
+	}
+	switch c := p.peek(); c {
+	case '"', '\'':
+		return p.parseString()
+	case '[':
+		return p.parseArray()
+	case '{':
+		return p.parseInlineTable()
+	default:
+		start := p.pos
+		for !p.eof() && !strings.ContainsRune(",]}# \t\r\n", rune(p.peek())) {
+			p.pos++
+		}
+		// 日期与时间之间允许以空格分隔，如 1979-05-27 07:32:00
+		if tomlDateRe.MatchString(p.src[start:p.pos]) && tomlTimeRe.MatchString(p.src[p.pos:]) {
+			p.pos++
+			for !p.eof() && !strings.ContainsRune(",]}# \t\r\n", rune(p.peek())) {
+				p.pos++
+			}
+		}
+		token := p.src[start:p.pos]
+		if token == "" {
+			return nil, p.errorf("missing value")
+		}
+		switch token {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		clean := strings.ReplaceAll(token, "_", "")
+		if i, err := strconv.ParseInt(clean, 0, 64); err == nil {
+			return i, nil
+		}
+		if f, err := strconv.ParseFloat(clean, 64); err == nil {
+			return f, nil
+		}
+		// 其余合法的裸值只有日期时间，均以数字开头
+		if c := token[0]; c < '0' || c > '9' {
+			return nil, p.errorf("invalid value %q", token)
+		}
+		return token, nil
+	}
+}
+
+// parseArray 解析数组，允许跨行、注释和末尾逗号
+func (p *tomlParser) parseArray() ([]any, error) {
+	p.pos++ // '['
+	var arr []any
+	for {
+		p.skipBlank(true)
+		if p.eof() {
+			return nil, p.errorf("unterminated array")
+		}
+		if p.peek() == ']' {
+			p.pos++
+			return arr, nil
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, value)
+		p.skipBlank(true)
+		if p.eof() {
+			return nil, p.errorf("unterminated array")
+		}
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+		default:
+			return nil, p.errorf("expected ',' or ']' in array, got %q", p.peek())
+		}
+	}
+}
+
+// parseInlineTable 解析内联表 {a = 1, b = "x"}
+func (p *tomlParser) parseInlineTable() (map[string]any, error) {
+	p.pos++ // '{'
+	table := make(map[string]any)
+	for {
+		p.skipBlank(false)
+		if p.eof() {
+			return nil, p.errorf("unterminated inline table")
+		}
+		if p.peek() == '}' {
+			p.pos++
+			return table, nil
+		}
+		keys, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+		p.skipBlank(false)
+		if p.eof() || p.peek() != '=' {
+			return nil, p.errorf("expected '=' in inline table")
+		}
+		p.pos++
+		p.skipBlank(false)
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		target, err := tomlTable(table, keys[:len(keys)-1])
+		if err != nil {
+			return nil, p.errorf("%v", err)
+		}
+		target[keys[len(keys)-1]] = value
+		p.skipBlank(false)
+		if p.eof() {
+			return nil, p.errorf("unterminated inline table")
+		}
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+		default:
+			return nil, p.errorf("expected ',' or '}' in inline table, got %q", p.peek())
+		}
+	}
+}
+
+// parseString 解析基本字符串、字面量字符串及其多行形式
+func (p *tomlParser) parseString() (string, error) {
+	quote := p.peek()
+	multi := strings.Repeat(string(quote), 3)
+	if strings.HasPrefix(p.src[p.pos:], multi) {
+		p.pos += 3
+		// 紧跟开头引号的换行符会被忽略
+		if strings.HasPrefix(p.src[p.pos:], "\r\n") {
+			p.pos += 2
+		} else if strings.HasPrefix(p.src[p.pos:], "\n") {
+			p.pos++
+		}
+		end := strings.Index(p.src[p.pos:], multi)
+		if end < 0 {
+			return "", p.errorf("unterminated multi-line string")
+		}
+		raw := p.src[p.pos : p.pos+end]
+		p.line += strings.Count(raw, "\n")
+		p.pos += end + 3
+		if quote == '\'' {
+			return raw, nil
+		}
+		return unescapeTOML(raw), nil
+	}
+
+	p.pos++
+	var sb strings.Builder
+	for !p.eof() {
+		c := p.peek()
+		switch {
+		case c == quote:
+			p.pos++
+			if quote == '\'' {
+				return sb.String(), nil
+			}
+			return unescapeTOML(sb.String()), nil
+		case c == '\n':
+			return "", p.errorf("unterminated string")
+		case c == '\\' && quote == '"' && p.pos+1 < len(p.src):
+			sb.WriteByte(c)
+			sb.WriteByte(p.src[p.pos+1])
+			p.pos += 2
+		default:
+			sb.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// unescapeTOML 处理基本字符串中的转义序列
+func unescapeTOML(s string) string {
+	if !strings.Contains(s, "\\") {
+		return s
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 >= len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			sb.WriteByte('\n')
+		case 't':
+			sb.WriteByte('\t')
+		case 'r':
+			sb.WriteByte('\r')
+		case '"', '\\':
+			sb.WriteByte(s[i])
+		case 'u', 'U':
+			size := 4
+			if s[i] == 'U' {
+				size = 8
+			}
+			if i+size < len(s) {
+				if r, err := strconv.ParseUint(s[i+1:i+1+size], 16, 32); err == nil {
+					sb.WriteRune(rune(r))
+					i += size
+					continue
+				}
+			}
+			sb.WriteByte('\\')
+			sb.WriteByte(s[i])
+		case '\n', ' ', '\t', '\r':
+			// 行尾反斜杠：去除换行及后续空白
+			for i+1 < len(s) && strings.ContainsRune(" \t\r\n", rune(s[i+1])) {
+				i++
+			}
+		default:
+			sb.WriteByte('\\')
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String()
+}
+
+// skipBlank 跳过空白和注释，newlines 为 true 时同时跳过换行
+func (p *tomlParser) skipBlank(newlines bool) {
+	for !p.eof() {
+		c := p.peek()
+		switch {
+		case c == ' ' || c == '\t':
+			p.pos++
+		case c == '#':
+			for !p.eof() && p.peek() != '\n' {
+				p.pos++
+			}
+		case (c == '\n' || c == '\r') && newlines:
+			if c == '\n' {
+				p.line++
+			}
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *tomlParser) peek() byte {
+	return p.src[p.pos]
+}
+
+func (p *tomlParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *tomlParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("toml line %d: %s", p.line+1, fmt.Sprintf(format, args...))
+}
+
+// isTomlBareKeyChar 判断是否为裸键允许的字符
+func isTomlBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTOML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  map[string]any
+	}{
+		{
+			name:  "scalars and comments",
+			input: "# comment\nname = \"app\" # trailing\nport = 8_080\nratio = 0.5\nenabled = true\nhex = 0xff\n",
+			want:  map[string]any{"name": "app", "port": int64(8080), "ratio": 0.5, "enabled": true, "hex": int64(255)},
+		},
+		{
+			name:  "dotted keys",
+			input: "tool.poetry.name = \"demo\"\ntool.poetry.version = \"1.0\"\n",
+			want:  map[string]any{"tool": map[string]any{"poetry": map[string]any{"name": "demo", "version": "1.0"}}},
+		},
+		{
+			name:  "quoted keys",
+			input: "[dependencies]\n\"serde_json\" = \"1.0\"\n'literal.key' = \"x\"\nsite.\"google.com\" = true\n",
+			want: map[string]any{"dependencies": map[string]any{
+				"serde_json":  "1.0",
+				"literal.key": "x",
+				"site":        map[string]any{"google.com": true},
+			}},
+		},
+		{
+			name:  "tables",
+			input: "[package]\nname = \"crate\"\n\n[target.'cfg(unix)'.dependencies]\nlibc = \"0.2\"\n",
+			want: map[string]any{
+				"package": map[string]any{"name": "crate"},
+				"target":  map[string]any{"cfg(unix)": map[string]any{"dependencies": map[string]any{"libc": "0.2"}}},
+			},
+		},
+		{
+			name:  "inline tables",
+			input: "serde = { version = \"1.0\", features = [\"derive\"], opt.level = 3 }\nempty = {}\n",
+			want: map[string]any{
+				"serde": map[string]any{"version": "1.0", "features": []any{"derive"}, "opt": map[string]any{"level": int64(3)}},
+				"empty": map[string]any{},
+			},
+		},
+		{
+			name:  "arrays of tables",
+			input: "[[package]]\nname = \"a\"\n[package.source]\nurl = \"x\"\n[[package]]\nname = \"b\"\n",
+			want: map[string]any{"package": []any{
+				map[string]any{"name": "a", "source": map[string]any{"url": "x"}},
+				map[string]any{"name": "b"},
+			}},
+		},
+		{
+			name:  "multi-line arrays",
+			input: "deps = [\n  \"a\", # first\n  \"b\",\n]\nnested = [[1, 2], [3]]\n",
+			want:  map[string]any{"deps": []any{"a", "b"}, "nested": []any{[]any{int64(1), int64(2)}, []any{int64(3)}}},
+		},
+		{
+			name:  "multi-line strings",
+			input: "basic = \"\"\"\nline1\nline2\\\n   continued\"\"\"\nliteral = '''\nC:\\path\\n'''\n",
+			want:  map[string]any{"basic": "line1\nline2continued", "literal": "C:\\path\\n"},
+		},
+		{
+			name:  "escapes",
+			input: "s = \"tab\\tquote\\\"\\u00e9\"\nraw = 'no\\escape'\n",
+			want:  map[string]any{"s": "tab\tquote\"é", "raw": "no\\escape"},
+		},
+		{
+			name:  "date-times",
+			input: "a = 1979-05-27T07:32:00Z\nb = 1979-05-27 07:32:00\nc = 1979-05-27\n",
+			want:  map[string]any{"a": "1979-05-27T07:32:00Z", "b": "1979-05-27 07:32:00", "c": "1979-05-27"},
+		},
+		{
+			name:  "CRLF line endings",
+			input: "[a]\r\nb = 1\r\n",
+			want:  map[string]any{"a": map[string]any{"b": int64(1)}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTOML([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("ParseTOML(%q) returned error: %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTOML(%q) = %#v, expected %#v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTOMLInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"two key-values on one line", "a = 1 b = 2\n"},
+		{"content after string", "a = \"x\" b = 2\n"},
+		{"content after table header", "[a] b = 1\n"},
+		{"missing equals", "a 1\n"},
+		{"missing value", "a =\n"},
+		{"bare word value", "a = hello\n"},
+		{"unterminated string", "a = \"x\n"},
+		{"unterminated multi-line string", "a = \"\"\"x\n"},
+		{"unterminated array", "a = [1, 2\n"},
+		{"array without comma", "a = [1 2]\n"},
+		{"unterminated inline table", "a = { b = 1\n"},
+		{"inline table without comma", "a = { b = 1 c = 2 }\n"},
+		{"unclosed table header", "[a\n"},
+		{"unclosed array of tables header", "[[a]\n"},
+		{"table over value", "a = 1\n[a.b]\n"},
+		{"invalid key character", "a! = 1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := ParseTOML([]byte(tt.input)); err == nil {
+				t.Errorf("ParseTOML(%q) = %#v, expected error", tt.input, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// XMLNode 通用 XML 节点，用于解析结构不固定的清单文件（如 csproj、pom.xml）
+type XMLNode struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Content string     `xml:",chardata"`
+	Nodes   []*XMLNode `xml:",any"`
+}
+
+// ParseXML 将 XML 文本解析为节点树，返回根节点
+func ParseXML(data []byte) (*XMLNode, error) {
+	var root XMLNode
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	// 清单文件常见非 UTF-8 声明，按原样读取字节
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	decoder.Strict = false
+	if err := decoder.Decode(&root); err != nil {
+		return nil, err
+	}
+	return &root, nil
+}
+
+// Name 返回节点的本地名称（不含命名空间）
+func (n *XMLNode) Name() string {
+	return n.XMLName.Local
+}
+
+// Text 返回去除首尾空白的文本内容
+func (n *XMLNode) Text() string {
+	return strings.TrimSpace(n.Content)
+}
+
+// Attr 返回指定名称的属性值（不区分大小写），不存在时返回空字符串
+func (n *XMLNode) Attr(name string) string {
+	for _, attr := range n.Attrs {
+		if strings.EqualFold(attr.Name.Local, name) {
+			return strings.TrimSpace(attr.Value)
+		}
+	}
+	return ""
+}
+
+// Children 返回指定名称的直接子节点（不区分大小写）
+func (n *XMLNode) Children(name string) []*XMLNode {
+	var result []*XMLNode
+	for _, child := range n.Nodes {
+		if strings.EqualFold(child.Name(), name) {
+			result = append(result, child)
+		}
+	}
+	return result
+}
+
+// Child 返回第一个指定名称的直接子节点，不存在时返回 nil
+func (n *XMLNode) Child(name string) *XMLNode {
+	for _, child := range n.Nodes {
+		if strings.EqualFold(child.Name(), name) {
+			return child
+		}
+	}
+	return nil
+}
+
+// ChildText 返回第一个指定名称的直接子节点的文本，不存在时返回空字符串
+func (n *XMLNode) ChildText(name string) string {
+	if child := n.Child(name); child != nil {
+		return child.Text()
+	}
+	return ""
+}
+
+// FindAll 递归查找所有指定名称的后代节点（不区分大小写）
+func (n *XMLNode) FindAll(name string) []*XMLNode {
+	var result []*XMLNode
+	for _, child := range n.Nodes {
+		if strings.EqualFold(child.Name(), name) {
+			result = append(result, child)
+		}
+		result = append(result, child.FindAll(name)...)
+	}
+	return result
+}
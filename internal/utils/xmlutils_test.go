@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestParseXML(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		root string
+	}{
+		{"plain", "<project><name>app</name></project>", "project"},
+		{"byte order mark", "\xef\xbb\xbf<?xml version=\"1.0\"?><project/>", "project"},
+		{"non UTF-8 declaration", "<?xml version=\"1.0\" encoding=\"GBK\"?><project/>", "project"},
+		{"namespaced root", `<m:project xmlns:m="urn:m"/>`, "project"},
+		{"unknown entity", "<project><name>a &nbsp; b</name></project>", "project"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := ParseXML([]byte(tt.doc))
+			if err != nil {
+				t.Fatalf("ParseXML(%q) returned error: %v", tt.doc, err)
+			}
+			if root.Name() != tt.root {
+				t.Errorf("ParseXML(%q) root = %q, expected %q", tt.doc, root.Name(), tt.root)
+			}
+		})
+	}
+}
+
+func TestParseXMLInvalid(t *testing.T) {
+	for _, doc := range []string{"", "not xml", "<project><name>app", "<project attr=\"x></project>"} {
+		if _, err := ParseXML([]byte(doc)); err == nil {
+			t.Errorf("ParseXML(%q) expected error", doc)
+		}
+	}
+}
+
+func TestXMLNodeAccessors(t *testing.T) {
+	root, err := ParseXML([]byte(`<Project Sdk=" Microsoft.NET.Sdk ">
+  <PropertyGroup><TargetFramework>
+    net8.0
+  </TargetFramework></PropertyGroup>
+  <ItemGroup>
+    <PackageReference Include="Serilog" Version="3.1.1" />
+    <packagereference Include="Dapper" />
+  </ItemGroup>
+  <ItemGroup><ProjectReference Include="../Lib/Lib.csproj" /></ItemGroup>
+</Project>`))
+	if err != nil {
+		t.Fatalf("ParseXML returned error: %v", err)
+	}
+
+	if got := root.Attr("sdk"); got != "Microsoft.NET.Sdk" {
+		t.Errorf("Attr(sdk) = %q, expected trimmed case-insensitive match", got)
+	}
+	if got := root.Attr("missing"); got != "" {
+		t.Errorf("Attr(missing) = %q, expected empty", got)
+	}
+	if got := len(root.Children("itemgroup")); got != 2 {
+		t.Errorf("Children(itemgroup) returned %d nodes, expected 2", got)
+	}
+	if got := root.Child("PropertyGroup").ChildText("TargetFramework"); got != "net8.0" {
+		t.Errorf("ChildText(TargetFramework) = %q, expected net8.0", got)
+	}
+	if root.Child("Missing") != nil || root.ChildText("Missing") != "" {
+		t.Errorf("missing child should return nil and empty text")
+	}
+	references := root.FindAll("PackageReference")
+	if len(references) != 2 || references[0].Attr("Include") != "Serilog" || references[1].Attr("Include") != "Dapper" {
+		t.Errorf("FindAll(PackageReference) returned unexpected nodes: %d", len(references))
+	}
+	if got := len(root.FindAll("ProjectReference")); got != 1 {
+		t.Errorf("FindAll(ProjectReference) returned %d nodes, expected 1", got)
+	}
+}
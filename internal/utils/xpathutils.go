@@ -0,0 +1,279 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// xpathNameRe 支持的步骤：节点名称（可带命名空间前缀）、*、@属性、@* 与 text()
+	xpathNameRe = regexp.MustCompile(`^(\*|text\(\)|@\*|@?[A-Za-z_][\w.-]*(:[A-Za-z_][\w.-]*)?)$`)
+	// xpathPredicateRe 支持的谓词：位置 n，以及 name、@attr、text()、. 可选地与引号中的值比较
+	xpathPredicateRe = regexp.MustCompile(`^(\d+|(text\(\)|\.|@?[A-Za-z_][\w.-]*(:[A-Za-z_][\w.-]*)?)(\s*=\s*('[^']*'|"[^"]*"))?)$`)
+)
+
+// xpathStep XPath 表达式中的一步
+type xpathStep struct {
+	// descendant 是否为后代轴（//）
+	descendant bool
+	// name 节点名称，* 表示任意节点；以 @ 开头表示属性；text() 表示文本
+	name       string
+	predicates []string
+}
+
+// SelectXPath 使用简化的 XPath 从节点树中选择节点，返回所选元素的文本或属性值。
+// 以当前节点作为根元素，支持的语法：
+// - /project/dependencies/dependency：从根元素开始的绝对路径
+// - //dependency：任意位置的后代节点，不以 / 开头的路径等同于 // 开头
+// - *：任意名称的节点
+// - [artifactId='rome']、[@Include='Newtonsoft.Json']、[@Version]、[1]：子节点值、属性值、属性存在与位置谓词
+// - 末尾的 /@attr 与 /text()：选择属性值或文本
+// 名称匹配忽略命名空间且不区分大小写。选择到的元素没有文本时返回空字符串，可通过结果数量判断是否存在。
+// 表达式为空或包含不支持的语法时返回错误。
+func (n *XMLNode) SelectXPath(expr string) ([]string, error) {
+	steps, err := parseXPath(expr)
+	if err != nil {
+		return nil, err
+	}
+	// 虚拟文档节点，根元素是其唯一子节点
+	current := []*XMLNode{{Nodes: []*XMLNode{n}}}
+	for i, step := range steps {
+		last := i == len(steps)-1
+		if strings.HasPrefix(step.name, "@") || step.name == "text()" {
+			if !last {
+				return nil, fmt.Errorf("xpath %q: %s must be the last step", expr, step.name)
+			}
+			return selectXPathValues(current, step), nil
+		}
+		var next []*XMLNode
+		for _, node := range current {
+			var candidates []*XMLNode
+			if step.descendant {
+				candidates = node.findAllMatching(step.name)
+			} else {
+				for _, child := range node.Nodes {
+					if xpathNameMatch(step.name, child.Name()) {
+						candidates = append(candidates, child)
+					}
+				}
+			}
+			next = append(next, filterXPathPredicates(candidates, step.predicates)...)
+		}
+		if len(next) == 0 {
+			return nil, nil
+		}
+		current = next
+	}
+	values := make([]string, 0, len(current))
+	for _, node := range current {
+		values = append(values, node.Text())
+	}
+	return values, nil
+}
+
+// parseXPath 将 XPath 表达式拆分为步骤，忽略谓词与引号中的 /
+func parseXPath(expr string) ([]xpathStep, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("empty xpath")
+	}
+	if !strings.HasPrefix(expr, "/") {
+		expr = "//" + expr
+	}
+	var steps []xpathStep
+	i := 0
+	for i < len(expr) {
+		if expr[i] != '/' {
+			return nil, fmt.Errorf("xpath %q: unexpected %q at %d", expr, expr[i], i)
+		}
+		step := xpathStep{}
+		i++
+		if i < len(expr) && expr[i] == '/' {
+			step.descendant = true
+			i++
+		}
+		start := i
+		depth := 0
+		var quote byte
+		for i < len(expr) {
+			c := expr[i]
+			if quote != 0 {
+				if c == quote {
+					quote = 0
+				}
+			} else if c == '\'' || c == '"' {
+				quote = c
+			} else if c == '[' {
+				depth++
+			} else if c == ']' {
+				if depth--; depth < 0 {
+					return nil, fmt.Errorf("xpath %q: unbalanced predicate", expr)
+				}
+			} else if c == '/' && depth == 0 {
+				break
+			}
+			i++
+		}
+		if quote != 0 || depth != 0 {
+			return nil, fmt.Errorf("xpath %q: unbalanced predicate", expr)
+		}
+		raw := expr[start:i]
+		name, predicates, err := splitXPathPredicates(raw)
+		if err != nil {
+			return nil, fmt.Errorf("xpath %q: %v", expr, err)
+		}
+		if name == "" {
+			return nil, fmt.Errorf("xpath %q: empty step", expr)
+		}
+		if !xpathNameRe.MatchString(name) {
+			return nil, fmt.Errorf("xpath %q: unsupported step %q", expr, name)
+		}
+		if (strings.HasPrefix(name, "@") || name == "text()") && len(predicates) > 0 {
+			return nil, fmt.Errorf("xpath %q: predicates are not supported on %s", expr, name)
+		}
+		step.name = name
+		step.predicates = predicates
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// splitXPathPredicates 拆分步骤中的节点名称与谓词列表，谓词之后出现其他字符或谓词语法不受支持时返回错误
+func splitXPathPredicates(raw string) (string, []string, error) {
+	open := strings.IndexByte(raw, '[')
+	if open < 0 {
+		return strings.TrimSpace(raw), nil, nil
+	}
+	name := strings.TrimSpace(raw[:open])
+	var predicates []string
+	rest := raw[open:]
+	for len(rest) > 0 {
+		if rest[0] != '[' {
+			return "", nil, fmt.Errorf("unexpected %q after predicate", rest)
+		}
+		var quote byte
+		end := -1
+		for j := 1; j < len(rest); j++ {
+			c := rest[j]
+			if quote != 0 {
+				if c == quote {
+					quote = 0
+				}
+			} else if c == '\'' || c == '"' {
+				quote = c
+			} else if c == ']' {
+				end = j
+				break
+			}
+		}
+		if end < 0 {
+			return "", nil, fmt.Errorf("unbalanced predicate")
+		}
+		predicate := strings.TrimSpace(rest[1:end])
+		if !xpathPredicateRe.MatchString(predicate) {
+			return "", nil, fmt.Errorf("unsupported predicate [%s]", predicate)
+		}
+		predicates = append(predicates, predicate)
+		rest = rest[end+1:]
+	}
+	return name, predicates, nil
+}
+
+// filterXPathPredicates 按顺序应用谓词过滤节点
+func filterXPathPredicates(nodes []*XMLNode, predicates []string) []*XMLNode {
+	for _, predicate := range predicates {
+		if position, err := strconv.Atoi(predicate); err == nil {
+			if position < 1 || position > len(nodes) {
+				return nil
+			}
+			nodes = []*XMLNode{nodes[position-1]}
+			continue
+		}
+		var filtered []*XMLNode
+		for _, node := range nodes {
+			if matchXPathPredicate(node, predicate) {
+				filtered = append(filtered, node)
+			}
+		}
+		nodes = filtered
+	}
+	return nodes
+}
+
+// matchXPathPredicate 判断节点是否满足 name='value'、@attr='value'、@attr 或 name 形式的谓词
+func matchXPathPredicate(node *XMLNode, predicate string) bool {
+	name, value, hasValue := strings.Cut(predicate, "=")
+	name = strings.TrimSpace(name)
+	if hasValue {
+		value = strings.Trim(strings.TrimSpace(value), `'"`)
+	}
+	if attr, ok := strings.CutPrefix(name, "@"); ok {
+		for _, a := range node.Attrs {
+			if xpathNameMatch(attr, a.Name.Local) {
+				return !hasValue || strings.TrimSpace(a.Value) == value
+			}
+		}
+		return false
+	}
+	if name == "text()" || name == "." {
+		if !hasValue {
+			return node.Text() != ""
+		}
+		return node.Text() == value
+	}
+	for _, child := range node.Nodes {
+		if xpathNameMatch(name, child.Name()) && (!hasValue || child.Text() == value) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectXPathValues 选择节点的属性值或文本
+func selectXPathValues(nodes []*XMLNode, step xpathStep) []string {
+	var values []string
+	for _, node := range nodes {
+		candidates := []*XMLNode{node}
+		if step.descendant {
+			candidates = append(candidates, node.findAllMatching("*")...)
+		}
+		for _, candidate := range candidates {
+			if step.name == "text()" {
+				values = append(values, candidate.Text())
+				continue
+			}
+			attr := strings.TrimPrefix(step.name, "@")
+			for _, a := range candidate.Attrs {
+				if xpathNameMatch(attr, a.Name.Local) {
+					values = append(values, strings.TrimSpace(a.Value))
+				}
+			}
+		}
+	}
+	return values
+}
+
+// findAllMatching 递归查找所有名称匹配的后代节点，* 匹配任意节点
+func (n *XMLNode) findAllMatching(name string) []*XMLNode {
+	var result []*XMLNode
+	for _, child := range n.Nodes {
+		if xpathNameMatch(name, child.Name()) {
+			result = append(result, child)
+		}
+		result = append(result, child.findAllMatching(name)...)
+	}
+	return result
+}
+
+// xpathNameMatch 判断节点名称是否匹配，忽略命名空间前缀且不区分大小写
+func xpathNameMatch(pattern, name string) bool {
+	if pattern == "*" {
+		return true
+	}
+	if i := strings.IndexByte(pattern, ':'); i >= 0 {
+		pattern = pattern[i+1:]
+	}
+	return strings.EqualFold(pattern, name)
+}
@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testPom = `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"
+         xsi:schemaLocation="http://maven.apache.org/POM/4.0.0 https://maven.apache.org/xsd/maven-4.0.0.xsd">
+  <groupId>com.example</groupId>
+  <dependencies>
+    <dependency><groupId>rome</groupId><artifactId>rome</artifactId><version>1.0</version></dependency>
+    <dependency><groupId>org.hibernate</groupId><artifactId>hibernate-core</artifactId><version>5.4.2</version><scope>test</scope></dependency>
+  </dependencies>
+  <build><plugins><plugin><artifactId>maven-compiler-plugin</artifactId><version>3.11.0</version></plugin></plugins></build>
+</project>`
+
+const testCsproj = `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup><TargetFramework>net8.0</TargetFramework></PropertyGroup>
+  <ItemGroup>
+    <PackageReference Include="Newtonsoft.Json" Version="13.0.3" />
+    <PackageReference Include="Serilog" Version="3.1.1" />
+    <PackageReference Include="Local.Project" />
+  </ItemGroup>
+</Project>`
+
+const testNamespaced = `<m:root xmlns:m="urn:m" xmlns:x="urn:x">
+  <m:item x:id="a">first</m:item>
+  <m:item x:id="b">second</m:item>
+</m:root>`
+
+func TestSelectXPath(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		expr string
+		want []string
+	}{
+		{"absolute path", testPom, "/project/groupId", []string{"com.example"}},
+		{"descendant", testPom, "//dependency/artifactId", []string{"rome", "hibernate-core"}},
+		{"relative path is descendant", testPom, "artifactId", []string{"rome", "hibernate-core", "maven-compiler-plugin"}},
+		{"child value predicate", testPom, "//dependencies/dependency[artifactId='rome']/version", []string{"1.0"}},
+		{"double-quoted predicate", testPom, `//dependency[artifactId="hibernate-core"]/version`, []string{"5.4.2"}},
+		{"child existence predicate", testPom, "//dependency[scope]/artifactId", []string{"hibernate-core"}},
+		{"position predicate", testPom, "//dependency[2]/groupId", []string{"org.hibernate"}},
+		{"chained predicates", testPom, "//dependency[groupId='rome'][1]/version", []string{"1.0"}},
+		{"position out of range", testPom, "//dependency[3]", nil},
+		{"wildcard step", testPom, "/project/*/dependency[1]/version", []string{"1.0"}},
+		{"case-insensitive names", testPom, "/PROJECT/GroupId", []string{"com.example"}},
+		{"element without text", testPom, "/project/dependencies/dependency[1]", []string{""}},
+		{"no match", testPom, "//parent/version", nil},
+		{"root attribute", testCsproj, "/Project/@Sdk", []string{"Microsoft.NET.Sdk"}},
+		{"attribute value predicate", testCsproj, "//PackageReference[@Include='Newtonsoft.Json']/@Version", []string{"13.0.3"}},
+		{"attribute existence predicate", testCsproj, "//PackageReference[@Version]/@Include", []string{"Newtonsoft.Json", "Serilog"}},
+		{"all attributes", testCsproj, "//PackageReference[@Include='Serilog']/@*", []string{"Serilog", "3.1.1"}},
+		{"descendant attributes", testCsproj, "/Project/ItemGroup//@Include", []string{"Newtonsoft.Json", "Serilog", "Local.Project"}},
+		{"text step", testCsproj, "//TargetFramework/text()", []string{"net8.0"}},
+		{"text predicate", testCsproj, "//PropertyGroup/TargetFramework[.='net8.0']", []string{"net8.0"}},
+		{"namespace prefix ignored", testNamespaced, "/m:root/m:item[2]", []string{"second"}},
+		{"namespaced attribute", testNamespaced, "//item[@x:id='a']", []string{"first"}},
+		{"unprefixed name in namespaced document", testNamespaced, "//item/@id", []string{"a", "b"}},
+		{"default namespace ignored", testPom, "/project/@xsi:schemaLocation", []string{"http://maven.apache.org/POM/4.0.0 https://maven.apache.org/xsd/maven-4.0.0.xsd"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := ParseXML([]byte(tt.doc))
+			if err != nil {
+				t.Fatalf("ParseXML returned error: %v", err)
+			}
+			got, err := root.SelectXPath(tt.expr)
+			if err != nil {
+				t.Fatalf("SelectXPath(%q) returned error: %v", tt.expr, err)
+			}
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SelectXPath(%q) = %q, expected %q", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectXPathInvalid(t *testing.T) {
+	root, err := ParseXML([]byte(testPom))
+	if err != nil {
+		t.Fatalf("ParseXML returned error: %v", err)
+	}
+	for _, expr := range []string{
+		"",
+		"   ",
+		"/",
+		"//",
+		"/project/",
+		"/project//",
+		"/project/@groupId/version",
+		"//version/text()/x",
+		"//dependency[artifactId='rome'",
+		"//dependency[artifactId='rome]",
+		"//dependency]",
+		"//dependency][",
+		"//dependency[]",
+		"//dependency[1]x",
+		"//dependency[artifactId=rome]",
+		"//dependency[artifactId!='rome']",
+		"//dependency[position()=1]",
+		"//dependency[contains(artifactId,'rome')]",
+		"//dependency/@version[1]",
+		"//dependency/count()",
+		"//depend ency",
+		"/project/../groupId",
+		"/project/./groupId",
+	} {
+		if got, err := root.SelectXPath(expr); err == nil {
+			t.Errorf("SelectXPath(%q) = %q, expected error", expr, got)
+		}
+	}
+}
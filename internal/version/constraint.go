@@ -0,0 +1,358 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 版本约束语法
+const (
+	// SchemeSemver npm、Cargo 与 Go 使用的 SemVer 范围：^、~、x 通配、a - b 连字符范围、空格表示 AND、|| 表示 OR
+	SchemeSemver = "semver"
+	// SchemeComposer Composer 约束：与 SemVer 相同，但 ~1.2 表示 >=1.2 <2.0，逗号同样表示 AND
+	SchemeComposer = "composer"
+	// SchemePEP440 PyPI 约束：逗号表示 AND，~= 兼容版本，==1.2.* 前缀匹配，!= 排除
+	SchemePEP440 = "pep440"
+	// SchemeMaven Maven 版本范围：[1.0,2.0)、(,1.0]、[1.2]，多个范围之间为 OR
+	SchemeMaven = "maven"
+	// SchemeRubyGems RubyGems/CocoaPods 约束：~> 悲观约束，逗号表示 AND
+	SchemeRubyGems = "rubygems"
+)
+
+// comparator 单个比较条件
+// - op: =、!=、>、>=、<、<=，以及表示“不以该发布号为前缀”的 !^
+// - literal: 版本号是否为约束中直接写出的完整版本（非通配或范围展开得到）
+type comparator struct {
+	op      string
+	version Version
+	literal bool
+}
+
+// Constraint 解析后的版本约束，sets 之间为 OR，set 内的比较条件为 AND
+type Constraint struct {
+	Raw    string
+	Scheme string
+	sets   [][]comparator
+}
+
+// operatorRe 拆分约束中的运算符与版本号
+var operatorRe = regexp.MustCompile(`^(===|==|!=|~=|~>|>=|<=|>|<|=|\^|~)?\s*(.*)$`)
+
+// ParseConstraint 按指定语法解析版本约束，scheme 为空时按 SemVer 解析（Maven 范围写法会自动识别）
+func ParseConstraint(raw, scheme string) (*Constraint, error) {
+	c := &Constraint{Raw: strings.TrimSpace(raw), Scheme: scheme}
+	if c.Raw == "" {
+		return nil, fmt.Errorf("empty constraint")
+	}
+	if scheme == SchemeMaven || strings.HasPrefix(c.Raw, "[") || strings.HasPrefix(c.Raw, "(") {
+		if err := c.parseMaven(); err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+	for _, part := range strings.Split(c.Raw, "||") {
+		set, err := parseSet(strings.TrimSpace(part), scheme)
+		if err != nil {
+			return nil, fmt.Errorf("invalid constraint %q: %w", raw, err)
+		}
+		c.sets = append(c.sets, set)
+	}
+	return c, nil
+}
+
+// Check 判断版本是否满足约束
+func (c *Constraint) Check(v Version) bool {
+	for _, set := range c.sets {
+		matched := true
+		for _, cmp := range set {
+			if !cmp.check(v) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Exact 返回约束锁定的唯一版本（如 ==4.2.1、=1.2.3、[1.2] 中的 4.2.1、1.2.3、1.2）。
+// 约束允许多个版本（如 ^1.2.3、>=2.0,<3）时返回 false
+func (c *Constraint) Exact() (Version, bool) {
+	if len(c.sets) != 1 || len(c.sets[0]) != 1 {
+		return Version{}, false
+	}
+	if cmp := c.sets[0][0]; cmp.op == "=" && cmp.literal {
+		return cmp.version, true
+	}
+	return Version{}, false
+}
+
+// check 判断版本是否满足单个比较条件
+func (cmp comparator) check(v Version) bool {
+	result := Compare(v, cmp.version)
+	switch cmp.op {
+	case "=":
+		return result == 0
+	case "!=":
+		return result != 0
+	case ">":
+		return result > 0
+	case ">=":
+		return result >= 0
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	case "!^":
+		// 不以指定发布号为前缀（如 PEP 440 的 !=1.2.*）
+		for i, n := range cmp.version.Release {
+			if segment(v.Release, i) != n {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// parseSet 解析一组 AND 关系的约束
+func parseSet(s, scheme string) ([]comparator, error) {
+	if s == "" || s == "*" || strings.EqualFold(s, "x") {
+		return []comparator{{op: ">=", version: Version{Release: []int{0}}}}, nil
+	}
+	// 连字符范围：1.2.3 - 2.3.4
+	if lower, upper, ok := strings.Cut(s, " - "); ok {
+		low, err := parseTerm(">=", strings.TrimSpace(lower), scheme)
+		if err != nil {
+			return nil, err
+		}
+		high, err := parseTerm("<=", strings.TrimSpace(upper), scheme)
+		if err != nil {
+			return nil, err
+		}
+		return append(low, high...), nil
+	}
+
+	var set []comparator
+	for _, term := range splitTerms(s) {
+		m := operatorRe.FindStringSubmatch(term)
+		cmps, err := parseTerm(m[1], strings.TrimSpace(m[2]), scheme)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, cmps...)
+	}
+	if len(set) == 0 {
+		return nil, fmt.Errorf("empty constraint")
+	}
+	return set, nil
+}
+
+// splitTerms 按逗号与空白拆分约束条件，运算符与版本号之间的空白（如 ">= 1.0"）不拆分
+func splitTerms(s string) []string {
+	var terms []string
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+		if n := len(terms); n > 0 && operatorRe.FindStringSubmatch(terms[n-1])[2] == "" {
+			terms[n-1] += field
+			continue
+		}
+		terms = append(terms, field)
+	}
+	return terms
+}
+
+// parseTerm 将单个运算符与版本号展开为比较条件
+func parseTerm(op, text, scheme string) ([]comparator, error) {
+	if text == "" {
+		return nil, fmt.Errorf("missing version after %q", op)
+	}
+	if parts, wildcard, ok := partialRelease(text); ok && (wildcard || (op == "" && scheme == SchemeSemver && len(parts) < 3)) {
+		// 通配与不完整版本（npm 中 1.2 等同于 1.2.x）按前缀范围展开
+		return prefixRange(op, parts), nil
+	}
+
+	v, err := Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	switch op {
+	case "", "=", "==", "===":
+		return []comparator{{op: "=", version: v, literal: true}}, nil
+	case "!=":
+		return []comparator{{op: "!=", version: v}}, nil
+	case ">", "<", "<=":
+		return []comparator{{op: op, version: v}}, nil
+	case ">=":
+		return []comparator{{op: ">=", version: v, literal: true}}, nil
+	case "^":
+		return []comparator{{op: ">=", version: v, literal: true}, {op: "<", version: caretUpper(v)}}, nil
+	case "~":
+		if scheme == SchemeComposer {
+			return []comparator{{op: ">=", version: v, literal: true}, {op: "<", version: pessimisticUpper(v)}}, nil
+		}
+		return []comparator{{op: ">=", version: v, literal: true}, {op: "<", version: tildeUpper(v)}}, nil
+	case "~>", "~=":
+		return []comparator{{op: ">=", version: v, literal: true}, {op: "<", version: pessimisticUpper(v)}}, nil
+	}
+	return nil, fmt.Errorf("unsupported operator %q", op)
+}
+
+// partialRelease 解析可能以通配段结尾的发布号前缀，如 1.2.x -> [1 2]、*、1.+。
+// 第三个返回值表示文本是否为纯数字段（可带通配段）组成的前缀
+func partialRelease(text string) ([]int, bool, bool) {
+	text = strings.TrimPrefix(strings.TrimPrefix(text, "v"), "V")
+	var parts []int
+	for _, field := range strings.Split(text, ".") {
+		switch strings.ToLower(field) {
+		case "x", "*", "+":
+			return parts, true, true
+		}
+		n, err := strconv.Atoi(field)
+		if err != nil || field[0] == '+' || field[0] == '-' {
+			return nil, false, false
+		}
+		parts = append(parts, n)
+	}
+	return parts, false, true
+}
+
+// prefixRange 将发布号前缀展开为范围：1.2 -> >=1.2.0 <1.3.0-dev；空前缀匹配任意版本
+func prefixRange(op string, parts []int) []comparator {
+	lower := Version{Release: append([]int(nil), parts...)}
+	if len(parts) == 0 {
+		lower.Release = []int{0}
+		if op == "!=" || op == "<" || op == ">" {
+			return []comparator{{op: "<", version: lower}}
+		}
+		return []comparator{{op: ">=", version: lower}}
+	}
+	upper := bump(parts, len(parts)-1)
+	switch op {
+	case "!=":
+		return []comparator{{op: "!^", version: lower}}
+	case ">":
+		return []comparator{{op: ">=", version: upper}}
+	case ">=":
+		return []comparator{{op: ">=", version: lower}}
+	case "<":
+		return []comparator{{op: "<", version: lower}}
+	case "<=":
+		return []comparator{{op: "<", version: upper}}
+	}
+	return []comparator{{op: ">=", version: lower}, {op: "<", version: upper}}
+}
+
+// caretUpper ^ 约束的上限：递增第一个非零段，如 ^1.2.3 -> 2.0.0，^0.2.3 -> 0.3.0
+func caretUpper(v Version) Version {
+	release := append([]int(nil), v.Release...)
+	for len(release) < 3 {
+		release = append(release, 0)
+	}
+	idx := len(release) - 1
+	for i, n := range release {
+		if n != 0 {
+			idx = i
+			break
+		}
+	}
+	// ^0 与 ^0.0 只固定写出的段
+	if idx == len(release)-1 && len(v.Release) < 3 {
+		idx = len(v.Release) - 1
+	}
+	return bump(release, idx)
+}
+
+// tildeUpper npm ~ 约束的上限：写出次版本号时递增次版本号，否则递增主版本号
+func tildeUpper(v Version) Version {
+	if len(v.Release) >= 2 {
+		return bump(v.Release, 1)
+	}
+	return bump(v.Release, 0)
+}
+
+// pessimisticUpper ~>、~= 与 Composer ~ 约束的上限：递增倒数第二个写出的段，如 ~>1.2 -> 2.0，~>1.2.3 -> 1.3.0
+func pessimisticUpper(v Version) Version {
+	if len(v.Release) >= 2 {
+		return bump(v.Release, len(v.Release)-2)
+	}
+	return bump(v.Release, 0)
+}
+
+// bump 递增第 idx 段并截断之后的段，上限使用最低的预发布版本
+func bump(release []int, idx int) Version {
+	upper := append([]int(nil), release[:idx+1]...)
+	upper[idx]++
+	return Version{Release: upper, Qualifiers: []Qualifier{{Rank: rankDev, Label: "dev"}}}
+}
+
+// parseMaven 解析 Maven 版本范围，多个范围以逗号分隔时为 OR 关系
+func (c *Constraint) parseMaven() error {
+	s := c.Raw
+	if !strings.HasPrefix(s, "[") && !strings.HasPrefix(s, "(") {
+		// 软约束（如 1.0）按精确版本处理
+		v, err := Parse(s)
+		if err != nil {
+			return err
+		}
+		c.sets = [][]comparator{{{op: "=", version: v, literal: true}}}
+		return nil
+	}
+	for len(s) > 0 {
+		end := strings.IndexAny(s, "])")
+		if end < 0 {
+			return fmt.Errorf("invalid maven range %q", c.Raw)
+		}
+		set, err := parseMavenRange(s[:end+1])
+		if err != nil {
+			return fmt.Errorf("invalid maven range %q: %w", c.Raw, err)
+		}
+		c.sets = append(c.sets, set)
+		s = strings.TrimLeft(s[end+1:], ", ")
+	}
+	return nil
+}
+
+// parseMavenRange 解析单个 Maven 范围，如 [1.0,2.0)、(,1.0]、[1.2]
+func parseMavenRange(s string) ([]comparator, error) {
+	inclusiveLow, inclusiveHigh := s[0] == '[', s[len(s)-1] == ']'
+	body := strings.TrimSpace(s[1 : len(s)-1])
+	lower, upper, isRange := strings.Cut(body, ",")
+	if !isRange {
+		v, err := Parse(body)
+		if err != nil {
+			return nil, err
+		}
+		return []comparator{{op: "=", version: v, literal: true}}, nil
+	}
+	var set []comparator
+	if lower = strings.TrimSpace(lower); lower != "" {
+		v, err := Parse(lower)
+		if err != nil {
+			return nil, err
+		}
+		if inclusiveLow {
+			set = append(set, comparator{op: ">=", version: v, literal: true})
+		} else {
+			set = append(set, comparator{op: ">", version: v})
+		}
+	}
+	if upper = strings.TrimSpace(upper); upper != "" {
+		v, err := Parse(upper)
+		if err != nil {
+			return nil, err
+		}
+		if inclusiveHigh {
+			set = append(set, comparator{op: "<=", version: v})
+		} else {
+			set = append(set, comparator{op: "<", version: v})
+		}
+	}
+	if len(set) == 0 {
+		return []comparator{{op: ">=", version: Version{Release: []int{0}}}}, nil
+	}
+	return set, nil
+}
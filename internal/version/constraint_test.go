@@ -0,0 +1,57 @@
+package version
+
+import "testing"
+
+func TestConstraintCheck(t *testing.T) {
+	tests := []struct {
+		constraint string
+		scheme     string
+		matches    []string
+		rejects    []string
+	}{
+		{"^1.2.3", SchemeSemver, []string{"1.2.3", "1.9.0"}, []string{"1.2.2", "2.0.0", "2.0.0-rc.1"}},
+		{"^0.2.3", SchemeSemver, []string{"0.2.3", "0.2.9"}, []string{"0.3.0"}},
+		{"^0.0.3", SchemeSemver, []string{"0.0.3"}, []string{"0.0.4"}},
+		{"~1.2.3", SchemeSemver, []string{"1.2.9"}, []string{"1.3.0"}},
+		{"~1.2", SchemeComposer, []string{"1.2.0", "1.9.9"}, []string{"2.0.0", "1.1.0"}},
+		{"~1.2.3", SchemeComposer, []string{"1.2.9"}, []string{"1.3.0"}},
+		{"1.2.x", SchemeSemver, []string{"1.2.0", "1.2.99"}, []string{"1.3.0", "1.1.9"}},
+		{"1.2", SchemeSemver, []string{"1.2.5"}, []string{"1.3.0"}},
+		{">=1.0 <2.0 || >=3.0", SchemeSemver, []string{"1.5.0", "3.1.0"}, []string{"2.5.0", "0.9.0"}},
+		{"1.2.3 - 2.3.4", SchemeSemver, []string{"1.2.3", "2.3.4"}, []string{"2.3.5"}},
+		{">= 2.0, < 3", SchemePEP440, []string{"2.0", "2.9.9"}, []string{"3.0", "1.9"}},
+		{"~=1.4.2", SchemePEP440, []string{"1.4.5"}, []string{"1.5.0", "1.4.1"}},
+		{"~=2.2", SchemePEP440, []string{"2.9"}, []string{"3.0"}},
+		{"==1.2.*", SchemePEP440, []string{"1.2.0", "1.2.7"}, []string{"1.3.0"}},
+		{">=1.0,!=1.3.*", SchemePEP440, []string{"1.2.0", "1.4.0"}, []string{"1.3.2"}},
+		{"~> 2.1", SchemeRubyGems, []string{"2.1.0", "2.9.0"}, []string{"3.0.0"}},
+		{"[1.0,2.0)", SchemeMaven, []string{"1.0", "1.9.9"}, []string{"2.0", "0.9"}},
+		{"(,1.0]", SchemeMaven, []string{"0.5", "1.0"}, []string{"1.0.1"}},
+		{"[1.2]", SchemeMaven, []string{"1.2"}, []string{"1.2.1"}},
+		{"(,1.0),[1.2,)", SchemeMaven, []string{"0.9", "1.5"}, []string{"1.1"}},
+		{"*", SchemeSemver, []string{"0.0.1", "99.0.0"}, nil},
+	}
+	for _, tt := range tests {
+		c, err := ParseConstraint(tt.constraint, tt.scheme)
+		if err != nil {
+			t.Errorf("ParseConstraint(%q) failed: %v", tt.constraint, err)
+			continue
+		}
+		for _, raw := range tt.matches {
+			if !c.Check(MustParse(raw)) {
+				t.Errorf("%q (%s) should match %s", tt.constraint, tt.scheme, raw)
+			}
+		}
+		for _, raw := range tt.rejects {
+			if c.Check(MustParse(raw)) {
+				t.Errorf("%q (%s) should not match %s", tt.constraint, tt.scheme, raw)
+			}
+		}
+	}
+
+	for _, raw := range []string{"", "^", ">=abc", "[1.0,2.0"} {
+		if _, err := ParseConstraint(raw, SchemeSemver); err == nil {
+			t.Errorf("ParseConstraint(%q) expected error", raw)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package version
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// Info 版本字符串的解析结果
+// - Version: 具体版本号，来自精确版本或只允许一个版本的约束（如 ==4.2.1），约束允许多个版本（如 ^1.2.3）时为空
+// - Constraint: 声明的版本约束原文，值为精确版本时为空
+// - Normalized: Version 的规范化可比较形式（如 1.2 -> 1.2.0、1.0-RC1 -> 1.0.0-rc.1）
+// - Unresolved: 无法解析为版本的占位符或引用（如 ${project.version}、latest、workspace:*）
+type Info struct {
+	Version    string
+	Constraint string
+	Normalized string
+	Unresolved string
+}
+
+// placeholderRe 匹配构建工具的变量占位符：${...}、$(...)、@...@、{{...}}
+var placeholderRe = regexp.MustCompile(`\$\{[^}]*\}|\$\([^)]*\)|^@[\w.-]+@$|\{\{[^}]*\}\}`)
+
+// unresolvedKeywords 不指向具体版本的标签与分支名
+var unresolvedKeywords = map[string]bool{
+	"latest": true, "next": true, "stable": true, "release": true, "snapshot": true,
+	"latest.release": true, "latest.integration": true, "master": true, "main": true, "head": true,
+}
+
+// unresolvedPrefixes 指向本地路径、工作区或源码仓库的依赖声明
+var unresolvedPrefixes = []string{
+	"workspace:", "file:", "link:", "portal:", "catalog:", "patch:", "git+", "git:", "git@",
+	"github:", "gitlab:", "bitbucket:", "http://", "https://", "./", "../", "/", "dev-",
+}
+
+// IsUnresolved 判断版本字符串是否为占位符、标签或非版本引用
+func IsUnresolved(raw string) bool {
+	s := strings.ToLower(strings.TrimSpace(raw))
+	if s == "" {
+		return false
+	}
+	if placeholderRe.MatchString(s) || unresolvedKeywords[s] || strings.HasSuffix(s, "-dev") {
+		return true
+	}
+	for _, prefix := range unresolvedPrefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve 将原始版本字符串区分为具体版本、版本约束或未解析的占位符。
+// scheme 指定约束语法（见 Scheme 常量），为空时按通用规则解析，不完整的版本号（如 1.2）视为精确版本
+func Resolve(raw, scheme string) Info {
+	s := strings.Trim(strings.TrimSpace(raw), `"'`)
+	if s == "" {
+		return Info{}
+	}
+	if IsUnresolved(s) {
+		return Info{Unresolved: s}
+	}
+
+	// 精确版本：以数字或 v 前缀开头，npm 中不完整的版本号（如 1.2）表示范围
+	if v, err := Parse(s); err == nil && s[0] != '=' && !(scheme == SchemeSemver && len(v.Release) < 3 && len(v.Qualifiers) == 0) {
+		return Info{Version: plain(s), Normalized: v.String()}
+	}
+
+	c, err := ParseConstraint(s, scheme)
+	if err != nil {
+		return Info{Unresolved: s}
+	}
+	info := Info{Constraint: s}
+	if exact, ok := c.Exact(); ok {
+		info.Version = plain(exact.Raw)
+		info.Normalized = exact.String()
+	}
+	return info
+}
+
+// plain 去除版本号的 v 前缀与构建元数据
+func plain(raw string) string {
+	s := strings.TrimSpace(raw)
+	if len(s) > 1 && (s[0] == 'v' || s[0] == 'V') && isDigit(s[1]) {
+		s = s[1:]
+	}
+	if idx := strings.Index(s, "+"); idx > 0 {
+		s = s[:idx]
+	}
+	return s
+}
+
+// SchemeForEcosystem 返回依赖生态使用的版本约束语法
+func SchemeForEcosystem(ecosystem string) string {
+	switch ecosystem {
+	case model.EcosystemNpm, model.EcosystemCargo, model.EcosystemGo, model.EcosystemPub, model.EcosystemSwiftPM:
+		return SchemeSemver
+	case model.EcosystemComposer:
+		return SchemeComposer
+	case model.EcosystemPyPI:
+		return SchemePEP440
+	case model.EcosystemMaven, model.EcosystemGradle, model.EcosystemAndroid, model.EcosystemNuGet:
+		return SchemeMaven
+	case model.EcosystemRubyGems, model.EcosystemCocoaPods, model.EcosystemHex:
+		return SchemeRubyGems
+	}
+	return ""
+}
@@ -0,0 +1,303 @@
+// Package version 提供版本号的解析、比较与版本约束（范围）语义，
+// 兼容 SemVer（npm、Cargo、Go）、PEP 440（PyPI）、Maven 与 Composer 的常见版本写法。
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 限定符等级，数值越大版本越新；未知限定符视为预发布版本
+const (
+	rankUnknown = iota
+	rankDev
+	rankAlpha
+	rankBeta
+	rankMilestone
+	rankRC
+	rankSnapshot
+	rankRelease
+	rankPost
+)
+
+// qualifierRanks 各生态常见限定符及其等级
+var qualifierRanks = map[string]int{
+	"dev":       rankDev,
+	"a":         rankAlpha,
+	"alpha":     rankAlpha,
+	"b":         rankBeta,
+	"beta":      rankBeta,
+	"m":         rankMilestone,
+	"milestone": rankMilestone,
+	"c":         rankRC,
+	"rc":        rankRC,
+	"cr":        rankRC,
+	"pre":       rankRC,
+	"preview":   rankRC,
+	"snapshot":  rankSnapshot,
+	"final":     rankRelease,
+	"ga":        rankRelease,
+	"release":   rankRelease,
+	"post":      rankPost,
+	"sp":        rankPost,
+	"patch":     rankPost,
+	"pl":        rankPost,
+	"r":         rankPost,
+}
+
+// qualifierLabels 限定符等级对应的规范化名称
+var qualifierLabels = map[int]string{
+	rankDev:       "dev",
+	rankAlpha:     "alpha",
+	rankBeta:      "beta",
+	rankMilestone: "milestone",
+	rankRC:        "rc",
+	rankSnapshot:  "snapshot",
+	rankPost:      "post",
+}
+
+// Qualifier 版本号中发布号之后的限定符，如 rc.1、beta-2、SNAPSHOT、post1
+type Qualifier struct {
+	Rank   int
+	Label  string
+	Number int
+}
+
+// Version 解析后的版本号
+// - Release: 数字发布号，如 1.2.3 -> [1 2 3]
+// - Qualifiers: 预发布或后发布限定符
+// - Raw: 原始版本字符串
+type Version struct {
+	Release    []int
+	Qualifiers []Qualifier
+	Raw        string
+}
+
+// Parse 解析版本号，接受可选的 v 前缀与 = 前缀，忽略构建元数据（+ 之后的部分）。
+// 发布号之后的限定符可以用 .、-、_ 分隔或直接相连（如 1.0a1、1.0.0-rc.1、2.5.6.SEC03、1.0-SNAPSHOT）。
+// 版本号必须以数字开头，通配符（1.2.x）与占位符不是版本号。
+func Parse(raw string) (Version, error) {
+	s := strings.TrimSpace(raw)
+	s = strings.TrimLeft(s, "=")
+	s = strings.TrimSpace(s)
+	if len(s) > 1 && (s[0] == 'v' || s[0] == 'V') && isDigit(s[1]) {
+		s = s[1:]
+	}
+	if idx := strings.Index(s, "+"); idx > 0 {
+		// Gradle 的 1.+ 是动态版本而不是构建元数据
+		if s[idx-1] == '.' || idx == len(s)-1 {
+			return Version{}, fmt.Errorf("dynamic version %q", raw)
+		}
+		s = s[:idx]
+	}
+	if s == "" || !isDigit(s[0]) || strings.IndexFunc(s, invalidVersionRune) >= 0 {
+		return Version{}, fmt.Errorf("invalid version %q", raw)
+	}
+
+	v := Version{Raw: strings.TrimSpace(raw)}
+	i := 0
+	// 发布号：以 . 分隔的数字
+	for {
+		start := i
+		for i < len(s) && isDigit(s[i]) {
+			i++
+		}
+		n, err := strconv.Atoi(s[start:i])
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version %q", raw)
+		}
+		v.Release = append(v.Release, n)
+		if i+1 < len(s) && s[i] == '.' && isDigit(s[i+1]) {
+			i++
+			continue
+		}
+		break
+	}
+
+	// 限定符：字母与数字交替的片段
+	for _, token := range splitQualifiers(s[i:]) {
+		if token == "" {
+			continue
+		}
+		if isDigit(token[0]) {
+			n, err := strconv.Atoi(token)
+			if err != nil {
+				return Version{}, fmt.Errorf("invalid version %q", raw)
+			}
+			// 数字紧随限定符时作为其序号，否则作为未命名限定符的序号
+			if len(v.Qualifiers) > 0 && v.Qualifiers[len(v.Qualifiers)-1].Number == 0 {
+				v.Qualifiers[len(v.Qualifiers)-1].Number = n
+			} else {
+				v.Qualifiers = append(v.Qualifiers, Qualifier{Rank: rankUnknown, Number: n})
+			}
+			continue
+		}
+		label := strings.ToLower(token)
+		if strings.ContainsAny(label, "*x") && strings.Trim(label, "*x") == "" {
+			return Version{}, fmt.Errorf("wildcard is not a version %q", raw)
+		}
+		rank, ok := qualifierRanks[label]
+		if !ok {
+			rank = rankUnknown
+		}
+		if rank == rankRelease {
+			// final、GA、RELEASE 等同于正式版本
+			continue
+		}
+		v.Qualifiers = append(v.Qualifiers, Qualifier{Rank: rank, Label: label})
+	}
+	return v, nil
+}
+
+// MustParse 解析版本号，失败时 panic，用于常量版本
+func MustParse(raw string) Version {
+	v, err := Parse(raw)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// splitQualifiers 将限定符部分拆分为字母片段与数字片段
+func splitQualifiers(s string) []string {
+	var tokens []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '.' || c == '-' || c == '_':
+			flush()
+		case current.Len() > 0 && isDigit(c) != isDigit(current.String()[current.Len()-1]):
+			flush()
+			current.WriteByte(c)
+		default:
+			current.WriteByte(c)
+		}
+	}
+	flush()
+	return tokens
+}
+
+// String 返回规范化的版本号：发布号至少三段，限定符使用统一名称，如 1.0.0-rc.1、2.0.0-post.1
+func (v Version) String() string {
+	release := v.Release
+	for len(release) < 3 {
+		release = append(release, 0)
+	}
+	parts := make([]string, len(release))
+	for i, n := range release {
+		parts[i] = strconv.Itoa(n)
+	}
+	s := strings.Join(parts, ".")
+	for _, q := range v.Qualifiers {
+		label := qualifierLabels[q.Rank]
+		if label == "" {
+			label = q.Label
+		}
+		switch {
+		case label == "":
+			s += "-" + strconv.Itoa(q.Number)
+		case q.Number > 0:
+			s += "-" + label + "." + strconv.Itoa(q.Number)
+		default:
+			s += "-" + label
+		}
+	}
+	return s
+}
+
+// IsPrerelease 判断是否为预发布版本（dev、alpha、beta、rc、SNAPSHOT 等）
+func (v Version) IsPrerelease() bool {
+	for _, q := range v.Qualifiers {
+		if q.Rank < rankRelease && q.Label != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Compare 比较两个版本号，a < b 返回 -1，a == b 返回 0，a > b 返回 1。
+// 发布号缺失的段视为 0（1.2 == 1.2.0），预发布版本小于对应正式版本，post 等后发布版本大于正式版本。
+func Compare(a, b Version) int {
+	for i := 0; i < len(a.Release) || i < len(b.Release); i++ {
+		if c := compareInt(segment(a.Release, i), segment(b.Release, i)); c != 0 {
+			return c
+		}
+	}
+	for i := 0; i < len(a.Qualifiers) || i < len(b.Qualifiers); i++ {
+		qa, qb := qualifier(a.Qualifiers, i), qualifier(b.Qualifiers, i)
+		if c := compareInt(qa.Rank, qb.Rank); c != 0 {
+			return c
+		}
+		if qa.Rank == rankUnknown && qa.Label != qb.Label {
+			if qa.Label < qb.Label {
+				return -1
+			}
+			return 1
+		}
+		if c := compareInt(qa.Number, qb.Number); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// CompareStrings 解析并比较两个版本字符串，无法解析时返回错误
+func CompareStrings(a, b string) (int, error) {
+	va, err := Parse(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := Parse(b)
+	if err != nil {
+		return 0, err
+	}
+	return Compare(va, vb), nil
+}
+
+// segment 返回第 i 段发布号，缺失时为 0
+func segment(release []int, i int) int {
+	if i < len(release) {
+		return release[i]
+	}
+	return 0
+}
+
+// qualifier 返回第 i 个限定符，缺失时视为正式版本
+func qualifier(qualifiers []Qualifier, i int) Qualifier {
+	if i < len(qualifiers) {
+		q := qualifiers[i]
+		// 纯数字限定符（如 1.0-1）视为正式版本的补丁序号
+		if q.Label == "" && q.Rank == rankUnknown {
+			q.Rank = rankRelease
+		}
+		return q
+	}
+	return Qualifier{Rank: rankRelease}
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// invalidVersionRune 判断字符是否不能出现在版本号中（空白、运算符、逗号等）
+func invalidVersionRune(r rune) bool {
+	return !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '.' || r == '-' || r == '_')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
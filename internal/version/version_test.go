@@ -0,0 +1,107 @@
+package version
+
+import "testing"
+
+func TestParseAndString(t *testing.T) {
+	tests := []struct {
+		raw        string
+		normalized string
+	}{
+		{"1.2.3", "1.2.3"},
+		{"v5.4.1", "5.4.1"},
+		{"2.0", "2.0.0"},
+		{"1.0.0-rc.1", "1.0.0-rc.1"},
+		{"1.0rc1", "1.0.0-rc.1"},
+		{"1.0a2", "1.0.0-alpha.2"},
+		{"2.1.0.dev3", "2.1.0-dev.3"},
+		{"1.0.post1", "1.0.0-post.1"},
+		{"5.3.2.RELEASE", "5.3.2"},
+		{"6.0.0.Final", "6.0.0"},
+		{"1.0-SNAPSHOT", "1.0.0-snapshot"},
+		{"1.0.0-beta-2", "1.0.0-beta.2"},
+		{"1.2.3+build.5", "1.2.3"},
+		{"1.2.3.4", "1.2.3.4"},
+	}
+	for _, tt := range tests {
+		v, err := Parse(tt.raw)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tt.raw, err)
+			continue
+		}
+		if got := v.String(); got != tt.normalized {
+			t.Errorf("Parse(%q).String() = %q, expected %q", tt.raw, got, tt.normalized)
+		}
+	}
+
+	for _, raw := range []string{"", "latest", "1.2.x", "1.*", "1.+", ">=1.0", "${project.version}", "1.0 - 2.0", "^1.2"} {
+		if _, err := Parse(raw); err == nil {
+			t.Errorf("Parse(%q) expected error", raw)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"1.2", "1.2.0", 0},
+		{"1.2.3", "1.10.0", -1},
+		{"2.0.0", "1.99.99", 1},
+		{"1.0.0-alpha", "1.0.0-beta", -1},
+		{"1.0.0-rc.1", "1.0.0", -1},
+		{"1.0.0-rc.2", "1.0.0-rc.10", -1},
+		{"1.0.dev1", "1.0a1", -1},
+		{"1.0-SNAPSHOT", "1.0", -1},
+		{"1.0.RELEASE", "1.0", 0},
+		{"1.0.post1", "1.0", 1},
+		{"2.5.6.SEC03", "2.5.6", -1},
+		{"v1.2.3", "1.2.3", 0},
+	}
+	for _, tt := range tests {
+		got, err := CompareStrings(tt.a, tt.b)
+		if err != nil {
+			t.Errorf("CompareStrings(%q, %q) failed: %v", tt.a, tt.b, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("CompareStrings(%q, %q) = %d, expected %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		raw    string
+		scheme string
+		want   Info
+	}{
+		{"1.2.3", "", Info{Version: "1.2.3", Normalized: "1.2.3"}},
+		{"v1.9.1", SchemeSemver, Info{Version: "1.9.1", Normalized: "1.9.1"}},
+		{"5.4", "", Info{Version: "5.4", Normalized: "5.4.0"}},
+		{"^18.2.0", SchemeSemver, Info{Constraint: "^18.2.0"}},
+		{"~> 7.0", SchemeRubyGems, Info{Constraint: "~> 7.0"}},
+		{">=2.0,<3", SchemePEP440, Info{Constraint: ">=2.0,<3"}},
+		{"==4.2.1", SchemePEP440, Info{Version: "4.2.1", Constraint: "==4.2.1", Normalized: "4.2.1"}},
+		{"=1.2.3", SchemeSemver, Info{Version: "1.2.3", Constraint: "=1.2.3", Normalized: "1.2.3"}},
+		{"[1.2]", SchemeMaven, Info{Version: "1.2", Constraint: "[1.2]", Normalized: "1.2.0"}},
+		{"[1.0,2.0)", SchemeMaven, Info{Constraint: "[1.0,2.0)"}},
+		{"1.2.x", SchemeSemver, Info{Constraint: "1.2.x"}},
+		{"1.2", SchemeSemver, Info{Constraint: "1.2"}},
+		{"^7.0 || ^8.0", SchemeComposer, Info{Constraint: "^7.0 || ^8.0"}},
+		{"*", SchemeSemver, Info{Constraint: "*"}},
+		{"${project.version}", SchemeMaven, Info{Unresolved: "${project.version}"}},
+		{"$(PackageVersion)", "", Info{Unresolved: "$(PackageVersion)"}},
+		{"latest", SchemeSemver, Info{Unresolved: "latest"}},
+		{"workspace:*", SchemeSemver, Info{Unresolved: "workspace:*"}},
+		{"dev-master", SchemeComposer, Info{Unresolved: "dev-master"}},
+		{"git+https://github.com/a/b.git", SchemeSemver, Info{Unresolved: "git+https://github.com/a/b.git"}},
+		{"", "", Info{}},
+	}
+	for _, tt := range tests {
+		if got := Resolve(tt.raw, tt.scheme); got != tt.want {
+			t.Errorf("Resolve(%q, %q) = %+v, expected %+v", tt.raw, tt.scheme, got, tt.want)
+		}
+	}
+}
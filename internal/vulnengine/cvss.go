@@ -0,0 +1,73 @@
+package vulnengine
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// cvss3Weights CVSS v3 基础指标的权重，PR 在作用域改变（S:C）时使用 cvss3ChangedPR
+var cvss3Weights = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"PR": {"N": 0.85, "L": 0.62, "H": 0.27},
+	"UI": {"N": 0.85, "R": 0.62},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+}
+
+var cvss3ChangedPR = map[string]float64{"N": 0.85, "L": 0.68, "H": 0.5}
+
+// cvss3BaseScore 按 CVSS v3.0/v3.1 规范计算向量（如 "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"）的基础分数
+func cvss3BaseScore(vector string) (float64, error) {
+	parts := strings.Split(vector, "/")
+	if len(parts) == 0 || !strings.HasPrefix(parts[0], "CVSS:3.") {
+		return 0, fmt.Errorf("not a CVSS v3 vector %q", vector)
+	}
+	metrics := make(map[string]string)
+	for _, part := range parts[1:] {
+		if name, value, ok := strings.Cut(part, ":"); ok {
+			metrics[name] = value
+		}
+	}
+
+	scope := metrics["S"]
+	if scope != "U" && scope != "C" {
+		return 0, fmt.Errorf("invalid scope in CVSS vector %q", vector)
+	}
+	weights := make(map[string]float64)
+	for name, table := range cvss3Weights {
+		weight, ok := table[metrics[name]]
+		if name == "PR" && scope == "C" {
+			weight, ok = cvss3ChangedPR[metrics[name]]
+		}
+		if !ok {
+			return 0, fmt.Errorf("invalid %s in CVSS vector %q", name, vector)
+		}
+		weights[name] = weight
+	}
+
+	iss := 1 - (1-weights["C"])*(1-weights["I"])*(1-weights["A"])
+	impact := 6.42 * iss
+	if scope == "C" {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	if impact <= 0 {
+		return 0, nil
+	}
+	exploitability := 8.22 * weights["AV"] * weights["AC"] * weights["PR"] * weights["UI"]
+	if scope == "C" {
+		return roundUp(math.Min(1.08*(impact+exploitability), 10)), nil
+	}
+	return roundUp(math.Min(impact+exploitability, 10)), nil
+}
+
+// roundUp 按 CVSS v3.1 的规则向上取整到一位小数，避免浮点误差
+func roundUp(value float64) float64 {
+	scaled := int(math.Round(value * 100000))
+	if scaled%10000 == 0 {
+		return float64(scaled) / 100000
+	}
+	return float64(scaled/10000+1) / 10
+}
@@ -0,0 +1,256 @@
+// Package vulnengine 加载本地的 OSV 格式公告数据（按生态组织的 JSON 文件目录或 zip 压缩包），
+// 按生态、包名称与版本范围离线匹配已知漏洞，并将解析结果缓存为索引以便快速加载大型数据。
+package vulnengine
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/logging"
+)
+
+// cacheFormat 索引缓存的格式版本，索引结构变化时递增以使旧缓存失效
+const cacheFormat = 1
+
+// Advisory 索引中的公告信息
+type Advisory struct {
+	ID       string
+	Aliases  []string
+	Summary  string
+	Severity string
+	Score    float64
+}
+
+// Event OSV 版本范围中的一个事件，四个字段中只有一个非空
+type Event struct {
+	Introduced   string
+	Fixed        string
+	LastAffected string
+	Limit        string
+}
+
+// Range OSV 版本范围，事件之间的顺序无关
+type Range struct {
+	Events []Event
+}
+
+// Affected 某个软件包受某条公告影响的版本
+// - Advisory: 公告在索引中的下标
+// - Severity/Score: 受影响软件包自身的评分，为空时使用公告的评分
+type Affected struct {
+	Advisory int
+	Ranges   []Range
+	Versions []string
+	Severity string
+	Score    float64
+}
+
+// index 解析后的公告索引，同时作为缓存文件的内容
+// - Fingerprint: 公告数据文件的路径、大小与修改时间的摘要，数据变化时缓存失效
+// - Packages: 生态与包名称的索引键（见 packageKey）到受影响记录的映射
+type index struct {
+	Format      int
+	Fingerprint string
+	Advisories  []Advisory
+	Packages    map[string][]Affected
+}
+
+// Database 离线公告数据
+// - Path: 公告数据的路径
+// - FromCache: 是否从索引缓存加载
+type Database struct {
+	Path      string
+	FromCache bool
+	idx       *index
+}
+
+// Open 加载公告数据：path 可以是包含 JSON 文件与 zip 压缩包（如 OSV 提供的各生态 all.zip）的目录，
+// 也可以是单个 zip 压缩包或 JSON 文件。cacheDir 非空时在其中读写索引缓存，数据未变化时直接从缓存加载
+func Open(path, cacheDir string) (*Database, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	files, fingerprint, err := dumpFiles(absPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no OSV advisories found in %s", path)
+	}
+
+	cachePath := ""
+	if cacheDir != "" {
+		sum := sha256.Sum256([]byte(absPath))
+		cachePath = filepath.Join(cacheDir, hex.EncodeToString(sum[:8])+".gob")
+		if idx := readCache(cachePath, fingerprint); idx != nil {
+			return &Database{Path: absPath, FromCache: true, idx: idx}, nil
+		}
+	}
+
+	idx := &index{Format: cacheFormat, Fingerprint: fingerprint, Packages: make(map[string][]Affected)}
+	for _, file := range files {
+		if err := idx.loadFile(file); err != nil {
+			return nil, err
+		}
+	}
+	if cachePath != "" {
+		if err := writeCache(cachePath, idx); err != nil {
+			logging.Warnf("failed to write OSV index cache %s: %v", cachePath, err)
+		}
+	}
+	return &Database{Path: absPath, idx: idx}, nil
+}
+
+// Advisories 返回加载的公告数量
+func (db *Database) Advisories() int {
+	return len(db.idx.Advisories)
+}
+
+// dumpFiles 返回公告数据中的 JSON 文件与 zip 压缩包（按路径排序）及其指纹
+func dumpFiles(path string) ([]string, string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, "", err
+	}
+	hash := sha256.New()
+	if !info.IsDir() {
+		fmt.Fprintf(hash, "%s\x00%d\x00%d\n", filepath.Base(path), info.Size(), info.ModTime().UnixNano())
+		return []string{path}, hex.EncodeToString(hash.Sum(nil)), nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(path, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if file != path && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(file)) {
+		case ".json", ".zip":
+			files = append(files, file)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		fileInfo, err := os.Stat(file)
+		if err != nil {
+			return nil, "", err
+		}
+		rel, _ := filepath.Rel(path, file)
+		fmt.Fprintf(hash, "%s\x00%d\x00%d\n", filepath.ToSlash(rel), fileInfo.Size(), fileInfo.ModTime().UnixNano())
+	}
+	return files, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// loadFile 解析单个 JSON 文件或 zip 压缩包中的全部 JSON 文件，无法解析的公告被跳过
+func (idx *index) loadFile(path string) error {
+	if !strings.EqualFold(filepath.Ext(path), ".zip") {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		idx.loadContent(path, content)
+		return nil
+	}
+
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("error opening OSV archive %s: %v", path, err)
+	}
+	defer reader.Close()
+	for _, member := range reader.File {
+		if member.FileInfo().IsDir() || !strings.EqualFold(filepath.Ext(member.Name), ".json") {
+			continue
+		}
+		rc, err := member.Open()
+		if err != nil {
+			return fmt.Errorf("error reading %s in %s: %v", member.Name, path, err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("error reading %s in %s: %v", member.Name, path, err)
+		}
+		idx.loadContent(path+"!/"+member.Name, content)
+	}
+	return nil
+}
+
+// loadContent 解析 JSON 内容，内容可以是单条公告或公告数组
+func (idx *index) loadContent(name string, content []byte) {
+	content = bytes.TrimSpace(content)
+	records := []json.RawMessage{content}
+	if bytes.HasPrefix(content, []byte("[")) {
+		if err := json.Unmarshal(content, &records); err != nil {
+			logging.Debugf("skipping OSV file %s: %v", name, err)
+			return
+		}
+	}
+	for _, record := range records {
+		if err := idx.parseRecord(record); err != nil {
+			logging.Debugf("skipping OSV advisory in %s: %v", name, err)
+		}
+	}
+}
+
+// readCache 读取索引缓存，缓存不存在、格式不同或指纹不一致时返回 nil
+func readCache(path, fingerprint string) *index {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+	var idx index
+	if err := gob.NewDecoder(file).Decode(&idx); err != nil {
+		logging.Debugf("ignoring OSV index cache %s: %v", path, err)
+		return nil
+	}
+	if idx.Format != cacheFormat || idx.Fingerprint != fingerprint {
+		return nil
+	}
+	if idx.Packages == nil {
+		idx.Packages = make(map[string][]Affected)
+	}
+	return &idx
+}
+
+// writeCache 写入索引缓存，先写入临时文件再重命名，避免并发读取到不完整的缓存
+func writeCache(path string, idx *index) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".osv-index-*")
+	if err != nil {
+		return err
+	}
+	if err := gob.NewEncoder(tmp).Encode(idx); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,146 @@
+package vulnengine
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/winezer0/codecanvas/internal/model"
+	"github.com/winezer0/codecanvas/internal/version"
+)
+
+// Query 返回影响指定依赖生态（如 "maven"）中包的指定版本的公告，按严重程度从高到低、编号升序排列。
+// 版本无法解析时只与公告中列出的具体版本比较
+func (db *Database) Query(ecosystem, name, raw string) []model.Vulnerability {
+	if raw == "" {
+		return nil
+	}
+	v, err := version.Parse(raw)
+	parsed := err == nil
+
+	byID := make(map[string]int)
+	var result []model.Vulnerability
+	for _, osvEcosystem := range osvEcosystems[ecosystem] {
+		for _, affected := range db.idx.Packages[packageKey(osvEcosystem, name)] {
+			if !affected.affects(raw, v, parsed) {
+				continue
+			}
+			advisory := db.idx.Advisories[affected.Advisory]
+			if i, ok := byID[advisory.ID]; ok {
+				result[i].Fixed = appendFixed(result[i].Fixed, affected)
+				continue
+			}
+			vuln := model.Vulnerability{
+				ID:       advisory.ID,
+				Aliases:  advisory.Aliases,
+				Summary:  advisory.Summary,
+				Severity: advisory.Severity,
+				Score:    advisory.Score,
+				Fixed:    appendFixed(nil, affected),
+			}
+			if affected.Severity != "" && affected.Severity != model.SeverityUnknown {
+				vuln.Severity, vuln.Score = affected.Severity, affected.Score
+			}
+			byID[advisory.ID] = len(result)
+			result = append(result, vuln)
+		}
+	}
+	SortVulnerabilities(result)
+	return result
+}
+
+// SortVulnerabilities 按严重程度从高到低、编号升序排列公告
+func SortVulnerabilities(vulns []model.Vulnerability) {
+	sort.SliceStable(vulns, func(i, j int) bool {
+		a, b := slices.Index(model.AllSeverity, vulns[i].Severity), slices.Index(model.AllSeverity, vulns[j].Severity)
+		if a != b {
+			return a > b
+		}
+		return vulns[i].ID < vulns[j].ID
+	})
+}
+
+// appendFixed 追加受影响记录中的修复版本，已存在的版本不重复添加
+func appendFixed(fixed []string, affected Affected) []string {
+	for _, r := range affected.Ranges {
+		for _, event := range r.Events {
+			if event.Fixed != "" && !slices.Contains(fixed, event.Fixed) {
+				fixed = append(fixed, event.Fixed)
+			}
+		}
+	}
+	return fixed
+}
+
+// affects 判断版本是否受影响：与列出的具体版本相同，或落在任一版本范围内
+func (a Affected) affects(raw string, v version.Version, parsed bool) bool {
+	for _, listed := range a.Versions {
+		if listed == raw {
+			return true
+		}
+		if parsed {
+			if lv, err := version.Parse(listed); err == nil && version.Compare(lv, v) == 0 {
+				return true
+			}
+		}
+	}
+	if !parsed {
+		return false
+	}
+	for _, r := range a.Ranges {
+		if r.affects(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// rangePoint 版本范围中的一个事件及其解析后的版本，zero 表示 introduced 为 "0"（最早的版本）
+type rangePoint struct {
+	event   Event
+	version version.Version
+	zero    bool
+}
+
+// affects 按 OSV 规范判断版本是否落在范围内：事件按版本升序排列后依次处理，
+// introduced 进入受影响区间，fixed 与 limit 在版本大于等于事件版本时离开，last_affected 在版本大于事件版本时离开。
+// 无法解析版本号的事件被忽略
+func (r Range) affects(v version.Version) bool {
+	var points []rangePoint
+	for _, event := range r.Events {
+		raw := event.Introduced + event.Fixed + event.LastAffected + event.Limit
+		if event.Introduced == "0" {
+			points = append(points, rangePoint{event: event, zero: true})
+			continue
+		}
+		pv, err := version.Parse(raw)
+		if err != nil {
+			continue
+		}
+		points = append(points, rangePoint{event: event, version: pv})
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		if points[i].zero || points[j].zero {
+			return points[i].zero && !points[j].zero
+		}
+		return version.Compare(points[i].version, points[j].version) < 0
+	})
+
+	affected := false
+	for _, point := range points {
+		switch {
+		case point.event.Introduced != "":
+			if point.zero || version.Compare(v, point.version) >= 0 {
+				affected = true
+			}
+		case point.event.Fixed != "", point.event.Limit != "":
+			if version.Compare(v, point.version) >= 0 {
+				affected = false
+			}
+		case point.event.LastAffected != "":
+			if version.Compare(v, point.version) > 0 {
+				affected = false
+			}
+		}
+	}
+	return affected
+}
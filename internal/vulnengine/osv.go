@@ -0,0 +1,180 @@
+package vulnengine
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/winezer0/codecanvas/internal/model"
+)
+
+// osvRecord OSV 格式的安全公告，只保留匹配与展示需要的字段
+type osvRecord struct {
+	ID               string         `json:"id"`
+	Aliases          []string       `json:"aliases"`
+	Summary          string         `json:"summary"`
+	Withdrawn        string         `json:"withdrawn"`
+	Severity         []osvSeverity  `json:"severity"`
+	Affected         []osvAffected  `json:"affected"`
+	DatabaseSpecific map[string]any `json:"database_specific"`
+}
+
+// osvSeverity 严重程度评分，Type 如 "CVSS_V3"，Score 为 CVSS 向量
+type osvSeverity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+// osvAffected 受影响的软件包及其版本范围
+type osvAffected struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	Ranges []struct {
+		Type   string              `json:"type"`
+		Events []map[string]string `json:"events"`
+	} `json:"ranges"`
+	Versions          []string       `json:"versions"`
+	Severity          []osvSeverity  `json:"severity"`
+	EcosystemSpecific map[string]any `json:"ecosystem_specific"`
+	DatabaseSpecific  map[string]any `json:"database_specific"`
+}
+
+// osvEcosystems 依赖生态对应的 OSV 生态名称
+var osvEcosystems = map[string][]string{
+	model.EcosystemNpm:      {"npm"},
+	model.EcosystemMaven:    {"Maven"},
+	model.EcosystemGradle:   {"Maven"},
+	model.EcosystemAndroid:  {"Maven"},
+	model.EcosystemGo:       {"Go"},
+	model.EcosystemPyPI:     {"PyPI"},
+	model.EcosystemComposer: {"Packagist"},
+	model.EcosystemCargo:    {"crates.io"},
+	model.EcosystemRubyGems: {"RubyGems"},
+	model.EcosystemHex:      {"Hex"},
+	model.EcosystemNuGet:    {"NuGet"},
+	model.EcosystemPub:      {"Pub"},
+	model.EcosystemConan:    {"ConanCenter"},
+	model.EcosystemDeb:      {"Debian", "Ubuntu"},
+	model.EcosystemApk:      {"Alpine"},
+}
+
+// pypiSeparatorRe PyPI 包名中可互换的分隔符
+var pypiSeparatorRe = regexp.MustCompile(`[-_.]+`)
+
+// packageKey 返回 OSV 生态与包名称的索引键：生态去除发行版版本后缀（如 "Debian:11" -> "Debian"），
+// 名称不区分大小写，PyPI 包名按 PEP 503 规范化
+func packageKey(ecosystem, name string) string {
+	ecosystem, _, _ = strings.Cut(ecosystem, ":")
+	name = strings.ToLower(strings.TrimSpace(name))
+	if ecosystem == "PyPI" {
+		name = pypiSeparatorRe.ReplaceAllString(name, "-")
+	}
+	return ecosystem + "/" + name
+}
+
+// parseRecord 解析 OSV 公告并加入索引，已撤回的公告与没有受影响软件包的公告被忽略
+func (idx *index) parseRecord(content []byte) error {
+	var record osvRecord
+	if err := json.Unmarshal(content, &record); err != nil {
+		return err
+	}
+	if record.ID == "" || record.Withdrawn != "" || len(record.Affected) == 0 {
+		return nil
+	}
+
+	severity, score := recordSeverity(record.Severity, record.DatabaseSpecific)
+	advisory := len(idx.Advisories)
+	idx.Advisories = append(idx.Advisories, Advisory{
+		ID:       record.ID,
+		Aliases:  record.Aliases,
+		Summary:  record.Summary,
+		Severity: severity,
+		Score:    score,
+	})
+
+	for _, affected := range record.Affected {
+		if affected.Package.Name == "" || affected.Package.Ecosystem == "" {
+			continue
+		}
+		entry := Affected{Advisory: advisory, Versions: affected.Versions}
+		// 受影响软件包自身的评分优先于公告的评分
+		if len(affected.Severity) > 0 || len(affected.EcosystemSpecific) > 0 || len(affected.DatabaseSpecific) > 0 {
+			entry.Severity, entry.Score = recordSeverity(affected.Severity, affected.DatabaseSpecific, affected.EcosystemSpecific)
+		}
+		for _, r := range affected.Ranges {
+			// GIT 范围以提交哈希表示，无法与包版本比较
+			if r.Type != "SEMVER" && r.Type != "ECOSYSTEM" {
+				continue
+			}
+			var events []Event
+			for _, raw := range r.Events {
+				events = append(events, Event{
+					Introduced:   raw["introduced"],
+					Fixed:        raw["fixed"],
+					LastAffected: raw["last_affected"],
+					Limit:        raw["limit"],
+				})
+			}
+			entry.Ranges = append(entry.Ranges, Range{Events: events})
+		}
+		if len(entry.Ranges) == 0 && len(entry.Versions) == 0 {
+			continue
+		}
+		key := packageKey(affected.Package.Ecosystem, affected.Package.Name)
+		idx.Packages[key] = append(idx.Packages[key], entry)
+	}
+	return nil
+}
+
+// recordSeverity 返回严重程度与 CVSS v3 分数：优先使用 CVSS v3 向量计算，
+// 其次是 database_specific/ecosystem_specific 中的 severity 文本（如 GHSA 的 MODERATE），都没有时为 unknown
+func recordSeverity(scores []osvSeverity, specifics ...map[string]any) (string, float64) {
+	for _, s := range scores {
+		if s.Type != "CVSS_V3" {
+			continue
+		}
+		if score, err := cvss3BaseScore(s.Score); err == nil {
+			return scoreSeverity(score), score
+		}
+	}
+	for _, specific := range specifics {
+		if text, ok := specific["severity"].(string); ok {
+			if severity := normalizeSeverity(text); severity != "" {
+				return severity, 0
+			}
+		}
+	}
+	return model.SeverityUnknown, 0
+}
+
+// normalizeSeverity 将各数据源的严重程度文本规范化为 low/medium/high/critical
+func normalizeSeverity(text string) string {
+	switch strings.ToLower(strings.TrimSpace(text)) {
+	case "critical":
+		return model.SeverityCritical
+	case "high", "important":
+		return model.SeverityHigh
+	case "moderate", "medium":
+		return model.SeverityMedium
+	case "low", "negligible", "unimportant":
+		return model.SeverityLow
+	}
+	return ""
+}
+
+// scoreSeverity 按 CVSS v3 的定性评级将分数转换为严重程度
+func scoreSeverity(score float64) string {
+	switch {
+	case score >= 9.0:
+		return model.SeverityCritical
+	case score >= 7.0:
+		return model.SeverityHigh
+	case score >= 4.0:
+		return model.SeverityMedium
+	case score > 0:
+		return model.SeverityLow
+	}
+	return model.SeverityUnknown
+}
@@ -0,0 +1,192 @@
+package vulnengine
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/winezer0/codecanvas/internal/model"
+	"github.com/winezer0/codecanvas/internal/version"
+)
+
+// 测试用公告：fastjson 带 CVSS 向量，log4j 带两个版本区间，requests 只有 GHSA 文本评级，withdrawn 已撤回
+const (
+	fastjsonAdvisory = `{"id": "GHSA-8r5m-5wc3-rvv5", "aliases": ["CVE-2022-25845"], "summary": "fastjson autoType bypass",
+  "severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}],
+  "affected": [{"package": {"ecosystem": "Maven", "name": "com.alibaba:fastjson"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "1.2.83"}]}]}]}`
+	log4jAdvisory = `{"id": "GHSA-jfh8-c2jp-5v3q", "aliases": ["CVE-2021-44228"],
+  "database_specific": {"severity": "CRITICAL"},
+  "affected": [{"package": {"ecosystem": "Maven", "name": "org.apache.logging.log4j:log4j-core"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "2.13.0"}, {"fixed": "2.15.0"}, {"introduced": "2.0-beta9"}, {"fixed": "2.12.2"}]},
+               {"type": "GIT", "events": [{"introduced": "0"}, {"fixed": "abcdef"}]}]}]}`
+	requestsAdvisory = `[{"id": "GHSA-j8r2-6x86-q33q", "database_specific": {"severity": "MODERATE"},
+  "affected": [{"package": {"ecosystem": "PyPI", "name": "Requests"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "2.3.0"}, {"last_affected": "2.30.0"}]}],
+    "versions": ["2.2.1"]}]}]`
+	withdrawnAdvisory = `{"id": "GHSA-xxxx-withdrawn", "withdrawn": "2023-01-01T00:00:00Z",
+  "affected": [{"package": {"ecosystem": "npm", "name": "lodash"}, "versions": ["4.17.20"]}]}`
+)
+
+// writeDump 写入测试用公告数据：Maven 公告放在 all.zip 中，其余为 JSON 文件
+func writeDump(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"PyPI/GHSA-j8r2-6x86-q33q.json": requestsAdvisory,
+		"npm/GHSA-xxxx-withdrawn.json":  withdrawnAdvisory,
+		"npm/broken.json":               `{"id": `,
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("无法创建目录: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("无法写入 %s: %v", name, err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "Maven"), 0755); err != nil {
+		t.Fatalf("无法创建目录: %v", err)
+	}
+	file, err := os.Create(filepath.Join(dir, "Maven", "all.zip"))
+	if err != nil {
+		t.Fatalf("无法创建压缩包: %v", err)
+	}
+	writer := zip.NewWriter(file)
+	for name, content := range map[string]string{"GHSA-8r5m-5wc3-rvv5.json": fastjsonAdvisory, "GHSA-jfh8-c2jp-5v3q.json": log4jAdvisory} {
+		w, _ := writer.Create(name)
+		w.Write([]byte(content))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("无法写入压缩包: %v", err)
+	}
+	file.Close()
+	return dir
+}
+
+func TestQuery(t *testing.T) {
+	dump := writeDump(t)
+	db, err := Open(dump, "")
+	if err != nil {
+		t.Fatalf("Open 失败: %v", err)
+	}
+	if db.Advisories() != 3 {
+		t.Errorf("应加载 3 条公告（撤回与无法解析的公告被忽略），实际为 %d", db.Advisories())
+	}
+
+	tests := []struct {
+		ecosystem, name, version string
+		want                     string
+		severity                 string
+	}{
+		{model.EcosystemMaven, "com.alibaba:fastjson", "1.2.80", "GHSA-8r5m-5wc3-rvv5", model.SeverityCritical},
+		{model.EcosystemGradle, "com.alibaba:fastjson", "1.2.24", "GHSA-8r5m-5wc3-rvv5", model.SeverityCritical},
+		{model.EcosystemMaven, "com.alibaba:fastjson", "1.2.83", "", ""},
+		{model.EcosystemMaven, "org.apache.logging.log4j:log4j-core", "2.14.1", "GHSA-jfh8-c2jp-5v3q", model.SeverityCritical},
+		{model.EcosystemMaven, "org.apache.logging.log4j:log4j-core", "2.12.1", "GHSA-jfh8-c2jp-5v3q", model.SeverityCritical},
+		{model.EcosystemMaven, "org.apache.logging.log4j:log4j-core", "2.12.2", "", ""},
+		{model.EcosystemMaven, "org.apache.logging.log4j:log4j-core", "2.15.0", "", ""},
+		{model.EcosystemMaven, "org.apache.logging.log4j:log4j-core", "1.2.17", "", ""},
+		{model.EcosystemPyPI, "requests", "2.30.0", "GHSA-j8r2-6x86-q33q", model.SeverityMedium},
+		{model.EcosystemPyPI, "Requests", "2.31.0", "", ""},
+		{model.EcosystemPyPI, "requests", "2.2.1", "GHSA-j8r2-6x86-q33q", model.SeverityMedium},
+		{model.EcosystemNpm, "lodash", "4.17.20", "", ""},
+		{model.EcosystemCargo, "com.alibaba:fastjson", "1.2.80", "", ""},
+	}
+	for _, tt := range tests {
+		vulns := db.Query(tt.ecosystem, tt.name, tt.version)
+		if tt.want == "" {
+			if len(vulns) > 0 {
+				t.Errorf("%s %s@%s 不应匹配公告，实际为 %+v", tt.ecosystem, tt.name, tt.version, vulns)
+			}
+			continue
+		}
+		if len(vulns) != 1 || vulns[0].ID != tt.want || vulns[0].Severity != tt.severity {
+			t.Errorf("%s %s@%s 应匹配 %s (%s)，实际为 %+v", tt.ecosystem, tt.name, tt.version, tt.want, tt.severity, vulns)
+		}
+	}
+
+	fastjson := db.Query(model.EcosystemMaven, "com.alibaba:fastjson", "1.2.80")[0]
+	if fastjson.Score != 9.8 || len(fastjson.Fixed) != 1 || fastjson.Fixed[0] != "1.2.83" || fastjson.Aliases[0] != "CVE-2022-25845" {
+		t.Errorf("fastjson 公告的分数、修复版本或别名错误: %+v", fastjson)
+	}
+	log4j := db.Query(model.EcosystemMaven, "org.apache.logging.log4j:log4j-core", "2.14.1")[0]
+	if len(log4j.Fixed) != 2 {
+		t.Errorf("log4j 公告应有两个修复版本: %+v", log4j)
+	}
+}
+
+func TestIndexCache(t *testing.T) {
+	dump := writeDump(t)
+	cacheDir := t.TempDir()
+	first, err := Open(dump, cacheDir)
+	if err != nil || first.FromCache {
+		t.Fatalf("首次加载不应使用缓存: %v", err)
+	}
+	second, err := Open(dump, cacheDir)
+	if err != nil || !second.FromCache {
+		t.Fatalf("数据未变化时应从缓存加载: %v", err)
+	}
+	if vulns := second.Query(model.EcosystemMaven, "com.alibaba:fastjson", "1.2.80"); len(vulns) != 1 {
+		t.Errorf("缓存加载后的查询结果错误: %+v", vulns)
+	}
+
+	// 修改数据后缓存失效
+	path := filepath.Join(dump, "PyPI", "GHSA-j8r2-6x86-q33q.json")
+	if err := os.WriteFile(path, []byte(`{"id": "GHSA-new", "affected": [{"package": {"ecosystem": "PyPI", "name": "flask"}, "versions": ["2.0.0"]}]}`), 0644); err != nil {
+		t.Fatalf("无法修改公告: %v", err)
+	}
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(path, later, later)
+	third, err := Open(dump, cacheDir)
+	if err != nil || third.FromCache {
+		t.Fatalf("数据变化后不应使用缓存: %v", err)
+	}
+	if vulns := third.Query(model.EcosystemPyPI, "Flask", "2.0.0"); len(vulns) != 1 {
+		t.Errorf("重新加载后的查询结果错误: %+v", vulns)
+	}
+
+	// 单个压缩包同样可以加载
+	zipDB, err := Open(filepath.Join(dump, "Maven", "all.zip"), "")
+	if err != nil || zipDB.Advisories() != 2 {
+		t.Errorf("加载单个压缩包失败: %v", err)
+	}
+	if _, err := Open(t.TempDir(), ""); err == nil {
+		t.Errorf("没有公告的目录应返回错误")
+	}
+}
+
+func TestCVSS3BaseScore(t *testing.T) {
+	tests := []struct {
+		vector string
+		score  float64
+	}{
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", 9.8},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", 10.0},
+		{"CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:U/C:L/I:N/A:N", 4.3},
+		{"CVSS:3.0/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N", 6.1},
+		{"CVSS:3.1/AV:L/AC:H/PR:H/UI:R/S:U/C:N/I:N/A:N", 0},
+	}
+	for _, tt := range tests {
+		score, err := cvss3BaseScore(tt.vector)
+		if err != nil || score != tt.score {
+			t.Errorf("cvss3BaseScore(%s) = %v (%v)，期望 %v", tt.vector, score, err, tt.score)
+		}
+	}
+	for _, invalid := range []string{"CVSS:2.0/AV:N", "CVSS:3.1/AV:X/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", "CVSS:3.1/AV:N/AC:L"} {
+		if _, err := cvss3BaseScore(invalid); err == nil {
+			t.Errorf("无效的向量 %s 应返回错误", invalid)
+		}
+	}
+}
+
+func TestRangeLastAffected(t *testing.T) {
+	r := Range{Events: []Event{{Introduced: "1.0.0"}, {LastAffected: "1.4.0"}}}
+	for raw, want := range map[string]bool{"0.9.0": false, "1.0.0": true, "1.4.0": true, "1.4.1": false} {
+		if got := r.affects(version.MustParse(raw)); got != want {
+			t.Errorf("%s 受影响应为 %v，实际为 %v", raw, want, got)
+		}
+	}
+}